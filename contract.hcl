@@ -63,7 +63,34 @@ contracts {
         }
     }
     
-    # Set environment variable. Use flags to 
+    # Schedule stanza causes the connector to invoke functions
+    # of the contract on cron expressions. Runs are skipped while
+    # the contract is unhealthy.
+    schedule {
+        job {
+            # A unique name for the job
+            name = "cleanup"
+            # A cron expression evaluated in UTC (ex: "*/5 * * * *", "@daily", "@every 90s")
+            cron = "0 * * * *"
+            # The function to invoke and its parameters
+            function = "cleanup"
+            params = ["expired"]
+            # What to do about missed runs: skip or run_once (Default: skip)
+            missed = "run_once"
+            # What to do if the previous run is still in progress: skip or allow (Default: skip)
+            overlap = "skip"
+        }
+    }
+
+    # Timeouts stanza sets how long an invocation of a function,
+    # including a scheduled one, may run (Default: 2m, Max: 10m).
+    # "*" applies to every function without a timeout.
+    timeouts {
+        "*" = "1m"
+        "cleanup" = "5m"
+    }
+
+    # Set environment variable. Use flags to
     # enable special directives for individual variables.
     # @private flag will cause the value to never show up in any publicly accessible channel
     # @genRand32 generates a 32 byte random string 
@@ -86,6 +86,8 @@ func (api *API) CreateCocoon(ctx context.Context, req *proto_api.ContractRequest
 	release.ID = releaseID
 	release.CocoonID = cocoon.ID
 	release.ACL = types.NewACLMapFromByte(req.ACL)
+	release.Schedule = types.NewScheduleFromByte(req.Schedule)
+	release.Timeouts = types.NewInvokeTimeouts(req.Timeouts)
 	release.CreatedAt = now.UTC().Format(time.RFC3339Nano)
 
 	// Resolve firewall rules destination if firewall is enabled.
@@ -272,6 +274,17 @@ func (api *API) UpdateCocoon(ctx context.Context, req *proto_api.ContractRequest
 		releaseUpd.ACL = types.NewACLMapFromByte(req.ACL)
 	}
 
+	// the schedule is JSON encoded in the request and cannot be copied
+	releaseUpd.Schedule = release.Schedule
+	if len(req.Schedule) > 0 {
+		releaseUpd.Schedule = types.NewScheduleFromByte(req.Schedule)
+	}
+
+	releaseUpd.Timeouts = release.Timeouts
+	if len(req.Timeouts) > 0 {
+		releaseUpd.Timeouts = types.NewInvokeTimeouts(req.Timeouts)
+	}
+
 	// process special "pin", "unpin" and "unpin_once" environment flags
 	updateReleaseEnv(release.Env, releaseUpd.Env)
 
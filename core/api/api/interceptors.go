@@ -2,11 +2,13 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/ellcrys/util"
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/types"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -20,11 +22,21 @@ var ExcludeMethodsFromAuth = []string{
 	"/proto_api.API/GetIdentity",
 }
 
+// requestDuration measures the time taken to handle API requests
+var requestDuration = metrics.NewHistogram("cocoon_api_request_duration_seconds", "Time taken to handle API requests", nil, "method", "outcome")
+
 // Interceptors returns the API interceptors
 func (api *API) Interceptors() grpc.UnaryServerInterceptor {
 	return middleware.ChainUnaryServer(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		apiLog.Debugf("New request [method=%s]", info.FullMethod)
-		return handler(ctx, req)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		if err != nil {
+			requestDuration.ObserveSince(start, info.FullMethod, "error")
+		} else {
+			requestDuration.ObserveSince(start, info.FullMethod, "ok")
+		}
+		return resp, err
 	}, api.authenticateInterceptor)
 }
 
@@ -239,6 +239,8 @@ type ContractRequest struct {
 	SigThreshold   int32             `protobuf:"varint,12,opt,name=sigThreshold,proto3" json:"sigThreshold,omitempty" structs:"sigThreshold,omitempty" mapstructure:"sigThreshold,omitempty"`
 	Env            map[string]string `protobuf:"bytes,13,rep,name=env" json:"env,omitempty" structs:"env,omitempty" mapstructure:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	EnableFirewall bool              `protobuf:"varint,14,opt,name=enableFirewall,proto3" json:"enableFirewall,omitempty" structs:"enableFirewall,omitempty" mapstructure:"enableFirewall,omitempty"`
+	Schedule       []byte            `protobuf:"bytes,15,opt,name=schedule,proto3" json:"schedule,omitempty" structs:"schedule,omitempty" mapstructure:"schedule,omitempty"`
+	Timeouts       map[string]string `protobuf:"bytes,16,rep,name=timeouts" json:"timeouts,omitempty" structs:"timeouts,omitempty" mapstructure:"timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *ContractRequest) Reset()                    { *m = ContractRequest{} }
@@ -344,6 +346,20 @@ func (m *ContractRequest) GetEnableFirewall() bool {
 	return false
 }
 
+func (m *ContractRequest) GetSchedule() []byte {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+func (m *ContractRequest) GetTimeouts() map[string]string {
+	if m != nil {
+		return m.Timeouts
+	}
+	return nil
+}
+
 type GetCocoonRequest struct {
 	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"id" structs:"id,omitempty" mapstructure:"id,omitempty"`
 }
@@ -1010,78 +1026,83 @@ var _API_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("server.proto", fileDescriptorServer) }
 
 var fileDescriptorServer = []byte{
-	// 1166 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0x5f, 0x6f, 0xe3, 0x44,
-	0x10, 0x57, 0x92, 0x6b, 0x9b, 0x4c, 0xd3, 0x5c, 0xbb, 0x5c, 0x0f, 0x53, 0x0e, 0x25, 0x72, 0x5f,
-	0x82, 0x04, 0x3d, 0xa9, 0x40, 0x81, 0xea, 0xe0, 0xe8, 0xbf, 0x2b, 0x41, 0x41, 0xaa, 0xb6, 0x14,
-	0x21, 0x21, 0x41, 0x37, 0xf1, 0x5c, 0xea, 0x3b, 0xdb, 0x1b, 0xec, 0x75, 0x8e, 0xbc, 0x20, 0x1e,
-	0x79, 0xe1, 0x85, 0x57, 0xbe, 0x9b, 0x3f, 0x40, 0xc4, 0xd3, 0x7d, 0x02, 0xe4, 0xf5, 0x9f, 0x78,
-	0xfd, 0xa7, 0xaa, 0x74, 0xf0, 0x14, 0xcf, 0xfe, 0x66, 0xe7, 0x37, 0x33, 0x3b, 0x93, 0x9d, 0x85,
-	0xb6, 0x87, 0xee, 0x0c, 0xdd, 0xbd, 0xa9, 0xcb, 0x05, 0x27, 0x2d, 0xf9, 0xf3, 0x33, 0x9b, 0x9a,
-	0x3b, 0x1f, 0x4e, 0x4c, 0x71, 0xe3, 0x8f, 0xf6, 0xc6, 0xdc, 0x7e, 0x3c, 0xe1, 0x13, 0xfe, 0x58,
-	0x42, 0x23, 0xff, 0xb9, 0x94, 0xa4, 0x20, 0xbf, 0xa2, 0x9d, 0xfa, 0xfb, 0xb0, 0x7d, 0x8a, 0x16,
-	0x0a, 0xbc, 0x44, 0xcf, 0x33, 0xb9, 0xe3, 0x51, 0xfc, 0xc5, 0x47, 0x4f, 0x90, 0x4d, 0x68, 0x30,
-	0xcb, 0xd2, 0x6a, 0xbd, 0x5a, 0xbf, 0x49, 0xc3, 0x4f, 0xfd, 0x1a, 0x3a, 0xe7, 0x28, 0x86, 0x7c,
-	0x92, 0xea, 0xec, 0x40, 0x73, 0xcc, 0xc7, 0x9c, 0x3b, 0x83, 0x53, 0xa9, 0xd8, 0xa2, 0xa9, 0x1c,
-	0x62, 0x8e, 0x6f, 0x0f, 0x4d, 0x07, 0x3d, 0xad, 0xde, 0xab, 0xf5, 0x57, 0x68, 0x2a, 0x93, 0x87,
-	0xb0, 0xea, 0x71, 0xdf, 0x1d, 0xa3, 0xd6, 0x90, 0xbb, 0x62, 0x49, 0x9f, 0x41, 0xfb, 0x99, 0xe9,
-	0xe2, 0x2b, 0x66, 0x59, 0xd4, 0xb7, 0x90, 0xf4, 0x60, 0xdd, 0x40, 0x4f, 0x98, 0x0e, 0x13, 0x26,
-	0x77, 0x62, 0x8a, 0xec, 0x12, 0xe9, 0xc3, 0xfd, 0x8c, 0x78, 0xc1, 0x5d, 0x21, 0xc9, 0x5a, 0x34,
-	0xbf, 0x1c, 0xfa, 0x23, 0x23, 0x1e, 0x73, 0x2b, 0x66, 0x4d, 0x65, 0xfd, 0x2b, 0x68, 0x0f, 0xf9,
-	0xc4, 0x74, 0x92, 0xb8, 0x1e, 0xc0, 0x0a, 0xda, 0xcc, 0xb4, 0x62, 0xc6, 0x48, 0x90, 0x16, 0x98,
-	0xe7, 0xbd, 0xe2, 0xae, 0x11, 0x93, 0xa4, 0xb2, 0x7e, 0x06, 0xdb, 0x47, 0x86, 0x71, 0x69, 0x4e,
-	0x1c, 0x26, 0xb8, 0x6b, 0xe2, 0x9d, 0x52, 0xb4, 0x09, 0x8d, 0xc1, 0x69, 0x98, 0x9d, 0x46, 0xbf,
-	0x45, 0xc3, 0x4f, 0xfd, 0x6b, 0xd0, 0x28, 0xda, 0x7c, 0x86, 0x6f, 0x6c, 0xe9, 0x18, 0x3a, 0x47,
-	0x86, 0xf1, 0x3d, 0x17, 0x98, 0xec, 0x7f, 0x04, 0x2d, 0x17, 0x2d, 0x64, 0x1e, 0xa6, 0x06, 0x96,
-	0x0b, 0x84, 0xc0, 0xbd, 0x19, 0x17, 0x18, 0x1f, 0x95, 0xfc, 0xd6, 0xff, 0xd9, 0x80, 0xfb, 0x27,
-	0xdc, 0x11, 0x2e, 0x1b, 0x8b, 0xc4, 0x8a, 0xc8, 0x7b, 0x71, 0xfc, 0xc3, 0x22, 0xe8, 0x92, 0x64,
-	0xed, 0x03, 0x6e, 0x9b, 0x02, 0xed, 0xa9, 0x98, 0xbf, 0x0e, 0xba, 0x5f, 0x7a, 0xc2, 0xf5, 0xc7,
-	0xc2, 0x3b, 0xd4, 0x8b, 0xb0, 0xde, 0xb3, 0xd9, 0x34, 0xc2, 0x7d, 0x17, 0x4b, 0x35, 0x32, 0xf1,
-	0xfd, 0x08, 0x8d, 0x2b, 0x3a, 0x8c, 0xb2, 0x7e, 0x3c, 0x58, 0x04, 0xdd, 0x0d, 0xdf, 0xb5, 0x14,
-	0xae, 0x8f, 0x53, 0x2e, 0x05, 0xc9, 0xd3, 0xa8, 0x20, 0x0d, 0xad, 0x86, 0x21, 0x59, 0xcc, 0x99,
-	0xf8, 0x6c, 0x12, 0xd7, 0x63, 0x14, 0x52, 0xb2, 0x56, 0x11, 0x52, 0x11, 0xce, 0x73, 0x95, 0x68,
-	0xd0, 0x94, 0x89, 0x70, 0x58, 0x9b, 0xa1, 0x1b, 0xb6, 0x9c, 0x76, 0x4f, 0x92, 0x5e, 0x2d, 0x82,
-	0xee, 0x56, 0xbc, 0xa4, 0x70, 0x3e, 0x49, 0x39, 0x0b, 0x68, 0x9e, 0xb2, 0xa8, 0x40, 0x13, 0x16,
-	0xf2, 0x1b, 0xc0, 0xc8, 0x37, 0x2d, 0xe3, 0x82, 0xb9, 0xcc, 0xd6, 0x56, 0x24, 0xe7, 0x4f, 0x8b,
-	0xa0, 0xfb, 0x60, 0xb9, 0xaa, 0xd0, 0x1e, 0xa7, 0xb4, 0x65, 0x0a, 0x79, 0xe6, 0x52, 0x1d, 0x9a,
-	0x61, 0x24, 0x7f, 0xd6, 0xa0, 0xf9, 0x3c, 0xee, 0x6e, 0x6d, 0xb5, 0xd7, 0xe8, 0xaf, 0xef, 0xbf,
-	0xbd, 0x97, 0xfe, 0x6f, 0xed, 0x65, 0x1b, 0x3f, 0x3a, 0x80, 0x44, 0xb9, 0xe2, 0x00, 0x8a, 0x70,
-	0xde, 0xa7, 0x12, 0x0d, 0x9a, 0xba, 0x10, 0xd6, 0xd4, 0xd1, 0xc9, 0x50, 0x5b, 0xeb, 0xd5, 0xfa,
-	0xed, 0xa8, 0xa6, 0xd8, 0xb8, 0xaa, 0xa6, 0x14, 0x24, 0x4f, 0xa3, 0x82, 0x34, 0xb4, 0x4a, 0x5e,
-	0xc0, 0xaa, 0x8d, 0x36, 0x77, 0xe7, 0x5a, 0x33, 0x6c, 0xa8, 0x63, 0xba, 0x08, 0xba, 0x9b, 0xd1,
-	0x8a, 0x42, 0x71, 0x98, 0x52, 0xe4, 0xc1, 0x3c, 0x4b, 0x01, 0xa7, 0x31, 0x43, 0x58, 0xbf, 0x27,
-	0x17, 0x57, 0x97, 0x37, 0xcc, 0x45, 0xad, 0x25, 0xd9, 0x64, 0xfa, 0x92, 0xb5, 0x8a, 0xf4, 0x15,
-	0xe1, 0x3c, 0x63, 0x89, 0x06, 0x4d, 0x99, 0xc8, 0x35, 0xdc, 0xb3, 0x4c, 0xe7, 0xa5, 0x06, 0xb2,
-	0x90, 0x86, 0x8b, 0xa0, 0xdb, 0x09, 0x65, 0x85, 0xed, 0x60, 0xd9, 0x2d, 0x0a, 0x54, 0xe8, 0x14,
-	0x15, 0xa5, 0xd2, 0x32, 0xf9, 0xab, 0x06, 0x1d, 0xc7, 0xb7, 0x33, 0x7f, 0x85, 0xda, 0xba, 0x0c,
-	0xef, 0xc5, 0x22, 0xe8, 0x6a, 0x2a, 0xa2, 0xd0, 0x7e, 0x93, 0xd2, 0x56, 0x29, 0xe5, 0x1d, 0xa8,
-	0xd4, 0xa3, 0x39, 0x0f, 0xc8, 0x1f, 0x35, 0x68, 0x7b, 0xe6, 0xe4, 0xbb, 0x1b, 0x17, 0xbd, 0x1b,
-	0x6e, 0x19, 0x5a, 0x5b, 0xba, 0x64, 0x2c, 0x82, 0xee, 0xc3, 0xec, 0xba, 0xe2, 0xd0, 0xb3, 0xd4,
-	0xa1, 0x72, 0x95, 0xbc, 0x3b, 0x15, 0x5a, 0x54, 0x61, 0x26, 0xbf, 0xd7, 0xa0, 0x81, 0xce, 0x4c,
-	0xdb, 0x90, 0xbd, 0xb4, 0x9b, 0xe9, 0xa5, 0xdc, 0x9f, 0xf6, 0xde, 0x99, 0x33, 0x3b, 0x73, 0x84,
-	0x3b, 0x8f, 0xca, 0x1c, 0x9d, 0x59, 0x45, 0x99, 0x2b, 0x48, 0xde, 0x29, 0x15, 0xa4, 0x21, 0xb5,
-	0x3c, 0x22, 0x74, 0xd8, 0xc8, 0xc2, 0xa4, 0x7d, 0xb5, 0x4e, 0x38, 0x30, 0x44, 0x47, 0xa4, 0x22,
-	0x15, 0x47, 0x54, 0xa5, 0x54, 0xa4, 0xaf, 0xd0, 0xa3, 0x39, 0x0f, 0x76, 0x0e, 0xa0, 0x99, 0x04,
-	0x1c, 0x5e, 0x8c, 0x2f, 0x71, 0x1e, 0x5f, 0x77, 0xe1, 0x67, 0x78, 0xb7, 0xcf, 0x98, 0xe5, 0x63,
-	0x7c, 0x85, 0x47, 0xc2, 0x61, 0xfd, 0xb3, 0x9a, 0x7e, 0x0d, 0x9b, 0xe7, 0x28, 0x4e, 0xe4, 0x9d,
-	0x93, 0x5c, 0x77, 0x43, 0xa8, 0xa7, 0x17, 0xdd, 0x93, 0x45, 0xd0, 0xad, 0x9b, 0xc6, 0xeb, 0xa0,
-	0xbb, 0x9f, 0x7a, 0x6f, 0xde, 0x72, 0x8a, 0x0a, 0x46, 0xeb, 0x83, 0x53, 0xfd, 0x57, 0x20, 0xe7,
-	0x28, 0x06, 0x06, 0x3a, 0xc2, 0x14, 0xf3, 0xdb, 0xa7, 0x8d, 0x88, 0xb9, 0xfe, 0x1f, 0x31, 0xef,
-	0xc2, 0xd6, 0x39, 0x0a, 0x1a, 0x5d, 0xf7, 0x09, 0x71, 0x67, 0x19, 0x9c, 0x54, 0x1a, 0xc0, 0xc6,
-	0x29, 0x4e, 0x2d, 0x3e, 0xbf, 0xcb, 0xc8, 0xa1, 0x8c, 0x13, 0xf5, 0xdc, 0x38, 0xa1, 0x0f, 0x60,
-	0xfb, 0xc4, 0x45, 0x26, 0xf0, 0x6e, 0xc1, 0xde, 0x36, 0x5a, 0x31, 0xd8, 0xba, 0x14, 0x7c, 0xfa,
-	0x7f, 0x9e, 0xcb, 0x01, 0x34, 0x29, 0x7a, 0x53, 0xee, 0x78, 0x28, 0x67, 0x53, 0xc1, 0x84, 0xef,
-	0x49, 0xeb, 0x2b, 0x34, 0x96, 0xc2, 0x01, 0x69, 0xc4, 0x8d, 0xb9, 0x74, 0xaf, 0x4d, 0xe5, 0xf7,
-	0xfe, 0xdf, 0x6b, 0xd0, 0x38, 0xba, 0x18, 0x90, 0x4f, 0x60, 0x45, 0xce, 0x8f, 0x24, 0x7b, 0xa1,
-	0x65, 0x27, 0xca, 0x9d, 0xb7, 0x32, 0x40, 0x4a, 0xf5, 0x14, 0xda, 0x51, 0x92, 0xa2, 0xd8, 0xc8,
-	0x4e, 0x75, 0x0b, 0x57, 0x1a, 0xb8, 0x9a, 0x1a, 0x6f, 0x64, 0x00, 0x96, 0x65, 0x41, 0x1e, 0x65,
-	0x54, 0x0a, 0xd5, 0x52, 0x6e, 0xe0, 0x1c, 0x3a, 0xea, 0x39, 0x93, 0x5e, 0xd6, 0x87, 0xb2, 0x12,
-	0x28, 0x37, 0xf4, 0x29, 0xac, 0x46, 0xb5, 0x47, 0xb4, 0x0c, 0xac, 0x94, 0x63, 0xf9, 0xc6, 0x2f,
-	0xa0, 0x95, 0x76, 0x2d, 0x79, 0x57, 0x8d, 0x40, 0xa9, 0x99, 0xf2, 0xed, 0x47, 0xb0, 0x9e, 0x69,
-	0x49, 0xf2, 0x9e, 0x6a, 0xe0, 0x4e, 0xae, 0x3f, 0x05, 0x58, 0x16, 0xa8, 0x92, 0xc4, 0x42, 0xdd,
-	0x56, 0x26, 0x51, 0x7d, 0x3c, 0x28, 0x49, 0x2c, 0x7d, 0x57, 0x94, 0x1b, 0xfa, 0x1c, 0xd6, 0xe2,
-	0xa1, 0x9f, 0xbc, 0xa3, 0x5a, 0xc8, 0x3c, 0x04, 0xca, 0xb7, 0x7e, 0x0b, 0x5b, 0x85, 0x97, 0x07,
-	0xd9, 0x55, 0x34, 0xcb, 0xdf, 0x25, 0x95, 0x9e, 0xc4, 0x6f, 0x45, 0xc5, 0x13, 0xf5, 0xfd, 0x58,
-	0x99, 0x0d, 0xf5, 0x45, 0xaa, 0x64, 0xa3, 0xf4, 0xb1, 0x5a, 0x6a, 0x68, 0xb4, 0x2a, 0xd7, 0x3e,
-	0xfa, 0x37, 0x00, 0x00, 0xff, 0xff, 0xd2, 0xc4, 0xce, 0xe3, 0x2b, 0x0f, 0x00, 0x00,
+	// 1238 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x6e, 0xe3, 0x54,
+	0x10, 0x56, 0x92, 0xfe, 0x24, 0xd3, 0x34, 0x4d, 0x0f, 0xdb, 0xc5, 0x84, 0x45, 0x89, 0xdc, 0x9b,
+	0x20, 0x41, 0x57, 0x2a, 0x50, 0xa0, 0x2c, 0x2c, 0xfd, 0xdb, 0x12, 0x14, 0xa4, 0xea, 0x74, 0x8b,
+	0x90, 0x90, 0xa0, 0x4e, 0x3c, 0x9b, 0x7a, 0xd7, 0xf6, 0x09, 0xf6, 0x71, 0x96, 0xdc, 0x20, 0x2e,
+	0xb9, 0xe1, 0x86, 0x5b, 0x24, 0x9e, 0x85, 0x27, 0xf1, 0x03, 0xe4, 0x72, 0x9f, 0x00, 0xf9, 0xf8,
+	0x27, 0x3e, 0xfe, 0xa9, 0x8a, 0x16, 0xae, 0xd6, 0x33, 0xdf, 0x9c, 0xf9, 0x66, 0xce, 0xcc, 0x64,
+	0xe7, 0x14, 0x9a, 0x2e, 0x3a, 0x33, 0x74, 0xf6, 0xa6, 0x0e, 0xe3, 0x8c, 0x34, 0xc4, 0x3f, 0x3f,
+	0x6a, 0x53, 0xa3, 0xf3, 0xfe, 0xc4, 0xe0, 0x37, 0xde, 0x68, 0x6f, 0xcc, 0xac, 0x87, 0x13, 0x36,
+	0x61, 0x0f, 0x05, 0x34, 0xf2, 0x9e, 0x09, 0x49, 0x08, 0xe2, 0x2b, 0x3c, 0xa9, 0xbe, 0x0b, 0x3b,
+	0xa7, 0x68, 0x22, 0xc7, 0x4b, 0x74, 0x5d, 0x83, 0xd9, 0x2e, 0xc5, 0x9f, 0x3c, 0x74, 0x39, 0x69,
+	0x43, 0x4d, 0x33, 0x4d, 0xa5, 0xd2, 0xab, 0xf4, 0xeb, 0x34, 0xf8, 0x54, 0xaf, 0xa1, 0x75, 0x8e,
+	0x7c, 0xc8, 0x26, 0x89, 0x4d, 0x07, 0xea, 0x63, 0x36, 0x66, 0xcc, 0x1e, 0x9c, 0x0a, 0xc3, 0x06,
+	0x4d, 0xe4, 0x00, 0xb3, 0x3d, 0x6b, 0x68, 0xd8, 0xe8, 0x2a, 0xd5, 0x5e, 0xa5, 0xbf, 0x4a, 0x13,
+	0x99, 0xdc, 0x87, 0x35, 0x97, 0x79, 0xce, 0x18, 0x95, 0x9a, 0x38, 0x15, 0x49, 0xea, 0x0c, 0x9a,
+	0x4f, 0x0c, 0x07, 0x5f, 0x6a, 0xa6, 0x49, 0x3d, 0x13, 0x49, 0x0f, 0x36, 0x74, 0x74, 0xb9, 0x61,
+	0x6b, 0xdc, 0x60, 0x76, 0x44, 0x91, 0x56, 0x91, 0x3e, 0x6c, 0xa5, 0xc4, 0x0b, 0xe6, 0x70, 0x41,
+	0xd6, 0xa0, 0x59, 0x75, 0x10, 0x8f, 0xc8, 0x78, 0xcc, 0xcc, 0x88, 0x35, 0x91, 0xd5, 0x2f, 0xa1,
+	0x39, 0x64, 0x13, 0xc3, 0x8e, 0xf3, 0xba, 0x07, 0xab, 0x68, 0x69, 0x86, 0x19, 0x31, 0x86, 0x82,
+	0xf0, 0xa0, 0xb9, 0xee, 0x4b, 0xe6, 0xe8, 0x11, 0x49, 0x22, 0xab, 0x67, 0xb0, 0x73, 0xa4, 0xeb,
+	0x97, 0xc6, 0xc4, 0xd6, 0x38, 0x73, 0x0c, 0xbc, 0xd3, 0x15, 0xb5, 0xa1, 0x36, 0x38, 0x0d, 0x6e,
+	0xa7, 0xd6, 0x6f, 0xd0, 0xe0, 0x53, 0xfd, 0x0a, 0x14, 0x8a, 0x16, 0x9b, 0xe1, 0x6b, 0x7b, 0x3a,
+	0x86, 0xd6, 0x91, 0xae, 0x7f, 0xcb, 0x38, 0xc6, 0xe7, 0x1f, 0x40, 0xc3, 0x41, 0x13, 0x35, 0x17,
+	0x13, 0x07, 0x4b, 0x05, 0x21, 0xb0, 0x32, 0x63, 0x1c, 0xa3, 0x52, 0x89, 0x6f, 0xf5, 0xef, 0x36,
+	0x6c, 0x9d, 0x30, 0x9b, 0x3b, 0xda, 0x98, 0xc7, 0x5e, 0x78, 0x36, 0x8a, 0xe3, 0xef, 0x16, 0x7e,
+	0x97, 0xc4, 0xba, 0xf7, 0x98, 0x65, 0x70, 0xb4, 0xa6, 0x7c, 0xfe, 0xca, 0xef, 0x7e, 0xe1, 0x72,
+	0xc7, 0x1b, 0x73, 0xf7, 0x50, 0xcd, 0xc3, 0x6a, 0xcf, 0xd2, 0xa6, 0x21, 0xee, 0x39, 0x58, 0x68,
+	0x91, 0xca, 0xef, 0x7b, 0xa8, 0x5d, 0xd1, 0x61, 0x78, 0xeb, 0xc7, 0x83, 0x85, 0xdf, 0xdd, 0xf4,
+	0x1c, 0x53, 0xe2, 0xfa, 0x30, 0xe1, 0x92, 0x90, 0x2c, 0x8d, 0x0c, 0xd2, 0xc0, 0x6b, 0x90, 0x92,
+	0xa9, 0xd9, 0x13, 0x4f, 0x9b, 0x44, 0xfd, 0x18, 0xa6, 0x14, 0xeb, 0x4a, 0x52, 0xca, 0xc3, 0x59,
+	0xae, 0x02, 0x0b, 0x9a, 0x30, 0x11, 0x06, 0xeb, 0x33, 0x74, 0x82, 0x91, 0x53, 0x56, 0x04, 0xe9,
+	0xd5, 0xc2, 0xef, 0x6e, 0x47, 0x2a, 0x89, 0xf3, 0x51, 0xc2, 0x99, 0x43, 0xb3, 0x94, 0x79, 0x03,
+	0x1a, 0xb3, 0x90, 0x5f, 0x00, 0x46, 0x9e, 0x61, 0xea, 0x17, 0x9a, 0xa3, 0x59, 0xca, 0xaa, 0xe0,
+	0xfc, 0x61, 0xe1, 0x77, 0xef, 0x2d, 0xb5, 0x12, 0xed, 0x71, 0x42, 0x5b, 0x64, 0x90, 0x65, 0x2e,
+	0xb4, 0xa1, 0x29, 0x46, 0xf2, 0x7b, 0x05, 0xea, 0xcf, 0xa2, 0xe9, 0x56, 0xd6, 0x7a, 0xb5, 0xfe,
+	0xc6, 0xfe, 0x9b, 0x7b, 0xc9, 0xef, 0xd6, 0x5e, 0x7a, 0xf0, 0xc3, 0x02, 0xc4, 0xc6, 0x25, 0x05,
+	0xc8, 0xc3, 0xd9, 0x98, 0x0a, 0x2c, 0x68, 0x12, 0x42, 0xd0, 0x53, 0x47, 0x27, 0x43, 0x65, 0xbd,
+	0x57, 0xe9, 0x37, 0xc3, 0x9e, 0xd2, 0xc6, 0x65, 0x3d, 0xa5, 0x8d, 0x6f, 0xa1, 0x91, 0x41, 0x1a,
+	0x78, 0x25, 0xcf, 0x61, 0xcd, 0x42, 0x8b, 0x39, 0x73, 0xa5, 0x1e, 0x0c, 0xd4, 0x31, 0x5d, 0xf8,
+	0xdd, 0x76, 0xa8, 0x91, 0x28, 0x0e, 0x13, 0x8a, 0x2c, 0x98, 0x65, 0xc9, 0xe1, 0x34, 0x62, 0x08,
+	0xfa, 0xf7, 0xe4, 0xe2, 0xea, 0xf2, 0x46, 0x73, 0x50, 0x69, 0x08, 0x36, 0x71, 0x7d, 0xb1, 0xae,
+	0xe4, 0xfa, 0xf2, 0x70, 0x96, 0xb1, 0xc0, 0x82, 0x26, 0x4c, 0xe4, 0x1a, 0x56, 0x4c, 0xc3, 0x7e,
+	0xa1, 0x80, 0x68, 0xa4, 0xe1, 0xc2, 0xef, 0xb6, 0x02, 0x59, 0x62, 0x3b, 0x58, 0x4e, 0x8b, 0x04,
+	0xe5, 0x26, 0x45, 0x46, 0xa9, 0xf0, 0x4c, 0xfe, 0xa8, 0x40, 0xcb, 0xf6, 0xac, 0xd4, 0x4f, 0xa1,
+	0xb2, 0x21, 0xd2, 0x7b, 0xbe, 0xf0, 0xbb, 0x8a, 0x8c, 0x48, 0xb4, 0x5f, 0x27, 0xb4, 0x65, 0x46,
+	0xd9, 0x00, 0x4a, 0xed, 0x68, 0x26, 0x02, 0xf2, 0x5b, 0x05, 0x9a, 0xae, 0x31, 0x79, 0x7a, 0xe3,
+	0xa0, 0x7b, 0xc3, 0x4c, 0x5d, 0x69, 0x8a, 0x90, 0xf4, 0x85, 0xdf, 0xbd, 0x9f, 0xd6, 0x4b, 0x01,
+	0x3d, 0x49, 0x02, 0x2a, 0x36, 0xc9, 0x86, 0x53, 0x62, 0x45, 0x25, 0x66, 0xf2, 0x6b, 0x05, 0x6a,
+	0x68, 0xcf, 0x94, 0x4d, 0x31, 0x4b, 0xbb, 0xa9, 0x59, 0xca, 0xfc, 0x68, 0xef, 0x9d, 0xd9, 0xb3,
+	0x33, 0x9b, 0x3b, 0xf3, 0xb0, 0xcd, 0xd1, 0x9e, 0x95, 0xb4, 0xb9, 0x84, 0x64, 0x83, 0x92, 0x41,
+	0x1a, 0x50, 0x8b, 0x12, 0xa1, 0xad, 0x8d, 0x4c, 0x8c, 0xc7, 0x57, 0x69, 0x05, 0x0b, 0x43, 0x58,
+	0x22, 0x19, 0x29, 0x29, 0x51, 0x99, 0x51, 0x9e, 0xbe, 0xc4, 0x8e, 0x66, 0x22, 0x08, 0xe6, 0xc1,
+	0x1d, 0xdf, 0xa0, 0xee, 0x99, 0xa8, 0x6c, 0x89, 0xe9, 0x16, 0xf3, 0x10, 0xeb, 0x4a, 0xe6, 0x21,
+	0x0f, 0xe7, 0xaa, 0x92, 0xb7, 0xa0, 0x09, 0x13, 0xf9, 0xab, 0x02, 0x75, 0x6e, 0x58, 0xc8, 0x3c,
+	0xee, 0x2a, 0x6d, 0x51, 0x92, 0xfe, 0x2d, 0x25, 0x79, 0x1a, 0x99, 0x86, 0x75, 0x11, 0x01, 0xc6,
+	0xa7, 0x4b, 0x02, 0xcc, 0xc3, 0xd9, 0x00, 0x0b, 0x2c, 0x68, 0x12, 0x53, 0xe7, 0x00, 0xea, 0x71,
+	0x1f, 0x04, 0xfb, 0xc2, 0x0b, 0x9c, 0x47, 0x5b, 0x40, 0xf0, 0x19, 0xac, 0x3c, 0x33, 0xcd, 0xf4,
+	0x30, 0xda, 0x6c, 0x42, 0xe1, 0xb0, 0xfa, 0x49, 0xa5, 0xf3, 0x19, 0x6c, 0x4a, 0xc1, 0xfe, 0x9b,
+	0xc3, 0xea, 0x35, 0xb4, 0xcf, 0x91, 0x9f, 0x88, 0xff, 0xc7, 0xe3, 0x15, 0x62, 0x08, 0xd5, 0x64,
+	0x79, 0x78, 0xb4, 0xf0, 0xbb, 0x55, 0x43, 0x7f, 0xe5, 0x77, 0xf7, 0x93, 0x44, 0x8d, 0x5b, 0x26,
+	0x43, 0xc2, 0x68, 0x75, 0x70, 0xaa, 0xfe, 0x0c, 0xe4, 0x1c, 0xf9, 0x40, 0x47, 0x9b, 0x1b, 0x7c,
+	0x7e, 0xfb, 0x06, 0x17, 0x32, 0x57, 0xff, 0x23, 0xe6, 0x5d, 0xd8, 0x3e, 0x47, 0x4e, 0xc3, 0x15,
+	0x2a, 0x26, 0x6e, 0x2d, 0x93, 0x13, 0x46, 0x03, 0xd8, 0x3c, 0xc5, 0xa9, 0xc9, 0xe6, 0x77, 0x59,
+	0xe3, 0xa4, 0x15, 0xad, 0x9a, 0x59, 0xd1, 0xd4, 0x01, 0xec, 0x9c, 0x38, 0xa8, 0x71, 0xbc, 0x5b,
+	0xb2, 0xb7, 0xad, 0xab, 0x1a, 0x6c, 0x5f, 0x72, 0x36, 0xfd, 0x3f, 0xeb, 0x72, 0x00, 0x75, 0x8a,
+	0xee, 0x94, 0xd9, 0x2e, 0x8a, 0x7d, 0x9f, 0x6b, 0xdc, 0x73, 0x85, 0xf7, 0x55, 0x1a, 0x49, 0xc1,
+	0xd2, 0x39, 0x62, 0xfa, 0x5c, 0x84, 0xd7, 0xa4, 0xe2, 0x7b, 0xff, 0xcf, 0x75, 0xa8, 0x1d, 0x5d,
+	0x0c, 0xc8, 0x47, 0xb0, 0x2a, 0x76, 0x72, 0x92, 0x5e, 0x12, 0xd2, 0x5b, 0x7a, 0xe7, 0x8d, 0x14,
+	0x90, 0x50, 0x3d, 0x86, 0x66, 0x78, 0x49, 0x61, 0x6e, 0xa4, 0x53, 0x3e, 0x83, 0xa5, 0x0e, 0xae,
+	0xa6, 0xfa, 0x6b, 0x39, 0x80, 0x65, 0x5b, 0x90, 0x07, 0x29, 0x93, 0x5c, 0xb7, 0x14, 0x3b, 0x38,
+	0x87, 0x96, 0x5c, 0x67, 0xd2, 0x4b, 0xc7, 0x50, 0xd4, 0x02, 0xc5, 0x8e, 0x3e, 0x86, 0xb5, 0xb0,
+	0xf7, 0x88, 0x92, 0x82, 0xa5, 0x76, 0x2c, 0x3e, 0xf8, 0x39, 0x34, 0x92, 0xa9, 0x25, 0x6f, 0xcb,
+	0x19, 0x48, 0x3d, 0x53, 0x7c, 0xfc, 0x08, 0x36, 0x52, 0x23, 0x49, 0xde, 0x91, 0x1d, 0xdc, 0x29,
+	0xf4, 0xc7, 0x00, 0xcb, 0x06, 0x95, 0x2e, 0x31, 0xd7, 0xb7, 0xa5, 0x97, 0x28, 0x3f, 0xc8, 0xa4,
+	0x4b, 0x2c, 0x7c, 0xab, 0x15, 0x3b, 0xfa, 0x14, 0xd6, 0xa3, 0x87, 0x14, 0x79, 0x4b, 0xf6, 0x90,
+	0x7a, 0x5c, 0x15, 0x1f, 0xfd, 0x06, 0xb6, 0x73, 0xaf, 0x39, 0xb2, 0x2b, 0x59, 0x16, 0xbf, 0xf5,
+	0x4a, 0x23, 0x89, 0xde, 0xdf, 0x52, 0x24, 0xf2, 0x9b, 0xbc, 0xf4, 0x36, 0xe4, 0x57, 0xbe, 0x74,
+	0x1b, 0x85, 0x7f, 0x00, 0x28, 0x74, 0x34, 0x5a, 0x13, 0xba, 0x0f, 0xfe, 0x19, 0x00, 0xcb, 0x22,
+	0xfa, 0xac, 0x7f, 0x10, 0x00, 0x00,
 }
@@ -94,6 +94,16 @@ func ValidateRelease(r *types.Release) error {
 			return fmt.Errorf("env: %s", errs[0])
 		}
 	}
+	if len(r.Schedule) > 0 {
+		if errs := r.Schedule.Validate(); len(errs) > 0 {
+			return fmt.Errorf("schedule: %s", errs[0])
+		}
+	}
+	if len(r.Timeouts) > 0 {
+		if errs := r.Timeouts.Validate(); len(errs) > 0 {
+			return fmt.Errorf("timeouts: %s", errs[0])
+		}
+	}
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/ellcrys/cocoon/core/api/api"
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/scheduler"
 	logging "github.com/op/go-logging"
 	"github.com/spf13/cobra"
@@ -60,6 +61,14 @@ var apiStartCmd = &cobra.Command{
 			api.Stop()
 		})
 
+		metricsAddr := scheduler.Getenv("ADDR_API_METRICS", "0.0.0.0:9005")
+		go func() {
+			apiLog.Infof("Serving metrics @ %s/metrics", metricsAddr)
+			if err := metrics.Serve(metricsAddr); err != nil {
+				apiLog.Errorf("Metrics server stopped: %s", err)
+			}
+		}()
+
 		var endedCh = make(chan bool)
 		api.Start(bindAddr, endedCh)
 		<-endedCh
@@ -0,0 +1,603 @@
+package impl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// BlockIDIndexBucketName is the name of the bucket that maps
+// block ids to the chain and number of the block.
+var BlockIDIndexBucketName = "block_ids"
+
+// BoltBlockchain implements the Blockchain interface on an embedded
+// single-file bolt database. It is intended for development and tests
+// where a postgres server is not available.
+type BoltBlockchain struct {
+	db     *bolt.DB
+	tx     *bolt.Tx
+	signer types.BlockSigner
+}
+
+// SetSigner sets the signer that signs the hash of new blocks.
+// New blocks are not signed if a signer is not set.
+func (b *BoltBlockchain) SetSigner(signer types.BlockSigner) {
+	b.signer = signer
+}
+
+// GetImplementationName returns the name of this blockchain implementation
+func (b *BoltBlockchain) GetImplementationName() string {
+	return "bolt.blockchain"
+}
+
+// Connect opens the bolt database referenced by the connection string.
+// The connection string must use the `bolt://` scheme followed by the
+// path of the database file.
+func (b *BoltBlockchain) Connect(dbAddr string) (interface{}, error) {
+	var err error
+	b.db, err = common.OpenBoltDB(dbAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to blockchain backend. %s", err)
+	}
+	return b.db, nil
+}
+
+// Close releases the database handle
+func (b *BoltBlockchain) Close() error {
+	if b.db != nil {
+		return common.CloseBoltDB(b.db)
+	}
+	return nil
+}
+
+// WithDBTx returns a copy of the blockchain whose operations are made
+// in dbTx, a read-write transaction of the database of the blockchain
+func (b *BoltBlockchain) WithDBTx(dbTx interface{}) (types.Blockchain, error) {
+	tx, ok := dbTx.(*bolt.Tx)
+	if !ok || !tx.Writable() || tx.DB() != b.db {
+		return nil, fmt.Errorf("database transaction must be a read-write transaction of the blockchain database")
+	}
+	return &BoltBlockchain{db: b.db, tx: tx, signer: b.signer}, nil
+}
+
+// update runs fn in a read-write transaction or in
+// the transaction the blockchain is bound to
+func (b *BoltBlockchain) update(fn func(*bolt.Tx) error) error {
+	if b.tx != nil {
+		return fn(b.tx)
+	}
+	return b.db.Update(fn)
+}
+
+// view runs fn in a read-only transaction or in
+// the transaction the blockchain is bound to
+func (b *BoltBlockchain) view(fn func(*bolt.Tx) error) error {
+	if b.tx != nil {
+		return fn(b.tx)
+	}
+	return b.db.View(fn)
+}
+
+// Init creates the chain, block, block index and checkpoint buckets
+func (b *BoltBlockchain) Init() error {
+	return b.update(func(tx *bolt.Tx) error {
+		for _, name := range []string{ChainTableName, BlockTableName, BlockIDIndexBucketName, CheckpointTableName} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create blockchain `%s` bucket. %s", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// itob returns an 8-byte big endian representation of v.
+// Keys encoded this way sort in numeric order.
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// CreateChain creates a new chain
+func (b *BoltBlockchain) CreateChain(name string, public bool) (*types.Chain, error) {
+
+	newChain := &types.Chain{
+		Name:      name,
+		Public:    public,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	err := b.update(func(tx *bolt.Tx) error {
+		chains, err := tx.CreateBucketIfNotExists([]byte(ChainTableName))
+		if err != nil {
+			return err
+		}
+
+		if chains.Get([]byte(name)) != nil {
+			return fmt.Errorf("chain with matching name already exists")
+		}
+
+		seq, _ := chains.NextSequence()
+		newChain.Number = uint(seq)
+		chainJSON, _ := util.ToJSON(newChain)
+		return chains.Put([]byte(name), chainJSON)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newChain, nil
+}
+
+// GetChain gets a chain
+func (b *BoltBlockchain) GetChain(name string) (*types.Chain, error) {
+
+	var chain *types.Chain
+	err := b.view(func(tx *bolt.Tx) error {
+		chains := tx.Bucket([]byte(ChainTableName))
+		if chains == nil {
+			return nil
+		}
+		chainJSON := chains.Get([]byte(name))
+		if chainJSON == nil {
+			return nil
+		}
+		chain = &types.Chain{}
+		return util.FromJSON(chainJSON, chain)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain. %s", err)
+	}
+
+	return chain, nil
+}
+
+// MakeChainName returns a new chain name prefixed with a namespace
+func (b *BoltBlockchain) MakeChainName(namespace, name string) string {
+	return fmt.Sprintf("%s;%s", namespace, name)
+}
+
+// CreateBlock creates a new block. It creates a chained structure by setting the new block's previous hash
+// value to the hash of the last block of the chain specified. The new block's hash is calculated from the
+// previous block hash and the merkle root of the contained transaction hashes. Bolt allows a single writer at a time, so the last block
+// read and the new block written are always consistent.
+func (b *BoltBlockchain) CreateBlock(id, chainName string, transactions []*types.Transaction) (*types.Block, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if err == nil && chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	if len(transactions) == 0 {
+		return nil, types.ErrZeroTransactions
+	}
+
+	failedTx, validTxs := VerifyTxs(transactions)
+	if !validTxs {
+		return nil, fmt.Errorf("Invalid block transaction; Transaction (%s) has an invalid hash", failedTx.ID)
+	}
+
+	var newBlock *types.Block
+	err = b.update(func(tx *bolt.Tx) error {
+
+		blocks, err := tx.Bucket([]byte(BlockTableName)).CreateBucketIfNotExists([]byte(chainName))
+		if err != nil {
+			return err
+		}
+
+		blockIDs := tx.Bucket([]byte(BlockIDIndexBucketName))
+		if blockIDs.Get([]byte(id)) != nil {
+			return fmt.Errorf("block with matching id already exists")
+		}
+
+		// get last block of chain. If there is no last block, use a dummy genesis block
+		var lastBlock = types.Block{
+			Hash: MakeGenesisBlockHash(chainName),
+		}
+		if _, lastBlockJSON := blocks.Cursor().Last(); lastBlockJSON != nil {
+			if err := util.FromJSON(lastBlockJSON, &lastBlock); err != nil {
+				return fmt.Errorf("failed to get last block of chain `%s`. %s", chainName, err)
+			}
+		}
+
+		txToJSONBytes, _ := util.ToJSON(transactions)
+		merkleRoot := MakeTxsMerkleRoot(transactions)
+		newBlock = &types.Block{
+			ID:            id,
+			Number:        lastBlock.Number + 1,
+			ChainName:     chainName,
+			PrevBlockHash: lastBlock.Hash,
+			Hash:          merkle.MakeBlockHash(lastBlock.Hash, merkleRoot),
+			MerkleRoot:    merkleRoot,
+			Transactions:  txToJSONBytes,
+			CreatedAt:     time.Now().Unix(),
+		}
+
+		if err := signBlock(b.signer, newBlock); err != nil {
+			return err
+		}
+
+		blockJSON, _ := util.ToJSON(newBlock)
+		if err := blocks.Put(itob(uint64(newBlock.Number)), blockJSON); err != nil {
+			return err
+		}
+
+		return blockIDs.Put([]byte(id), append([]byte(chainName+"\x00"), itob(uint64(newBlock.Number))...))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block. %s", err)
+	}
+
+	return newBlock, nil
+}
+
+// ImportBlock appends a block created by another blockchain to a chain without
+// changing its hash, signature or creation time. The block must follow the last
+// block of the chain or, if the chain has no block, its checkpoint.
+func (b *BoltBlockchain) ImportBlock(chainName string, block *types.Block) (*types.Block, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	newBlock := *block
+	newBlock.PK = 0
+	newBlock.ChainName = chainName
+	err = b.update(func(tx *bolt.Tx) error {
+
+		blocks, err := tx.Bucket([]byte(BlockTableName)).CreateBucketIfNotExists([]byte(chainName))
+		if err != nil {
+			return err
+		}
+
+		blockIDs := tx.Bucket([]byte(BlockIDIndexBucketName))
+		if blockIDs.Get([]byte(newBlock.ID)) != nil {
+			return fmt.Errorf("block with matching id already exists")
+		}
+
+		var lastBlock = types.Block{
+			Hash: MakeGenesisBlockHash(chainName),
+		}
+		if _, lastBlockJSON := blocks.Cursor().Last(); lastBlockJSON != nil {
+			if err := util.FromJSON(lastBlockJSON, &lastBlock); err != nil {
+				return fmt.Errorf("failed to get last block of chain `%s`. %s", chainName, err)
+			}
+		} else if checkpointJSON := tx.Bucket([]byte(CheckpointTableName)).Get([]byte(chainName)); checkpointJSON != nil {
+			var checkpoint types.Checkpoint
+			if err := util.FromJSON(checkpointJSON, &checkpoint); err != nil {
+				return fmt.Errorf("failed to get checkpoint. %s", err)
+			}
+			lastBlock = types.Block{Number: checkpoint.Number, Hash: checkpoint.Hash}
+		}
+
+		if err := checkImportedBlock(&lastBlock, &newBlock); err != nil {
+			return err
+		}
+
+		blockJSON, _ := util.ToJSON(newBlock)
+		if err := blocks.Put(itob(uint64(newBlock.Number)), blockJSON); err != nil {
+			return err
+		}
+
+		return blockIDs.Put([]byte(newBlock.ID), append([]byte(chainName+"\x00"), itob(uint64(newBlock.Number))...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &newBlock, nil
+}
+
+// GetBlock fetches a block by its chain name and id
+func (b *BoltBlockchain) GetBlock(chainName, id string) (*types.Block, error) {
+
+	var block *types.Block
+	err := b.view(func(tx *bolt.Tx) error {
+
+		blockIDs := tx.Bucket([]byte(BlockIDIndexBucketName))
+		if blockIDs == nil {
+			return nil
+		}
+
+		// the index value is the chain name and the block number separated by a null byte
+		loc := blockIDs.Get([]byte(id))
+		if loc == nil || len(loc) != len(chainName)+9 || string(loc[:len(chainName)]) != chainName {
+			return nil
+		}
+
+		blocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName))
+		if blocks == nil {
+			return nil
+		}
+
+		blockJSON := blocks.Get(loc[len(chainName)+1:])
+		if blockJSON == nil {
+			return nil
+		}
+
+		block = &types.Block{}
+		return util.FromJSON(blockJSON, block)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block. %s", err)
+	}
+
+	return block, nil
+}
+
+// GetBlockByNumber fetches a block by its chain name and number
+func (b *BoltBlockchain) GetBlockByNumber(chainName string, number uint) (*types.Block, error) {
+
+	var block *types.Block
+	err := b.view(func(tx *bolt.Tx) error {
+
+		blocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName))
+		if blocks == nil {
+			return nil
+		}
+
+		blockJSON := blocks.Get(itob(uint64(number)))
+		if blockJSON == nil {
+			return nil
+		}
+
+		block = &types.Block{}
+		return util.FromJSON(blockJSON, block)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block. %s", err)
+	}
+
+	return block, nil
+}
+
+// GetChainHeight returns the number of the last block of a chain.
+// It returns zero if the chain has no block.
+func (b *BoltBlockchain) GetChainHeight(chainName string) (uint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return 0, err
+	} else if chain == nil {
+		return 0, types.ErrChainNotFound
+	}
+
+	var height uint
+	err = b.view(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName))
+		if blocks == nil {
+			return nil
+		}
+		if k, _ := blocks.Cursor().Last(); k != nil {
+			height = uint(binary.BigEndian.Uint64(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain height. %s", err)
+	}
+
+	return height, nil
+}
+
+// ListBlocks fetches the blocks of a chain in ascending order of
+// block number, starting from the block with number fromNumber.
+func (b *BoltBlockchain) ListBlocks(chainName string, fromNumber uint, limit int) ([]*types.Block, error) {
+
+	var blocks []*types.Block
+	err := b.view(func(tx *bolt.Tx) error {
+
+		chainBlocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName))
+		if chainBlocks == nil {
+			return nil
+		}
+
+		c := chainBlocks.Cursor()
+		for k, v := c.Seek(itob(uint64(fromNumber))); k != nil && len(blocks) < limit; k, v = c.Next() {
+			var block types.Block
+			if err := util.FromJSON(v, &block); err != nil {
+				return err
+			}
+			blocks = append(blocks, &block)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocks. %s", err)
+	}
+
+	return blocks, nil
+}
+
+// Compact removes the blocks of a chain up to and including the block with number
+// toNumber and records the removed block as the checkpoint of the chain. The last
+// block of a chain cannot be removed as new blocks are linked to it.
+func (b *BoltBlockchain) Compact(chainName string, toNumber uint) (*types.Checkpoint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	var checkpoint *types.Checkpoint
+	err = b.update(func(tx *bolt.Tx) error {
+
+		blocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName))
+		if blocks == nil {
+			return types.ErrBlockNotFound
+		}
+
+		if k, _ := blocks.Cursor().Last(); k == nil || uint(binary.BigEndian.Uint64(k)) <= toNumber {
+			return fmt.Errorf("the last block of a chain cannot be compacted")
+		}
+
+		blockJSON := blocks.Get(itob(uint64(toNumber)))
+		if blockJSON == nil {
+			return types.ErrBlockNotFound
+		}
+
+		var block types.Block
+		if err := util.FromJSON(blockJSON, &block); err != nil {
+			return err
+		}
+
+		// collect the keys first as a bucket must not be modified while iterated
+		var removed [][]byte
+		c := blocks.Cursor()
+		for k, v := c.First(); k != nil && binary.BigEndian.Uint64(k) <= uint64(toNumber); k, v = c.Next() {
+			var removedBlock types.Block
+			if err := util.FromJSON(v, &removedBlock); err != nil {
+				return err
+			}
+			if err := tx.Bucket([]byte(BlockIDIndexBucketName)).Delete([]byte(removedBlock.ID)); err != nil {
+				return err
+			}
+			removed = append(removed, k)
+		}
+		for _, k := range removed {
+			if err := blocks.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		checkpoint = &types.Checkpoint{
+			ChainName: chainName,
+			Number:    block.Number,
+			Hash:      block.Hash,
+			Signature: block.Signature,
+			KeyID:     block.KeyID,
+			CreatedAt: time.Now().Unix(),
+		}
+		checkpointJSON, _ := util.ToJSON(checkpoint)
+		return tx.Bucket([]byte(CheckpointTableName)).Put([]byte(chainName), checkpointJSON)
+	})
+	if err == types.ErrBlockNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to compact chain. %s", err)
+	}
+
+	return checkpoint, nil
+}
+
+// ImportCheckpoint records the checkpoint of a chain compacted by another blockchain,
+// so that the blocks that followed the checkpoint can be imported. The chain must
+// have no block and no checkpoint.
+func (b *BoltBlockchain) ImportCheckpoint(chainName string, checkpoint *types.Checkpoint) (*types.Checkpoint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	newCheckpoint := *checkpoint
+	newCheckpoint.PK = 0
+	newCheckpoint.ChainName = chainName
+	err = b.update(func(tx *bolt.Tx) error {
+
+		if blocks := tx.Bucket([]byte(BlockTableName)).Bucket([]byte(chainName)); blocks != nil {
+			if k, _ := blocks.Cursor().First(); k != nil {
+				return fmt.Errorf("chain has blocks")
+			}
+		}
+
+		checkpoints := tx.Bucket([]byte(CheckpointTableName))
+		if checkpoints.Get([]byte(chainName)) != nil {
+			return fmt.Errorf("chain has a checkpoint")
+		}
+
+		checkpointJSON, _ := util.ToJSON(newCheckpoint)
+		return checkpoints.Put([]byte(chainName), checkpointJSON)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import checkpoint. %s", err)
+	}
+
+	return &newCheckpoint, nil
+}
+
+// GetCheckpoint fetches the checkpoint of a compacted chain.
+// It returns nil if the chain has not been compacted.
+func (b *BoltBlockchain) GetCheckpoint(chainName string) (*types.Checkpoint, error) {
+
+	var checkpoint *types.Checkpoint
+	err := b.view(func(tx *bolt.Tx) error {
+		checkpoints := tx.Bucket([]byte(CheckpointTableName))
+		if checkpoints == nil {
+			return nil
+		}
+		checkpointJSON := checkpoints.Get([]byte(chainName))
+		if checkpointJSON == nil {
+			return nil
+		}
+		checkpoint = &types.Checkpoint{}
+		return util.FromJSON(checkpointJSON, checkpoint)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint. %s", err)
+	}
+
+	return checkpoint, nil
+}
+
+// SetChainLeader records the address of the orderer that appends blocks to a chain
+func (b *BoltBlockchain) SetChainLeader(chainName, addr string) error {
+
+	err := b.update(func(tx *bolt.Tx) error {
+		chains := tx.Bucket([]byte(ChainTableName))
+		if chains == nil {
+			return types.ErrChainNotFound
+		}
+		chainJSON := chains.Get([]byte(chainName))
+		if chainJSON == nil {
+			return types.ErrChainNotFound
+		}
+		var chain types.Chain
+		if err := util.FromJSON(chainJSON, &chain); err != nil {
+			return err
+		}
+		chain.Leader = addr
+		chainJSON, _ = util.ToJSON(chain)
+		return chains.Put([]byte(chainName), chainJSON)
+	})
+	if err == types.ErrChainNotFound {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("failed to set chain leader. %s", err)
+	}
+
+	return nil
+}
+
+// CheckChainLeader checks that addr is the leader recorded on a chain. In a bound
+// transaction, the leader cannot be changed by another writer until it ends.
+// It returns ErrNotChainLeader if another leader is recorded.
+func (b *BoltBlockchain) CheckChainLeader(chainName, addr string) error {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return err
+	} else if chain == nil {
+		return types.ErrChainNotFound
+	} else if chain.Leader != addr {
+		return types.ErrNotChainLeader
+	}
+
+	return nil
+}
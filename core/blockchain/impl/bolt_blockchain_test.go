@@ -0,0 +1,322 @@
+package impl
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBoltBlockchain(t *testing.T) {
+
+	dbPath := path.Join(os.TempDir(), "test_blockchain_"+util.RandString(5)+".db")
+	defer os.Remove(dbPath)
+
+	boltChain := new(BoltBlockchain)
+	_, err := boltChain.Connect("bolt://" + dbPath)
+	if err != nil {
+		t.Fatal("failed to connect to bolt blockchain")
+	}
+	defer boltChain.Close()
+
+	Convey("BoltBlockchain", t, func() {
+
+		Convey(".Init", func() {
+			err := boltChain.Init()
+			So(err, ShouldBeNil)
+		})
+
+		Convey(".CreateChain", func() {
+
+			Convey("Should successfully create a chain", func() {
+				chainName := util.RandString(5)
+				chain, err := boltChain.CreateChain(chainName, true)
+				So(err, ShouldBeNil)
+				So(chain.Name, ShouldEqual, chainName)
+				So(chain.Public, ShouldEqual, true)
+
+				Convey("Should fail if chain with same name already exists", func() {
+					_, err := boltChain.CreateChain(chainName, true)
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "chain with matching name already exists")
+				})
+			})
+		})
+
+		Convey(".GetChain", func() {
+
+			Convey("Should return nil if chain does not exist", func() {
+				chain, err := boltChain.GetChain("unknown")
+				So(err, ShouldBeNil)
+				So(chain, ShouldBeNil)
+			})
+
+			Convey("Should successfully get a chain", func() {
+				chainName := util.RandString(5)
+				_, err := boltChain.CreateChain(chainName, true)
+				So(err, ShouldBeNil)
+				chain, err := boltChain.GetChain(chainName)
+				So(err, ShouldBeNil)
+				So(chain, ShouldNotBeNil)
+				So(chain.Name, ShouldEqual, chainName)
+			})
+		})
+
+		Convey(".SetChainLeader", func() {
+
+			Convey("Should record the leader of a chain", func() {
+				chainName := util.RandString(5)
+				_, err := boltChain.CreateChain(chainName, true)
+				So(err, ShouldBeNil)
+				err = boltChain.SetChainLeader(chainName, "127.0.0.1:8001")
+				So(err, ShouldBeNil)
+				chain, err := boltChain.GetChain(chainName)
+				So(err, ShouldBeNil)
+				So(chain.Leader, ShouldEqual, "127.0.0.1:8001")
+			})
+
+			Convey("Should return error if chain does not exist", func() {
+				err := boltChain.SetChainLeader("unknown", "127.0.0.1:8001")
+				So(err, ShouldEqual, types.ErrChainNotFound)
+			})
+		})
+
+		Convey(".CheckChainLeader", func() {
+
+			chainName := util.RandString(5)
+			_, err := boltChain.CreateChain(chainName, true)
+			So(err, ShouldBeNil)
+			err = boltChain.SetChainLeader(chainName, "127.0.0.1:8001")
+			So(err, ShouldBeNil)
+
+			Convey("Should return nil if the address is the recorded leader", func() {
+				So(boltChain.CheckChainLeader(chainName, "127.0.0.1:8001"), ShouldBeNil)
+			})
+
+			Convey("Should return error if the address is not the recorded leader", func() {
+				So(boltChain.CheckChainLeader(chainName, "127.0.0.1:8002"), ShouldEqual, types.ErrNotChainLeader)
+			})
+
+			Convey("Should return error if chain does not exist", func() {
+				So(boltChain.CheckChainLeader("unknown", "127.0.0.1:8001"), ShouldEqual, types.ErrChainNotFound)
+			})
+		})
+
+		Convey(".CreateBlock", func() {
+
+			chainName := util.RandString(5)
+			_, err := boltChain.CreateChain(chainName, true)
+			So(err, ShouldBeNil)
+
+			Convey("Should return error if chain does not exist", func() {
+				_, err := boltChain.CreateBlock(util.RandString(5), "unknown", nil)
+				So(err, ShouldResemble, types.ErrChainNotFound)
+			})
+
+			Convey("Should return error if no transaction is provided", func() {
+				_, err := boltChain.CreateBlock(util.RandString(5), chainName, nil)
+				So(err, ShouldResemble, types.ErrZeroTransactions)
+			})
+
+			Convey("Should successfully create blocks linked to the previous block", func() {
+				tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+				tx.Hash = tx.MakeHash()
+				block, err := boltChain.CreateBlock(util.RandString(5), chainName, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(block.Number, ShouldEqual, 1)
+				So(block.PrevBlockHash, ShouldEqual, MakeGenesisBlockHash(chainName))
+				So(block.MerkleRoot, ShouldEqual, MakeTxsMerkleRoot([]*types.Transaction{tx}))
+				So(block.Hash, ShouldEqual, merkle.MakeBlockHash(block.PrevBlockHash, block.MerkleRoot))
+
+				tx2 := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value2"}
+				tx2.Hash = tx2.MakeHash()
+				block2, err := boltChain.CreateBlock(util.RandString(5), chainName, []*types.Transaction{tx2})
+				So(err, ShouldBeNil)
+				So(block2.Number, ShouldEqual, 2)
+				So(block2.PrevBlockHash, ShouldEqual, block.Hash)
+
+				Convey("Should fail if a block with the same id exists", func() {
+					_, err := boltChain.CreateBlock(block.ID, chainName, []*types.Transaction{tx})
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "failed to create block. block with matching id already exists")
+				})
+			})
+
+			Convey("Should sign the block hash if a signer is set", func() {
+				blockSigner, _ := signer.Generate()
+				boltChain.SetSigner(blockSigner)
+				defer boltChain.SetSigner(nil)
+
+				tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+				tx.Hash = tx.MakeHash()
+				block, err := boltChain.CreateBlock(util.RandString(5), chainName, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(block.KeyID, ShouldEqual, blockSigner.KeyID())
+				So(blockSigner.PublicKey().VerifyBlock(block), ShouldBeNil)
+
+				found, _ := boltChain.GetBlock(chainName, block.ID)
+				So(found.Signature, ShouldEqual, block.Signature)
+			})
+		})
+
+		Convey(".GetBlock", func() {
+
+			Convey("Should return nil if block does not exist", func() {
+				block, err := boltChain.GetBlock("unknown", "unknown")
+				So(err, ShouldBeNil)
+				So(block, ShouldBeNil)
+			})
+
+			Convey("Should successfully get a block", func() {
+				chainName := util.RandString(5)
+				_, err := boltChain.CreateChain(chainName, true)
+				So(err, ShouldBeNil)
+				tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+				tx.Hash = tx.MakeHash()
+				block, err := boltChain.CreateBlock(util.RandString(5), chainName, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				found, err := boltChain.GetBlock(chainName, block.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				So(found.Hash, ShouldEqual, block.Hash)
+
+				found, err = boltChain.GetBlock("other_chain", block.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+			})
+		})
+
+		Convey(".GetBlockByNumber, .GetChainHeight and .ListBlocks", func() {
+
+			chainName, blocks := makeVerifyTestChain(boltChain, 3)
+
+			Convey("Should get a block by its number", func() {
+				found, err := boltChain.GetBlockByNumber(chainName, 2)
+				So(err, ShouldBeNil)
+				So(found.ID, ShouldEqual, blocks[1].ID)
+
+				found, err = boltChain.GetBlockByNumber(chainName, 4)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+			})
+
+			Convey("Should return the number of the last block", func() {
+				height, err := boltChain.GetChainHeight(chainName)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 3)
+
+				emptyChain := util.RandString(5)
+				boltChain.CreateChain(emptyChain, true)
+				height, err = boltChain.GetChainHeight(emptyChain)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 0)
+
+				_, err = boltChain.GetChainHeight("unknown")
+				So(err, ShouldEqual, types.ErrChainNotFound)
+			})
+
+			Convey("Should list blocks from a block number", func() {
+				found, err := boltChain.ListBlocks(chainName, 2, 10)
+				So(err, ShouldBeNil)
+				So(len(found), ShouldEqual, 2)
+				So(found[0].Number, ShouldEqual, 2)
+				So(found[1].Number, ShouldEqual, 3)
+
+				found, err = boltChain.ListBlocks(chainName, 1, 1)
+				So(err, ShouldBeNil)
+				So(len(found), ShouldEqual, 1)
+			})
+		})
+
+		Convey(".Compact", func() {
+
+			chainName, blocks := makeVerifyTestChain(boltChain, 4)
+
+			Convey("Should return nil checkpoint if the chain has not been compacted", func() {
+				checkpoint, err := boltChain.GetCheckpoint(chainName)
+				So(err, ShouldBeNil)
+				So(checkpoint, ShouldBeNil)
+			})
+
+			Convey("Should remove blocks and record the last removed block as the checkpoint", func() {
+				checkpoint, err := boltChain.Compact(chainName, 2)
+				So(err, ShouldBeNil)
+				So(checkpoint.Number, ShouldEqual, 2)
+				So(checkpoint.Hash, ShouldEqual, blocks[1].Hash)
+
+				found, err := boltChain.GetCheckpoint(chainName)
+				So(err, ShouldBeNil)
+				So(found.Hash, ShouldEqual, blocks[1].Hash)
+
+				remaining, err := boltChain.ListBlocks(chainName, 1, 10)
+				So(err, ShouldBeNil)
+				So(len(remaining), ShouldEqual, 2)
+				So(remaining[0].Number, ShouldEqual, 3)
+
+				removed, err := boltChain.GetBlock(chainName, blocks[0].ID)
+				So(err, ShouldBeNil)
+				So(removed, ShouldBeNil)
+
+				height, err := boltChain.GetChainHeight(chainName)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 4)
+
+				Convey("New blocks should follow the last block", func() {
+					tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+					tx.Hash = tx.MakeHash()
+					block, err := boltChain.CreateBlock(util.RandString(10), chainName, []*types.Transaction{tx})
+					So(err, ShouldBeNil)
+					So(block.Number, ShouldEqual, 5)
+					So(block.PrevBlockHash, ShouldEqual, blocks[3].Hash)
+				})
+
+				Convey("The checkpoint and remaining blocks should be importable by another chain", func() {
+					otherChain := util.RandString(10)
+					_, err := boltChain.CreateChain(otherChain, true)
+					So(err, ShouldBeNil)
+
+					_, err = boltChain.ImportBlock(otherChain, remaining[0])
+					So(err, ShouldNotBeNil)
+
+					imported, err := boltChain.ImportCheckpoint(otherChain, checkpoint)
+					So(err, ShouldBeNil)
+					So(imported.ChainName, ShouldEqual, otherChain)
+					So(imported.Hash, ShouldEqual, checkpoint.Hash)
+
+					_, err = boltChain.ImportCheckpoint(otherChain, checkpoint)
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "failed to import checkpoint. chain has a checkpoint")
+
+					remaining[0].ID = util.RandString(10)
+					block, err := boltChain.ImportBlock(otherChain, remaining[0])
+					So(err, ShouldBeNil)
+					So(block.Number, ShouldEqual, 3)
+				})
+			})
+
+			Convey("Should not remove the last block of a chain", func() {
+				_, err := boltChain.Compact(chainName, 4)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "the last block of a chain cannot be compacted")
+			})
+
+			Convey("Should return error if the block does not exist", func() {
+				_, err := boltChain.Compact(chainName, 2)
+				So(err, ShouldBeNil)
+				_, err = boltChain.Compact(chainName, 1)
+				So(err, ShouldEqual, types.ErrBlockNotFound)
+			})
+
+			Convey("Should return error if chain does not exist", func() {
+				_, err := boltChain.Compact("unknown", 1)
+				So(err, ShouldEqual, types.ErrChainNotFound)
+			})
+		})
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/ellcrys/util"
 	"github.com/jinzhu/gorm"
 	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
+	"github.com/ellcrys/cocoon/core/store/envelope"
 	"github.com/ellcrys/cocoon/core/types"
 )
 
@@ -17,9 +19,19 @@ var ChainTableName = "chains"
 // BlockTableName is the name of the transaction block table
 var BlockTableName = "blocks"
 
+// CheckpointTableName is the name of the table holding the checkpoints of compacted chains
+var CheckpointTableName = "checkpoints"
+
 // PostgresBlockchain implements the Blockchain interface
 type PostgresBlockchain struct {
-	db *gorm.DB
+	db     *gorm.DB
+	signer types.BlockSigner
+}
+
+// SetSigner sets the signer that signs the hash of new blocks.
+// New blocks are not signed if a signer is not set.
+func (b *PostgresBlockchain) SetSigner(signer types.BlockSigner) {
+	b.signer = signer
 }
 
 // GetImplementationName returns th
@@ -50,35 +62,35 @@ func (b *PostgresBlockchain) Close() error {
 	return nil
 }
 
-// Init creates the chain and block tables
-func (b *PostgresBlockchain) Init() error {
-
-	// create ledger table if not existing
-	if !b.db.HasTable(ChainTableName) {
-		if err := b.db.CreateTable(&types.Chain{}).Error; err != nil {
-			return fmt.Errorf("failed to create blockchain `%s` table. %s", ChainTableName, err)
-		}
+// WithDBTx returns a copy of the blockchain whose operations are made
+// in dbTx, a transaction of the database of the blockchain
+func (b *PostgresBlockchain) WithDBTx(dbTx interface{}) (types.Blockchain, error) {
+	db, ok := dbTx.(*gorm.DB)
+	if !ok || !common.IsPgTx(db) {
+		return nil, fmt.Errorf("database transaction must be a postgres transaction")
 	}
+	return &PostgresBlockchain{db: db, signer: b.signer}, nil
+}
 
-	// create block table if not existing
-	if !b.db.HasTable(BlockTableName) {
-		if err := b.db.CreateTable(&types.Block{}).
-			AddIndex("idx_name_chain_name_id", "chain_name", "id").Error; err != nil {
-			return fmt.Errorf("failed to create `%s` table. %s", BlockTableName, err)
-		}
-	}
+// Init creates the chain and block tables. The tables of an existing database
+// are not changed; Init fails if the database has pending migrations.
+func (b *PostgresBlockchain) Init() error {
 
-	return nil
+	// create the tables of a new database or ensure
+	// the schema of an existing database is up to date
+	return b.Migrator().Ensure(!b.db.HasTable(ChainTableName))
 }
 
 // CreateChain creates a new chain
 func (b *PostgresBlockchain) CreateChain(name string, public bool) (*types.Chain, error) {
 
-	tx := b.db.Begin()
+	tx := common.BeginPgTx(b.db)
 
-	err := tx.Exec(`SET TRANSACTION isolation level repeatable read`).Error
-	if err != nil {
-		return nil, fmt.Errorf("failed to set transaction isolation level. %s", err)
+	if !tx.IsSavepoint() {
+		err := tx.Exec(`SET TRANSACTION isolation level repeatable read`).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to set transaction isolation level. %s", err)
+		}
 	}
 
 	newChain := &types.Chain{
@@ -128,10 +140,24 @@ func MakeTxsHash(txs []*types.Transaction) string {
 	return util.Sha256(strings.Join(txHashes, ";"))
 }
 
+// MakeTxsMerkleRoot computes the merkle root of the hashes of the transactions
+func MakeTxsMerkleRoot(txs []*types.Transaction) string {
+	var txHashes = make([]string, len(txs))
+	for i, tx := range txs {
+		txHashes[i] = tx.Hash
+	}
+	return merkle.Root(txHashes)
+}
+
 // VerifyTxs checks whether the hash of a transactions are valid hashes
-// based on the hash algorithm defined by Transaction.MakeHash.
+// based on the hash algorithm defined by Transaction.MakeHash. Encrypted
+// values are hashed before they are encrypted, so the hashes of their
+// transactions can only be checked by the holder of the data key.
 func VerifyTxs(txs []*types.Transaction) (*types.Transaction, bool) {
 	for _, tx := range txs {
+		if envelope.IsEncrypted(tx.Value) {
+			continue
+		}
 		if tx.Hash != tx.MakeHash() {
 			return tx, false
 		}
@@ -145,9 +171,43 @@ func MakeGenesisBlockHash(chainName string) string {
 	return util.Sha256(fmt.Sprintf("%s;%s", chainName, strings.Repeat("0", 64)))
 }
 
+// signBlock signs the hash of a block if a signer is set
+func signBlock(signer types.BlockSigner, block *types.Block) error {
+	if signer == nil {
+		return nil
+	}
+	signature, err := signer.Sign(block.Hash)
+	if err != nil {
+		return fmt.Errorf("failed to sign block. %s", err)
+	}
+	block.Signature = signature
+	block.KeyID = signer.KeyID()
+	return nil
+}
+
+// checkImportedBlock checks that an imported block follows the last block
+// of its chain and that its hash matches its transactions
+func checkImportedBlock(lastBlock, block *types.Block) error {
+
+	if block.Number != lastBlock.Number+1 {
+		return fmt.Errorf("failed to import block. expected block number %d", lastBlock.Number+1)
+	} else if block.PrevBlockHash != lastBlock.Hash {
+		return fmt.Errorf("failed to import block. previous block hash does not match the hash of block %d", lastBlock.Number)
+	}
+
+	txs, err := block.GetTransactions()
+	if err != nil {
+		return fmt.Errorf("failed to import block. %s", err)
+	} else if !VerifyBlockHash(block, txs) {
+		return fmt.Errorf("failed to import block. block hash does not match its transactions")
+	}
+
+	return nil
+}
+
 // CreateBlock creates a new block. It creates a chained structure by setting the new block's previous hash
-// value to the hash of the last block of the chain specified. The new block's hash is calculated from the hash of
-// all the contained transaction hashes.
+// value to the hash of the last block of the chain specified. The new block's hash is calculated from the
+// previous block hash and the merkle root of the contained transaction hashes.
 func (b *PostgresBlockchain) CreateBlock(id, chainName string, transactions []*types.Transaction) (*types.Block, error) {
 
 	chain, err := b.GetChain(chainName)
@@ -166,10 +226,12 @@ func (b *PostgresBlockchain) CreateBlock(id, chainName string, transactions []*t
 		return nil, fmt.Errorf("Invalid block transaction; Transaction (%s) has an invalid hash", failedTx.ID)
 	}
 
-	dbTx := b.db.Begin()
-	err = dbTx.Exec(`SET TRANSACTION isolation level repeatable read`).Error
-	if err != nil {
-		return nil, fmt.Errorf("failed to set transaction isolation level. %s", err)
+	dbTx := common.BeginPgTx(b.db)
+	if !dbTx.IsSavepoint() {
+		err = dbTx.Exec(`SET TRANSACTION isolation level repeatable read`).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to set transaction isolation level. %s", err)
+		}
 	}
 	var dummyBlock = types.Block{
 		Hash: MakeGenesisBlockHash(chainName),
@@ -185,22 +247,24 @@ func (b *PostgresBlockchain) CreateBlock(id, chainName string, transactions []*t
 		lastBlock = dummyBlock
 	}
 
-	var curBlockCount uint
-	if err = dbTx.Model(&types.Block{}).Where("chain_name = ?", chainName).Count(&curBlockCount).Error; err != nil {
-		return nil, fmt.Errorf("failed to get chain `%s` block count. %s", chainName, err)
-	}
-
 	txToJSONBytes, _ := util.ToJSON(transactions)
+	merkleRoot := MakeTxsMerkleRoot(transactions)
 	newBlock := &types.Block{
 		ID:            id,
-		Number:        curBlockCount + 1,
+		Number:        lastBlock.Number + 1,
 		ChainName:     chainName,
 		PrevBlockHash: lastBlock.Hash,
-		Hash:          MakeTxsHash(transactions),
+		Hash:          merkle.MakeBlockHash(lastBlock.Hash, merkleRoot),
+		MerkleRoot:    merkleRoot,
 		Transactions:  txToJSONBytes,
 		CreatedAt:     time.Now().Unix(),
 	}
 
+	if err = signBlock(b.signer, newBlock); err != nil {
+		dbTx.Rollback()
+		return nil, err
+	}
+
 	if err = dbTx.Create(&newBlock).Error; err != nil {
 		dbTx.Rollback()
 		return nil, fmt.Errorf("failed to create block. %s", err)
@@ -210,6 +274,56 @@ func (b *PostgresBlockchain) CreateBlock(id, chainName string, transactions []*t
 	return newBlock, nil
 }
 
+// ImportBlock appends a block created by another blockchain to a chain without
+// changing its hash, signature or creation time. The block must follow the last
+// block of the chain or, if the chain has no block, its checkpoint.
+func (b *PostgresBlockchain) ImportBlock(chainName string, block *types.Block) (*types.Block, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	dbTx := common.BeginPgTx(b.db)
+
+	var lastBlock = types.Block{Hash: MakeGenesisBlockHash(chainName)}
+	err = dbTx.Where("chain_name = ?", chainName).Last(&lastBlock).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to get last block of chain `%s`. %s", chainName, err)
+	} else if err == gorm.ErrRecordNotFound {
+		var checkpoint types.Checkpoint
+		err = dbTx.Where("chain_name = ?", chainName).First(&checkpoint).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			dbTx.Rollback()
+			return nil, fmt.Errorf("failed to get checkpoint. %s", err)
+		} else if err == nil {
+			lastBlock = types.Block{Number: checkpoint.Number, Hash: checkpoint.Hash}
+		}
+	}
+
+	if err := checkImportedBlock(&lastBlock, block); err != nil {
+		dbTx.Rollback()
+		return nil, err
+	}
+
+	newBlock := *block
+	newBlock.PK = 0
+	newBlock.ChainName = chainName
+	if err = dbTx.Create(&newBlock).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import block. %s", err)
+	}
+
+	if err = dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import block. %s", err)
+	}
+
+	return &newBlock, nil
+}
+
 // GetBlock fetches a block by its chain name and id
 func (b *PostgresBlockchain) GetBlock(chainName, id string) (*types.Block, error) {
 
@@ -223,3 +337,199 @@ func (b *PostgresBlockchain) GetBlock(chainName, id string) (*types.Block, error
 
 	return &block, nil
 }
+
+// GetBlockByNumber fetches a block by its chain name and number
+func (b *PostgresBlockchain) GetBlockByNumber(chainName string, number uint) (*types.Block, error) {
+
+	var block types.Block
+	err := b.db.Where("chain_name = ? AND number = ?", chainName, number).First(&block).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get block. %s", err)
+	} else if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+
+	return &block, nil
+}
+
+// GetChainHeight returns the number of the last block of a chain.
+// It returns zero if the chain has no block.
+func (b *PostgresBlockchain) GetChainHeight(chainName string) (uint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return 0, err
+	} else if chain == nil {
+		return 0, types.ErrChainNotFound
+	}
+
+	var height uint
+	err = b.db.Model(&types.Block{}).Where("chain_name = ?", chainName).
+		Select("COALESCE(MAX(number), 0)").Row().Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain height. %s", err)
+	}
+
+	return height, nil
+}
+
+// ListBlocks fetches the blocks of a chain in ascending order of
+// block number, starting from the block with number fromNumber.
+func (b *PostgresBlockchain) ListBlocks(chainName string, fromNumber uint, limit int) ([]*types.Block, error) {
+
+	var blocks []*types.Block
+	err := b.db.Where("chain_name = ? AND number >= ?", chainName, fromNumber).
+		Order("number asc, pk asc").
+		Limit(limit).
+		Find(&blocks).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get blocks. %s", err)
+	}
+
+	return blocks, nil
+}
+
+// Compact removes the blocks of a chain up to and including the block with number
+// toNumber and records the removed block as the checkpoint of the chain. The last
+// block of a chain cannot be removed as new blocks are linked to it.
+func (b *PostgresBlockchain) Compact(chainName string, toNumber uint) (*types.Checkpoint, error) {
+
+	height, err := b.GetChainHeight(chainName)
+	if err != nil {
+		return nil, err
+	} else if height <= toNumber {
+		return nil, fmt.Errorf("failed to compact chain. the last block of a chain cannot be compacted")
+	}
+
+	block, err := b.GetBlockByNumber(chainName, toNumber)
+	if err != nil {
+		return nil, err
+	} else if block == nil {
+		return nil, types.ErrBlockNotFound
+	}
+
+	checkpoint := &types.Checkpoint{
+		ChainName: chainName,
+		Number:    block.Number,
+		Hash:      block.Hash,
+		Signature: block.Signature,
+		KeyID:     block.KeyID,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	dbTx := common.BeginPgTx(b.db)
+
+	if err = dbTx.Where("chain_name = ? AND number <= ?", chainName, toNumber).Delete(&types.Block{}).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to compact chain. %s", err)
+	}
+
+	if err = dbTx.Where("chain_name = ?", chainName).Delete(&types.Checkpoint{}).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to compact chain. %s", err)
+	}
+
+	if err = dbTx.Create(checkpoint).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to compact chain. %s", err)
+	}
+
+	if err = dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to compact chain. %s", err)
+	}
+
+	return checkpoint, nil
+}
+
+// ImportCheckpoint records the checkpoint of a chain compacted by another blockchain,
+// so that the blocks that followed the checkpoint can be imported. The chain must
+// have no block and no checkpoint.
+func (b *PostgresBlockchain) ImportCheckpoint(chainName string, checkpoint *types.Checkpoint) (*types.Checkpoint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return nil, err
+	} else if chain == nil {
+		return nil, types.ErrChainNotFound
+	}
+
+	dbTx := common.BeginPgTx(b.db)
+
+	var count int
+	if err = dbTx.Model(&types.Block{}).Where("chain_name = ?", chainName).Count(&count).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import checkpoint. %s", err)
+	} else if count > 0 {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import checkpoint. chain has blocks")
+	}
+
+	if err = dbTx.Model(&types.Checkpoint{}).Where("chain_name = ?", chainName).Count(&count).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import checkpoint. %s", err)
+	} else if count > 0 {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import checkpoint. chain has a checkpoint")
+	}
+
+	newCheckpoint := *checkpoint
+	newCheckpoint.PK = 0
+	newCheckpoint.ChainName = chainName
+	if err = dbTx.Create(&newCheckpoint).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to import checkpoint. %s", err)
+	}
+
+	if err = dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to import checkpoint. %s", err)
+	}
+
+	return &newCheckpoint, nil
+}
+
+// GetCheckpoint fetches the checkpoint of a compacted chain.
+// It returns nil if the chain has not been compacted.
+func (b *PostgresBlockchain) GetCheckpoint(chainName string) (*types.Checkpoint, error) {
+
+	var checkpoint types.Checkpoint
+	err := b.db.Where("chain_name = ?", chainName).First(&checkpoint).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get checkpoint. %s", err)
+	} else if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+
+	return &checkpoint, nil
+}
+
+// SetChainLeader records the address of the orderer that appends blocks to a chain
+func (b *PostgresBlockchain) SetChainLeader(chainName, addr string) error {
+
+	res := b.db.Model(&types.Chain{}).Where("name = ?", chainName).Update("leader", addr)
+	if res.Error != nil {
+		return fmt.Errorf("failed to set chain leader. %s", res.Error)
+	} else if res.RowsAffected == 0 {
+		return types.ErrChainNotFound
+	}
+
+	return nil
+}
+
+// CheckChainLeader checks that addr is the leader recorded on a chain. The chain is
+// locked until the end of the database transaction of the blockchain, so that the
+// leader cannot be changed before the writes of the transaction are committed.
+// It returns ErrNotChainLeader if another leader is recorded.
+func (b *PostgresBlockchain) CheckChainLeader(chainName, addr string) error {
+
+	var chain types.Chain
+	err := b.db.Raw(fmt.Sprintf(`SELECT * FROM "%s" WHERE name = ? FOR UPDATE`, ChainTableName), chainName).Scan(&chain).Error
+	if err == gorm.ErrRecordNotFound {
+		return types.ErrChainNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to check chain leader. %s", err)
+	} else if chain.Leader != addr {
+		return types.ErrNotChainLeader
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ellcrys/util"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres" // gorm requires it
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
 	"github.com/ellcrys/cocoon/core/types"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -101,6 +102,40 @@ func TestPosgresBlockchain(t *testing.T) {
 						So(err, ShouldBeNil)
 					})
 				})
+
+				Convey(".SetChainLeader", func() {
+
+					Convey("Should record the leader of a chain", func() {
+						err := pgChain.SetChainLeader(chainName, "127.0.0.1:8001")
+						So(err, ShouldBeNil)
+						chain, err := pgChain.GetChain(chainName)
+						So(err, ShouldBeNil)
+						So(chain.Leader, ShouldEqual, "127.0.0.1:8001")
+					})
+
+					Convey("Should return error if chain does not exist", func() {
+						err := pgChain.SetChainLeader("chain2", "127.0.0.1:8001")
+						So(err, ShouldEqual, types.ErrChainNotFound)
+					})
+				})
+
+				Convey(".CheckChainLeader", func() {
+
+					err := pgChain.SetChainLeader(chainName, "127.0.0.1:8001")
+					So(err, ShouldBeNil)
+
+					Convey("Should return nil if the address is the recorded leader", func() {
+						So(pgChain.CheckChainLeader(chainName, "127.0.0.1:8001"), ShouldBeNil)
+					})
+
+					Convey("Should return error if the address is not the recorded leader", func() {
+						So(pgChain.CheckChainLeader(chainName, "127.0.0.1:8002"), ShouldEqual, types.ErrNotChainLeader)
+					})
+
+					Convey("Should return error if chain does not exist", func() {
+						So(pgChain.CheckChainLeader("chain2", "127.0.0.1:8001"), ShouldEqual, types.ErrChainNotFound)
+					})
+				})
 			})
 		})
 
@@ -197,7 +232,8 @@ func TestPosgresBlockchain(t *testing.T) {
 				So(blk.ChainName, ShouldEqual, chainName)
 				So(blk.Number, ShouldEqual, 1)
 				So(blk.PrevBlockHash, ShouldEqual, MakeGenesisBlockHash(chainName))
-				So(blk.Hash, ShouldEqual, MakeTxsHash(txs))
+				So(blk.MerkleRoot, ShouldEqual, MakeTxsMerkleRoot(txs))
+				So(blk.Hash, ShouldEqual, merkle.MakeBlockHash(blk.PrevBlockHash, blk.MerkleRoot))
 				txsBytes, _ := util.ToJSON(txs)
 				So(blk.Transactions, ShouldResemble, txsBytes)
 
@@ -212,7 +248,8 @@ func TestPosgresBlockchain(t *testing.T) {
 					So(blk2.ChainName, ShouldEqual, chainName)
 					So(blk2.Number, ShouldEqual, 2)
 					So(blk2.PrevBlockHash, ShouldEqual, blk.Hash)
-					So(blk2.Hash, ShouldEqual, MakeTxsHash(txs))
+					So(blk2.MerkleRoot, ShouldEqual, MakeTxsMerkleRoot(txs))
+					So(blk2.Hash, ShouldEqual, merkle.MakeBlockHash(blk.Hash, blk2.MerkleRoot))
 					txsBytes, _ := util.ToJSON(txs)
 					So(blk2.Transactions, ShouldResemble, txsBytes)
 				})
@@ -249,5 +286,86 @@ func TestPosgresBlockchain(t *testing.T) {
 				So(block.Hash, ShouldEqual, blk.Hash)
 			})
 		})
+
+		Convey(".GetBlockByNumber and .GetChainHeight", func() {
+
+			chainName := util.RandString(5)
+			chain, err := pgChain.CreateChain(chainName, true)
+			So(err, ShouldBeNil)
+
+			height, err := pgChain.GetChainHeight(chain.Name)
+			So(err, ShouldBeNil)
+			So(height, ShouldEqual, 0)
+
+			tx1 := &types.Transaction{Number: 1, Ledger: "ledger1", ID: util.UUID4(), Key: "key", Value: "value", CreatedAt: 123456789}
+			tx1.Hash = tx1.MakeHash()
+			blk, err := pgChain.CreateBlock(util.RandString(10), chain.Name, []*types.Transaction{tx1})
+			So(err, ShouldBeNil)
+
+			Convey("Should return a block by its number", func() {
+				block, err := pgChain.GetBlockByNumber(chain.Name, 1)
+				So(err, ShouldBeNil)
+				So(block.ID, ShouldEqual, blk.ID)
+
+				block, err = pgChain.GetBlockByNumber(chain.Name, 2)
+				So(err, ShouldBeNil)
+				So(block, ShouldBeNil)
+			})
+
+			Convey("Should return the number of the last block", func() {
+				height, err := pgChain.GetChainHeight(chain.Name)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 1)
+
+				_, err = pgChain.GetChainHeight("unknown")
+				So(err, ShouldEqual, types.ErrChainNotFound)
+			})
+		})
+
+		Convey(".Compact", func() {
+
+			chainName := util.RandString(5)
+			chain, err := pgChain.CreateChain(chainName, true)
+			So(err, ShouldBeNil)
+
+			var blocks []*types.Block
+			for i := 0; i < 3; i++ {
+				tx := &types.Transaction{Ledger: "ledger1", ID: util.UUID4(), Key: "key", Value: util.RandString(5), CreatedAt: 123456789}
+				tx.Hash = tx.MakeHash()
+				blk, err := pgChain.CreateBlock(util.RandString(10), chain.Name, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				blocks = append(blocks, blk)
+			}
+
+			Convey("Should remove blocks and record the last removed block as the checkpoint", func() {
+				checkpoint, err := pgChain.Compact(chain.Name, 2)
+				So(err, ShouldBeNil)
+				So(checkpoint.Hash, ShouldEqual, blocks[1].Hash)
+
+				found, err := pgChain.GetCheckpoint(chain.Name)
+				So(err, ShouldBeNil)
+				So(found.Number, ShouldEqual, 2)
+
+				remaining, err := pgChain.ListBlocks(chain.Name, 1, 10)
+				So(err, ShouldBeNil)
+				So(len(remaining), ShouldEqual, 1)
+				So(remaining[0].Number, ShouldEqual, 3)
+
+				Convey("New blocks should follow the last block", func() {
+					tx := &types.Transaction{Ledger: "ledger1", ID: util.UUID4(), Key: "key", Value: "value", CreatedAt: 123456789}
+					tx.Hash = tx.MakeHash()
+					blk, err := pgChain.CreateBlock(util.RandString(10), chain.Name, []*types.Transaction{tx})
+					So(err, ShouldBeNil)
+					So(blk.Number, ShouldEqual, 4)
+					So(blk.PrevBlockHash, ShouldEqual, blocks[2].Hash)
+				})
+			})
+
+			Convey("Should not remove the last block of a chain", func() {
+				_, err := pgChain.Compact(chain.Name, 3)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "the last block of a chain cannot be compacted")
+			})
+		})
 	})
 }
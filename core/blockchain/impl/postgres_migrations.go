@@ -0,0 +1,82 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/cocoon/core/store/migration"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/jinzhu/gorm"
+)
+
+// blockchainSchemaComponent is the name under which the migrations of the blockchain are recorded
+const blockchainSchemaComponent = "blockchain"
+
+// blockchainMigrations are the schema migrations of the postgres blockchain.
+// New migrations must be appended with the next version number.
+var blockchainMigrations = []*migration.Migration{
+	{
+		Version:     1,
+		Description: "create chains and blocks tables",
+		Up: func(tx *gorm.DB) error {
+			if has, err := migration.HasTable(tx, ChainTableName); err != nil {
+				return err
+			} else if !has {
+				if err := tx.CreateTable(&types.Chain{}).Error; err != nil {
+					return fmt.Errorf("failed to create blockchain `%s` table. %s", ChainTableName, err)
+				}
+			}
+			if has, err := migration.HasTable(tx, BlockTableName); err != nil {
+				return err
+			} else if !has {
+				if err := tx.CreateTable(&types.Block{}).
+					AddIndex("idx_name_chain_name_id", "chain_name", "id").Error; err != nil {
+					return fmt.Errorf("failed to create `%s` table. %s", BlockTableName, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "add merkle_root column to blocks",
+		Up: func(tx *gorm.DB) error {
+			return migration.AddColumn(tx, BlockTableName, "merkle_root", "varchar(64)")
+		},
+	},
+	{
+		Version:     3,
+		Description: "add signature and key_id columns to blocks",
+		Up: func(tx *gorm.DB) error {
+			if err := migration.AddColumn(tx, BlockTableName, "signature", "varchar(128)"); err != nil {
+				return err
+			}
+			return migration.AddColumn(tx, BlockTableName, "key_id", "varchar(64)")
+		},
+	},
+	{
+		Version:     4,
+		Description: "create checkpoints table",
+		Up: func(tx *gorm.DB) error {
+			if has, err := migration.HasTable(tx, CheckpointTableName); err != nil {
+				return err
+			} else if !has {
+				if err := tx.CreateTable(&types.Checkpoint{}).Error; err != nil {
+					return fmt.Errorf("failed to create `%s` table. %s", CheckpointTableName, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     5,
+		Description: "add leader column to chains",
+		Up: func(tx *gorm.DB) error {
+			return migration.AddColumn(tx, ChainTableName, "leader", "varchar(255)")
+		},
+	},
+}
+
+// Migrator returns the migrator of the schema of the blockchain
+func (b *PostgresBlockchain) Migrator() *migration.Migrator {
+	return migration.New(b.db, blockchainSchemaComponent, blockchainMigrations)
+}
@@ -0,0 +1,259 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
+	"github.com/ellcrys/cocoon/core/types"
+)
+
+// Chain verification issue types
+const (
+	// IssueBrokenLink indicates a block whose previous block hash does not match the hash of the previous block
+	IssueBrokenLink = "broken_link"
+
+	// IssueBadBlockHash indicates a block whose hash does not match its transactions
+	IssueBadBlockHash = "bad_block_hash"
+
+	// IssueTamperedTx indicates a transaction whose hash does not match its content
+	// or does not match the copy held by the store
+	IssueTamperedTx = "tampered_transaction"
+
+	// IssueBadSignature indicates a block whose signature is not a valid signature
+	// of its hash or was created by a key other than the verifying key
+	IssueBadSignature = "bad_signature"
+
+	// IssueUnsignedBlock indicates an unsigned block that must be signed
+	IssueUnsignedBlock = "unsigned_block"
+
+	// IssueNumberGap indicates a block whose number does not follow the number of the previous block
+	IssueNumberGap = "number_gap"
+
+	// IssueBadCheckpoint indicates a checkpoint that is not signed, whose signature is not a
+	// valid signature of its hash or was created by a key other than the verifying key
+	IssueBadCheckpoint = "bad_checkpoint"
+
+	// ChainReportSummary is the type of the entry that concludes a chain verification report
+	ChainReportSummary = "summary"
+)
+
+// verifyChainPageSize is the number of blocks fetched per read
+var verifyChainPageSize = 100
+
+// ChainIssue describes a problem found while verifying a chain
+type ChainIssue struct {
+	Type        string `json:"type"`
+	BlockID     string `json:"blockId,omitempty"`
+	BlockNumber uint   `json:"blockNumber,omitempty"`
+	TxID        string `json:"txId,omitempty"`
+	Message     string `json:"message"`
+}
+
+// TxLookupFunc fetches the stored copy of a transaction by its id.
+// It returns nil if the transaction does not exist.
+type TxLookupFunc func(id string) (*types.Transaction, error)
+
+// VerifyBlockHash checks that the hash of a block is derived from
+// its transactions. Blocks created before merkle roots were introduced
+// are checked against the flat hash of their transactions.
+func VerifyBlockHash(block *types.Block, txs []*types.Transaction) bool {
+	if len(block.MerkleRoot) == 0 {
+		return block.Hash == MakeTxsHash(txs)
+	}
+	if MakeTxsMerkleRoot(txs) != block.MerkleRoot {
+		return false
+	}
+	return block.Hash == merkle.MakeBlockHash(block.PrevBlockHash, block.MerkleRoot)
+}
+
+// verifySignature checks the signature of a block. Unsigned blocks are only
+// accepted if their number is lower than signedFrom, the number of the first
+// block that must be signed. It returns a description of the problem found, if any.
+func verifySignature(block *types.Block, publicKey *signer.PublicKey, signedFrom uint) (string, string) {
+	if len(block.Signature) == 0 {
+		if block.Number >= signedFrom {
+			return IssueUnsignedBlock, fmt.Sprintf("block is not signed but blocks from block %d must be signed", signedFrom)
+		}
+		return "", ""
+	}
+	if err := publicKey.VerifyBlock(block); err != nil {
+		return IssueBadSignature, err.Error()
+	}
+	return "", ""
+}
+
+// VerifyChain walks the blocks of a chain in order and reports the first broken link, blocks
+// with invalid hashes, tampered transactions and gaps in block numbers through the report function.
+// The blocks of a compacted chain are verified starting from the checkpoint of the chain.
+// If lookupTx is set, every transaction is also compared with the copy held by the store. If
+// publicKey is set, block signatures are checked against it and every block must be signed,
+// except the blocks numbered below signedFrom if it is set; use it for chains whose first
+// blocks were created before blocks were signed. Verification stops if report returns an
+// error. It returns the number of blocks checked.
+func VerifyChain(b types.Blockchain, chainName string, lookupTx TxLookupFunc, publicKey *signer.PublicKey, signedFrom uint, report func(*ChainIssue) error) (uint, error) {
+
+	chain, err := b.GetChain(chainName)
+	if err != nil {
+		return 0, err
+	} else if chain == nil {
+		return 0, types.ErrChainNotFound
+	}
+
+	var checked uint
+	var brokenLinkFound bool
+	if signedFrom == 0 {
+		signedFrom = 1
+	}
+	var prevBlock = &types.Block{Hash: MakeGenesisBlockHash(chainName)}
+	var from uint = 1
+
+	// the blocks of a compacted chain are verified from its checkpoint
+	checkpoint, err := b.GetCheckpoint(chainName)
+	if err != nil {
+		return 0, err
+	} else if checkpoint != nil {
+		prevBlock = &types.Block{
+			Number:    checkpoint.Number,
+			Hash:      checkpoint.Hash,
+			Signature: checkpoint.Signature,
+			KeyID:     checkpoint.KeyID,
+		}
+		from = checkpoint.Number + 1
+		if publicKey != nil {
+			if _, msg := verifySignature(prevBlock, publicKey, signedFrom); len(msg) > 0 {
+				err = report(&ChainIssue{
+					Type:        IssueBadCheckpoint,
+					BlockNumber: checkpoint.Number,
+					Message:     msg,
+				})
+				if err != nil {
+					return checked, err
+				}
+			}
+		}
+	}
+
+	for {
+
+		blocks, err := b.ListBlocks(chainName, from, verifyChainPageSize)
+		if err != nil {
+			return checked, err
+		}
+
+		for _, block := range blocks {
+
+			checked++
+
+			if block.Number != prevBlock.Number+1 {
+				err = report(&ChainIssue{
+					Type:        IssueNumberGap,
+					BlockID:     block.ID,
+					BlockNumber: block.Number,
+					Message:     fmt.Sprintf("expected block number %d, found %d", prevBlock.Number+1, block.Number),
+				})
+				if err != nil {
+					return checked, err
+				}
+			}
+
+			if !brokenLinkFound && block.PrevBlockHash != prevBlock.Hash {
+				brokenLinkFound = true
+				err = report(&ChainIssue{
+					Type:        IssueBrokenLink,
+					BlockID:     block.ID,
+					BlockNumber: block.Number,
+					Message:     fmt.Sprintf("previous block hash does not match the hash of block %d", prevBlock.Number),
+				})
+				if err != nil {
+					return checked, err
+				}
+			}
+
+			txs, err := block.GetTransactions()
+			if err != nil {
+				return checked, fmt.Errorf("block %d: %s", block.Number, err)
+			}
+
+			if !VerifyBlockHash(block, txs) {
+				err = report(&ChainIssue{
+					Type:        IssueBadBlockHash,
+					BlockID:     block.ID,
+					BlockNumber: block.Number,
+					Message:     "block hash does not match its transactions",
+				})
+				if err != nil {
+					return checked, err
+				}
+			}
+
+			if publicKey != nil {
+				if issueType, msg := verifySignature(block, publicKey, signedFrom); len(issueType) > 0 {
+					err = report(&ChainIssue{
+						Type:        issueType,
+						BlockID:     block.ID,
+						BlockNumber: block.Number,
+						Message:     msg,
+					})
+					if err != nil {
+						return checked, err
+					}
+				}
+			}
+
+			for _, tx := range txs {
+				msg, err := verifyTx(tx, lookupTx)
+				if err != nil {
+					return checked, err
+				} else if len(msg) == 0 {
+					continue
+				}
+				err = report(&ChainIssue{
+					Type:        IssueTamperedTx,
+					BlockID:     block.ID,
+					BlockNumber: block.Number,
+					TxID:        tx.ID,
+					Message:     msg,
+				})
+				if err != nil {
+					return checked, err
+				}
+			}
+
+			prevBlock = block
+		}
+
+		if len(blocks) < verifyChainPageSize {
+			break
+		}
+		from = prevBlock.Number + 1
+	}
+
+	return checked, nil
+}
+
+// verifyTx checks the hash of a block transaction and compares it with the copy
+// held by the store. It returns a description of the problem found, if any.
+func verifyTx(tx *types.Transaction, lookupTx TxLookupFunc) (string, error) {
+
+	if tx.Hash != tx.MakeHash() {
+		return "transaction hash does not match its content", nil
+	}
+
+	if lookupTx == nil {
+		return "", nil
+	}
+
+	storedTx, err := lookupTx(tx.ID)
+	if err != nil {
+		return "", err
+	} else if storedTx == nil {
+		return "transaction is missing from the store", nil
+	}
+
+	if storedTx.Hash != tx.Hash || storedTx.MakeHash() != storedTx.Hash {
+		return "stored transaction does not match the block transaction", nil
+	}
+
+	return "", nil
+}
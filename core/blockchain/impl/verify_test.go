@@ -0,0 +1,293 @@
+package impl
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// makeVerifyTestChain creates a chain with the given number of blocks
+func makeVerifyTestChain(b *BoltBlockchain, numBlocks int) (string, []*types.Block) {
+	chainName := util.RandString(5)
+	b.CreateChain(chainName, true)
+	var blocks []*types.Block
+	for i := 0; i < numBlocks; i++ {
+		tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: util.RandString(5)}
+		tx.Hash = tx.MakeHash()
+		block, _ := b.CreateBlock(util.RandString(10), chainName, []*types.Transaction{tx})
+		blocks = append(blocks, block)
+	}
+	return chainName, blocks
+}
+
+// overwriteBlock replaces a stored block of a bolt chain
+func overwriteBlock(b *BoltBlockchain, block *types.Block) {
+	b.db.Update(func(tx *bolt.Tx) error {
+		blockJSON, _ := util.ToJSON(block)
+		return tx.Bucket([]byte(BlockTableName)).Bucket([]byte(block.ChainName)).Put(itob(uint64(block.Number)), blockJSON)
+	})
+}
+
+// deleteBlock removes a stored block of a bolt chain
+func deleteBlock(b *BoltBlockchain, block *types.Block) {
+	b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BlockTableName)).Bucket([]byte(block.ChainName)).Delete(itob(uint64(block.Number)))
+	})
+}
+
+func collectIssues(b *BoltBlockchain, chainName string, lookupTx TxLookupFunc, publicKey ...*signer.PublicKey) (uint, []*ChainIssue, error) {
+	var issues []*ChainIssue
+	var key *signer.PublicKey
+	if len(publicKey) > 0 {
+		key = publicKey[0]
+	}
+	checked, err := VerifyChain(b, chainName, lookupTx, key, 0, func(issue *ChainIssue) error {
+		issues = append(issues, issue)
+		return nil
+	})
+	return checked, issues, err
+}
+
+func TestVerifyChain(t *testing.T) {
+
+	dbPath := path.Join(os.TempDir(), "test_verify_"+util.RandString(5)+".db")
+	defer os.Remove(dbPath)
+
+	boltChain := new(BoltBlockchain)
+	_, err := boltChain.Connect("bolt://" + dbPath)
+	if err != nil {
+		t.Fatal("failed to connect to bolt blockchain")
+	}
+	defer boltChain.Close()
+	boltChain.Init()
+
+	Convey("VerifyChain", t, func() {
+
+		Convey("Should return error if chain does not exist", func() {
+			_, _, err := collectIssues(boltChain, "unknown", nil)
+			So(err, ShouldEqual, types.ErrChainNotFound)
+		})
+
+		Convey("Should find no issue in a valid chain", func() {
+			chainName, _ := makeVerifyTestChain(boltChain, 5)
+			checked, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(checked, ShouldEqual, 5)
+			So(issues, ShouldBeEmpty)
+		})
+
+		Convey("Should walk chains longer than a page", func() {
+			verifyChainPageSize = 2
+			defer func() { verifyChainPageSize = 100 }()
+			chainName, _ := makeVerifyTestChain(boltChain, 5)
+			checked, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(checked, ShouldEqual, 5)
+			So(issues, ShouldBeEmpty)
+		})
+
+		Convey("Should report a tampered transaction", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 3)
+			txs, _ := blocks[1].GetTransactions()
+			txs[0].Value = "tampered"
+			blocks[1].Transactions, _ = util.ToJSON(txs)
+			overwriteBlock(boltChain, blocks[1])
+
+			_, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(len(issues), ShouldEqual, 1)
+			So(issues[0].Type, ShouldEqual, IssueTamperedTx)
+			So(issues[0].TxID, ShouldEqual, txs[0].ID)
+		})
+
+		Convey("Should report a bad block hash if a transaction is replaced along with its hash", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 3)
+			txs, _ := blocks[1].GetTransactions()
+			txs[0].Value = "tampered"
+			txs[0].Hash = txs[0].MakeHash()
+			blocks[1].Transactions, _ = util.ToJSON(txs)
+			overwriteBlock(boltChain, blocks[1])
+
+			_, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(len(issues), ShouldEqual, 1)
+			So(issues[0].Type, ShouldEqual, IssueBadBlockHash)
+			So(issues[0].BlockNumber, ShouldEqual, 2)
+		})
+
+		Convey("Should report only the first broken link", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 4)
+			blocks[1].PrevBlockHash = "something_else"
+			overwriteBlock(boltChain, blocks[1])
+			blocks[2].PrevBlockHash = "something_else"
+			overwriteBlock(boltChain, blocks[2])
+
+			_, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			var brokenLinks []*ChainIssue
+			for _, issue := range issues {
+				if issue.Type == IssueBrokenLink {
+					brokenLinks = append(brokenLinks, issue)
+				}
+			}
+			So(len(brokenLinks), ShouldEqual, 1)
+			So(brokenLinks[0].BlockNumber, ShouldEqual, 2)
+		})
+
+		Convey("Should report gaps in block numbers", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 4)
+			deleteBlock(boltChain, blocks[1])
+
+			checked, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(checked, ShouldEqual, 3)
+			So(len(issues), ShouldEqual, 2)
+			So(issues[0].Type, ShouldEqual, IssueNumberGap)
+			So(issues[0].BlockNumber, ShouldEqual, 3)
+			So(issues[1].Type, ShouldEqual, IssueBrokenLink)
+		})
+
+		Convey("Should report transactions that differ from the stored copy", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 1)
+			txs, _ := blocks[0].GetTransactions()
+			lookupTx := func(id string) (*types.Transaction, error) {
+				storedTx := *txs[0]
+				storedTx.Value = "changed"
+				storedTx.Hash = storedTx.MakeHash()
+				return &storedTx, nil
+			}
+
+			_, issues, err := collectIssues(boltChain, chainName, lookupTx)
+			So(err, ShouldBeNil)
+			So(len(issues), ShouldEqual, 1)
+			So(issues[0].Type, ShouldEqual, IssueTamperedTx)
+			So(issues[0].Message, ShouldEqual, "stored transaction does not match the block transaction")
+		})
+
+		Convey("Should verify a compacted chain from its checkpoint", func() {
+			chainName, blocks := makeVerifyTestChain(boltChain, 4)
+			_, err := boltChain.Compact(chainName, 2)
+			So(err, ShouldBeNil)
+			checked, issues, err := collectIssues(boltChain, chainName, nil)
+			So(err, ShouldBeNil)
+			So(checked, ShouldEqual, 2)
+			So(issues, ShouldBeEmpty)
+
+			Convey("and report a block that does not follow the checkpoint", func() {
+				blocks[2].PrevBlockHash = blocks[0].Hash
+				overwriteBlock(boltChain, blocks[2])
+				_, issues, err := collectIssues(boltChain, chainName, nil)
+				So(err, ShouldBeNil)
+				So(issues[0].Type, ShouldEqual, IssueBrokenLink)
+				So(issues[0].BlockNumber, ShouldEqual, 3)
+			})
+		})
+
+		Convey("With signed blocks", func() {
+			blockSigner, _ := signer.Generate()
+			boltChain.SetSigner(blockSigner)
+			defer boltChain.SetSigner(nil)
+
+			Convey("Should find no issue in a chain signed by the verifying key", func() {
+				chainName, _ := makeVerifyTestChain(boltChain, 3)
+				_, issues, err := collectIssues(boltChain, chainName, nil, blockSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(issues, ShouldBeEmpty)
+			})
+
+			Convey("Should report a block signed by another key", func() {
+				chainName, _ := makeVerifyTestChain(boltChain, 2)
+				otherSigner, _ := signer.Generate()
+				_, issues, err := collectIssues(boltChain, chainName, nil, otherSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(len(issues), ShouldEqual, 2)
+				So(issues[0].Type, ShouldEqual, IssueBadSignature)
+				So(issues[0].Message, ShouldContainSubstring, "unknown key")
+			})
+
+			Convey("Should report a rewritten block that kept its signature", func() {
+				chainName, blocks := makeVerifyTestChain(boltChain, 2)
+				blocks[1].Signature = blocks[0].Signature
+				overwriteBlock(boltChain, blocks[1])
+				_, issues, err := collectIssues(boltChain, chainName, nil, blockSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(len(issues), ShouldEqual, 1)
+				So(issues[0].Type, ShouldEqual, IssueBadSignature)
+				So(issues[0].BlockNumber, ShouldEqual, 2)
+			})
+
+			Convey("Should report an unsigned block", func() {
+				chainName, blocks := makeVerifyTestChain(boltChain, 2)
+				blocks[1].Signature = ""
+				overwriteBlock(boltChain, blocks[1])
+				_, issues, err := collectIssues(boltChain, chainName, nil, blockSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(len(issues), ShouldEqual, 1)
+				So(issues[0].Type, ShouldEqual, IssueUnsignedBlock)
+			})
+
+			Convey("Should check the signature of the checkpoint of a compacted chain", func() {
+				chainName, _ := makeVerifyTestChain(boltChain, 3)
+				_, err := boltChain.Compact(chainName, 1)
+				So(err, ShouldBeNil)
+				_, issues, err := collectIssues(boltChain, chainName, nil, blockSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(issues, ShouldBeEmpty)
+
+				otherSigner, _ := signer.Generate()
+				_, issues, err = collectIssues(boltChain, chainName, nil, otherSigner.PublicKey())
+				So(err, ShouldBeNil)
+				So(len(issues), ShouldEqual, 3)
+				So(issues[0].Type, ShouldEqual, IssueBadCheckpoint)
+				So(issues[0].BlockNumber, ShouldEqual, 1)
+			})
+
+			Convey("With a chain whose first blocks were created before blocks were signed", func() {
+				boltChain.SetSigner(nil)
+				chainName, _ := makeVerifyTestChain(boltChain, 2)
+				boltChain.SetSigner(blockSigner)
+				tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+				tx.Hash = tx.MakeHash()
+				_, err := boltChain.CreateBlock(util.RandString(10), chainName, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				Convey("Should report the unsigned blocks if the first signed block is not set", func() {
+					_, issues, err := collectIssues(boltChain, chainName, nil, blockSigner.PublicKey())
+					So(err, ShouldBeNil)
+					So(len(issues), ShouldEqual, 2)
+					So(issues[0].Type, ShouldEqual, IssueUnsignedBlock)
+					So(issues[0].BlockNumber, ShouldEqual, 1)
+					So(issues[1].BlockNumber, ShouldEqual, 2)
+				})
+
+				Convey("Should accept unsigned blocks numbered below the first signed block", func() {
+					var issues []*ChainIssue
+					_, err := VerifyChain(boltChain, chainName, nil, blockSigner.PublicKey(), 3, func(issue *ChainIssue) error {
+						issues = append(issues, issue)
+						return nil
+					})
+					So(err, ShouldBeNil)
+					So(issues, ShouldBeEmpty)
+				})
+
+				Convey("Should report unsigned blocks numbered from the first signed block", func() {
+					var issues []*ChainIssue
+					_, err := VerifyChain(boltChain, chainName, nil, blockSigner.PublicKey(), 2, func(issue *ChainIssue) error {
+						issues = append(issues, issue)
+						return nil
+					})
+					So(err, ShouldBeNil)
+					So(len(issues), ShouldEqual, 1)
+					So(issues[0].Type, ShouldEqual, IssueUnsignedBlock)
+					So(issues[0].BlockNumber, ShouldEqual, 2)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,153 @@
+// Package merkle computes the merkle root of the transactions of a block
+// and creates and verifies transaction inclusion proofs. It only depends on
+// the standard library so that proofs can be verified offline by any client.
+package merkle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+var (
+	// ErrTxNotInBlock represents an error about a transaction not included in a block
+	ErrTxNotInBlock = fmt.Errorf("transaction not included in block")
+
+	// ErrProofRootMismatch represents an error about a proof path that does not lead to the merkle root
+	ErrProofRootMismatch = fmt.Errorf("proof does not match merkle root")
+
+	// ErrProofBlockHashMismatch represents an error about a proof whose merkle root is not committed to by the block hash
+	ErrProofBlockHashMismatch = fmt.Errorf("proof does not match block hash")
+)
+
+// ProofNode is a sibling hash on the path from a transaction to the merkle root.
+// Left is true if the sibling is the left node of the pair.
+type ProofNode struct {
+	Hash string `json:"hash,omitempty"`
+	Left bool   `json:"left,omitempty"`
+}
+
+// Proof defines the information required to prove that a
+// transaction is included in a block.
+type Proof struct {
+	TxID          string       `json:"txId,omitempty"`
+	TxHash        string       `json:"txHash,omitempty"`
+	BlockID       string       `json:"blockId,omitempty"`
+	BlockNumber   uint         `json:"blockNumber,omitempty"`
+	BlockHash     string       `json:"blockHash,omitempty"`
+	PrevBlockHash string       `json:"prevBlockHash,omitempty"`
+	MerkleRoot    string       `json:"merkleRoot,omitempty"`
+	Path          []*ProofNode `json:"path,omitempty"`
+}
+
+func sha256Hex(prefix byte, parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte{prefix})
+	for _, p := range parts {
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashLeaf returns the leaf node hash of a transaction hash. Leaf and inner
+// nodes are hashed with different prefixes so that an inner node cannot be
+// presented as a transaction.
+func HashLeaf(txHash string) string {
+	return sha256Hex(0x00, txHash)
+}
+
+// HashNodes returns the parent hash of a pair of nodes
+func HashNodes(left, right string) string {
+	return sha256Hex(0x01, left, right)
+}
+
+// MakeBlockHash creates the hash of a block from the hash of
+// the previous block and the merkle root of its transactions.
+func MakeBlockHash(prevBlockHash, merkleRoot string) string {
+	return sha256Hex(0x02, prevBlockHash, ";", merkleRoot)
+}
+
+// nextLevel computes the parent level of a tree level. A node
+// without a sibling is moved to the next level unchanged.
+func nextLevel(level []string) []string {
+	var parents []string
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			parents = append(parents, level[i])
+			continue
+		}
+		parents = append(parents, HashNodes(level[i], level[i+1]))
+	}
+	return parents
+}
+
+// leaves returns the leaf hashes of a list of transaction hashes
+func leaves(txHashes []string) []string {
+	var level = make([]string, len(txHashes))
+	for i, h := range txHashes {
+		level[i] = HashLeaf(h)
+	}
+	return level
+}
+
+// Root computes the merkle root of a list of transaction hashes.
+// Returns an empty string if the list is empty.
+func Root(txHashes []string) string {
+	if len(txHashes) == 0 {
+		return ""
+	}
+	level := leaves(txHashes)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// MakePath returns the sibling hashes on the path from the
+// transaction hash at index to the merkle root.
+func MakePath(txHashes []string, index int) ([]*ProofNode, error) {
+
+	if index < 0 || index >= len(txHashes) {
+		return nil, ErrTxNotInBlock
+	}
+
+	var path []*ProofNode
+	level := leaves(txHashes)
+	for len(level) > 1 {
+		if index%2 == 1 {
+			path = append(path, &ProofNode{Hash: level[index-1], Left: true})
+		} else if index+1 < len(level) {
+			path = append(path, &ProofNode{Hash: level[index+1]})
+		}
+		level = nextLevel(level)
+		index /= 2
+	}
+
+	return path, nil
+}
+
+// RootFromPath computes the merkle root of a transaction hash and its proof path
+func RootFromPath(txHash string, path []*ProofNode) string {
+	node := HashLeaf(txHash)
+	for _, sibling := range path {
+		if sibling.Left {
+			node = HashNodes(sibling.Hash, node)
+		} else {
+			node = HashNodes(node, sibling.Hash)
+		}
+	}
+	return node
+}
+
+// Verify checks that the proof path of the transaction leads to the merkle root of the proof and that
+// the merkle root is committed to by the given block hash. The block hash should be obtained
+// from a trusted source rather than from the proof.
+func (p *Proof) Verify(blockHash string) error {
+	if RootFromPath(p.TxHash, p.Path) != p.MerkleRoot {
+		return ErrProofRootMismatch
+	}
+	if MakeBlockHash(p.PrevBlockHash, p.MerkleRoot) != blockHash {
+		return ErrProofBlockHashMismatch
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func makeTxHashes(n int) []string {
+	var hashes []string
+	for i := 0; i < n; i++ {
+		hashes = append(hashes, fmt.Sprintf("tx_hash_%d", i))
+	}
+	return hashes
+}
+
+func TestMerkle(t *testing.T) {
+	Convey("Merkle", t, func() {
+
+		Convey(".Root", func() {
+
+			Convey("Should return empty string if there are no transactions", func() {
+				So(Root(nil), ShouldEqual, "")
+			})
+
+			Convey("Should return the leaf hash of a single transaction", func() {
+				So(Root([]string{"tx_hash"}), ShouldEqual, HashLeaf("tx_hash"))
+			})
+
+			Convey("Should move an unpaired node to the next level", func() {
+				a, b, c := HashLeaf("a"), HashLeaf("b"), HashLeaf("c")
+				So(Root([]string{"a", "b", "c"}), ShouldEqual, HashNodes(HashNodes(a, b), c))
+			})
+
+			Convey("Should change if transaction order changes", func() {
+				So(Root([]string{"a", "b"}), ShouldNotEqual, Root([]string{"b", "a"}))
+			})
+		})
+
+		Convey(".MakePath", func() {
+
+			Convey("Should return error if index is out of range", func() {
+				_, err := MakePath(makeTxHashes(3), 3)
+				So(err, ShouldEqual, ErrTxNotInBlock)
+			})
+
+			Convey("Should create paths that lead to the merkle root for all transactions", func() {
+				for n := 1; n <= 9; n++ {
+					hashes := makeTxHashes(n)
+					root := Root(hashes)
+					for i, h := range hashes {
+						path, err := MakePath(hashes, i)
+						So(err, ShouldBeNil)
+						So(RootFromPath(h, path), ShouldEqual, root)
+					}
+				}
+			})
+		})
+
+		Convey(".Verify", func() {
+
+			hashes := makeTxHashes(5)
+			root := Root(hashes)
+			path, _ := MakePath(hashes, 3)
+			blockHash := MakeBlockHash("prev_hash", root)
+			proof := &Proof{TxHash: hashes[3], PrevBlockHash: "prev_hash", MerkleRoot: root, Path: path}
+
+			Convey("Should successfully verify a valid proof", func() {
+				So(proof.Verify(blockHash), ShouldBeNil)
+			})
+
+			Convey("Should fail if transaction hash is not the proven transaction", func() {
+				proof.TxHash = hashes[2]
+				So(proof.Verify(blockHash), ShouldEqual, ErrProofRootMismatch)
+			})
+
+			Convey("Should fail if block hash does not commit to the merkle root", func() {
+				So(proof.Verify(MakeBlockHash("other_hash", root)), ShouldEqual, ErrProofBlockHashMismatch)
+			})
+		})
+	})
+}
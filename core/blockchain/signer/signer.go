@@ -0,0 +1,142 @@
+// Package signer signs and verifies the hashes of blocks with ed25519 keys.
+// Private keys are stored as PEM encoded PKCS #8 keys, the format created by
+// `openssl genpkey -algorithm ed25519`.
+package signer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// Algorithm is the name of the signature algorithm
+const Algorithm = "ed25519"
+
+// MakeKeyID creates the id of a public key. It is the first
+// 16 characters of the hex encoded SHA256 hash of the key.
+func MakeKeyID(key ed25519.PublicKey) string {
+	return util.Sha256(hex.EncodeToString(key))[:16]
+}
+
+// PublicKey is a key that verifies block signatures
+type PublicKey struct {
+	ID  string
+	Key ed25519.PublicKey
+}
+
+// NewPublicKey creates a public key from its hex encoding
+func NewPublicKey(hexKey string) (*PublicKey, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key")
+	}
+	return &PublicKey{ID: MakeKeyID(key), Key: key}, nil
+}
+
+// Hex returns the hex encoding of the key
+func (k *PublicKey) Hex() string {
+	return hex.EncodeToString(k.Key)
+}
+
+// Verify checks that a hex encoded signature is a signature of a hash by this key
+func (k *PublicKey) Verify(hash, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(k.Key, []byte(hash), sig)
+}
+
+// VerifyBlock checks that a block was signed by this key
+func (k *PublicKey) VerifyBlock(block *types.Block) error {
+	if len(block.Signature) == 0 {
+		return fmt.Errorf("block is not signed")
+	} else if block.KeyID != k.ID {
+		return fmt.Errorf("block was signed by an unknown key (%s)", block.KeyID)
+	} else if !k.Verify(block.Hash, block.Signature) {
+		return fmt.Errorf("block signature is not valid")
+	}
+	return nil
+}
+
+// Signer signs block hashes with an ed25519 private key.
+// It implements types.BlockSigner.
+type Signer struct {
+	key       ed25519.PrivateKey
+	publicKey *PublicKey
+}
+
+// New creates a signer from a private key
+func New(key ed25519.PrivateKey) *Signer {
+	pub := key.Public().(ed25519.PublicKey)
+	return &Signer{
+		key:       key,
+		publicKey: &PublicKey{ID: MakeKeyID(pub), Key: pub},
+	}
+}
+
+// Generate creates a signer with a new random key
+func Generate() (*Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key. %s", err)
+	}
+	return New(key), nil
+}
+
+// Parse creates a signer from a PEM encoded PKCS #8 private key
+func Parse(pemBytes []byte) (*Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key. %s", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not an ed25519 key")
+	}
+	return New(edKey), nil
+}
+
+// Load creates a signer from a file containing a PEM encoded PKCS #8 private key
+func Load(file string) (*Signer, error) {
+	pemBytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file. %s", err)
+	}
+	return Parse(pemBytes)
+}
+
+// Marshal returns the PEM encoding of the private key
+func (s *Signer) Marshal() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// KeyID returns the id of the public key of the signer
+func (s *Signer) KeyID() string {
+	return s.publicKey.ID
+}
+
+// PublicKey returns the public key of the signer
+func (s *Signer) PublicKey() *PublicKey {
+	return s.publicKey
+}
+
+// Sign signs a hash. It returns the hex encoded signature.
+func (s *Signer) Sign(hash string) (string, error) {
+	return hex.EncodeToString(ed25519.Sign(s.key, []byte(hash))), nil
+}
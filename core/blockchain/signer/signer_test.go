@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSigner(t *testing.T) {
+	Convey("Signer", t, func() {
+
+		s, err := Generate()
+		So(err, ShouldBeNil)
+
+		Convey(".Sign", func() {
+			Convey("Should create a signature that is verified by the public key", func() {
+				sig, err := s.Sign("hash")
+				So(err, ShouldBeNil)
+				So(s.PublicKey().Verify("hash", sig), ShouldBeTrue)
+				So(s.PublicKey().Verify("other_hash", sig), ShouldBeFalse)
+				So(s.PublicKey().Verify("hash", "not_hex"), ShouldBeFalse)
+			})
+		})
+
+		Convey(".Marshal and .Load", func() {
+			Convey("Should write and read back the same key", func() {
+				pemBytes, err := s.Marshal()
+				So(err, ShouldBeNil)
+				file := path.Join(os.TempDir(), "test_signer_"+util.RandString(5)+".pem")
+				defer os.Remove(file)
+				f, _ := os.Create(file)
+				f.Write(pemBytes)
+				f.Close()
+
+				loaded, err := Load(file)
+				So(err, ShouldBeNil)
+				So(loaded.KeyID(), ShouldEqual, s.KeyID())
+			})
+
+			Convey("Should return error if the file has no PEM encoded key", func() {
+				_, err := Parse([]byte("not a key"))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "no PEM encoded key found")
+			})
+		})
+
+		Convey("NewPublicKey", func() {
+			Convey("Should create the public key from its hex encoding", func() {
+				key, err := NewPublicKey(s.PublicKey().Hex())
+				So(err, ShouldBeNil)
+				So(key.ID, ShouldEqual, s.KeyID())
+			})
+
+			Convey("Should return error if the key is not valid", func() {
+				_, err := NewPublicKey("abcd")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid ed25519 public key")
+			})
+		})
+
+		Convey(".VerifyBlock", func() {
+
+			block := &types.Block{Hash: util.Sha256("block")}
+			block.Signature, _ = s.Sign(block.Hash)
+			block.KeyID = s.KeyID()
+
+			Convey("Should accept a block signed by the key", func() {
+				So(s.PublicKey().VerifyBlock(block), ShouldBeNil)
+			})
+
+			Convey("Should reject an unsigned block", func() {
+				block.Signature = ""
+				So(s.PublicKey().VerifyBlock(block).Error(), ShouldEqual, "block is not signed")
+			})
+
+			Convey("Should reject a block signed by another key", func() {
+				other, _ := Generate()
+				So(other.PublicKey().VerifyBlock(block).Error(), ShouldContainSubstring, "unknown key")
+			})
+
+			Convey("Should reject a block whose hash was changed", func() {
+				block.Hash = util.Sha256("other")
+				So(s.PublicKey().VerifyBlock(block).Error(), ShouldEqual, "block signature is not valid")
+			})
+		})
+	})
+}
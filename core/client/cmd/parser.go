@@ -188,6 +188,38 @@ func makeContractFromFile(path, repoVersion string) ([]*proto_api.ContractReques
 				}
 			}
 
+			// parse 'schedule' stanza
+			if schedules, ok := _contract["schedule"].([]map[string]interface{}); ok && len(schedules) > 0 {
+				mergedSchedule := common.MergeMapSlice(schedules)
+				if jobs, ok := mergedSchedule["job"].([]map[string]interface{}); ok && len(jobs) > 0 {
+					var schedule types.Schedule
+					bs, _ := util.ToJSON(jobs)
+					if err := util.FromJSON(bs, &schedule); err != nil {
+						errs = append(errs, fmt.Errorf("schedule: invalid job: %s", err))
+						return nil, errs
+					}
+					if _errs := schedule.Validate(); len(_errs) > 0 {
+						for _, err := range _errs {
+							errs = append(errs, fmt.Errorf("schedule: %s", err))
+						}
+						return nil, errs
+					}
+					contract.Schedule = schedule.ToJSON()
+				}
+			}
+
+			// parse 'timeouts' stanza
+			if timeouts, ok := _contract["timeouts"].([]map[string]interface{}); ok && len(timeouts) > 0 {
+				invokeTimeouts := types.NewInvokeTimeouts(common.MergeMapSlice(timeouts))
+				if _errs := invokeTimeouts.Validate(); len(_errs) > 0 {
+					for _, err := range _errs {
+						errs = append(errs, fmt.Errorf("timeouts: %s", err))
+					}
+					return nil, errs
+				}
+				contract.Timeouts = invokeTimeouts
+			}
+
 			// parse 'env' stanza
 			if envs, ok := _contract["env"].([]map[string]interface{}); ok && len(envs) > 0 {
 				contract.Env = types.NewEnv(common.MergeMapSlice(envs))
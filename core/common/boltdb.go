@@ -0,0 +1,91 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// BoltConStrScheme is the connection string scheme that selects
+// the embedded, single-file store and blockchain backend.
+// Example: bolt:///var/lib/cocoon/ledger.db
+const BoltConStrScheme = "bolt://"
+
+// sharedBoltDB is an open bolt database and the number
+// of callers currently holding a reference to it.
+type sharedBoltDB struct {
+	db   *bolt.DB
+	refs int
+}
+
+// openBoltDBs holds all open bolt databases keyed by file path. Bolt holds an exclusive
+// file lock on an open database, so a store and a blockchain connecting with the
+// same connection string must share a single handle.
+var openBoltDBs = make(map[string]*sharedBoltDB)
+var openBoltDBsMu = sync.Mutex{}
+
+// IsBoltConStr checks whether a connection string refers to an embedded bolt database
+func IsBoltConStr(conStr string) bool {
+	return strings.HasPrefix(conStr, BoltConStrScheme)
+}
+
+// GetBoltDBPath returns the database file path of a bolt connection string
+func GetBoltDBPath(conStr string) string {
+	return strings.TrimPrefix(conStr, BoltConStrScheme)
+}
+
+// OpenBoltDB opens the bolt database referenced by a connection string or returns
+// the handle of an already opened database. Every successful call must be
+// matched by a call to CloseBoltDB.
+func OpenBoltDB(conStr string) (*bolt.DB, error) {
+
+	if !IsBoltConStr(conStr) {
+		return nil, fmt.Errorf("connection string must begin with %s", BoltConStrScheme)
+	}
+
+	dbPath := GetBoltDBPath(conStr)
+	if len(dbPath) == 0 {
+		return nil, fmt.Errorf("database file path is required")
+	}
+
+	openBoltDBsMu.Lock()
+	defer openBoltDBsMu.Unlock()
+
+	if shared, ok := openBoltDBs[dbPath]; ok {
+		shared.refs++
+		return shared.db, nil
+	}
+
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	openBoltDBs[dbPath] = &sharedBoltDB{db: db, refs: 1}
+	return db, nil
+}
+
+// CloseBoltDB releases a reference to a bolt database opened by OpenBoltDB.
+// The database is closed when no more references are held.
+func CloseBoltDB(db *bolt.DB) error {
+
+	openBoltDBsMu.Lock()
+	defer openBoltDBsMu.Unlock()
+
+	dbPath := db.Path()
+	shared, ok := openBoltDBs[dbPath]
+	if !ok || shared.db != db {
+		return db.Close()
+	}
+
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+
+	delete(openBoltDBs, dbPath)
+	return db.Close()
+}
@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCron(t *testing.T) {
+	Convey("Cron", t, func() {
+
+		// Wednesday, 2017-05-10 10:17:30 UTC
+		now := time.Date(2017, 5, 10, 10, 17, 30, 0, time.UTC)
+
+		Convey(".Parse", func() {
+			Convey("Should return error if the expression is not valid", func() {
+				for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@every 10ms", "@every soon"} {
+					_, err := Parse(expr)
+					So(err, ShouldNotBeNil)
+				}
+			})
+		})
+
+		Convey(".Next", func() {
+			Convey("Should return the expected activation times", func() {
+				var cases = []struct {
+					expr     string
+					expected time.Time
+				}{
+					{"* * * * *", time.Date(2017, 5, 10, 10, 18, 0, 0, time.UTC)},
+					{"*/15 * * * *", time.Date(2017, 5, 10, 10, 30, 0, 0, time.UTC)},
+					{"5 * * * *", time.Date(2017, 5, 10, 11, 5, 0, 0, time.UTC)},
+					{"0 9-17/4 * * *", time.Date(2017, 5, 10, 13, 0, 0, 0, time.UTC)},
+					{"30 2 * * *", time.Date(2017, 5, 11, 2, 30, 0, 0, time.UTC)},
+					{"0 0 * * sun", time.Date(2017, 5, 14, 0, 0, 0, 0, time.UTC)},
+					{"0 0 * * 7", time.Date(2017, 5, 14, 0, 0, 0, 0, time.UTC)},
+					{"0 0 1 * *", time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)},
+					{"0 0 1,15 * fri", time.Date(2017, 5, 12, 0, 0, 0, 0, time.UTC)},
+					{"0 0 29 feb *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+					{"@hourly", time.Date(2017, 5, 10, 11, 0, 0, 0, time.UTC)},
+					{"@yearly", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{"@every 90s", time.Date(2017, 5, 10, 10, 19, 0, 0, time.UTC)},
+					{"0 0 31 2 *", time.Time{}},
+				}
+				for _, c := range cases {
+					s, err := Parse(c.expr)
+					So(err, ShouldBeNil)
+					So(s.Next(now), ShouldResemble, c.expected)
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
+
+// pgTxSavepoint is the name of the savepoints created by BeginPgTx
+const pgTxSavepoint = "cocoon_tx"
+
+// PgTx is a postgres database transaction. If it was begun on a database
+// handle that is already a transaction, it is a savepoint of that transaction.
+type PgTx struct {
+	*gorm.DB
+	savepoint bool
+}
+
+// IsPgTx checks whether a database handle is a database transaction
+func IsPgTx(db *gorm.DB) bool {
+	_, ok := db.CommonDB().(*sql.Tx)
+	return ok
+}
+
+// BeginPgTx begins a database transaction. If db is already a transaction,
+// a savepoint is created instead, so that the operations made after it can
+// be committed or rolled back without ending the enclosing transaction.
+func BeginPgTx(db *gorm.DB) *PgTx {
+	if IsPgTx(db) {
+		return &PgTx{DB: db.Exec("SAVEPOINT " + pgTxSavepoint), savepoint: true}
+	}
+	return &PgTx{DB: db.Begin()}
+}
+
+// IsSavepoint checks whether the transaction is a savepoint of an enclosing transaction
+func (tx *PgTx) IsSavepoint() bool {
+	return tx.savepoint
+}
+
+// Commit commits the transaction or releases the savepoint
+func (tx *PgTx) Commit() error {
+	if tx.savepoint {
+		return tx.DB.Exec("RELEASE SAVEPOINT " + pgTxSavepoint).Error
+	}
+	return tx.DB.Commit().Error
+}
+
+// Rollback rolls back the transaction or the operations made after the savepoint
+func (tx *PgTx) Rollback() error {
+	if tx.savepoint {
+		if err := tx.DB.Exec("ROLLBACK TO SAVEPOINT " + pgTxSavepoint).Error; err != nil {
+			return err
+		}
+		return tx.DB.Exec("RELEASE SAVEPOINT " + pgTxSavepoint).Error
+	}
+	return tx.DB.Rollback().Error
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ellcrys/cocoon/core/connector/connector/languages"
 	"github.com/ellcrys/cocoon/core/connector/router"
 	"github.com/ellcrys/cocoon/core/connector/server"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/platform"
 	"github.com/ellcrys/cocoon/core/scheduler"
 	"github.com/ellcrys/cocoon/core/types"
@@ -31,6 +32,9 @@ var (
 
 	// default cocoon code RPC ADDR
 	defaultCocoonCodeRPCAddr = util.Env("DEV_ADDR_COCOON_CODE_RPC", "127.0.0.1:8004")
+
+	// default connector metrics Addr
+	defaultConnectorMetricsAddr = util.Env("DEV_ADDR_CONNECTOR_METRICS", ":8902")
 )
 
 func init() {
@@ -98,6 +102,7 @@ var startCmd = &cobra.Command{
 		connectorRPCAddr := scheduler.Getenv("ADDR_RPC", defaultConnectorRPCAddr)
 		connectorHTTPAddr := scheduler.Getenv("ADDR_HTTP", defaultConnectorHTTPAddr)
 		cocoonCodeRPCAddr := scheduler.Getenv("ADDR_code_RPC", defaultCocoonCodeRPCAddr)
+		connectorMetricsAddr := scheduler.Getenv("ADDR_METRICS", defaultConnectorMetricsAddr)
 
 		// create router helper
 		routerHelper, err := router.NewHelper(routerLog, connectorHTTPAddr)
@@ -127,6 +132,14 @@ var startCmd = &cobra.Command{
 		go httpServer.Start(connectorHTTPAddr, httpServerStartedCh)
 		<-httpServerStartedCh
 
+		// serve metrics separately from the public http server
+		go func() {
+			log.Infof("Serving metrics @ %s/metrics", connectorMetricsAddr)
+			if err := metrics.Serve(connectorMetricsAddr); err != nil {
+				log.Errorf("Metrics server stopped: %s", err)
+			}
+		}()
+
 		// listen to terminate request
 		common.OnTerminate(func(s os.Signal) {
 			log.Info("Terminate signal received")
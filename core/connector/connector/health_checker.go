@@ -3,6 +3,7 @@ package connector
 import (
 	"time"
 
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/stub/proto_runtime"
 
 	"fmt"
@@ -13,6 +14,9 @@ import (
 	"google.golang.org/grpc"
 )
 
+// healthCheckFailures counts failed health checks of the cocoon code
+var healthCheckFailures = metrics.NewCounter("cocoon_connector_health_check_failures_total", "Failed health checks of the cocoon code")
+
 // HealthChecker checks the health status of
 // the cocoon code. It repeatedly calls the cocoon coder
 // health check method. If cocoon code refuses to respond,
@@ -74,6 +78,7 @@ func (hc *HealthChecker) check() error {
 		ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
 		_, err = stub.HealthCheck(ctx, &proto_runtime.Ok{})
 		if err != nil {
+			healthCheckFailures.Inc()
 			logHealthChecker.Warningf("health check not passed. Retry Remaining: %d", retryLimit)
 			retryLimit--
 			time.Sleep(2 * time.Second)
@@ -9,6 +9,7 @@ import (
 	"github.com/chuckpreslar/emission"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/metrics"
 	logging "github.com/op/go-logging"
 )
 
@@ -17,6 +18,17 @@ var log *logging.Logger
 // ErrNoContainerFound represents a error about not finding containers
 var ErrNoContainerFound = errors.New("no container found")
 
+var (
+	// diskUsage is the last reported disk usage of the cocoon code container
+	diskUsage = metrics.NewGauge("cocoon_connector_disk_usage_bytes", "Disk usage of the cocoon code container")
+
+	// netRx is the last reported number of bytes received by the cocoon code container
+	netRx = metrics.NewGauge("cocoon_connector_network_rx_bytes", "Bytes received by the cocoon code container")
+
+	// netTx is the last reported number of bytes sent by the cocoon code container
+	netTx = metrics.NewGauge("cocoon_connector_network_tx_bytes", "Bytes sent by the cocoon code container")
+)
+
 // HandleFunc is the expected function signature
 type HandleFunc func(map[string]interface{})
 
@@ -138,6 +150,10 @@ func (m *Monitor) Monitor() {
 			NetTx:     txBytes,
 		}
 
+		diskUsage.Set(float64(report.DiskUsage))
+		netRx.Set(float64(report.NetRx))
+		netTx.Set(float64(report.NetTx))
+
 		m.emitter.Emit("monitor.report", report)
 		time.Sleep(30 * time.Second)
 	}
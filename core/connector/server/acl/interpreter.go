@@ -18,6 +18,10 @@ var (
 	PrivAllowPut = "allow-put"
 	// PrivAllowGet allows a get operation
 	PrivAllowGet = "allow-get"
+	// PrivAllowDelete allows a delete operation
+	PrivAllowDelete = "allow-delete"
+	// PrivAllowInvoke allows an invoke operation
+	PrivAllowInvoke = "allow-invoke"
 	// PrivDeny denies all operations
 	PrivDeny = "deny"
 	// PrivDenyCreateLedger denies a ledger creation operation
@@ -26,6 +30,10 @@ var (
 	PrivDenyPut = "deny-put"
 	// PrivDenyGet denies a get operation
 	PrivDenyGet = "deny-get"
+	// PrivDenyDelete denies a delete operation
+	PrivDenyDelete = "deny-delete"
+	// PrivDenyInvoke denies an invoke operation
+	PrivDenyInvoke = "deny-invoke"
 
 	// validPrivileges refers to the accepted/known privileges
 	validPrivileges = []string{
@@ -33,13 +41,24 @@ var (
 		PrivAllowCreateLedger,
 		PrivAllowPut,
 		PrivAllowGet,
+		PrivAllowDelete,
+		PrivAllowInvoke,
 		PrivDeny,
 		PrivDenyCreateLedger,
 		PrivDenyPut,
 		PrivDenyGet,
+		PrivDenyDelete,
+		PrivDenyInvoke,
 	}
 )
 
+// FunctionKey returns the key of the rules of a function. Rules of
+// functions control the invoke operation and are set along the rules
+// of ledgers, with the name of the function prefixed with `@`.
+func FunctionKey(function string) string {
+	return "@" + function
+}
+
 // Interpreter represents an ACL rule interpreter.
 type Interpreter struct {
 	rules         map[string]interface{}
@@ -121,6 +140,21 @@ func (i *Interpreter) Validate() []error {
 	return errs
 }
 
+// getOperations are the operations controlled by the get privileges
+var getOperations = []string{
+	types.TxGet,
+	types.TxGetLedger,
+	types.TxGetBlockByID,
+	types.TxGetBlockByNumber,
+	types.TxGetChainHeight,
+	types.TxListBlocks,
+	types.TxRangeGet,
+	types.TxQuery,
+	types.TxGetHistory,
+	types.TxGetTxProof,
+	types.TxSubscribe,
+}
+
 // isPermitted takes a privilege and an operation and returns
 // 0 if the operation is not allowed, 1 if the operation is allowed
 // and -1 if the privilege and operation combo has no permission rule logic.
@@ -135,9 +169,9 @@ func (i *Interpreter) isPermitted(privilege, operation string) int {
 	} else if privilege == PrivDenyCreateLedger && operation == types.TxNewLedger {
 		return 0
 	}
-	if privilege == PrivAllowGet && util.InStringSlice([]string{types.TxGet, types.TxGetLedger, types.TxGetBlockByID, types.TxRangeGet}, operation) {
+	if privilege == PrivAllowGet && util.InStringSlice(getOperations, operation) {
 		return 1
-	} else if privilege == PrivDenyGet && util.InStringSlice([]string{types.TxGet, types.TxGetLedger, types.TxGetBlockByID, types.TxRangeGet}, operation) {
+	} else if privilege == PrivDenyGet && util.InStringSlice(getOperations, operation) {
 		return 0
 	}
 	if privilege == PrivAllowPut && operation == types.TxPut {
@@ -145,6 +179,16 @@ func (i *Interpreter) isPermitted(privilege, operation string) int {
 	} else if privilege == PrivDenyPut && operation == types.TxPut {
 		return 0
 	}
+	if privilege == PrivAllowDelete && operation == types.TxDelete {
+		return 1
+	} else if privilege == PrivDenyDelete && operation == types.TxDelete {
+		return 0
+	}
+	if privilege == PrivAllowInvoke && operation == types.TxInvoke {
+		return 1
+	} else if privilege == PrivDenyInvoke && operation == types.TxInvoke {
+		return 0
+	}
 	return -1
 }
 
@@ -188,6 +188,100 @@ func TestInterpreter(t *testing.T) {
 						},
 					}, "ledger1", "actor1", types.TxGet, false, true,
 				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-get",
+					}, "ledger1", "actor_id", types.TxGetHistory, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxGetHistory, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxGetTxProof, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-get",
+					}, "ledger1", "actor_id", types.TxSubscribe, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxSubscribe, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-get",
+					}, "ledger1", "actor_id", types.TxGetBlockByNumber, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxGetBlockByNumber, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-get",
+					}, "ledger1", "actor_id", types.TxGetChainHeight, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxGetChainHeight, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-get",
+					}, "ledger1", "actor_id", types.TxListBlocks, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-get",
+					}, "ledger1", "actor_id", types.TxListBlocks, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-put",
+					}, "ledger1", "actor_id", types.TxDelete, false, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-delete",
+					}, "ledger1", "actor_id", types.TxDelete, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "allow-put deny-delete",
+					}, "ledger1", "actor_id", types.TxDelete, true, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*": "deny-delete",
+					}, "ledger1", "actor_id", types.TxPut, true, true,
+				},
+				[]interface{}{
+					map[string]interface{}{}, FunctionKey("transfer"), "actor_id", types.TxInvoke, false, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"@transfer": "allow-invoke",
+					}, FunctionKey("transfer"), "actor_id", types.TxInvoke, false, true,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"@transfer": "allow-invoke",
+					}, FunctionKey("transfer"), "actor_id", types.TxGet, false, false,
+				},
+				[]interface{}{
+					map[string]interface{}{
+						"*":         "allow",
+						"@transfer": "deny-invoke",
+					}, FunctionKey("transfer"), "actor_id", types.TxInvoke, false, false,
+				},
 			}
 
 			for _, c := range cases {
@@ -2,14 +2,19 @@ package handlers
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/stub/proto_runtime"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
+// invokeDuration measures the time taken by the cocoon code to respond to invocations
+var invokeDuration = metrics.NewHistogram("cocoon_connector_invoke_duration_seconds", "Time taken to invoke a function of the cocoon code", nil, "function", "outcome")
+
 // CocoonCodeOperations represents a cocoon code operation handlers
 type CocoonCodeOperations struct {
 	cocoonCodeRPCAddr string
@@ -31,6 +36,7 @@ func (l *CocoonCodeOperations) Handle(ctx context.Context, op *proto_connector.C
 	}
 	defer client.Close()
 
+	start := time.Now()
 	stub := proto_runtime.NewStubClient(client)
 	resp, err := stub.Invoke(ctx, &proto_runtime.InvokeParam{
 		ID:       op.ID,
@@ -40,14 +46,49 @@ func (l *CocoonCodeOperations) Handle(ctx context.Context, op *proto_connector.C
 	})
 
 	if err != nil {
+		invokeDuration.ObserveSince(start, op.GetFunction(), "error")
 		return nil, fmt.Errorf(common.GetRPCErrDesc(err))
 	}
 
-	return &proto_connector.Response{
+	codeResp := &proto_connector.Response{
 		ID:     resp.ID,
-		Status: 200,
+		Status: resp.Status,
 		Body:   resp.Body,
-	}, nil
+		Header: resp.Header,
+		Raw:    resp.Raw,
+	}
+
+	outcome := "ok"
+	if IsErrorResponse(codeResp) {
+		outcome = "error"
+	}
+	invokeDuration.ObserveSince(start, op.GetFunction(), outcome)
+
+	return codeResp, nil
+}
+
+// IsErrorResponse checks whether a response of the cocoon code describes an error.
+// A raw response, whose status, headers and body are set by the cocoon code, describes
+// an error if its status is 400 or above. Otherwise, a status other than 200
+// describes an error returned by the cocoon code.
+func IsErrorResponse(resp *proto_connector.Response) bool {
+	if resp.GetRaw() {
+		return resp.GetStatus() >= 400
+	}
+	return resp.GetStatus() != 200
+}
+
+// HealthCheck checks whether the cocoon code is running and responsive
+func (l *CocoonCodeOperations) HealthCheck(ctx context.Context) error {
+	client, err := grpc.Dial(l.cocoonCodeRPCAddr, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	stub := proto_runtime.NewStubClient(client)
+	_, err = stub.HealthCheck(ctx, new(proto_runtime.Ok))
+	return err
 }
 
 // Stop the cocoon code
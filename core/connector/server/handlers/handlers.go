@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/types"
+	context "golang.org/x/net/context"
+)
+
+// Platform provides the cocoons and releases that
+// operations are checked against
+type Platform interface {
+
+	// GetCocoonAndRelease gets a cocoon and one of its releases
+	GetCocoonAndRelease(ctx context.Context, cocoonID, releaseID string, includePrivateFields bool) (*types.Cocoon, *types.Release, error)
+
+	// GetCocoonAndLastActiveRelease gets a cocoon and its last deployed release
+	GetCocoonAndLastActiveRelease(ctx context.Context, cocoonID string, includePrivateFields bool) (*types.Cocoon, *types.Release, error)
+}
+
+// OrdererClients provides clients of the orderers. The
+// function returned with a client closes its connection.
+type OrdererClients interface {
+
+	// GetClient returns a client of an orderer
+	GetClient() (proto_orderer.OrdererClient, func(), error)
+
+	// GetLeaderClient returns a client of the orderer that
+	// leads the chains of the ledgers of a cocoon
+	GetLeaderClient(cocoonID string, ledgers ...string) (proto_orderer.OrdererClient, func(), error)
+
+	// InvalidateLeader forgets the leader of the chain of a ledger
+	InvalidateLeader(cocoonID, ledger string)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/connector/server/acl"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/scheduler"
+	"github.com/ellcrys/cocoon/core/types"
+	logging "github.com/op/go-logging"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// callerTokenKey is the metadata key of the token through which a connector
+// forwarding an invoke operation authenticates the invoking cocoon
+const callerTokenKey = "x-caller-token"
+
+// callerTokenType is the type of the tokens that authenticate invoking cocoons
+const callerTokenType = "token.invoke"
+
+// CallerTokenTTL is how long the token sent with a forwarded invoke operation is valid
+var CallerTokenTTL = time.Minute
+
+// InvokeOperations handles the invocation of the functions of other cocoons by the cocoon code
+type InvokeOperations struct {
+	log           *logging.Logger
+	spec          *types.Spec
+	platform      Platform
+	scheduler     scheduler.Scheduler
+	signKey       []byte
+	cocoonCodeOps *CocoonCodeOperations
+}
+
+// NewInvokeOperationHandler creates a new InvokeOperations instance for the cocoon described
+// by spec. The connectors of other cocoons are found through the service discovery of sched.
+// Connectors authenticate the cocoons invoking functions of other cocoons with tokens signed
+// with signKey, a key shared by the connectors but not by cocoon codes. Functions of other
+// cocoons cannot be invoked, nor invoked by other cocoons, without a key.
+func NewInvokeOperationHandler(log *logging.Logger, spec *types.Spec, platform Platform, sched scheduler.Scheduler, signKey []byte, cocoonCodeOps *CocoonCodeOperations) *InvokeOperations {
+	return &InvokeOperations{
+		log:           log,
+		spec:          spec,
+		platform:      platform,
+		scheduler:     sched,
+		signKey:       signKey,
+		cocoonCodeOps: cocoonCodeOps,
+	}
+}
+
+// makeCallerToken creates the token that authenticates the cocoon
+// of the connector as the invoker of an operation of another cocoon
+func (o *InvokeOperations) makeCallerToken(op *proto_connector.CocoonCodeOperation) (string, error) {
+	if len(o.signKey) == 0 {
+		return "", fmt.Errorf("connector sign key is not set")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
+		"type":   callerTokenType,
+		"caller": o.spec.ID,
+		"cocoon": op.GetLinkTo(),
+		"opID":   op.GetID(),
+		"exp":    time.Now().Add(CallerTokenTTL).Unix(),
+	})
+	return token.SignedString(o.signKey)
+}
+
+// getCaller returns the id of the cocoon authenticated by the token of an invoke
+// operation forwarded by another connector. It returns an empty string if the
+// operation was not forwarded. The token must have been issued for the operation
+// and the cocoon of the connector.
+func (o *InvokeOperations) getCaller(ctx context.Context, op *proto_connector.CocoonCodeOperation) (string, error) {
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[callerTokenKey]) == 0 {
+		return "", nil
+	}
+
+	errInvalidToken := fmt.Errorf("permission denied: invalid caller token")
+	if len(o.signKey) == 0 {
+		return "", errInvalidToken
+	}
+
+	token, err := jwt.Parse(md[callerTokenKey][0], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return o.signKey, nil
+	})
+	if err != nil || !token.Valid {
+		return "", errInvalidToken
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	caller, _ := claims["caller"].(string)
+	if claims["type"] != callerTokenType || claims["cocoon"] != o.spec.ID || claims["opID"] != op.GetID() || len(caller) == 0 {
+		return "", errInvalidToken
+	}
+
+	return caller, nil
+}
+
+// withoutCallerToken removes the caller token from the metadata of an operation
+func withoutCallerToken(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[callerTokenKey]) == 0 {
+		return ctx
+	}
+	md = md.Copy()
+	delete(md, callerTokenKey)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// Handle handles an invoke operation. An operation of the cocoon code linked to
+// another cocoon is forwarded to a connector of that cocoon. An operation
+// forwarded by another connector is authenticated by its caller token, checked
+// against the ACL of the cocoon and passed to the cocoon code, with the id of the
+// invoking cocoon in its header. Operations with an invalid token are rejected and
+// the token of any operation is removed before it is handled.
+// The deadline of the operation is propagated to the cocoon code and shortened
+// to the timeout set for the function in the release of the cocoon.
+func (o *InvokeOperations) Handle(ctx context.Context, op *proto_connector.CocoonCodeOperation) (*proto_connector.Response, error) {
+
+	cocoonID := o.spec.ID
+
+	caller, err := o.getCaller(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withoutCallerToken(ctx)
+
+	if len(caller) == 0 {
+		if len(op.GetLinkTo()) > 0 && op.GetLinkTo() != cocoonID {
+			return o.forward(ctx, op)
+		}
+		caller = cocoonID
+	} else if err := o.checkACL(ctx, caller, op.GetFunction()); err != nil {
+		return nil, err
+	}
+
+	ctx, cc := context.WithTimeout(ctx, o.spec.Release.Timeouts.Get(op.GetFunction()))
+	defer cc()
+
+	return o.cocoonCodeOps.Handle(ctx, &proto_connector.CocoonCodeOperation{
+		ID:       op.GetID(),
+		Function: op.GetFunction(),
+		Params:   op.GetParams(),
+		Header: map[string]string{
+			"Transaction-Id":           op.GetID(),
+			types.HeaderCallerCocoonID: caller,
+		},
+	})
+}
+
+// forward sends an invoke operation to a connector of the linked cocoon
+func (o *InvokeOperations) forward(ctx context.Context, op *proto_connector.CocoonCodeOperation) (*proto_connector.Response, error) {
+
+	if op.GetLinkTo() == types.SystemCocoonID {
+		return nil, fmt.Errorf("system cocoon functions cannot be invoked")
+	}
+
+	token, err := o.makeCallerToken(op)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := o.getConnectorAddr(op.GetLinkTo())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach linked cocoon: %s", err)
+	}
+	defer conn.Close()
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(callerTokenKey, token))
+	resp, err := proto_connector.NewConnectorClient(conn).Transact(ctx, &proto_connector.Request{
+		OpType: proto_connector.OpType_CocoonCodeOp,
+		CocoonCodeOp: &proto_connector.CocoonCodeOperation{
+			ID:       op.GetID(),
+			Function: op.GetFunction(),
+			Params:   op.GetParams(),
+			LinkTo:   op.GetLinkTo(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s", common.GetRPCErrDesc(err))
+	}
+
+	return resp, nil
+}
+
+// getConnectorAddr finds the RPC address of a connector of a cocoon
+func (o *InvokeOperations) getConnectorAddr(cocoonID string) (string, error) {
+
+	sd, err := o.scheduler.GetServiceDiscoverer()
+	if err != nil {
+		return "", err
+	}
+
+	services, err := sd.GetByID(scheduler.ConnectorServiceName, map[string]string{"tag": cocoonID})
+	if err != nil {
+		return "", err
+	} else if len(services) == 0 {
+		return "", fmt.Errorf("linked cocoon is not running")
+	}
+
+	service := services[rand.Intn(len(services))]
+	return fmt.Sprintf("%s:%d", service.IP, service.Port), nil
+}
+
+// checkACL checks whether a cocoon is allowed to invoke a function of the cocoon code.
+// A natively linked cocoon is allowed. Otherwise, the ACL of the cocoon must grant
+// the invoke privilege on the function to the invoking cocoon.
+func (o *InvokeOperations) checkACL(ctx context.Context, callerID, function string) error {
+
+	_, release, err := o.platform.GetCocoonAndRelease(ctx, o.spec.ID, o.spec.ReleaseID, false)
+	if err != nil {
+		return err
+	}
+
+	_, callerRelease, err := o.platform.GetCocoonAndLastActiveRelease(ctx, callerID, false)
+	if err != nil {
+		if common.CompareErr(err, types.ErrCocoonNotFound) == 0 {
+			return fmt.Errorf("calling cocoon not found")
+		}
+		return err
+	}
+
+	// natively linked cocoon currently have same privilege as the linked cocoon
+	if callerRelease.Link == o.spec.ID {
+		return nil
+	}
+
+	i := acl.NewInterpreterFromACLMap(release.ACL, false)
+	if errs := i.Validate(); len(errs) != 0 {
+		return fmt.Errorf("cocoon ACL is not valid")
+	}
+	if !i.IsAllowed(acl.FunctionKey(function), callerID, types.TxInvoke) {
+		return fmt.Errorf("permission denied: %s operation not allowed", types.TxInvoke)
+	}
+
+	return nil
+}
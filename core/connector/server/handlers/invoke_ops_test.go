@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCallerToken(t *testing.T) {
+	Convey("InvokeOperations", t, func() {
+
+		key := []byte("sign-key")
+		caller := NewInvokeOperationHandler(nil, &types.Spec{ID: "caller"}, nil, nil, key, nil)
+		callee := NewInvokeOperationHandler(nil, &types.Spec{ID: "callee"}, nil, nil, key, nil)
+		op := &proto_connector.CocoonCodeOperation{ID: "op1", Function: "fn", LinkTo: "callee"}
+
+		withToken := func(token string) context.Context {
+			return metadata.NewIncomingContext(context.Background(), metadata.Pairs(callerTokenKey, token))
+		}
+
+		Convey(".getCaller", func() {
+
+			Convey("Should return the cocoon authenticated by the token of a forwarded operation", func() {
+				token, err := caller.makeCallerToken(op)
+				So(err, ShouldBeNil)
+				callerID, err := callee.getCaller(withToken(token), op)
+				So(err, ShouldBeNil)
+				So(callerID, ShouldEqual, "caller")
+			})
+
+			Convey("Should return an empty caller if the operation was not forwarded", func() {
+				callerID, err := callee.getCaller(context.Background(), op)
+				So(err, ShouldBeNil)
+				So(callerID, ShouldBeEmpty)
+			})
+
+			Convey("Should return error if the token is not signed with the key of the connectors", func() {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.MapClaims{
+					"type":   callerTokenType,
+					"caller": "forged",
+					"cocoon": "callee",
+					"opID":   "op1",
+				}).SignedString([]byte("other-key"))
+				So(err, ShouldBeNil)
+				_, err = callee.getCaller(withToken(token), op)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "permission denied: invalid caller token")
+			})
+
+			Convey("Should return error if the token is not a signed token", func() {
+				_, err := callee.getCaller(withToken("forged"), op)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should return error if the token was issued for another cocoon", func() {
+				token, err := caller.makeCallerToken(&proto_connector.CocoonCodeOperation{ID: "op1", LinkTo: "other"})
+				So(err, ShouldBeNil)
+				_, err = callee.getCaller(withToken(token), op)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should return error if the token was issued for another operation", func() {
+				token, err := caller.makeCallerToken(&proto_connector.CocoonCodeOperation{ID: "op2", LinkTo: "callee"})
+				So(err, ShouldBeNil)
+				_, err = callee.getCaller(withToken(token), op)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should return error if the connector has no sign key", func() {
+				token, err := caller.makeCallerToken(op)
+				So(err, ShouldBeNil)
+				noKey := NewInvokeOperationHandler(nil, &types.Spec{ID: "callee"}, nil, nil, nil, nil)
+				_, err = noKey.getCaller(withToken(token), op)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey(".makeCallerToken should return error if the connector has no sign key", func() {
+			noKey := NewInvokeOperationHandler(nil, &types.Spec{ID: "caller"}, nil, nil, nil, nil)
+			_, err := noKey.makeCallerToken(op)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("withoutCallerToken should remove the caller token from the metadata", func() {
+			ctx := withoutCallerToken(withToken("token"))
+			md, _ := metadata.FromIncomingContext(ctx)
+			So(md[callerTokenKey], ShouldBeEmpty)
+		})
+	})
+}
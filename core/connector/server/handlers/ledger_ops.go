@@ -2,14 +2,14 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"strconv"
+	"sync"
 
 	"github.com/ellcrys/util"
 	"github.com/ellcrys/cocoon/core/common"
-	"github.com/ellcrys/cocoon/core/connector/connector"
 	"github.com/ellcrys/cocoon/core/connector/server/acl"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
-	"github.com/ellcrys/cocoon/core/orderer/orderer"
 	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
 	"github.com/ellcrys/cocoon/core/types"
 	logging "github.com/op/go-logging"
@@ -18,19 +18,26 @@ import (
 
 // LedgerOperations represents a ledger operation handler
 type LedgerOperations struct {
-	CocoonID         string
-	ordererDiscovery *orderer.Discovery
-	connector        *connector.Connector
-	log              *logging.Logger
+	sync.Mutex
+	CocoonID     string
+	releaseID    string
+	platform     Platform
+	orderers     OrdererClients
+	log          *logging.Logger
+	eventLedgers map[string]bool
 }
 
 // NewLedgerOperationHandler creates a new instance of a ledger operation handler
-func NewLedgerOperationHandler(log *logging.Logger, connector *connector.Connector) *LedgerOperations {
+// for the cocoon described by spec. Operations are checked against the cocoons
+// and releases of the platform and sent to the orderers.
+func NewLedgerOperationHandler(log *logging.Logger, spec *types.Spec, platform Platform, orderers OrdererClients) *LedgerOperations {
 	return &LedgerOperations{
-		connector:        connector,
-		CocoonID:         connector.GetSpec().ID,
-		ordererDiscovery: connector.GetOrdererDiscoverer(),
-		log:              log,
+		CocoonID:     spec.ID,
+		releaseID:    spec.ReleaseID,
+		platform:     platform,
+		orderers:     orderers,
+		log:          log,
+		eventLedgers: make(map[string]bool),
 	}
 }
 
@@ -55,7 +62,7 @@ func (l *LedgerOperations) checkACL(ctx context.Context, op *proto_connector.Led
 	// Handle links to other cocoon code
 	if op.LinkTo != l.CocoonID {
 
-		_, callingCocoonRelease, err := l.connector.Platform.GetCocoonAndRelease(ctx, l.CocoonID, l.connector.GetSpec().ReleaseID, false)
+		_, callingCocoonRelease, err := l.platform.GetCocoonAndRelease(ctx, l.CocoonID, l.releaseID, false)
 		if err != nil {
 			if common.CompareErr(err, types.ErrCocoonNotFound) == 0 {
 				return fmt.Errorf("calling cocoon not found")
@@ -63,7 +70,7 @@ func (l *LedgerOperations) checkACL(ctx context.Context, op *proto_connector.Led
 			return err
 		}
 
-		_, linkedCocoonRelease, err := l.connector.Platform.GetCocoonAndLastActiveRelease(ctx, op.LinkTo, false)
+		_, linkedCocoonRelease, err := l.platform.GetCocoonAndLastActiveRelease(ctx, op.LinkTo, false)
 		if err != nil {
 			if common.CompareErr(err, types.ErrCocoonNotFound) == 0 {
 				return fmt.Errorf("linked cocoon not found")
@@ -106,6 +113,21 @@ func (l *LedgerOperations) checkACL(ctx context.Context, op *proto_connector.Led
 // Handle handles all types of ledger operations
 func (l *LedgerOperations) Handle(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
 
+	// a multi-ledger put is checked against the ACL of each of its ledgers
+	if op.GetName() == types.TxPutMulti {
+		return l.putMulti(ctx, op)
+	}
+
+	// the event ledger is only written through emit operations
+	if len(op.GetParams()) > 0 && op.GetParams()[0] == types.EventLedgerName {
+		switch op.GetName() {
+		case types.TxEmit:
+			return l.emit(ctx, op)
+		case types.TxPut, types.TxDelete:
+			return nil, fmt.Errorf("events can only be recorded by emitting them")
+		}
+	}
+
 	if err := l.checkACL(ctx, op); err != nil {
 		return nil, err
 	}
@@ -115,14 +137,26 @@ func (l *LedgerOperations) Handle(ctx context.Context, op *proto_connector.Ledge
 		return l.createLedger(ctx, op)
 	case types.TxGetLedger:
 		return l.getLedger(ctx, op)
-	case types.TxPut:
+	case types.TxPut, types.TxDelete:
 		return l.put(ctx, op)
 	case types.TxGet:
 		return l.get(ctx, op)
 	case types.TxGetBlockByID:
 		return l.getBlock(ctx, op)
+	case types.TxGetBlockByNumber:
+		return l.getBlockByNumber(ctx, op)
+	case types.TxGetChainHeight:
+		return l.getChainHeight(ctx, op)
+	case types.TxListBlocks:
+		return l.listBlocks(ctx, op)
 	case types.TxRangeGet:
 		return l.getRange(ctx, op)
+	case types.TxQuery:
+		return l.query(ctx, op)
+	case types.TxGetHistory:
+		return l.getHistory(ctx, op)
+	case types.TxGetTxProof:
+		return l.getTxProof(ctx, op)
 	default:
 		return nil, fmt.Errorf("unsupported operation [%s]", op.GetName())
 	}
@@ -141,18 +175,26 @@ func (l *LedgerOperations) createLedger(ctx context.Context, op *proto_connector
 		return nil, types.ErrInvalidResourceName
 	}
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	// the secondary indexes of the ledger are optional
+	var indexes []*proto_orderer.Index
+	if len(op.GetParams()) > 3 && len(op.GetParams()[3]) > 0 {
+		if err := util.FromJSON([]byte(op.GetParams()[3]), &indexes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ledger indexes")
+		}
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
 	if err != nil {
 		return nil, err
 	}
-	defer ordererConn.Close()
+	defer closeConn()
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	result, err := odc.CreateLedger(ctx, &proto_orderer.CreateLedgerParams{
 		CocoonID: cocoonID,
 		Name:     op.GetParams()[0],
 		Chained:  op.GetParams()[1] == "true",
 		Public:   op.GetParams()[2] == "true",
+		Indexes:  indexes,
 	})
 
 	if err != nil {
@@ -171,13 +213,12 @@ func (l *LedgerOperations) createLedger(ctx context.Context, op *proto_connector
 // _getLedger fetches a ledger
 func (l *LedgerOperations) _getLedger(ctx context.Context, cocoonID, ledgerName string) (*proto_orderer.Ledger, error) {
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	odc, closeConn, err := l.orderers.GetClient()
 	if err != nil {
 		return nil, err
 	}
-	defer ordererConn.Close()
+	defer closeConn()
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	result, err := odc.GetLedger(ctx, &proto_orderer.GetLedgerParams{
 		Name:     ledgerName,
 		CocoonID: cocoonID,
@@ -211,7 +252,76 @@ func (l *LedgerOperations) getLedger(ctx context.Context, op *proto_connector.Le
 	}, nil
 }
 
-// put adds a new transaction to a ledger
+// ensureEventLedger creates the event ledger of a cocoon if it does not exist.
+// The event ledger is chained and public; other cocoons can read and subscribe
+// to it unless the ACL of the cocoon denies them.
+func (l *LedgerOperations) ensureEventLedger(ctx context.Context, cocoonID string) error {
+
+	l.Lock()
+	ready := l.eventLedgers[cocoonID]
+	l.Unlock()
+	if ready {
+		return nil
+	}
+
+	_, err := l._getLedger(ctx, cocoonID, types.EventLedgerName)
+	if err != nil && common.CompareErr(err, types.ErrLedgerNotFound) != 0 {
+		return err
+	} else if err != nil {
+
+		odc, closeConn, err := l.orderers.GetClient()
+		if err != nil {
+			return err
+		}
+		defer closeConn()
+
+		if _, err = odc.CreateLedger(ctx, &proto_orderer.CreateLedgerParams{
+			CocoonID: cocoonID,
+			Name:     types.EventLedgerName,
+			Chained:  true,
+			Public:   true,
+		}); err != nil {
+
+			// the ledger may have been created by another connector of the cocoon
+			if _, getErr := l._getLedger(ctx, cocoonID, types.EventLedgerName); getErr != nil {
+				return fmt.Errorf("failed to create event ledger: %s", err)
+			}
+		}
+	}
+
+	l.Lock()
+	l.eventLedgers[cocoonID] = true
+	l.Unlock()
+	return nil
+}
+
+// checkEmit checks whether events can be emitted on behalf of a cocoon.
+// Only the cocoon code can emit its events.
+func (l *LedgerOperations) checkEmit(ctx context.Context, cocoonID string) error {
+	if cocoonID != l.CocoonID {
+		return fmt.Errorf("permission denied: events can only be emitted by the cocoon")
+	}
+	return l.ensureEventLedger(ctx, cocoonID)
+}
+
+// emit records events in the event ledger of the cocoon
+func (l *LedgerOperations) emit(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	if err := l.checkEmit(ctx, cocoonID); err != nil {
+		return nil, err
+	}
+
+	return l.put(ctx, op)
+}
+
+// put adds new transactions to a ledger. For delete operations,
+// every transaction must be a tombstone, while put operations
+// cannot include tombstones.
 func (l *LedgerOperations) put(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
 
 	var cocoonID = l.CocoonID
@@ -223,11 +333,11 @@ func (l *LedgerOperations) put(ctx context.Context, op *proto_connector.LedgerOp
 		return nil, err
 	}
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	odc, closeConn, err := l.orderers.GetLeaderClient(cocoonID, op.GetParams()[0])
 	if err != nil {
 		return nil, err
 	}
-	defer ordererConn.Close()
+	defer closeConn()
 
 	var txs []*proto_orderer.Transaction
 	err = util.FromJSON(op.GetBody(), &txs)
@@ -236,13 +346,17 @@ func (l *LedgerOperations) put(ctx context.Context, op *proto_connector.LedgerOp
 	}
 
 	// validate transaction key
-	for _, tx := range txs {
+	for i, tx := range txs {
 		if !common.IsValidResName(tx.Key) {
-			return nil, fmt.Errorf("tx 0: %s", types.ErrInvalidResourceName)
+			return nil, fmt.Errorf("tx %d: %s", i, types.ErrInvalidResourceName)
+		}
+		if op.GetName() == types.TxDelete && !tx.Deleted {
+			return nil, fmt.Errorf("tx %d: not a tombstone transaction", i)
+		} else if op.GetName() != types.TxDelete && tx.Deleted {
+			return nil, fmt.Errorf("tx %d: tombstone transactions require a delete operation", i)
 		}
 	}
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	result, err := odc.Put(ctx, &proto_orderer.PutTransactionParams{
 		CocoonID:     cocoonID,
 		LedgerName:   op.GetParams()[0],
@@ -250,6 +364,91 @@ func (l *LedgerOperations) put(ctx context.Context, op *proto_connector.LedgerOp
 	})
 
 	if err != nil {
+		l.orderers.InvalidateLeader(cocoonID, op.GetParams()[0])
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(result)
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// putMulti atomically adds new transactions to several ledgers. The operation
+// must be allowed by the ACL of each ledger: put operations for transactions
+// and delete operations for tombstones.
+func (l *LedgerOperations) putMulti(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	var ledgers []*proto_orderer.LedgerTransactions
+	if err := util.FromJSON(op.GetBody(), &ledgers); err != nil {
+		return nil, fmt.Errorf("failed to coerce transactions from bytes to order_proto.LedgerTransactions")
+	}
+
+	for _, group := range ledgers {
+
+		// events of the batch are recorded in the event ledger of the cocoon
+		if group.GetLedger() == types.EventLedgerName {
+			if err := l.checkEmit(ctx, cocoonID); err != nil {
+				return nil, err
+			}
+			for i, tx := range group.GetTransactions() {
+				if tx.Deleted {
+					return nil, fmt.Errorf("%s: tx %d: events cannot be deleted", group.GetLedger(), i)
+				}
+			}
+			continue
+		}
+
+		hasPut, hasDelete := false, false
+		for i, tx := range group.GetTransactions() {
+			if !common.IsValidResName(tx.Key) {
+				return nil, fmt.Errorf("%s: tx %d: %s", group.GetLedger(), i, types.ErrInvalidResourceName)
+			}
+			hasDelete = hasDelete || tx.Deleted
+			hasPut = hasPut || !tx.Deleted
+		}
+
+		for _, opName := range []string{types.TxPut, types.TxDelete} {
+			if (opName == types.TxPut && !hasPut) || (opName == types.TxDelete && !hasDelete) {
+				continue
+			}
+			if err := l.checkACL(ctx, &proto_connector.LedgerOperation{
+				ID:     op.GetID(),
+				Name:   opName,
+				LinkTo: op.GetLinkTo(),
+				Params: []string{group.GetLedger()},
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var ledgerNames []string
+	for _, group := range ledgers {
+		ledgerNames = append(ledgerNames, group.GetLedger())
+	}
+
+	odc, closeConn, err := l.orderers.GetLeaderClient(cocoonID, ledgerNames...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	result, err := odc.PutMulti(ctx, &proto_orderer.PutMultiParams{
+		CocoonID: cocoonID,
+		Ledgers:  ledgers,
+	})
+	if err != nil {
+		for _, ledgerName := range ledgerNames {
+			l.orderers.InvalidateLeader(cocoonID, ledgerName)
+		}
 		return nil, err
 	}
 
@@ -273,17 +472,17 @@ func (l *LedgerOperations) get(ctx context.Context, op *proto_connector.LedgerOp
 		cocoonID = op.GetLinkTo()
 	}
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	odc, closeConn, err := l.orderers.GetClient()
 	if err != nil {
 		return nil, err
 	}
-	defer ordererConn.Close()
+	defer closeConn()
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	result, err = odc.Get(ctx, &proto_orderer.GetParams{
-		CocoonID: cocoonID,
-		Ledger:   op.GetParams()[0],
-		Key:      op.GetParams()[1],
+		CocoonID:       cocoonID,
+		Ledger:         op.GetParams()[0],
+		Key:            op.GetParams()[1],
+		IncludeDeleted: len(op.GetParams()) > 2 && op.GetParams()[2] == "true",
 	})
 
 	if err != nil {
@@ -307,13 +506,12 @@ func (l *LedgerOperations) getBlock(ctx context.Context, op *proto_connector.Led
 		cocoonID = op.GetLinkTo()
 	}
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	odc, closeConn, err := l.orderers.GetClient()
 	if err != nil {
 		return nil, err
 	}
-	defer ordererConn.Close()
+	defer closeConn()
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	block, err := odc.GetBlockByID(ctx, &proto_orderer.GetBlockParams{
 		CocoonID: cocoonID,
 		Ledger:   op.GetParams()[0],
@@ -333,7 +531,8 @@ func (l *LedgerOperations) getBlock(ctx context.Context, op *proto_connector.Led
 	}, nil
 }
 
-// getRange fetches transactions with keys between a specified range.
+// getRange fetches transactions with keys between a specified range
+// or matching a prefix. The response includes a cursor for the next page.
 func (l *LedgerOperations) getRange(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
 
 	var cocoonID = l.CocoonID
@@ -341,24 +540,248 @@ func (l *LedgerOperations) getRange(ctx context.Context, op *proto_connector.Led
 		cocoonID = op.GetLinkTo()
 	}
 
-	ordererConn, err := l.ordererDiscovery.GetGRPConn()
+	// optional parameters: include deleted, prefix, reverse and cursor
+	if len(op.GetParams()) < 6 {
+		return nil, fmt.Errorf("invalid range parameters")
+	}
+	var params = make([]string, 10)
+	copy(params, op.GetParams())
+
+	limit, err := strconv.Atoi(params[4])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid limit")
 	}
-	defer ordererConn.Close()
 
-	limit, _ := strconv.Atoi(op.GetParams()[4])
-	offset, _ := strconv.Atoi(op.GetParams()[5])
+	offset, err := strconv.Atoi(params[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid offset")
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
 
-	odc := proto_orderer.NewOrdererClient(ordererConn)
 	txs, err := odc.GetRange(ctx, &proto_orderer.GetRangeParams{
-		CocoonID:  cocoonID,
-		Ledger:    op.GetParams()[0],
-		StartKey:  op.GetParams()[1],
-		EndKey:    op.GetParams()[2],
-		Inclusive: op.GetParams()[3] == "true",
-		Limit:     int32(limit),
-		Offset:    int32(offset),
+		CocoonID:       cocoonID,
+		Ledger:         params[0],
+		StartKey:       params[1],
+		EndKey:         params[2],
+		Inclusive:      params[3] == "true",
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+		IncludeDeleted: params[6] == "true",
+		Prefix:         params[7],
+		Reverse:        params[8] == "true",
+		Cursor:         params[9],
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(txs)
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// query fetches the transactions of the keys matching a query on a secondary index
+func (l *LedgerOperations) query(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	// the limit and cursor are optional
+	if len(op.GetParams()) < 4 {
+		return nil, fmt.Errorf("invalid query parameters")
+	}
+	var params = make([]string, 6)
+	copy(params, op.GetParams())
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	limit, _ := strconv.Atoi(params[4])
+
+	txs, err := odc.Query(ctx, &proto_orderer.QueryParams{
+		CocoonID: cocoonID,
+		Ledger:   params[0],
+		Index:    params[1],
+		Op:       params[2],
+		Value:    params[3],
+		Limit:    int32(limit),
+		Cursor:   params[5],
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(txs)
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// getBlockByNumber gets a block by its ledger name and number
+func (l *LedgerOperations) getBlockByNumber(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	number, err := strconv.ParseInt(op.GetParams()[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number")
+	}
+
+	block, err := odc.GetBlockByNumber(ctx, &proto_orderer.GetBlockByNumberParams{
+		CocoonID: cocoonID,
+		Ledger:   op.GetParams()[0],
+		Number:   number,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(block)
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// getChainHeight gets the number of the last block of a ledger
+func (l *LedgerOperations) getChainHeight(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	height, err := odc.GetChainHeight(ctx, &proto_orderer.GetChainHeightParams{
+		CocoonID: cocoonID,
+		Ledger:   op.GetParams()[0],
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   []byte(strconv.FormatInt(height.GetHeight(), 10)),
+	}, nil
+}
+
+// listBlocks gets the blocks of a ledger starting from a block number
+func (l *LedgerOperations) listBlocks(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	fromNumber, err := strconv.ParseInt(op.GetParams()[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number")
+	}
+
+	limit, err := strconv.Atoi(op.GetParams()[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit")
+	}
+
+	blocks, err := odc.ListBlocks(ctx, &proto_orderer.ListBlocksParams{
+		CocoonID:   cocoonID,
+		Ledger:     op.GetParams()[0],
+		FromNumber: fromNumber,
+		Limit:      int32(limit),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(blocks.Blocks)
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// getHistory fetches the previous transactions of a key.
+func (l *LedgerOperations) getHistory(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	if len(op.GetParams()) < 4 {
+		return nil, fmt.Errorf("invalid history parameters")
+	}
+
+	limit, err := strconv.Atoi(op.GetParams()[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit")
+	}
+
+	cursor, err := strconv.ParseUint(op.GetParams()[3], 10, 63)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	txs, err := odc.GetHistory(ctx, &proto_orderer.GetHistoryParams{
+		CocoonID: cocoonID,
+		Ledger:   op.GetParams()[0],
+		Key:      op.GetParams()[1],
+		Limit:    int32(limit),
+		Cursor:   int64(cursor),
 	})
 
 	if err != nil {
@@ -373,3 +796,110 @@ func (l *LedgerOperations) getRange(ctx context.Context, op *proto_connector.Led
 		Body:   body,
 	}, nil
 }
+
+// getTxProof gets the proof of inclusion of a transaction in its block
+func (l *LedgerOperations) getTxProof(ctx context.Context, op *proto_connector.LedgerOperation) (*proto_connector.Response, error) {
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	proof, err := odc.GetTxProof(ctx, &proto_orderer.GetTxProofParams{
+		CocoonID: cocoonID,
+		Ledger:   op.GetParams()[0],
+		TxID:     op.GetParams()[1],
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := util.ToJSON(proof)
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: 200,
+		Body:   body,
+	}, nil
+}
+
+// Subscribe subscribes to the changes of a ledger and calls send for every ledger event
+// received from the orderer. It returns when the subscription ends or send returns an error.
+func (l *LedgerOperations) Subscribe(ctx context.Context, op *proto_connector.LedgerOperation, send func(*proto_connector.Response) error) error {
+
+	if op.GetName() != types.TxSubscribe {
+		return fmt.Errorf("unsupported operation [%s]", op.GetName())
+	} else if len(op.GetParams()) == 0 {
+		return fmt.Errorf("ledger name is required")
+	}
+
+	var cocoonID = l.CocoonID
+	if len(op.GetLinkTo()) > 0 {
+		cocoonID = op.GetLinkTo()
+	}
+
+	// the event ledger of a cocoon that has not emitted events
+	// is created so that its first events are received
+	if op.GetParams()[0] == types.EventLedgerName && cocoonID != types.SystemCocoonID {
+		if cocoonID != l.CocoonID {
+			if _, _, err := l.platform.GetCocoonAndLastActiveRelease(ctx, cocoonID, false); err != nil {
+				if common.CompareErr(err, types.ErrCocoonNotFound) == 0 {
+					return fmt.Errorf("linked cocoon not found")
+				}
+				return err
+			}
+		}
+		if err := l.ensureEventLedger(ctx, cocoonID); err != nil {
+			return err
+		}
+	}
+
+	if err := l.checkACL(ctx, op); err != nil {
+		return err
+	}
+
+	params := append(op.GetParams(), make([]string, 3)...)[:3]
+	fromBlock, _ := strconv.ParseInt(params[2], 10, 64)
+
+	odc, closeConn, err := l.orderers.GetClient()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	stream, err := odc.Subscribe(ctx, &proto_orderer.SubscribeParams{
+		CocoonID:  cocoonID,
+		Ledger:    params[0],
+		Prefix:    params[1],
+		FromBlock: fromBlock,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		body, _ := util.ToJSON(event)
+		if err := send(&proto_connector.Response{
+			ID:     op.GetID(),
+			Status: 200,
+			Body:   body,
+		}); err != nil {
+			return err
+		}
+	}
+}
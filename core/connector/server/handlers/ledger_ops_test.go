@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+func TestLedgerOperations(t *testing.T) {
+	Convey("LedgerOperations", t, func() {
+
+		l := NewLedgerOperationHandler(nil, &types.Spec{ID: "cocoon"}, nil, nil)
+		ctx := context.Background()
+
+		Convey(".getRange", func() {
+			Convey("Should return error if the parameters are missing", func() {
+				_, err := l.getRange(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "a", "z", "false"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid range parameters")
+			})
+
+			Convey("Should return error if the limit or offset is not a number", func() {
+				_, err := l.getRange(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "a", "z", "false", "ten", "0"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid limit")
+
+				_, err = l.getRange(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "a", "z", "false", "10", ""}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid offset")
+			})
+		})
+
+		Convey(".getHistory", func() {
+			Convey("Should return error if the parameters are missing", func() {
+				_, err := l.getHistory(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "key"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid history parameters")
+			})
+
+			Convey("Should return error if the limit is not a number", func() {
+				_, err := l.getHistory(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "key", "ten", "0"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "invalid limit")
+			})
+
+			Convey("Should return error if the cursor is not valid", func() {
+				for _, cursor := range []string{"", "next", "-1"} {
+					_, err := l.getHistory(ctx, &proto_connector.LedgerOperation{Params: []string{"ledger", "key", "10", cursor}})
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, "invalid cursor")
+				}
+			})
+		})
+	})
+}
@@ -3,29 +3,56 @@ package handlers
 import (
 	"fmt"
 	"strconv"
-	"time"
-
 	"strings"
+	"time"
 
 	"github.com/ellcrys/cocoon/core/common"
-	"github.com/ellcrys/cocoon/core/connector/connector"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
 	"github.com/ellcrys/cocoon/core/types"
 	logging "github.com/op/go-logging"
 	context "golang.org/x/net/context"
 )
 
+// LockFunc creates a lock on a key of a cocoon.
+// The default ttl of the lock is used if ttl is zero.
+type LockFunc func(cocoonID, key string, ttl time.Duration) (types.Lock, error)
+
+// NewLock creates a lock on a key of a cocoon using the lock backend of the platform
+func NewLock(cocoonID, key string, ttl time.Duration) (types.Lock, error) {
+	var lock types.Lock
+	var err error
+	if ttl > 0 {
+		lock, err = common.NewLockWithTTL(key, ttl)
+	} else {
+		lock, err = common.NewLock(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock.SetState(map[string]interface{}{
+		"lock_key_prefix": fmt.Sprintf("platform/lock/%s", cocoonID),
+	})
+	return lock, nil
+}
+
 // LockOperations defines a structure for handling lock operations from the cocoon code
 type LockOperations struct {
 	log       *logging.Logger
-	connector *connector.Connector
+	cocoonID  string
+	releaseID string
+	platform  Platform
+	newLock   LockFunc
 }
 
-// NewLockOperationHandler creates a new LockOperations instance
-func NewLockOperationHandler(log *logging.Logger, connector *connector.Connector) *LockOperations {
+// NewLockOperationHandler creates a new LockOperations instance for the cocoon
+// described by spec. Locks are created with newLock.
+func NewLockOperationHandler(log *logging.Logger, spec *types.Spec, platform Platform, newLock LockFunc) *LockOperations {
 	return &LockOperations{
 		log:       log,
-		connector: connector,
+		cocoonID:  spec.ID,
+		releaseID: spec.ReleaseID,
+		platform:  platform,
+		newLock:   newLock,
 	}
 }
 
@@ -38,17 +65,17 @@ func (l *LockOperations) checkPermission(ctx context.Context, op *proto_connecto
 
 	// Target cocoon is the system cocoon
 	if op.LinkTo == types.SystemCocoonID {
-		if strings.HasPrefix(op.Params[1], "_") {
+		if len(op.Params) > 1 && strings.HasPrefix(op.Params[1], "_") {
 			return fmt.Errorf("reserved key error: cannot create/access a lock with key starting with an underscore on system cocoon")
 		}
 		return nil
 	}
 
 	// Target cocoon is not the same as the current cocoon
-	if op.LinkTo != l.connector.GetSpec().ID {
+	if op.LinkTo != l.cocoonID {
 
 		// get the currently executing release of the current cocoon
-		_, release, err := l.connector.Platform.GetCocoonAndRelease(ctx, l.connector.GetSpec().ID, l.connector.GetSpec().ReleaseID, false)
+		_, release, err := l.platform.GetCocoonAndRelease(ctx, l.cocoonID, l.releaseID, false)
 		if err != nil {
 			if common.CompareErr(err, types.ErrCocoonNotFound) == 0 {
 				return fmt.Errorf("calling cocoon not found")
@@ -105,13 +132,12 @@ func (l *LockOperations) acquire(op *proto_connector.LockOperation) (*proto_conn
 	ttlDur := time.Duration(ttl) * time.Second
 
 	// create lock and set default state
-	lock, err := common.NewLockWithTTL(op.Params[1], ttlDur)
+	lock, err := l.newLock(op.Params[0], op.Params[1], ttlDur)
 	if err != nil {
 		return nil, err
 	}
 	lock.SetState(map[string]interface{}{
-		"lock_key_prefix": fmt.Sprintf("platform/lock/%s", op.Params[0]),
-		"lock_session":    op.Params[3],
+		"lock_session": op.Params[3],
 	})
 
 	if err := lock.Acquire(); err != nil {
@@ -145,13 +171,12 @@ func (l *LockOperations) isAcquirer(op *proto_connector.LockOperation) (*proto_c
 	}
 
 	// create lock and set default state
-	lock, err := common.NewLock(op.Params[1])
+	lock, err := l.newLock(op.Params[0], op.Params[1], 0)
 	if err != nil {
 		return nil, err
 	}
 	lock.SetState(map[string]interface{}{
-		"lock_key_prefix": fmt.Sprintf("platform/lock/%s", op.Params[0]),
-		"lock_session":    op.Params[2],
+		"lock_session": op.Params[2],
 	})
 
 	if err := lock.IsAcquirer(); err != nil {
@@ -183,13 +208,12 @@ func (l *LockOperations) release(op *proto_connector.LockOperation) (*proto_conn
 	}
 
 	// create lock and set default state
-	lock, err := common.NewLock(op.Params[1])
+	lock, err := l.newLock(op.Params[0], op.Params[1], 0)
 	if err != nil {
 		return nil, err
 	}
 	lock.SetState(map[string]interface{}{
-		"lock_key_prefix": fmt.Sprintf("platform/lock/%s", op.Params[0]),
-		"lock_session":    op.Params[2],
+		"lock_session": op.Params[2],
 	})
 
 	if err := lock.Release(); err != nil {
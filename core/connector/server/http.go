@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"time"
 
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/asaskevich/govalidator"
 	"github.com/ellcrys/cocoon/core/config"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
 	"github.com/ellcrys/util"
 	"github.com/gorilla/mux"
 	logging "github.com/op/go-logging"
@@ -77,6 +79,8 @@ func valuesToMap(values url.Values) map[string]string {
 // that will be sent to the cocoon code.
 func prepareInvokeHeader(w http.ResponseWriter, r *http.Request) http.Header {
 	header := r.Header
+	header.Del(types.HeaderCallerCocoonID)
+	header.Del(types.HeaderScheduledJob)
 	header.Set("Method", r.Method)
 	header.Set("Host", r.Host)
 	header.Set("Remote-Addr", r.RemoteAddr)
@@ -125,6 +129,8 @@ func (s *HTTP) getRouter() *mux.Router {
 	// v1 API routes
 	v1 := r.PathPrefix("/v1").Subrouter()
 	v1.HandleFunc("/invoke", s.invokeCocoonCode)
+	v1.HandleFunc("/functions", s.listFunctions).Methods("GET")
+	v1.HandleFunc("/events", s.streamEvents).Methods("GET")
 
 	// every other route should serve the index.html file
 	r.HandleFunc("/{name:.*}", func(w http.ResponseWriter, r *http.Request) {
@@ -153,6 +159,13 @@ func (s *HTTP) Start(addr string, startedCh chan bool) error {
 	return http.ListenAndServe(addr, s.getRouter())
 }
 
+// invokeContext creates the context of an invocation of a function of the cocoon
+// code. It is canceled when the client goes away or the timeout set for the
+// function in the release of the cocoon passes.
+func (s *HTTP) invokeContext(r *http.Request, function string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), s.rpc.connector.GetSpec().Release.Timeouts.Get(function))
+}
+
 // invokeCocoonCode handles cocoon code invocation
 func (s *HTTP) invokeCocoonCode(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -246,7 +259,7 @@ func (s *HTTP) invokeCocoonCode(w http.ResponseWriter, r *http.Request) {
 	preparedHeader.Set("Transaction-Id", invokeRequest.ID)
 	preparedHeader.Set("Structured", "yes")
 
-	ctx, cc = context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cc = s.invokeContext(r, invokeRequest.Function)
 	defer cc()
 	resp, err = s.rpc.cocoonCodeOps.Handle(ctx, &proto_connector.CocoonCodeOperation{
 		ID:       invokeRequest.ID,
@@ -268,9 +281,7 @@ func (s *HTTP) invokeCocoonCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.NewEncoder(w).Encode(Success{
-		Body: resp.GetBody(),
-	})
+	writeInvokeResponse(w, resp)
 	return
 
 UNSTRUCTURED:
@@ -281,7 +292,7 @@ UNSTRUCTURED:
 		preparedHeader.Set("Raw-Body", string(body))
 	}
 
-	ctx, cc = context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cc = s.invokeContext(r, "")
 	defer cc()
 	resp, err = s.rpc.cocoonCodeOps.Handle(ctx, &proto_connector.CocoonCodeOperation{
 		Header: headerToMap(preparedHeader),
@@ -296,8 +307,176 @@ UNSTRUCTURED:
 		return
 	}
 
+	writeInvokeResponse(w, resp)
+}
+
+// writeInvokeResponse writes the response of the cocoon code. A raw response
+// is written with the status, headers and body set by the cocoon code. An error
+// returned by the cocoon code with a code is written as an invoke error
+// with the status of the response. A response whose status is not a
+// valid HTTP status is written as a 500 invoke error.
+func writeInvokeResponse(w http.ResponseWriter, resp *proto_connector.Response) {
+
+	if status := resp.GetStatus(); status < 100 || status > 999 {
+		w.WriteHeader(500)
+		json.NewEncoder(w).Encode(InvokeError{
+			Error: true,
+			Code:  "invalid_status",
+			Msg:   fmt.Sprintf("cocoon code returned an invalid status: %d", status),
+		})
+		return
+	}
+
+	if resp.GetRaw() {
+		w.Header().Del("Content-Type")
+		for key, value := range resp.GetHeader() {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(int(resp.GetStatus()))
+		w.Write(resp.GetBody())
+		return
+	}
+
+	if resp.GetStatus() != 200 {
+		var invokeErr InvokeError
+		if err := util.FromJSON(resp.GetBody(), &invokeErr); err != nil || len(invokeErr.Code) == 0 {
+			invokeErr = InvokeError{Code: "code_error", Msg: string(resp.GetBody())}
+		}
+		invokeErr.Error = true
+		w.WriteHeader(int(resp.GetStatus()))
+		json.NewEncoder(w).Encode(invokeErr)
+		return
+	}
+
 	w.WriteHeader(200)
 	json.NewEncoder(w).Encode(Success{
 		Body: resp.GetBody(),
 	})
 }
+
+// listFunctions returns the catalog of the functions of the cocoon code
+func (s *HTTP) listFunctions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/json")
+
+	ctx, cc := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cc()
+	resp, err := s.rpc.cocoonCodeOps.Handle(ctx, &proto_connector.CocoonCodeOperation{
+		ID:       util.UUID4(),
+		Function: types.SysFuncListFunctions,
+	})
+	if err != nil {
+		w.WriteHeader(500)
+		json.NewEncoder(w).Encode(InvokeError{
+			Error: true,
+			Code:  "code_error",
+			Msg:   err.Error(),
+		})
+		return
+	}
+
+	w.Write(resp.GetBody())
+}
+
+// streamEvents streams the events of the cocoon as server-sent events. The
+// `name` query parameter, which can be repeated, filters the events by name.
+// Events recorded from the block set by the `from` query parameter are sent
+// before new events. The id of a server-sent event is made of the block number
+// and the index of the event (see makeEventID), so a client that reconnects with
+// the Last-Event-ID header resumes from the event after the last one received.
+func (s *HTTP) streamEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(500)
+		json.NewEncoder(w).Encode(InvokeError{
+			Error: true,
+			Code:  "server_error",
+			Msg:   "streaming is not supported",
+		})
+		return
+	}
+
+	var fromBlock, skip uint64
+	if from := r.URL.Query().Get("from"); len(from) > 0 {
+		fromBlock, _ = strconv.ParseUint(from, 10, 64)
+	} else if lastID := r.Header.Get("Last-Event-ID"); len(lastID) > 0 {
+		fromBlock, skip = parseEventID(lastID)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(200)
+	flusher.Flush()
+
+	cocoonID := s.rpc.connector.GetSpec().ID
+	names := r.URL.Query()["name"]
+	err := s.rpc.ledgerOps.Subscribe(r.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxSubscribe,
+		LinkTo: cocoonID,
+		Params: []string{types.EventLedgerName, "", strconv.FormatUint(fromBlock, 10)},
+	}, func(resp *proto_connector.Response) error {
+
+		var ledgerEvent types.LedgerEvent
+		if err := util.FromJSON(resp.GetBody(), &ledgerEvent); err != nil {
+			return fmt.Errorf("failed to unmarshal ledger event")
+		}
+
+		for _, event := range types.GetEvents(cocoonID, &ledgerEvent, names...) {
+
+			// skip the events of the first block received before the reconnection
+			if uint64(event.BlockNumber) == fromBlock && uint64(event.Index) < skip {
+				continue
+			}
+
+			data, _ := util.ToJSON(event)
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", makeEventID(event), event.Name, data); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+		return nil
+	})
+
+	// the subscription ends with an error unless the client disconnected
+	if err != nil && r.Context().Err() == nil {
+		data, _ := util.ToJSON(InvokeError{
+			Error: true,
+			Code:  "subscription_error",
+			Msg:   err.Error(),
+		})
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+		flusher.Flush()
+	}
+}
+
+// makeEventID creates the id of the server-sent event of an event.
+// It is the block number and the index of the event, as in "12-3".
+func makeEventID(event *types.Event) string {
+	return fmt.Sprintf("%d-%d", event.BlockNumber, event.Index)
+}
+
+// parseEventID returns the block to resume a stream of events from after the
+// event identified by the id of a server-sent event, and the number of events of
+// that block to skip. An id that is only a block number, as sent by previous
+// versions, identifies the last event of the block. The stream is resumed from
+// the first block if the id is not valid.
+func parseEventID(id string) (fromBlock, skip uint64) {
+	parts := strings.SplitN(id, "-", 2)
+	block, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+	if len(parts) == 1 {
+		return block + 1, 0
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+	return block, index + 1
+}
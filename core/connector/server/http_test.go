@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWriteInvokeResponse(t *testing.T) {
+	Convey("writeInvokeResponse", t, func() {
+
+		Convey("Should write a raw response with the status set by the cocoon code", func() {
+			w := httptest.NewRecorder()
+			writeInvokeResponse(w, &proto_connector.Response{Raw: true, Status: 302, Header: map[string]string{"Location": "/home"}})
+			So(w.Code, ShouldEqual, 302)
+			So(w.Header().Get("Location"), ShouldEqual, "/home")
+		})
+
+		Convey("Should write an invoke error if the status is not a valid HTTP status", func() {
+			for _, resp := range []*proto_connector.Response{
+				{Raw: true, Status: 42},
+				{Raw: true, Status: 1000},
+				{Status: -1},
+			} {
+				w := httptest.NewRecorder()
+				So(func() { writeInvokeResponse(w, resp) }, ShouldNotPanic)
+				So(w.Code, ShouldEqual, 500)
+				So(w.Body.String(), ShouldContainSubstring, `"code":"invalid_status"`)
+			}
+		})
+	})
+}
@@ -192,6 +192,7 @@ type CocoonCodeOperation struct {
 	Function string            `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
 	Params   []string          `protobuf:"bytes,3,rep,name=params" json:"params,omitempty"`
 	Header   map[string]string `protobuf:"bytes,4,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LinkTo   string            `protobuf:"bytes,5,opt,name=linkTo,proto3" json:"linkTo,omitempty"`
 }
 
 func (m *CocoonCodeOperation) Reset()                    { *m = CocoonCodeOperation{} }
@@ -227,11 +228,20 @@ func (m *CocoonCodeOperation) GetHeader() map[string]string {
 	return nil
 }
 
+func (m *CocoonCodeOperation) GetLinkTo() string {
+	if m != nil {
+		return m.LinkTo
+	}
+	return ""
+}
+
 // Response represents the response
 type Response struct {
-	ID     string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Status int32  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
-	Body   []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	ID     string            `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Status int32             `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Body   []byte            `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Header map[string]string `protobuf:"bytes,4,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Raw    bool              `protobuf:"varint,5,opt,name=raw,proto3" json:"raw,omitempty"`
 }
 
 func (m *Response) Reset()                    { *m = Response{} }
@@ -260,6 +270,20 @@ func (m *Response) GetBody() []byte {
 	return nil
 }
 
+func (m *Response) GetHeader() map[string]string {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *Response) GetRaw() bool {
+	if m != nil {
+		return m.Raw
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Request)(nil), "proto_connector.Request")
 	proto.RegisterType((*LockOperation)(nil), "proto_connector.LockOperation")
@@ -281,6 +305,7 @@ const _ = grpc.SupportPackageIsVersion4
 
 type ConnectorClient interface {
 	Transact(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	Subscribe(ctx context.Context, in *LedgerOperation, opts ...grpc.CallOption) (Connector_SubscribeClient, error)
 }
 
 type connectorClient struct {
@@ -300,10 +325,43 @@ func (c *connectorClient) Transact(ctx context.Context, in *Request, opts ...grp
 	return out, nil
 }
 
+func (c *connectorClient) Subscribe(ctx context.Context, in *LedgerOperation, opts ...grpc.CallOption) (Connector_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Connector_serviceDesc.Streams[0], c.cc, "/proto_connector.Connector/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &connectorSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Connector_SubscribeClient interface {
+	Recv() (*Response, error)
+	grpc.ClientStream
+}
+
+type connectorSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *connectorSubscribeClient) Recv() (*Response, error) {
+	m := new(Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Connector service
 
 type ConnectorServer interface {
 	Transact(context.Context, *Request) (*Response, error)
+	Subscribe(*LedgerOperation, Connector_SubscribeServer) error
 }
 
 func RegisterConnectorServer(s *grpc.Server, srv ConnectorServer) {
@@ -328,6 +386,27 @@ func _Connector_Transact_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Connector_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LedgerOperation)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConnectorServer).Subscribe(m, &connectorSubscribeServer{stream})
+}
+
+type Connector_SubscribeServer interface {
+	Send(*Response) error
+	grpc.ServerStream
+}
+
+type connectorSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *connectorSubscribeServer) Send(m *Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Connector_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto_connector.Connector",
 	HandlerType: (*ConnectorServer)(nil),
@@ -337,40 +416,49 @@ var _Connector_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Connector_Transact_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Connector_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "server.proto",
 }
 
 func init() { proto.RegisterFile("server.proto", fileDescriptorServer) }
 
 var fileDescriptorServer = []byte{
-	// 443 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x51, 0x6f, 0xd3, 0x30,
-	0x10, 0x26, 0x49, 0x1b, 0xd2, 0x6b, 0xd8, 0xa2, 0x03, 0x0d, 0xd3, 0x07, 0x14, 0x45, 0x3c, 0x44,
-	0x3c, 0x14, 0x54, 0x10, 0x02, 0x84, 0xc4, 0x43, 0x07, 0xea, 0xa4, 0x49, 0x95, 0x4c, 0xdf, 0x91,
-	0x97, 0x1a, 0x98, 0xda, 0xd9, 0xc6, 0x76, 0x27, 0xe5, 0xcf, 0xf2, 0x43, 0x78, 0x42, 0x75, 0x9c,
-	0xd2, 0x2d, 0x9d, 0xb4, 0xa7, 0xdc, 0xf9, 0xbb, 0xef, 0xbb, 0xef, 0xce, 0x0e, 0xa4, 0x86, 0xeb,
-	0x6b, 0xae, 0xc7, 0x4a, 0x4b, 0x2b, 0xf1, 0xd8, 0x7d, 0xbe, 0x57, 0x52, 0x08, 0x5e, 0x59, 0xa9,
-	0x8b, 0xbf, 0x01, 0x3c, 0xa4, 0xfc, 0xf7, 0x86, 0x1b, 0x8b, 0xaf, 0x20, 0x96, 0x6a, 0x51, 0x2b,
-	0x4e, 0x82, 0x3c, 0x28, 0x8f, 0x26, 0x4f, 0xc7, 0xb7, 0xaa, 0xc7, 0x73, 0x07, 0x53, 0x5f, 0x86,
-	0x9f, 0x20, 0x59, 0xf3, 0xe5, 0x4f, 0xae, 0xe7, 0x8a, 0x84, 0x79, 0x50, 0x0e, 0x27, 0x79, 0x87,
-	0x72, 0xee, 0x0b, 0xb8, 0x66, 0xf6, 0x52, 0x0a, 0xba, 0x63, 0xe0, 0x0c, 0xd2, 0x4a, 0x56, 0x52,
-	0x8a, 0xa9, 0x5c, 0xf2, 0xb9, 0x22, 0x91, 0x53, 0x78, 0xd1, 0x51, 0x98, 0xee, 0x15, 0xb5, 0x2a,
-	0x37, 0x98, 0xf8, 0x0e, 0xe2, 0xb5, 0xac, 0x56, 0x73, 0x45, 0x7a, 0x4e, 0xe3, 0x79, 0xd7, 0x85,
-	0x83, 0x5b, 0xb6, 0xaf, 0x2e, 0xbe, 0xc1, 0xa3, 0x1b, 0x00, 0x22, 0xf4, 0x04, 0xbb, 0x6a, 0xe6,
-	0x1f, 0x50, 0x17, 0xe3, 0x09, 0xc4, 0x8a, 0x69, 0x76, 0x65, 0x48, 0x98, 0x47, 0xe5, 0x80, 0xfa,
-	0x6c, 0x7b, 0xbe, 0xbe, 0x14, 0xab, 0x85, 0x74, 0xc6, 0x07, 0xd4, 0x67, 0x45, 0x0d, 0xc7, 0xb7,
-	0x66, 0xc6, 0x23, 0x08, 0xcf, 0x4e, 0xbd, 0x68, 0x78, 0x76, 0xba, 0x6b, 0x13, 0x1e, 0x6c, 0x13,
-	0xdd, 0xd1, 0xa6, 0xb7, 0xdf, 0x66, 0xab, 0x71, 0x21, 0x97, 0x35, 0xe9, 0xe7, 0x41, 0x99, 0x52,
-	0x17, 0x17, 0x7f, 0x02, 0x78, 0x7c, 0x60, 0x5b, 0x9d, 0xfe, 0x23, 0x48, 0x7e, 0x6c, 0x44, 0xb5,
-	0xc5, 0xbc, 0x87, 0x5d, 0x7e, 0xa7, 0x8f, 0x19, 0xc4, 0xbf, 0x38, 0x5b, 0x72, 0x4d, 0x7a, 0x79,
-	0x54, 0x0e, 0x27, 0xaf, 0xef, 0x73, 0x4f, 0xe3, 0x99, 0xa3, 0x7c, 0x11, 0x56, 0xd7, 0xd4, 0xf3,
-	0x47, 0x1f, 0x60, 0xb8, 0x77, 0x8c, 0x19, 0x44, 0x2b, 0x5e, 0x7b, 0x77, 0xdb, 0x10, 0x9f, 0x40,
-	0xff, 0x9a, 0xad, 0x37, 0xed, 0x7e, 0x9a, 0xe4, 0x63, 0xf8, 0x3e, 0x28, 0xbe, 0x42, 0x42, 0xb9,
-	0x51, 0x52, 0x18, 0xde, 0x19, 0xea, 0x04, 0x62, 0x63, 0x99, 0xdd, 0x18, 0x47, 0xeb, 0x53, 0x9f,
-	0xed, 0x16, 0x15, 0xfd, 0x5f, 0xd4, 0xcb, 0xb7, 0x10, 0x37, 0x4f, 0x19, 0x53, 0x48, 0xda, 0xdb,
-	0xca, 0x1e, 0x60, 0x06, 0xe9, 0xfe, 0x14, 0x59, 0x80, 0x00, 0x71, 0xf3, 0x44, 0xb2, 0x70, 0x72,
-	0x0e, 0x83, 0x69, 0x3b, 0x2d, 0x7e, 0x86, 0x64, 0xa1, 0x99, 0x30, 0xac, 0xb2, 0x48, 0x3a, 0xbb,
-	0xf0, 0xbf, 0xd4, 0xe8, 0xd9, 0x01, 0xa4, 0xf1, 0x7f, 0x11, 0x3b, 0xe4, 0xcd, 0xbf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xe4, 0x1d, 0x19, 0x15, 0xa1, 0x03, 0x00, 0x00,
+	// 489 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x51, 0x8b, 0xd3, 0x40,
+	0x10, 0x76, 0x93, 0x36, 0xa6, 0xd3, 0x78, 0x17, 0x46, 0x39, 0x63, 0x1f, 0x24, 0x04, 0x85, 0xe0,
+	0x43, 0x3d, 0xaa, 0x88, 0x8a, 0xe2, 0x43, 0x4f, 0xe8, 0x89, 0x50, 0xd8, 0xeb, 0xbb, 0x6c, 0xd3,
+	0x55, 0x4b, 0x7b, 0xbb, 0x71, 0x93, 0x9c, 0xe4, 0x9f, 0xf8, 0xbb, 0xfc, 0x31, 0x3e, 0xf8, 0x24,
+	0xd9, 0x6e, 0x6a, 0xae, 0x69, 0x55, 0xf0, 0xa9, 0x33, 0x9d, 0xf9, 0xbe, 0xf9, 0xbe, 0xc9, 0x0e,
+	0x78, 0x19, 0x57, 0x57, 0x5c, 0x0d, 0x53, 0x25, 0x73, 0x89, 0xc7, 0xfa, 0xe7, 0x43, 0x22, 0x85,
+	0xe0, 0x49, 0x2e, 0x55, 0xf4, 0x93, 0xc0, 0x4d, 0xca, 0xbf, 0x14, 0x3c, 0xcb, 0xf1, 0x31, 0x38,
+	0x32, 0x9d, 0x95, 0x29, 0x0f, 0x48, 0x48, 0xe2, 0xa3, 0xd1, 0xdd, 0xe1, 0x4e, 0xf7, 0x70, 0xaa,
+	0xcb, 0xd4, 0xb4, 0xe1, 0x2b, 0x70, 0xd7, 0x7c, 0xf1, 0x89, 0xab, 0x69, 0x1a, 0x58, 0x21, 0x89,
+	0xfb, 0xa3, 0xb0, 0x05, 0x79, 0x6f, 0x1a, 0xb8, 0x62, 0xf9, 0x52, 0x0a, 0xba, 0x45, 0xe0, 0x04,
+	0xbc, 0x44, 0x26, 0x52, 0x8a, 0xb1, 0x5c, 0xf0, 0x69, 0x1a, 0xd8, 0x9a, 0xe1, 0x41, 0x8b, 0x61,
+	0xdc, 0x68, 0xaa, 0x59, 0xae, 0x21, 0xf1, 0x19, 0x38, 0x6b, 0x99, 0xac, 0xa6, 0x69, 0xd0, 0xd1,
+	0x1c, 0xf7, 0xdb, 0x2a, 0x74, 0xb9, 0x46, 0x9b, 0xee, 0xe8, 0x02, 0x6e, 0x5d, 0x2b, 0x20, 0x42,
+	0x47, 0xb0, 0xcb, 0x8d, 0xff, 0x1e, 0xd5, 0x31, 0x9e, 0x80, 0x93, 0x32, 0xc5, 0x2e, 0xb3, 0xc0,
+	0x0a, 0xed, 0xb8, 0x47, 0x4d, 0x56, 0xfd, 0xbf, 0x5e, 0x8a, 0xd5, 0x4c, 0x6a, 0xe1, 0x3d, 0x6a,
+	0xb2, 0xa8, 0x84, 0xe3, 0x1d, 0xcf, 0x78, 0x04, 0xd6, 0xf9, 0x99, 0x21, 0xb5, 0xce, 0xcf, 0xb6,
+	0x63, 0xac, 0xbd, 0x63, 0xec, 0x03, 0x63, 0x3a, 0xcd, 0x31, 0x15, 0xc7, 0x5c, 0x2e, 0xca, 0xa0,
+	0x1b, 0x92, 0xd8, 0xa3, 0x3a, 0x8e, 0x7e, 0x10, 0xb8, 0xbd, 0x67, 0x5b, 0xad, 0xf9, 0x03, 0x70,
+	0x3f, 0x16, 0x22, 0xa9, 0x6a, 0x46, 0xc3, 0x36, 0x3f, 0xa8, 0x63, 0x02, 0xce, 0x67, 0xce, 0x16,
+	0x5c, 0x05, 0x9d, 0xd0, 0x8e, 0xfb, 0xa3, 0xd3, 0x7f, 0xf9, 0x4e, 0xc3, 0x89, 0x86, 0xbc, 0x15,
+	0xb9, 0x2a, 0xa9, 0xc1, 0x37, 0x1c, 0x75, 0x9b, 0x8e, 0x06, 0x2f, 0xa0, 0xdf, 0x68, 0x47, 0x1f,
+	0xec, 0x15, 0x2f, 0x8d, 0xea, 0x2a, 0xc4, 0x3b, 0xd0, 0xbd, 0x62, 0xeb, 0xa2, 0xde, 0xdb, 0x26,
+	0x79, 0x69, 0x3d, 0x27, 0xd1, 0x77, 0x02, 0x2e, 0xe5, 0x59, 0x2a, 0x45, 0xc6, 0x5b, 0x6e, 0x4f,
+	0xc0, 0xc9, 0x72, 0x96, 0x17, 0x99, 0xc6, 0x75, 0xa9, 0xc9, 0xb6, 0x1b, 0xb4, 0x7f, 0x6f, 0x10,
+	0x5f, 0xef, 0xb8, 0x7c, 0xd8, 0x72, 0x59, 0x8f, 0xd9, 0x6b, 0xcd, 0x07, 0x5b, 0xb1, 0xaf, 0xda,
+	0x97, 0x4b, 0xab, 0xf0, 0x3f, 0x4c, 0x3d, 0x7a, 0x0a, 0xce, 0xe6, 0xde, 0xd0, 0x03, 0xb7, 0x7e,
+	0x52, 0xfe, 0x0d, 0xf4, 0xc1, 0x6b, 0xae, 0xda, 0x27, 0x08, 0xe0, 0x6c, 0xde, 0xb1, 0x6f, 0x8d,
+	0xbe, 0x11, 0xe8, 0x8d, 0x6b, 0xb5, 0xf8, 0x06, 0xdc, 0x99, 0x62, 0x22, 0x63, 0x49, 0x8e, 0xc1,
+	0x1e, 0x2f, 0xfa, 0xf0, 0x07, 0xf7, 0x0e, 0xba, 0xc4, 0x77, 0xd0, 0xbb, 0x28, 0xe6, 0x59, 0xa2,
+	0x96, 0x73, 0x8e, 0x7f, 0xbd, 0xee, 0x3f, 0x30, 0x9d, 0x92, 0xb9, 0xa3, 0x6b, 0x4f, 0x7e, 0x0d,
+	0x00, 0xf4, 0x4a, 0x09, 0x32, 0x93, 0x04, 0x00, 0x00,
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 	"net"
+	"os"
 	"time"
 
 	"github.com/ellcrys/cocoon/core/config"
@@ -25,6 +26,8 @@ type RPC struct {
 	ledgerOps     *handlers.LedgerOperations
 	cocoonCodeOps *handlers.CocoonCodeOperations
 	lockOps       *handlers.LockOperations
+	invokeOps     *handlers.InvokeOperations
+	schedule      *Schedule
 }
 
 // NewRPC creates a new grpc API server
@@ -32,9 +35,11 @@ func NewRPC(connector *connector.Connector) *RPC {
 	log = config.MakeLogger("connector.rpc")
 	server := new(RPC)
 	server.connector = connector
-	server.ledgerOps = handlers.NewLedgerOperationHandler(log, connector)
+	server.ledgerOps = handlers.NewLedgerOperationHandler(log, connector.GetSpec(), connector.Platform, connector.GetOrdererDiscoverer())
 	server.cocoonCodeOps = handlers.NewCocoonCodeHandler(connector.GetCocoonCodeRPCAddr())
-	server.lockOps = handlers.NewLockOperationHandler(log, connector)
+	server.lockOps = handlers.NewLockOperationHandler(log, connector.GetSpec(), connector.Platform, handlers.NewLock)
+	server.invokeOps = handlers.NewInvokeOperationHandler(log, connector.GetSpec(), connector.Platform, connector.Platform.GetScheduler(), []byte(os.Getenv("CONNECTOR_SIGN_KEY")), server.cocoonCodeOps)
+	server.schedule = NewSchedule(connector.GetSpec().Release.Schedule, connector.GetSpec().Release.Timeouts, server.cocoonCodeOps)
 	return server
 }
 
@@ -64,6 +69,8 @@ func (rpc *RPC) Start(addr string, startedCh chan bool) {
 		time.Sleep(1 * time.Second)
 	})
 
+	rpc.schedule.Start()
+
 	rpc.server = grpc.NewServer()
 	proto_connector.RegisterConnectorServer(rpc.server, rpc)
 	rpc.server.Serve(lis)
@@ -71,6 +78,7 @@ func (rpc *RPC) Start(addr string, startedCh chan bool) {
 
 // Stop stops the server
 func (rpc *RPC) Stop() {
+	rpc.schedule.Stop()
 	rpc.stopCocoonCode()
 	if rpc.server != nil {
 		rpc.server.Stop()
@@ -84,11 +92,18 @@ func (rpc *RPC) Transact(ctx context.Context, req *proto_connector.Request) (*pr
 		return rpc.ledgerOps.Handle(ctx, req.LedgerOp)
 	case proto_connector.OpType_LockOp:
 		return rpc.lockOps.Handle(ctx, req.LockOp)
+	case proto_connector.OpType_CocoonCodeOp:
+		return rpc.invokeOps.Handle(ctx, req.CocoonCodeOp)
 	default:
 		return nil, fmt.Errorf("unsupported operation type")
 	}
 }
 
+// Subscribe streams the changes of a ledger to the cocoon code
+func (rpc *RPC) Subscribe(op *proto_connector.LedgerOperation, stream proto_connector.Connector_SubscribeServer) error {
+	return rpc.ledgerOps.Subscribe(stream.Context(), op, stream.Send)
+}
+
 // stop the cocoon code
 func (rpc *RPC) stopCocoonCode() {
 	ctx, cc := context.WithTimeout(context.Background(), 15*time.Second)
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/common/cron"
+	"github.com/ellcrys/cocoon/core/connector/server/handlers"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/metrics"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	context "golang.org/x/net/context"
+)
+
+// ScheduledRunRetryInterval is the time to wait before retrying a missed
+// run of a job whose missed-run policy is run_once
+var ScheduledRunRetryInterval = 30 * time.Second
+
+// scheduledRuns counts the activations of the scheduled jobs by outcome
+var scheduledRuns = metrics.NewCounter("cocoon_connector_scheduled_runs_total", "Activations of the scheduled jobs of the cocoon code by outcome (ok, error, skipped_unhealthy or skipped_overlap)", "job", "outcome")
+
+// CocoonCode is the cocoon code whose functions a schedule invokes
+type CocoonCode interface {
+
+	// Handle invokes a function of the cocoon code
+	Handle(ctx context.Context, op *proto_connector.CocoonCodeOperation) (*proto_connector.Response, error)
+
+	// HealthCheck checks whether the cocoon code is healthy
+	HealthCheck(ctx context.Context) error
+}
+
+// clock provides the current time and timers to a schedule
+type clock interface {
+
+	// Now returns the current time
+	Now() time.Time
+
+	// After returns a channel that receives the time once d has
+	// elapsed and a function that stops the timer
+	After(d time.Duration) (<-chan time.Time, func())
+}
+
+// realClock is the clock of the system
+type realClock struct{}
+
+// Now returns the current time
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns a channel that receives the time once d has elapsed
+func (realClock) After(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTimer(d)
+	return t.C, func() { t.Stop() }
+}
+
+// Schedule invokes the functions of the cocoon code on the cron expressions
+// of the jobs of the cocoon schedule. Cron expressions are evaluated in UTC.
+type Schedule struct {
+	jobs          types.Schedule
+	timeouts      types.InvokeTimeouts
+	cocoonCodeOps CocoonCode
+	clock         clock
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewSchedule creates a new Schedule instance. A function invoked by
+// a job may run for the time set for it in the invoke timeouts.
+func NewSchedule(jobs types.Schedule, timeouts types.InvokeTimeouts, cocoonCodeOps CocoonCode) *Schedule {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Schedule{
+		jobs:          jobs,
+		timeouts:      timeouts,
+		cocoonCodeOps: cocoonCodeOps,
+		clock:         realClock{},
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Start starts a routine for each job of the schedule
+func (s *Schedule) Start() {
+	for _, job := range s.jobs {
+		expr, err := cron.Parse(job.Cron)
+		if err != nil {
+			log.Errorf("scheduled job %s not started: invalid cron expression: %s", job.Name, err)
+			continue
+		}
+		s.wg.Add(1)
+		go s.run(job, expr)
+		log.Infof("Started scheduled job %s [cron=%s, function=%s]", job.Name, job.Cron, job.Function)
+	}
+}
+
+// Stop stops the schedule, cancels running invocations and waits for them to end
+func (s *Schedule) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run triggers a job on each activation time of its cron expression until
+// the schedule is stopped. An activation that is skipped because the cocoon
+// code is unhealthy or the previous run has not ended is dropped, unless the
+// missed-run policy of the job is run_once, in which case the run is retried
+// until it succeeds or the next activation time is reached.
+func (s *Schedule) run(job types.ScheduledJob, expr *cron.Schedule) {
+	defer s.wg.Done()
+
+	var running int32
+	var pending bool
+	next := expr.Next(s.clock.Now().UTC())
+
+	for {
+		if next.IsZero() && !pending {
+			log.Warningf("scheduled job %s stopped: cron expression has no future activation", job.Name)
+			return
+		}
+
+		var due, retry <-chan time.Time
+		var stopDue, stopRetry func()
+		if !next.IsZero() {
+			due, stopDue = s.clock.After(next.Sub(s.clock.Now()))
+		}
+		if pending {
+			retry, stopRetry = s.clock.After(ScheduledRunRetryInterval)
+		}
+
+		stopped := false
+		select {
+		case <-s.ctx.Done():
+			stopped = true
+		case <-due:
+			next = expr.Next(s.clock.Now().UTC())
+		case <-retry:
+		}
+
+		if stopDue != nil {
+			stopDue()
+		}
+		if stopRetry != nil {
+			stopRetry()
+		}
+		if stopped {
+			return
+		}
+
+		pending = !s.trigger(job, &running) && job.GetMissed() == types.ScheduleMissedRunOnce
+	}
+}
+
+// trigger invokes the function of a job if the cocoon code is healthy and
+// the overlap policy of the job permits it. It returns false if the run was skipped.
+func (s *Schedule) trigger(job types.ScheduledJob, running *int32) bool {
+
+	skipOverlap := job.GetOverlap() == types.ScheduleOverlapSkip
+	if skipOverlap && atomic.LoadInt32(running) > 0 {
+		log.Warningf("scheduled job %s skipped: previous run has not ended", job.Name)
+		scheduledRuns.Inc(job.Name, "skipped_overlap")
+		return false
+	}
+
+	ctx, cc := context.WithTimeout(s.ctx, 5*time.Second)
+	err := s.cocoonCodeOps.HealthCheck(ctx)
+	cc()
+	if err != nil {
+		log.Warningf("scheduled job %s skipped: cocoon code is not healthy", job.Name)
+		scheduledRuns.Inc(job.Name, "skipped_unhealthy")
+		return false
+	}
+
+	atomic.AddInt32(running, 1)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer atomic.AddInt32(running, -1)
+		s.invoke(job)
+	}()
+
+	return true
+}
+
+// invoke invokes the function of a job with the timeout set for the
+// function. The job name is passed to the cocoon code in the invoke header.
+func (s *Schedule) invoke(job types.ScheduledJob) {
+
+	ctx, cc := context.WithTimeout(s.ctx, s.timeouts.Get(job.Function))
+	defer cc()
+
+	id := util.UUID4()
+	resp, err := s.cocoonCodeOps.Handle(ctx, &proto_connector.CocoonCodeOperation{
+		ID:       id,
+		Function: job.Function,
+		Params:   job.Params,
+		Header: map[string]string{
+			"Transaction-Id":         id,
+			types.HeaderScheduledJob: job.Name,
+		},
+	})
+	if err != nil {
+		log.Errorf("scheduled job %s failed: %s", job.Name, err)
+		scheduledRuns.Inc(job.Name, "error")
+		return
+	} else if handlers.IsErrorResponse(resp) {
+		log.Errorf("scheduled job %s failed: %s", job.Name, resp.Body)
+		scheduledRuns.Inc(job.Name, "error")
+		return
+	}
+
+	scheduledRuns.Inc(job.Name, "ok")
+}
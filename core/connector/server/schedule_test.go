@@ -0,0 +1,289 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+// fakeClock is a clock whose time only changes when it is advanced.
+// The duration of each timer created is sent on the timers channel.
+type fakeClock struct {
+	sync.Mutex
+	now     time.Time
+	pending []*fakeTimer
+	timers  chan time.Duration
+}
+
+type fakeTimer struct {
+	at      time.Time
+	c       chan time.Time
+	stopped bool
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, timers: make(chan time.Duration, 16)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) (<-chan time.Time, func()) {
+	c.Lock()
+	defer c.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.pending = append(c.pending, t)
+	c.fire()
+	c.timers <- d
+	return t.c, func() {
+		c.Lock()
+		defer c.Unlock()
+		t.stopped = true
+	}
+}
+
+// Advance moves the time forward and fires the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+func (c *fakeClock) fire() {
+	var pending []*fakeTimer
+	for _, t := range c.pending {
+		if t.stopped {
+			continue
+		} else if !t.at.After(c.now) {
+			t.c <- c.now
+			continue
+		}
+		pending = append(pending, t)
+	}
+	c.pending = pending
+}
+
+// waitTimer waits for the schedule to create a timer and returns its duration
+func (c *fakeClock) waitTimer() time.Duration {
+	select {
+	case d := <-c.timers:
+		return d
+	case <-time.After(time.Second):
+		return -1
+	}
+}
+
+// fakeCocoonCode records the functions invoked, the time they may run and
+// their returns. If block is set, invocations do not return until it is
+// closed or they are canceled.
+type fakeCocoonCode struct {
+	healthy   int32
+	calls     chan *proto_connector.CocoonCodeOperation
+	deadlines chan time.Duration
+	returns   chan struct{}
+	block     chan struct{}
+}
+
+func newFakeCocoonCode(healthy bool) *fakeCocoonCode {
+	c := &fakeCocoonCode{
+		calls:     make(chan *proto_connector.CocoonCodeOperation, 16),
+		deadlines: make(chan time.Duration, 16),
+		returns:   make(chan struct{}, 16),
+	}
+	c.setHealthy(healthy)
+	return c
+}
+
+func (c *fakeCocoonCode) setHealthy(healthy bool) {
+	var v int32
+	if healthy {
+		v = 1
+	}
+	atomic.StoreInt32(&c.healthy, v)
+}
+
+func (c *fakeCocoonCode) Handle(ctx context.Context, op *proto_connector.CocoonCodeOperation) (*proto_connector.Response, error) {
+	defer func() { c.returns <- struct{}{} }()
+	deadline, _ := ctx.Deadline()
+	c.deadlines <- deadline.Sub(time.Now())
+	c.calls <- op
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &proto_connector.Response{ID: op.GetID(), Status: 200}, nil
+}
+
+func (c *fakeCocoonCode) HealthCheck(ctx context.Context) error {
+	if atomic.LoadInt32(&c.healthy) == 0 {
+		return fmt.Errorf("cocoon code is not running")
+	}
+	return nil
+}
+
+// waitCall waits for a function of the cocoon code to be invoked
+func (c *fakeCocoonCode) waitCall() *proto_connector.CocoonCodeOperation {
+	select {
+	case op := <-c.calls:
+		return op
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	log = config.MakeLogger("connector.rpc")
+
+	Convey("Schedule", t, func() {
+
+		// 2017-05-10 10:17:30 UTC; the next activation
+		// of "* * * * *" is 30 seconds later
+		clk := newFakeClock(time.Date(2017, 5, 10, 10, 17, 30, 0, time.UTC))
+		job := types.ScheduledJob{Name: "cleanup", Cron: "* * * * *", Function: "cleanup", Params: []string{"expired"}}
+		timeouts := types.InvokeTimeouts{types.AnyFunction: "1m", "cleanup": "5m"}
+
+		start := func(job types.ScheduledJob, code *fakeCocoonCode) *Schedule {
+			s := NewSchedule(types.Schedule{job}, timeouts, code)
+			s.clock = clk
+			s.Start()
+			So(clk.waitTimer(), ShouldEqual, 30*time.Second)
+			return s
+		}
+
+		Convey("Should invoke the function of a job on its activation time", func() {
+			code := newFakeCocoonCode(true)
+			s := start(job, code)
+			defer s.Stop()
+
+			clk.Advance(30 * time.Second)
+			op := code.waitCall()
+			So(op, ShouldNotBeNil)
+			So(op.GetFunction(), ShouldEqual, "cleanup")
+			So(op.GetParams(), ShouldResemble, []string{"expired"})
+			So(op.GetHeader()[types.HeaderScheduledJob], ShouldEqual, "cleanup")
+			So(clk.waitTimer(), ShouldEqual, time.Minute)
+		})
+
+		Convey("Should invoke the function of a job with the timeout set for the function", func() {
+			code := newFakeCocoonCode(true)
+			s := start(job, code)
+			defer s.Stop()
+
+			clk.Advance(30 * time.Second)
+			So(code.waitCall(), ShouldNotBeNil)
+			So(<-code.deadlines, ShouldAlmostEqual, 5*time.Minute, time.Second)
+		})
+
+		Convey("Should skip a run while the cocoon code is unhealthy", func() {
+			code := newFakeCocoonCode(false)
+			s := start(job, code)
+			defer s.Stop()
+
+			clk.Advance(30 * time.Second)
+			So(clk.waitTimer(), ShouldEqual, time.Minute)
+			So(code.calls, ShouldBeEmpty)
+
+			code.setHealthy(true)
+			clk.Advance(time.Minute)
+			So(code.waitCall(), ShouldNotBeNil)
+		})
+
+		Convey("With the run_once missed-run policy", func() {
+			retryInterval := ScheduledRunRetryInterval
+			ScheduledRunRetryInterval = 20 * time.Second
+			defer func() { ScheduledRunRetryInterval = retryInterval }()
+
+			job.Missed = types.ScheduleMissedRunOnce
+
+			Convey("Should retry a missed run until it is made", func() {
+				code := newFakeCocoonCode(false)
+				s := start(job, code)
+				defer s.Stop()
+
+				// missed at 10:18:00, retried at 10:18:20 and 10:18:40
+				clk.Advance(30 * time.Second)
+				So(clk.waitTimer(), ShouldEqual, time.Minute)
+				So(clk.waitTimer(), ShouldEqual, 20*time.Second)
+
+				clk.Advance(20 * time.Second)
+				So(clk.waitTimer(), ShouldEqual, 40*time.Second)
+				So(clk.waitTimer(), ShouldEqual, 20*time.Second)
+				So(code.calls, ShouldBeEmpty)
+
+				code.setHealthy(true)
+				clk.Advance(20 * time.Second)
+				So(code.waitCall(), ShouldNotBeNil)
+
+				// no retry is pending once the run is made
+				So(clk.waitTimer(), ShouldEqual, 20*time.Second)
+				So(clk.timers, ShouldBeEmpty)
+			})
+		})
+
+		Convey("With the skip overlap policy", func() {
+			job.Overlap = types.ScheduleOverlapSkip
+
+			Convey("Should skip a run while the previous run is in progress", func() {
+				code := newFakeCocoonCode(true)
+				code.block = make(chan struct{})
+				defer close(code.block)
+				s := start(job, code)
+				defer s.Stop()
+
+				clk.Advance(30 * time.Second)
+				So(code.waitCall(), ShouldNotBeNil)
+				So(clk.waitTimer(), ShouldEqual, time.Minute)
+
+				clk.Advance(time.Minute)
+				So(clk.waitTimer(), ShouldEqual, time.Minute)
+				So(code.calls, ShouldBeEmpty)
+			})
+
+			Convey("Should cancel the running run and wait for it to end when stopped", func() {
+				code := newFakeCocoonCode(true)
+				code.block = make(chan struct{})
+				defer close(code.block)
+				s := start(job, code)
+
+				clk.Advance(30 * time.Second)
+				So(code.waitCall(), ShouldNotBeNil)
+				s.Stop()
+				So(len(code.returns), ShouldEqual, 1)
+			})
+		})
+
+		Convey("With the allow overlap policy", func() {
+			job.Overlap = types.ScheduleOverlapAllow
+
+			Convey("Should make a run while the previous run is in progress", func() {
+				code := newFakeCocoonCode(true)
+				code.block = make(chan struct{})
+				defer close(code.block)
+				s := start(job, code)
+				defer s.Stop()
+
+				clk.Advance(30 * time.Second)
+				So(code.waitCall(), ShouldNotBeNil)
+				So(clk.waitTimer(), ShouldEqual, time.Minute)
+
+				clk.Advance(time.Minute)
+				So(code.waitCall(), ShouldNotBeNil)
+			})
+		})
+	})
+}
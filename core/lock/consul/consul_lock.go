@@ -7,12 +7,16 @@ import (
 	"github.com/ellcrys/util"
 	"github.com/franela/goreq"
 	"github.com/hashicorp/consul/api"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/types"
 )
 
 // LockTTL defines max time to live of a lock.
 var LockTTL = 10 * time.Second
 
+// acquireFailures counts failed attempts to acquire a lock
+var acquireFailures = metrics.NewCounter("cocoon_lock_acquire_failures_total", "Failed attempts to acquire a lock", "backend")
+
 // WaitForLock defines the about of time to wait to acquire a lock
 var WaitForLock = 5 * time.Second
 
@@ -100,8 +104,29 @@ func (l *Lock) createSession(ttl int) (string, error) {
 
 // Acquire acquires a lock. Returns error if it failed to acquire the lock.
 // If the lock object has a session that is still tired to the locked key,
-// nil is returned.
+// the session is renewed and nil is returned, so calling Acquire
+// periodically keeps the lock held.
 func (l *Lock) Acquire() error {
+	if err := l.acquire(); err != nil {
+		acquireFailures.Inc("consul")
+		return err
+	}
+	return nil
+}
+
+// acquire attempts to acquire the lock
+func (l *Lock) acquire() error {
+
+	// renew the session of the lock object. A session
+	// that has expired is replaced by a new session.
+	if sessionID := l.state["lock_session"].(string); sessionID != "" {
+		entry, _, err := l.client.Session().Renew(sessionID, nil)
+		if err != nil {
+			return fmt.Errorf("failed to renew session: %s", err)
+		} else if entry == nil {
+			l.state["lock_session"] = ""
+		}
+	}
 
 	// If lock object has got a session, get one.
 	if l.state["lock_session"].(string) == "" {
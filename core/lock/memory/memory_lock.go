@@ -7,12 +7,16 @@ import (
 	"fmt"
 
 	"github.com/ellcrys/util"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/types"
 )
 
 // LockTTL defines max time to live of a lock.
 var LockTTL = time.Duration(10 * time.Second)
 
+// acquireFailures counts failed attempts to acquire a lock
+var acquireFailures = metrics.NewCounter("cocoon_lock_acquire_failures_total", "Failed attempts to acquire a lock", "backend")
+
 // Global store for locked keys
 var lockedKeys = make(map[string]*LockValue)
 var lockKeyMu = sync.Mutex{}
@@ -55,6 +59,7 @@ func NewLockWithTTL(key string, lockTTL time.Duration) *Lock {
 
 // Acquire acquires a lock. A time-to-live time is set
 // on the lock to ensure the lock is invalidated after the time is passed.
+// Acquiring a lock already held by this instance extends its time-to-live.
 func (l *Lock) Acquire() error {
 
 	if l.state["lock_session"].(string) == "" {
@@ -65,15 +70,16 @@ func (l *Lock) Acquire() error {
 
 	lockKeyMu.Lock()
 	defer lockKeyMu.Unlock()
-	if lockValue, hasKey := lockedKeys[lockKey]; hasKey {
+	if lockValue, hasKey := lockedKeys[lockKey]; hasKey && time.Now().UTC().Before(lockValue.Exp) {
 		if lockValue.Session != l.state["lock_session"].(string) {
+			acquireFailures.Inc("memory")
 			return types.ErrLockAlreadyAcquired
 		}
 	}
 
 	lockedKeys[lockKey] = &LockValue{
 		Session: l.state["lock_session"].(string),
-		Exp:     time.Now().UTC().Add(LockTTL),
+		Exp:     time.Now().UTC().Add(l.lockTTL),
 	}
 
 	return nil
@@ -100,8 +106,10 @@ func (l *Lock) IsAcquirer() error {
 		return fmt.Errorf("key is not set")
 	}
 
+	lockKeyMu.Lock()
+	defer lockKeyMu.Unlock()
 	key := l.state["key"].(string)
-	if lockVal, hasLock := lockedKeys[key]; hasLock {
+	if lockVal, hasLock := lockedKeys[key]; hasLock && time.Now().UTC().Before(lockVal.Exp) {
 		if lockVal.Session == l.state["lock_session"].(string) {
 			return nil
 		}
@@ -67,8 +67,7 @@ func TestMemoryLock(t *testing.T) {
 
 			Convey("Should return err if lock is no longer acquired due to TTL being reached", func() {
 				key := util.RandString(10)
-				l := NewLock(key)
-				LockTTL = 2 * time.Second
+				l := NewLockWithTTL(key, 2*time.Second)
 				err := l.Acquire()
 				So(err, ShouldBeNil)
 				cancel := StartLockWatcher()
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of latency histograms
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// series holds the samples of a combination of label values
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+// vec is a metric partitioned by label values
+type vec struct {
+	sync.Mutex
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+func newVec(name, help, typ string, buckets []float64, labelNames []string) *vec {
+	return defaultRegistry.register(&vec{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	})
+}
+
+// with calls fn with the series of the label values while holding the lock.
+// The series is created if it does not exist, unless MaxSeries is reached.
+// It panics if the number of label values does not match the label names.
+func (v *vec) with(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label value(s), got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	v.Lock()
+	defer v.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		if len(v.series) >= MaxSeries {
+			return
+		}
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if v.typ == typeHistogram {
+			s.counts = make([]uint64, len(v.buckets))
+		}
+		v.series[key] = s
+	}
+	fn(s)
+}
+
+// get returns a copy of the series of the label values or nil if none exists
+func (v *vec) get(labelValues []string) *series {
+	v.Lock()
+	defer v.Unlock()
+	if s, ok := v.series[strings.Join(labelValues, "\xff")]; ok {
+		cp := *s
+		return &cp
+	}
+	return nil
+}
+
+// write writes the metric in the text format
+func (v *vec) write(buf *bytes.Buffer) {
+	v.Lock()
+	defer v.Unlock()
+
+	fmt.Fprintf(buf, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	fmt.Fprintf(buf, "# TYPE %s %s\n", v.name, v.typ)
+
+	var keys []string
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := v.series[key]
+		if v.typ != typeHistogram {
+			fmt.Fprintf(buf, "%s%s %s\n", v.name, formatLabels(v.labelNames, s.labelValues), formatFloat(s.value))
+			continue
+		}
+		var cumulative uint64
+		for i, upperBound := range v.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", v.name, formatLabels(v.labelNames, s.labelValues, "le", formatFloat(upperBound)), cumulative)
+		}
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", v.name, formatLabels(v.labelNames, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", v.name, formatLabels(v.labelNames, s.labelValues), formatFloat(s.value))
+		fmt.Fprintf(buf, "%s_count%s %d\n", v.name, formatLabels(v.labelNames, s.labelValues), s.count)
+	}
+}
+
+// Counter is a metric whose value only goes up
+type Counter struct {
+	v *vec
+}
+
+// NewCounter creates and registers a counter partitioned by the given label names
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{v: newVec(name, help, typeCounter, nil, labelNames)}
+}
+
+// Inc increments the counter of the label values by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta to the counter of the label values. Negative values are ignored.
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.v.with(labelValues, func(s *series) { s.value += delta })
+}
+
+// Value returns the value of the counter of the label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	if s := c.v.get(labelValues); s != nil {
+		return s.value
+	}
+	return 0
+}
+
+// Gauge is a metric whose value can go up and down
+type Gauge struct {
+	v *vec
+}
+
+// NewGauge creates and registers a gauge partitioned by the given label names
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{v: newVec(name, help, typeGauge, nil, labelNames)}
+}
+
+// Set sets the value of the gauge of the label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.v.with(labelValues, func(s *series) { s.value = value })
+}
+
+// Add adds delta, which may be negative, to the gauge of the label values
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.v.with(labelValues, func(s *series) { s.value += delta })
+}
+
+// Value returns the value of the gauge of the label values
+func (g *Gauge) Value(labelValues ...string) float64 {
+	if s := g.v.get(labelValues); s != nil {
+		return s.value
+	}
+	return 0
+}
+
+// Histogram counts observations in buckets and tracks their sum
+type Histogram struct {
+	v *vec
+}
+
+// NewHistogram creates and registers a histogram partitioned by the given label
+// names. Buckets are the increasing upper bounds of the buckets; DefaultBuckets
+// is used if none is given.
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &Histogram{v: newVec(name, help, typeHistogram, buckets, labelNames)}
+}
+
+// Observe adds an observation to the histogram of the label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if math.IsNaN(value) {
+		return
+	}
+	h.v.with(labelValues, func(s *series) {
+		for i, upperBound := range h.v.buckets {
+			if value <= upperBound {
+				s.counts[i]++
+				break
+			}
+		}
+		s.count++
+		s.value += value
+	})
+}
+
+// ObserveSince observes the seconds elapsed since start
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Count returns the number of observations of the label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	if s := h.v.get(labelValues); s != nil {
+		return s.count
+	}
+	return 0
+}
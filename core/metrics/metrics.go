@@ -0,0 +1,123 @@
+// Package metrics provides counters, gauges and histograms exposed in the
+// Prometheus text exposition format. Metrics are registered in a process-wide
+// registry when created and served by the /metrics endpoint started with Serve.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MaxSeries is the maximum number of label value combinations of a metric.
+// Observations of new combinations beyond it are dropped to protect the
+// process from labels with unbounded values.
+var MaxSeries = 1000
+
+// metric types
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// registry holds the metrics of the process by name
+type registry struct {
+	sync.Mutex
+	metrics map[string]*vec
+}
+
+var defaultRegistry = &registry{metrics: make(map[string]*vec)}
+
+// register adds a metric to the registry. If a metric with the same name
+// exists, it is returned instead, so packages can share a metric by creating
+// it with the same definition. It panics if the definitions differ.
+func (r *registry) register(v *vec) *vec {
+	r.Lock()
+	defer r.Unlock()
+	if existing, ok := r.metrics[v.name]; ok {
+		if existing.typ != v.typ || strings.Join(existing.labelNames, ",") != strings.Join(v.labelNames, ",") {
+			panic(fmt.Sprintf("metrics: %s already registered with a different definition", v.name))
+		}
+		return existing
+	}
+	r.metrics[v.name] = v
+	return v
+}
+
+// write writes every registered metric in the text format, sorted by name
+func (r *registry) write(w io.Writer) error {
+	r.Lock()
+	var names []string
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.Unlock()
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		r.Lock()
+		v := r.metrics[name]
+		r.Unlock()
+		v.write(&buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Write writes the registered metrics to w in the Prometheus text format
+func Write(w io.Writer) error {
+	return defaultRegistry.write(w)
+}
+
+// Handler returns an http handler that serves the registered metrics
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		Write(w)
+	})
+}
+
+// Serve starts an http server that serves the registered metrics at /metrics.
+// It blocks until the server fails.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return http.ListenAndServe(addr, mux)
+}
+
+// formatFloat formats a sample value
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds
+var escapeLabelValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace
+
+// escapeHelp escapes backslashes and line feeds
+var escapeHelp = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace
+
+// formatLabels formats label names and values as {name="value",...}.
+// Extra label pairs, such as the `le` label of histogram buckets, are appended.
+func formatLabels(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escapeLabelValue(extra[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetrics(t *testing.T) {
+	Convey("Metrics", t, func() {
+
+		Convey("Counter", func() {
+
+			Convey("Should count by label values", func() {
+				name := "test_counter_" + util.RandString(5)
+				c := NewCounter(name, "a test counter", "outcome")
+				c.Inc("ok")
+				c.Add(2, "ok")
+				c.Add(-1, "ok")
+				c.Inc("failed")
+				So(c.Value("ok"), ShouldEqual, 3)
+				So(c.Value("failed"), ShouldEqual, 1)
+				So(c.Value("unknown"), ShouldEqual, 0)
+
+				var buf bytes.Buffer
+				So(Write(&buf), ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, "# TYPE "+name+" counter\n")
+				So(buf.String(), ShouldContainSubstring, name+`{outcome="failed"} 1`+"\n"+name+`{outcome="ok"} 3`+"\n")
+			})
+
+			Convey("Should return the existing counter if created again with the same definition", func() {
+				name := "test_counter_" + util.RandString(5)
+				NewCounter(name, "a test counter", "backend").Inc("memory")
+				So(NewCounter(name, "a test counter", "backend").Value("memory"), ShouldEqual, 1)
+				So(func() { NewGauge(name, "a test gauge", "backend") }, ShouldPanic)
+			})
+
+			Convey("Should panic if the number of label values is wrong", func() {
+				c := NewCounter("test_counter_"+util.RandString(5), "a test counter", "outcome")
+				So(func() { c.Inc() }, ShouldPanic)
+			})
+
+			Convey("Should drop new label values once MaxSeries is reached", func() {
+				defer func(max int) { MaxSeries = max }(MaxSeries)
+				MaxSeries = 1
+				c := NewCounter("test_counter_"+util.RandString(5), "a test counter", "function")
+				c.Inc("a")
+				c.Inc("b")
+				c.Inc("a")
+				So(c.Value("a"), ShouldEqual, 2)
+				So(c.Value("b"), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Gauge", func() {
+
+			Convey("Should set the value of a gauge without labels", func() {
+				name := "test_gauge_" + util.RandString(5)
+				g := NewGauge(name, "a test gauge")
+				g.Set(10)
+				g.Add(-4)
+				So(g.Value(), ShouldEqual, 6)
+
+				var buf bytes.Buffer
+				So(Write(&buf), ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, name+" 6\n")
+			})
+		})
+
+		Convey("Histogram", func() {
+
+			Convey("Should write cumulative buckets, sum and count", func() {
+				name := "test_histogram_" + util.RandString(5)
+				h := NewHistogram(name, "a test histogram", []float64{1, 5}, "function")
+				h.Observe(0.5, `say "hi"`)
+				h.Observe(3, `say "hi"`)
+				h.Observe(10, `say "hi"`)
+				So(h.Count(`say "hi"`), ShouldEqual, 3)
+
+				var buf bytes.Buffer
+				So(Write(&buf), ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, name+`_bucket{function="say \"hi\"",le="1"} 1`+"\n")
+				So(buf.String(), ShouldContainSubstring, name+`_bucket{function="say \"hi\"",le="5"} 2`+"\n")
+				So(buf.String(), ShouldContainSubstring, name+`_bucket{function="say \"hi\"",le="+Inf"} 3`+"\n")
+				So(buf.String(), ShouldContainSubstring, name+`_sum{function="say \"hi\""} 13.5`+"\n")
+				So(buf.String(), ShouldContainSubstring, name+`_count{function="say \"hi\""} 3`+"\n")
+			})
+		})
+
+		Convey("Handler", func() {
+
+			Convey("Should serve the metrics in the text format", func() {
+				name := "test_counter_" + util.RandString(5)
+				NewCounter(name, "a test counter").Inc()
+				rec := httptest.NewRecorder()
+				Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+				So(rec.Header().Get("Content-Type"), ShouldEqual, ContentType)
+				So(rec.Body.String(), ShouldContainSubstring, name+" 1\n")
+			})
+		})
+	})
+}
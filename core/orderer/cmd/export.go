@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/util"
+	"github.com/spf13/cobra"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [cocoon id]",
+	Short: "Export the ledgers of a cocoon to an archive",
+	Long: `Writes the ledgers of a cocoon, their transactions and blocks to an archive
+that can be imported by an orderer of another cluster. The archive is written
+to the standard output unless an output file is provided.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.export")
+
+		if len(args) < 1 {
+			cmd.Usage()
+			return
+		}
+
+		var out io.Writer = os.Stdout
+		outFile, _ := cmd.Flags().GetString("out")
+		if len(outFile) > 0 {
+			f, err := os.Create(outFile)
+			if err != nil {
+				log.Fatalf("Failed to create output file: %s", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		ordererAddr, _ := cmd.Flags().GetString("orderer")
+		conn, err := grpc.Dial(ordererAddr, grpc.WithInsecure())
+		if err != nil {
+			log.Fatalf("Failed to connect to orderer. Is orderer running on %s", ordererAddr)
+		}
+		defer conn.Close()
+
+		odc := proto_orderer.NewOrdererClient(conn)
+		stream, err := odc.Export(context.Background(), &proto_orderer.ExportParams{
+			CocoonID: args[0],
+		})
+		if err != nil {
+			log.Fatalf("Failed to export ledgers: %s", err)
+		}
+
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				log.Fatalf("Failed to export ledgers: %s", err)
+			}
+			if _, err := out.Write(chunk.GetData()); err != nil {
+				log.Fatalf("Failed to write archive: %s", err)
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("out", "o", "", "The file to write the archive to")
+	exportCmd.Flags().String("orderer", util.Env("ADDR_ORDERER_RPC", "127.0.0.1:8001"), "The address of the orderer")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/util"
+	"github.com/spf13/cobra"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [archive file]",
+	Short: "Import ledgers from an archive",
+	Long: `Sends an archive created by the export command to the orderer. The orderer checks
+the archive and replays its ledgers, transactions and blocks into its store.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.import")
+
+		if len(args) < 1 {
+			cmd.Usage()
+			return
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalf("Failed to open archive: %s", err)
+		}
+		defer f.Close()
+
+		ordererAddr, _ := cmd.Flags().GetString("orderer")
+		conn, err := grpc.Dial(ordererAddr, grpc.WithInsecure())
+		if err != nil {
+			log.Fatalf("Failed to connect to orderer. Is orderer running on %s", ordererAddr)
+		}
+		defer conn.Close()
+
+		odc := proto_orderer.NewOrdererClient(conn)
+		stream, err := odc.Import(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to import archive: %s", err)
+		}
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&proto_orderer.ArchiveChunk{Data: buf[:n]}); err != nil {
+					break
+				}
+			}
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				log.Fatalf("Failed to read archive: %s", err)
+			}
+		}
+
+		result, err := stream.CloseAndRecv()
+		if err != nil {
+			log.Fatalf("Failed to import archive: %s", err)
+		}
+
+		fmt.Printf("Imported %d ledger(s), %d block(s) and %d transaction(s)\n", result.GetLedgers(), result.GetBlocks(), result.GetTransactions())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+	importCmd.Flags().String("orderer", util.Env("ADDR_ORDERER_RPC", "127.0.0.1:8001"), "The address of the orderer")
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/store/envelope"
+	"github.com/spf13/cobra"
+)
+
+// keygenCmd represents the keygen command
+var keygenCmd = &cobra.Command{
+	Use:   "keygen [key file]",
+	Short: "Generate a block signing key or an encryption master key",
+	Long: `Generates an ed25519 private key for signing blocks and writes it to a file
+as a PEM encoded PKCS #8 key. Pass the file to the start command with --signing-key.
+
+With --encryption, a master key for encrypting private ledgers is written instead.
+Pass the file to the start command with --encryption-key-file. To rotate the master
+key, add a new key at the top of the file and restart the orderer; the previous keys
+can be removed once the orderer has wrapped the data keys with the new key.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.keygen")
+
+		if len(args) < 1 {
+			cmd.Usage()
+			return
+		}
+
+		if encryption, _ := cmd.Flags().GetBool("encryption"); encryption {
+			key, err := envelope.GenerateKey()
+			if err != nil {
+				log.Fatalf("Failed to generate key: %s", err)
+			}
+			if err := ioutil.WriteFile(args[0], []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+				log.Fatalf("Failed to write key file: %s", err)
+			}
+			fmt.Printf("Key ID: %s\n", envelope.MakeKeyID(key))
+			return
+		}
+
+		blockSigner, err := signer.Generate()
+		if err != nil {
+			log.Fatalf("Failed to generate key: %s", err)
+		}
+
+		pemBytes, err := blockSigner.Marshal()
+		if err != nil {
+			log.Fatalf("Failed to encode key: %s", err)
+		}
+
+		if err := ioutil.WriteFile(args[0], pemBytes, 0600); err != nil {
+			log.Fatalf("Failed to write key file: %s", err)
+		}
+
+		fmt.Printf("Key ID: %s\nPublic key: %s\n", blockSigner.KeyID(), blockSigner.PublicKey().Hex())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(keygenCmd)
+	keygenCmd.Flags().Bool("encryption", false, "Generate a master key for encrypting private ledgers")
+}
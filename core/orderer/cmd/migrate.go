@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	blkch_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/store/migration"
+	"github.com/ellcrys/util"
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations to the store and blockchain database",
+	Long: `Applies the schema migrations that have not been applied to the postgres database
+of the store and blockchain. The orderer does not start while migrations are pending.
+With --dry-run, the migrations are run and rolled back without changing the database.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.migrate")
+
+		storeConStr := util.Env("STORE_CON_STR", "host=localhost user=ned dbname=cocoon sslmode=disable password=")
+		if common.IsBoltConStr(storeConStr) {
+			fmt.Println("The bolt backend has no schema migrations")
+			return
+		}
+
+		blockchain := new(blkch_impl.PostgresBlockchain)
+		if _, err := blockchain.Connect(storeConStr); err != nil {
+			log.Fatalf("%s", err)
+		}
+		defer blockchain.Close()
+
+		store := new(impl.PostgresStore)
+		if _, err := store.Connect(storeConStr); err != nil {
+			log.Fatalf("%s", err)
+		}
+		defer store.Close()
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		for _, migrator := range []*migration.Migrator{blockchain.Migrator(), store.Migrator()} {
+
+			version, err := migrator.Version()
+			if err != nil {
+				log.Fatalf("%s", err)
+			}
+
+			applied, err := migrator.Migrate(dryRun)
+			if err != nil {
+				log.Fatalf("%s: %s", migrator.GetComponent(), err)
+			}
+
+			if len(applied) == 0 {
+				fmt.Printf("%s: schema is up to date (version %d)\n", migrator.GetComponent(), version)
+				continue
+			}
+
+			for _, m := range applied {
+				fmt.Printf("%s: %d - %s\n", migrator.GetComponent(), m.Version, m.Description)
+			}
+
+			if dryRun {
+				fmt.Printf("%s: %d migration(s) can be applied (dry run, nothing was changed)\n", migrator.GetComponent(), len(applied))
+			} else {
+				fmt.Printf("%s: migrated from version %d to %d\n", migrator.GetComponent(), version, applied[len(applied)-1].Version)
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().Bool("dry-run", false, "Run the migrations and roll them back without changing the database")
+}
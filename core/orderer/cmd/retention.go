@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/util"
+	"github.com/spf13/cobra"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// retentionCmd represents the retention command
+var retentionCmd = &cobra.Command{
+	Use:   "retention [cocoon id] [ledger]",
+	Short: "Set the retention policy of a ledger",
+	Long: `Sets how long the superseded transactions of a ledger are kept. A transaction is
+removed once it is not among the --keep-revisions most recent revisions of its key or is
+older than --keep-for. The most recent revision of a key is always kept. Chained ledgers
+are compacted into a checkpoint instead; the most recent revisions included in removed
+blocks are kept but can no longer be proven against a block. Set both flags to zero to
+remove the policy.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.retention")
+
+		if len(args) < 2 {
+			cmd.Usage()
+			return
+		}
+
+		keepRevisions, _ := cmd.Flags().GetInt64("keep-revisions")
+		keepFor, _ := cmd.Flags().GetDuration("keep-for")
+
+		ordererAddr, _ := cmd.Flags().GetString("orderer")
+		conn, err := grpc.Dial(ordererAddr, grpc.WithInsecure())
+		if err != nil {
+			log.Fatalf("Failed to connect to orderer. Is orderer running on %s", ordererAddr)
+		}
+		defer conn.Close()
+
+		odc := proto_orderer.NewOrdererClient(conn)
+		ledger, err := odc.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{
+			CocoonID:      args[0],
+			Name:          args[1],
+			KeepRevisions: keepRevisions,
+			KeepFor:       int64(keepFor / time.Second),
+		})
+		if err != nil {
+			log.Fatalf("Failed to set retention policy: %s", err)
+		}
+
+		if ledger.GetKeepRevisions() == 0 && ledger.GetKeepFor() == 0 {
+			fmt.Printf("Removed the retention policy of ledger %s\n", ledger.GetName())
+			return
+		}
+
+		fmt.Printf("Set the retention policy of ledger %s\n", ledger.GetName())
+		if ledger.GetKeepRevisions() > 0 {
+			fmt.Printf("  Keep revisions: %d\n", ledger.GetKeepRevisions())
+		}
+		if ledger.GetKeepFor() > 0 {
+			fmt.Printf("  Keep for: %s\n", time.Duration(ledger.GetKeepFor())*time.Second)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(retentionCmd)
+	retentionCmd.Flags().Int64("keep-revisions", 0, "The number of revisions of a key to keep (0 keeps all revisions)")
+	retentionCmd.Flags().Duration("keep-for", 0, "How long superseded revisions are kept, e.g 720h (0 keeps revisions regardless of age)")
+	retentionCmd.Flags().String("orderer", util.Env("ADDR_ORDERER_RPC", "127.0.0.1:8001"), "The address of the orderer")
+}
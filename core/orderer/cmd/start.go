@@ -7,14 +7,15 @@ import (
 	"os"
 
 	"github.com/ellcrys/util"
-	b_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/config"
 	"github.com/ellcrys/cocoon/core/lock/consul"
 	"github.com/ellcrys/cocoon/core/lock/memory"
+	"github.com/ellcrys/cocoon/core/metrics"
 	"github.com/ellcrys/cocoon/core/orderer/orderer"
 	"github.com/ellcrys/cocoon/core/scheduler"
-	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/store/envelope"
 	"github.com/spf13/cobra"
 )
 
@@ -47,10 +48,77 @@ var ordererCmd = &cobra.Command{
 
 		endedCh := make(chan bool)
 		newOrderer := orderer.NewOrderer()
-		newOrderer.SetStore(new(impl.PostgresStore))
-		newOrderer.SetBlockchain(new(b_impl.PostgresBlockchain))
+
+		signingKeyFile, _ := cmd.Flags().GetString("signing-key")
+		if len(signingKeyFile) > 0 {
+			blockSigner, err := signer.Load(signingKeyFile)
+			if err != nil {
+				log.Fatalf("Failed to load signing key: %s", err)
+			}
+			newOrderer.SetSigner(blockSigner)
+		}
+
+		// the values of private ledgers are encrypted if master keys are provided
+		encryptionKeyFile, _ := cmd.Flags().GetString("encryption-key-file")
+		if len(encryptionKeyFile) > 0 {
+			keyring, err := envelope.LoadKeyFile(encryptionKeyFile)
+			if err != nil {
+				log.Fatalf("Failed to load encryption keys: %s", err)
+			}
+			newOrderer.SetKeyProvider(keyring)
+		} else if len(os.Getenv("ORDERER_ENCRYPTION_KEYS")) > 0 {
+			keyring, err := envelope.LoadEnv("ORDERER_ENCRYPTION_KEYS")
+			if err != nil {
+				log.Fatalf("Failed to load encryption keys: %s", err)
+			}
+			newOrderer.SetKeyProvider(keyring)
+		} else {
+			log.Warning("No encryption key set. Values of private ledgers will not be encrypted")
+		}
+
+		pruneInterval, _ := cmd.Flags().GetString("prune-interval")
+		if len(pruneInterval) > 0 {
+			interval, err := time.ParseDuration(pruneInterval)
+			if err != nil {
+				log.Fatalf("Invalid prune interval: %s", err)
+			}
+			newOrderer.SetPruneInterval(interval)
+		}
+
+		keyRotationInterval, _ := cmd.Flags().GetString("key-rotation-interval")
+		if len(keyRotationInterval) > 0 {
+			interval, err := time.ParseDuration(keyRotationInterval)
+			if err != nil {
+				log.Fatalf("Invalid key rotation interval: %s", err)
+			}
+			newOrderer.SetKeyRotationInterval(interval)
+		}
+
+		// other orderers forward writes on the chains this orderer leads to this address
+		advertiseAddr, _ := cmd.Flags().GetString("advertise-addr")
+		if len(advertiseAddr) > 0 {
+			newOrderer.SetAdvertiseAddr(advertiseAddr)
+		}
+
+		leaderTTL, _ := cmd.Flags().GetString("leader-ttl")
+		if len(leaderTTL) > 0 {
+			ttl, err := time.ParseDuration(leaderTTL)
+			if err != nil {
+				log.Fatalf("Invalid leader ttl: %s", err)
+			}
+			newOrderer.SetLeaderTTL(ttl)
+		}
+
 		go newOrderer.Start(bindAddr, storeConStr, endedCh)
 
+		metricsAddr := scheduler.Getenv("ADDR_ORDERER_METRICS", "0.0.0.0:9001")
+		go func() {
+			log.Infof("Serving metrics @ %s/metrics", metricsAddr)
+			if err := metrics.Serve(metricsAddr); err != nil {
+				log.Errorf("Metrics server stopped: %s", err)
+			}
+		}()
+
 		common.OnTerminate(func(s os.Signal) {
 			log.Info("Terminate signal received. Stopping...")
 			newOrderer.Stop()
@@ -63,4 +131,10 @@ var ordererCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(ordererCmd)
+	ordererCmd.Flags().String("signing-key", os.Getenv("ORDERER_SIGNING_KEY"), "The PEM encoded ed25519 private key file used to sign blocks")
+	ordererCmd.Flags().String("encryption-key-file", os.Getenv("ORDERER_ENCRYPTION_KEY_FILE"), "The file of hex encoded master keys that encrypt private ledgers. The first key is the current key. Falls back to the comma separated keys in ORDERER_ENCRYPTION_KEYS")
+	ordererCmd.Flags().String("prune-interval", util.Env("ORDERER_PRUNE_INTERVAL", "10m"), "How often ledgers with a retention policy are pruned (0 disables pruning)")
+	ordererCmd.Flags().String("key-rotation-interval", util.Env("ORDERER_KEY_ROTATION_INTERVAL", "1h"), "How often data keys are wrapped again with the current master key and values stored in plaintext are encrypted (0 disables rotation)")
+	ordererCmd.Flags().String("advertise-addr", os.Getenv("ORDERER_ADVERTISE_ADDR"), "The address other orderers use to reach this orderer. Defaults to the RPC address")
+	ordererCmd.Flags().String("leader-ttl", util.Env("ORDERER_LEADER_TTL", "15s"), "How long the leadership of a chain is held after the leader stops appending to it")
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/util"
+	"github.com/spf13/cobra"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// verifyChainCmd represents the verify-chain command
+var verifyChainCmd = &cobra.Command{
+	Use:   "verify-chain [cocoon id] [ledger name]",
+	Short: "Verify the integrity of a chained ledger",
+	Long: `Asks the orderer to walk the blocks of a chained ledger and report broken block links,
+tampered transactions, invalid block signatures and gaps in block numbers. Signatures are checked
+against the public key of the orderer unless a public key is provided. Every block must be signed;
+use --signed-from for chains whose first blocks were created before blocks were signed. Exits with
+a non-zero status if an issue is found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		var log = config.MakeLogger("orderer.verify-chain")
+
+		if len(args) < 2 {
+			cmd.Usage()
+			return
+		}
+
+		ordererAddr, _ := cmd.Flags().GetString("orderer")
+		conn, err := grpc.Dial(ordererAddr, grpc.WithInsecure())
+		if err != nil {
+			log.Fatalf("Failed to connect to orderer. Is orderer running on %s", ordererAddr)
+		}
+		defer conn.Close()
+
+		publicKey, _ := cmd.Flags().GetString("public-key")
+		signedFrom, _ := cmd.Flags().GetUint("signed-from")
+		odc := proto_orderer.NewOrdererClient(conn)
+		stream, err := odc.VerifyChain(context.Background(), &proto_orderer.VerifyChainParams{
+			CocoonID:   args[0],
+			Ledger:     args[1],
+			PublicKey:  publicKey,
+			SignedFrom: int64(signedFrom),
+		})
+		if err != nil {
+			log.Fatalf("Failed to verify chain: %s", err)
+		}
+
+		var issues int
+		for {
+			report, err := stream.Recv()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				log.Fatalf("Failed to verify chain: %s", err)
+			}
+
+			if report.GetType() == impl.ChainReportSummary {
+				fmt.Println(report.GetMessage())
+				continue
+			}
+
+			issues++
+			if len(report.GetTxId()) > 0 {
+				fmt.Printf("[%s] block %d (%s), transaction %s: %s\n", report.GetType(), report.GetBlockNumber(), report.GetBlockId(), report.GetTxId(), report.GetMessage())
+			} else {
+				fmt.Printf("[%s] block %d (%s): %s\n", report.GetType(), report.GetBlockNumber(), report.GetBlockId(), report.GetMessage())
+			}
+		}
+
+		if issues > 0 {
+			fmt.Printf("%d issue(s) found\n", issues)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyChainCmd)
+	verifyChainCmd.Flags().String("orderer", util.Env("ADDR_ORDERER_RPC", "127.0.0.1:8001"), "The address of the orderer")
+	verifyChainCmd.Flags().String("public-key", "", "The hex encoded ed25519 public key expected to have signed the blocks")
+	verifyChainCmd.Flags().Uint("signed-from", 0, "The number of the first block that must be signed. Blocks numbered below it may be unsigned")
+}
@@ -0,0 +1,80 @@
+package orderer
+
+import (
+	"bufio"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/archive"
+)
+
+// archiveChunkSize is the maximum size of an archive chunk sent to clients
+var archiveChunkSize = 64 * 1024
+
+// chunkWriter sends the data written to it as archive chunks
+type chunkWriter struct {
+	stream proto_orderer.Orderer_ExportServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	var written int
+	for written < len(p) {
+		end := written + archiveChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := w.stream.Send(&proto_orderer.ArchiveChunk{Data: p[written:end]}); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+// chunkReader reads the data of archive chunks received from a client
+type chunkReader struct {
+	stream proto_orderer.Orderer_ImportServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Export streams an archive of the ledgers of a cocoon, including
+// their transactions and blocks. See the archive package for the format.
+func (od *Orderer) Export(params *proto_orderer.ExportParams, stream proto_orderer.Orderer_ExportServer) error {
+
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, archiveChunkSize)
+	if _, err := archive.Export(od.store, od.blockchain, params.GetCocoonID(), w); err != nil {
+		log.Errorf("failed to export ledgers of cocoon (%s): %s", params.GetCocoonID(), err)
+		return err
+	}
+
+	return w.Flush()
+}
+
+// Import receives an archive created by Export, checks it and replays it into the store.
+func (od *Orderer) Import(stream proto_orderer.Orderer_ImportServer) error {
+
+	manifest, err := archive.Import(od.store, od.blockchain, &chunkReader{stream: stream})
+	if err != nil {
+		log.Errorf("failed to import archive: %s", err)
+		return err
+	}
+
+	ledgers, blocks, txs := manifest.Count()
+	return stream.SendAndClose(&proto_orderer.ImportResult{
+		Ledgers:      int64(ledgers),
+		Blocks:       int64(blocks),
+		Transactions: int64(txs),
+	})
+}
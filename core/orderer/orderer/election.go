@@ -0,0 +1,333 @@
+package orderer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/types"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultLeaderTTL is how long the leadership of a chain is held
+// by an orderer that stops appending blocks to the chain
+var DefaultLeaderTTL = 15 * time.Second
+
+// forwardedByKey is the metadata key that marks a request
+// forwarded by an orderer to the leader of a chain
+const forwardedByKey = "x-forwarded-by"
+
+// pruneLeaderLockKey is the key of the lock held by the
+// orderer that prunes the ledgers that are not chained
+const pruneLeaderLockKey = "orderer/pruner"
+
+// leadership is the state of the leadership of a chain. Its lock is held
+// while the leadership is acquired, renewed or recorded on the chain.
+type leadership struct {
+	sync.Mutex
+	name      string
+	lock      types.Lock
+	leading   bool
+	renewedAt time.Time
+}
+
+// elector elects, for every chain, the only orderer allowed to append blocks
+// to it. The leadership of a chain is a lock on the chain name. It is held as
+// long as the leader keeps renewing the lock and the address of the leader is
+// recorded on the chain for other orderers to forward writes to. The lock of
+// the elector only guards the leaderships; each leadership has its own lock.
+type elector struct {
+	sync.Mutex
+	addr       string
+	ttl        time.Duration
+	blockchain types.Blockchain
+	chains     map[string]*leadership
+	pruner     *leadership
+}
+
+// newElector creates an elector for an orderer reachable at addr
+func newElector(addr string, ttl time.Duration, blockchain types.Blockchain) *elector {
+	return &elector{
+		addr:       addr,
+		ttl:        ttl,
+		blockchain: blockchain,
+		chains:     make(map[string]*leadership),
+	}
+}
+
+// hold acquires or renews the lock of a leadership. A held leadership is renewed
+// at most once every third of the ttl. It returns whether the orderer holds the
+// leadership and whether it has just been acquired. The leadership must be locked.
+func (e *elector) hold(l *leadership) (leading, elected bool, err error) {
+
+	if l.leading && time.Since(l.renewedAt) < e.ttl/3 {
+		return true, false, nil
+	}
+
+	err = l.lock.Acquire()
+	if err == nil {
+		elected = !l.leading
+		l.leading, l.renewedAt = true, time.Now()
+		return true, elected, nil
+	} else if err != types.ErrLockAlreadyAcquired && err != types.ErrLockNotAcquired {
+		return false, false, fmt.Errorf("failed to acquire leader lock: %s", err)
+	}
+
+	if l.leading {
+		log.Infof("Lost leadership of %s", l.name)
+		l.leading = false
+	}
+
+	return false, false, nil
+}
+
+// leaderOf returns the address of the leader of a chain and whether it is this
+// orderer. The orderer becomes the leader if no other orderer holds the leadership.
+// Without an elector, as in an orderer opened with Open, every chain is led locally.
+func (e *elector) leaderOf(chainName string) (string, bool, error) {
+	if e == nil {
+		return "", true, nil
+	}
+
+	l, err := e.getLeadership(chainName)
+	if err != nil {
+		return "", false, err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	leading, elected, err := e.hold(l)
+	if err != nil {
+		return "", false, err
+	}
+
+	if elected {
+		if err := e.blockchain.SetChainLeader(chainName, e.addr); err != nil {
+			l.lock.Release()
+			l.leading = false
+			return "", false, err
+		}
+		log.Infof("Elected leader of chain %s", chainName)
+	}
+
+	if leading {
+		return e.addr, true, nil
+	}
+
+	chain, err := e.blockchain.GetChain(chainName)
+	if err != nil {
+		return "", false, err
+	} else if chain == nil {
+		return "", false, types.ErrChainNotFound
+	}
+
+	// the leader may not have recorded its address yet
+	if len(chain.Leader) == 0 || chain.Leader == e.addr {
+		return "", false, types.ErrChainLeaderUnknown
+	}
+
+	return chain.Leader, false, nil
+}
+
+// getLeadership returns the leadership of a chain, creating it if needed
+func (e *elector) getLeadership(chainName string) (*leadership, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	l := e.chains[chainName]
+	if l == nil {
+		lock, err := common.NewLockWithTTL("orderer/leader/"+chainName, e.ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create leader lock: %s", err)
+		}
+		l = &leadership{name: "chain " + chainName, lock: lock}
+		e.chains[chainName] = l
+	}
+
+	return l, nil
+}
+
+// fence checks, before the blocks appended to a chain are committed, that the orderer
+// still leads the chain. The leadership is renewed if it is due and the leader recorded
+// on the chain is checked with the blockchain of the database transaction of the
+// writes, which keeps another orderer from recording itself as the leader until the
+// transaction ends. It returns ErrNotChainLeader if the leadership was lost.
+func (e *elector) fence(blockchain types.Blockchain, chainName string) error {
+	if e == nil {
+		return nil
+	}
+
+	e.Lock()
+	l := e.chains[chainName]
+	e.Unlock()
+
+	if l == nil {
+		return types.ErrNotChainLeader
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	leading, elected, err := e.hold(l)
+	if err != nil {
+		return err
+	} else if !leading {
+		return types.ErrNotChainLeader
+	} else if elected {
+		// the leadership was lost and acquired again, so another orderer may
+		// have led the chain. It is given up until it is recorded by leaderOf.
+		l.lock.Release()
+		l.leading = false
+		return types.ErrNotChainLeader
+	}
+
+	return blockchain.CheckChainLeader(chainName, e.addr)
+}
+
+// leaderAddr returns the address of the leader of a chain without electing one.
+// Unless the orderer leads the chain, it is the address recorded by the last orderer
+// elected, which may no longer hold the leadership. It is empty if no orderer has
+// been elected. Without an elector, every chain is led locally.
+func (e *elector) leaderAddr(chainName string) (string, error) {
+	if e == nil {
+		return "", nil
+	}
+
+	e.Lock()
+	l := e.chains[chainName]
+	e.Unlock()
+
+	var leading bool
+	if l != nil {
+		l.Lock()
+		leading = l.leading && time.Since(l.renewedAt) < e.ttl
+		l.Unlock()
+	}
+
+	if leading {
+		return e.addr, nil
+	}
+
+	chain, err := e.blockchain.GetChain(chainName)
+	if err != nil {
+		return "", err
+	} else if chain == nil {
+		return "", types.ErrChainNotFound
+	}
+
+	return chain.Leader, nil
+}
+
+// leadsPruning checks whether the orderer prunes the ledgers that are not
+// chained. Only one orderer prunes them; it becomes the pruner if no other
+// orderer is. Without an elector, the ledgers are pruned locally.
+func (e *elector) leadsPruning() (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+
+	e.Lock()
+	if e.pruner == nil {
+		lock, err := common.NewLockWithTTL(pruneLeaderLockKey, e.ttl)
+		if err != nil {
+			e.Unlock()
+			return false, fmt.Errorf("failed to create leader lock: %s", err)
+		}
+		e.pruner = &leadership{name: "pruner", lock: lock}
+	}
+	pruner := e.pruner
+	e.Unlock()
+
+	pruner.Lock()
+	defer pruner.Unlock()
+
+	leading, elected, err := e.hold(pruner)
+	if elected {
+		log.Infof("Elected pruner")
+	}
+
+	return leading, err
+}
+
+// release gives up the leadership of every chain led by the orderer
+func (e *elector) release() {
+	if e == nil {
+		return
+	}
+	e.Lock()
+	leaderships := make([]*leadership, 0, len(e.chains)+1)
+	for _, l := range e.chains {
+		leaderships = append(leaderships, l)
+	}
+	if e.pruner != nil {
+		leaderships = append(leaderships, e.pruner)
+	}
+	e.Unlock()
+
+	for _, l := range leaderships {
+		l.Lock()
+		if l.leading {
+			if err := l.lock.Release(); err != nil {
+				log.Errorf("Failed to release leadership of %s: %s", l.name, err)
+			}
+			l.leading = false
+		}
+		l.Unlock()
+	}
+}
+
+// isForwarded checks whether a request was forwarded by another orderer
+func isForwarded(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md[forwardedByKey]) > 0
+}
+
+// forwardToLeader calls the leader of a chain with a client to the orderer at
+// leaderAddr. A request that has already been forwarded is not forwarded
+// again; ErrNotChainLeader is returned instead.
+func (od *Orderer) forwardToLeader(ctx context.Context, leaderAddr string, call func(context.Context, proto_orderer.OrdererClient) error) error {
+
+	if isForwarded(ctx) {
+		return types.ErrNotChainLeader
+	}
+
+	conn, err := grpc.Dial(leaderAddr, grpc.WithInsecure())
+	if err != nil {
+		return fmt.Errorf("failed to reach chain leader: %s", err)
+	}
+	defer conn.Close()
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(forwardedByKey, od.advertiseAddr))
+	return call(ctx, proto_orderer.NewOrdererClient(conn))
+}
+
+// GetLeader returns the address of the orderer that leads the chain of a ledger.
+// The address is empty if the ledger is not chained or no orderer has been elected
+// leader of the chain yet. It does not elect a leader; the first orderer to append
+// to the chain becomes its leader.
+func (od *Orderer) GetLeader(ctx context.Context, params *proto_orderer.GetLeaderParams) (*proto_orderer.Leader, error) {
+
+	internalLedgerName := types.MakeLedgerName(params.GetCocoonID(), params.GetLedger())
+	ledger, err := od.store.GetLedger(internalLedgerName)
+	if err != nil {
+		return nil, err
+	} else if ledger == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	if !ledger.Chained {
+		return &proto_orderer.Leader{}, nil
+	}
+
+	addr, err := od.elector.leaderAddr(internalLedgerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto_orderer.Leader{Addr: addr}, nil
+}
@@ -0,0 +1,174 @@
+package orderer
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// startTestOrderer starts an orderer and returns a function that stops it
+func startTestOrderer(addr, conStr string) (*Orderer, func()) {
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	return od, func() {
+		od.Stop()
+		<-endCh
+	}
+}
+
+func TestLeaderElection(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_election_"+util.RandString(5)+".db")
+	addr1 := util.Env("ORDERER_ELECTION_ADDR_1", "127.0.0.1:7017")
+	addr2 := util.Env("ORDERER_ELECTION_ADDR_2", "127.0.0.1:7018")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od1, stop1 := startTestOrderer(addr1, conStr)
+	defer stop1()
+	od2, stop2 := startTestOrderer(addr2, conStr)
+	defer stop2()
+
+	Convey("Orderer", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		_, err := od1.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+			CocoonID: cocoonID,
+			Name:     ledgerName,
+			Chained:  true,
+		})
+		So(err, ShouldBeNil)
+
+		put := func(od *Orderer, ctx context.Context, key string) (*proto_orderer.PutResult, error) {
+			return od.Put(ctx, &proto_orderer.PutTransactionParams{
+				CocoonID:     cocoonID,
+				LedgerName:   ledgerName,
+				Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: key, Value: "value"}},
+			})
+		}
+
+		getLeader := func(od *Orderer) string {
+			leader, err := od.GetLeader(context.Background(), &proto_orderer.GetLeaderParams{
+				CocoonID: cocoonID,
+				Ledger:   ledgerName,
+			})
+			So(err, ShouldBeNil)
+			return leader.Addr
+		}
+
+		Convey("The first orderer to append to a chain should become its leader", func() {
+			result, err := put(od1, context.Background(), "key1")
+			So(err, ShouldBeNil)
+			So(result.Block.Number, ShouldEqual, 1)
+			So(getLeader(od1), ShouldEqual, addr1)
+			So(getLeader(od2), ShouldEqual, addr1)
+
+			chain, err := od1.blockchain.GetChain(types.MakeLedgerName(cocoonID, ledgerName))
+			So(err, ShouldBeNil)
+			So(chain.Leader, ShouldEqual, addr1)
+
+			Convey("A follower should forward puts to the leader", func() {
+				result, err := put(od2, context.Background(), "key2")
+				So(err, ShouldBeNil)
+				So(result.Block.Number, ShouldEqual, 2)
+				So(result.TxReceipts[0].Err, ShouldBeEmpty)
+			})
+
+			Convey("A follower should not forward a request that has already been forwarded", func() {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(forwardedByKey, addr1))
+				_, err := put(od2, ctx, "key2")
+				So(err, ShouldEqual, types.ErrNotChainLeader)
+			})
+
+			Convey("Another orderer should become the leader once the leader gives up the leadership", func() {
+				od1.elector.release()
+				result, err := put(od2, context.Background(), "key3")
+				So(err, ShouldBeNil)
+				So(result.Block.Number, ShouldEqual, 2)
+				So(getLeader(od1), ShouldEqual, addr2)
+
+				result, err = put(od1, context.Background(), "key4")
+				So(err, ShouldBeNil)
+				So(result.Block.Number, ShouldEqual, 3)
+			})
+		})
+
+		Convey("GetLeader should not elect a leader", func() {
+			So(getLeader(od2), ShouldEqual, "")
+			So(getLeader(od1), ShouldEqual, "")
+
+			chain, err := od1.blockchain.GetChain(types.MakeLedgerName(cocoonID, ledgerName))
+			So(err, ShouldBeNil)
+			So(chain.Leader, ShouldBeEmpty)
+
+			_, err = put(od2, context.Background(), "key1")
+			So(err, ShouldBeNil)
+			So(getLeader(od1), ShouldEqual, addr2)
+		})
+
+		Convey("A leader should not commit blocks once another orderer leads the chain", func() {
+			chainName := types.MakeLedgerName(cocoonID, ledgerName)
+			_, err := put(od1, context.Background(), "key1")
+			So(err, ShouldBeNil)
+			So(od1.elector.fence(od1.blockchain, chainName), ShouldBeNil)
+
+			Convey("when another leader is recorded on the chain", func() {
+				So(od1.blockchain.SetChainLeader(chainName, addr2), ShouldBeNil)
+				So(od1.elector.fence(od1.blockchain, chainName), ShouldEqual, types.ErrNotChainLeader)
+				od1.elector.release()
+			})
+
+			Convey("when the leadership was lost", func() {
+				od1.elector.release()
+				_, err := put(od2, context.Background(), "key2")
+				So(err, ShouldBeNil)
+				So(od1.elector.fence(od1.blockchain, chainName), ShouldEqual, types.ErrNotChainLeader)
+				od2.elector.release()
+			})
+		})
+
+		Convey("Unchained ledgers should have no leader", func() {
+			unchainedName := util.RandString(5)
+			_, err := od1.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+				CocoonID: cocoonID,
+				Name:     unchainedName,
+			})
+			So(err, ShouldBeNil)
+			leader, err := od2.GetLeader(context.Background(), &proto_orderer.GetLeaderParams{
+				CocoonID: cocoonID,
+				Ledger:   unchainedName,
+			})
+			So(err, ShouldBeNil)
+			So(leader.Addr, ShouldBeEmpty)
+		})
+
+		Convey("Only one orderer should prune unchained ledgers", func() {
+			pruning, err := od1.elector.leadsPruning()
+			So(err, ShouldBeNil)
+			So(pruning, ShouldBeTrue)
+			pruning, err = od2.elector.leadsPruning()
+			So(err, ShouldBeNil)
+			So(pruning, ShouldBeFalse)
+
+			od1.elector.release()
+			pruning, err = od2.elector.leadsPruning()
+			So(err, ShouldBeNil)
+			So(pruning, ShouldBeTrue)
+			od2.elector.release()
+		})
+	})
+}
@@ -0,0 +1,339 @@
+package orderer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/store/envelope"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// DefaultKeyRotationInterval is how often data keys are rotated by default
+var DefaultKeyRotationInterval = time.Hour
+
+// sealBatchSize is the number of transactions read per
+// page when encrypting the values stored in plaintext
+var sealBatchSize = 1000
+
+// dataKeyCache holds the unwrapped data keys of private ledgers.
+// Data keys never change, so they are kept for the life of the orderer.
+type dataKeyCache struct {
+	sync.Mutex
+	keys map[string][]byte
+}
+
+// SetKeyProvider sets the provider of the master keys that wrap the data keys of
+// private ledgers. Values of private ledgers are stored in plaintext if it is not set.
+func (od *Orderer) SetKeyProvider(provider envelope.KeyProvider) {
+	od.keyProvider = provider
+}
+
+// getDataKey returns the data key of a private ledger. A data key is created for a
+// ledger that has none if create is true. It returns nil if the values of the ledger
+// are not encrypted.
+func (od *Orderer) getDataKey(ledgerName string, public, create bool) ([]byte, error) {
+
+	if od.keyProvider == nil || public {
+		return nil, nil
+	}
+
+	od.dataKeys.Lock()
+	defer od.dataKeys.Unlock()
+
+	if dataKey, ok := od.dataKeys.keys[ledgerName]; ok {
+		return dataKey, nil
+	}
+
+	ledger, err := od.store.GetLedger(ledgerName)
+	if err != nil {
+		return nil, err
+	} else if ledger == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	if len(ledger.DataKey) == 0 {
+		if !create {
+			return nil, nil
+		}
+
+		dataKey, wrappedDataKey, err := envelope.NewDataKey(od.keyProvider)
+		if err != nil {
+			return nil, err
+		}
+
+		// another orderer may have created a data key for the ledger first
+		created, err := od.store.SetLedgerDataKey(ledgerName, "", wrappedDataKey)
+		if err != nil {
+			return nil, err
+		} else if created {
+			od.dataKeys.keys[ledgerName] = dataKey
+			return dataKey, nil
+		}
+
+		if ledger, err = od.store.GetLedger(ledgerName); err != nil {
+			return nil, err
+		}
+	}
+
+	dataKey, err := envelope.OpenDataKey(od.keyProvider, ledger.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("ledger %s: %s", ledgerName, err)
+	}
+
+	od.dataKeys.keys[ledgerName] = dataKey
+	return dataKey, nil
+}
+
+// encryptTxs encrypts the values of transactions to be stored in a private ledger.
+// Transactions are hashed before their values are encrypted, so that their hashes,
+// the merkle roots of their blocks and their proofs are derived from the values
+// and remain valid when the values are encrypted again. The values at the paths
+// of the secondary indexes of the ledger are kept unencrypted in the index
+// document of each transaction, so that the ledger can be queried.
+func (od *Orderer) encryptTxs(ledger *types.Ledger, txs []*types.Transaction) error {
+
+	dataKey, err := od.getDataKey(ledger.Name, ledger.Public, true)
+	if err != nil || dataKey == nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		tx.Hash = tx.MakeHash()
+		if tx.Deleted {
+			continue
+		}
+		if len(ledger.Indexes) > 0 {
+			tx.IndexDoc = types.MakeIndexDoc(tx.Value, ledger.Indexes)
+		}
+		if tx.Value, err = envelope.Encrypt(dataKey, tx.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptTxs decrypts the values of transactions of a private ledger.
+// Values stored before encryption was enabled are left unchanged.
+func (od *Orderer) decryptTxs(ledgerName string, public bool, txs []*types.Transaction) error {
+
+	if public {
+		return nil
+	}
+
+	dataKey, err := od.getDataKey(ledgerName, public, false)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		if !envelope.IsEncrypted(tx.Value) {
+			continue
+		} else if dataKey == nil {
+			return types.ErrNoKeyProvider
+		}
+		if tx.Value, err = envelope.Decrypt(dataKey, tx.Value); err != nil {
+			return fmt.Errorf("transaction %s: %s", tx.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// decryptBlock returns a copy of a block of a private ledger whose
+// transactions have decrypted values. Blocks of public ledgers and
+// blocks with no encrypted value are returned unchanged.
+func (od *Orderer) decryptBlock(ledgerName string, public bool, block *types.Block) (*types.Block, error) {
+
+	if public || block == nil {
+		return block, nil
+	}
+
+	txs, err := block.GetTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted bool
+	for _, tx := range txs {
+		if envelope.IsEncrypted(tx.Value) {
+			encrypted = true
+			break
+		}
+	}
+	if !encrypted {
+		return block, nil
+	}
+
+	if err := od.decryptTxs(ledgerName, public, txs); err != nil {
+		return nil, err
+	}
+
+	decrypted := *block
+	if decrypted.Transactions, err = util.ToJSON(txs); err != nil {
+		return nil, err
+	}
+
+	return &decrypted, nil
+}
+
+// decryptingBlockchain reads the blocks of a private ledger with decrypted
+// values, so that the hashes of their transactions can be verified
+type decryptingBlockchain struct {
+	types.Blockchain
+	od     *Orderer
+	public bool
+}
+
+// GetBlockByNumber returns a block of a chain by its number
+func (b *decryptingBlockchain) GetBlockByNumber(chainName string, number uint) (*types.Block, error) {
+	block, err := b.Blockchain.GetBlockByNumber(chainName, number)
+	if err != nil {
+		return nil, err
+	}
+	return b.od.decryptBlock(chainName, b.public, block)
+}
+
+// ListBlocks returns the blocks of a chain starting from a block number
+func (b *decryptingBlockchain) ListBlocks(chainName string, fromNumber uint, limit int) ([]*types.Block, error) {
+	blocks, err := b.Blockchain.ListBlocks(chainName, fromNumber, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, block := range blocks {
+		if blocks[i], err = b.od.decryptBlock(chainName, b.public, block); err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// runKeyRotation rotates the data keys and encrypts the values stored in
+// plaintext once, then every interval until stopCh is closed
+func (od *Orderer) runKeyRotation(interval time.Duration, stopCh chan bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if rotated, err := od.RotateDataKeys(); err != nil {
+			log.Errorf("Failed to rotate data keys: %s", err)
+		} else if rotated > 0 {
+			log.Infof("Wrapped %d data key(s) with master key %s", rotated, od.keyProvider.CurrentKeyID())
+		}
+
+		if sealed, err := od.EncryptPlaintextValues(); err != nil {
+			log.Errorf("Failed to encrypt plaintext values: %s", err)
+		} else if sealed > 0 {
+			log.Infof("Encrypted %d plaintext value(s)", sealed)
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateDataKeys wraps the data keys of the encrypted ledgers that were wrapped by
+// a previous master key with the current master key of the key provider. Encrypted
+// values are unchanged. Once it completes, previous master keys can be removed
+// from the provider.
+// It returns the number of data keys wrapped again.
+func (od *Orderer) RotateDataKeys() (int, error) {
+
+	if od.keyProvider == nil {
+		return 0, types.ErrNoKeyProvider
+	}
+
+	ledgers, err := od.store.ListEncryptedLedgers()
+	if err != nil {
+		return 0, err
+	}
+
+	var rotated int
+	for _, ledger := range ledgers {
+
+		rewrapped, changed, err := envelope.RewrapDataKey(od.keyProvider, ledger.DataKey)
+		if err != nil {
+			return rotated, fmt.Errorf("ledger %s: %s", ledger.Name, err)
+		} else if !changed {
+			continue
+		}
+
+		// skip ledgers whose data key was concurrently wrapped again by another orderer
+		updated, err := od.store.SetLedgerDataKey(ledger.Name, ledger.DataKey, rewrapped)
+		if err != nil {
+			return rotated, err
+		} else if updated {
+			rotated++
+		}
+	}
+
+	return rotated, nil
+}
+
+// EncryptPlaintextValues encrypts the values of encrypted ledgers that were stored in
+// plaintext before encryption was enabled. Transactions are hashed before their values
+// are encrypted, so the hashes of transactions and blocks remain valid. Blocks keep the
+// values they were created with. The transactions of a ledger that have been checked
+// are not read again by later runs. It returns the number of values encrypted.
+func (od *Orderer) EncryptPlaintextValues() (int, error) {
+
+	if od.keyProvider == nil {
+		return 0, types.ErrNoKeyProvider
+	}
+
+	ledgers, err := od.store.ListEncryptedLedgers()
+	if err != nil {
+		return 0, err
+	}
+
+	var sealed int
+	for _, ledger := range ledgers {
+
+		dataKey, err := od.getDataKey(ledger.Name, ledger.Public, false)
+		if err != nil {
+			return sealed, fmt.Errorf("ledger %s: %s", ledger.Name, err)
+		} else if dataKey == nil {
+			continue
+		}
+
+		for {
+			txs, err := od.store.ListTransactions(ledger.Name, od.sealedUpTo[ledger.Name], sealBatchSize)
+			if err != nil {
+				return sealed, err
+			} else if len(txs) == 0 {
+				break
+			}
+
+			var plaintextTxs []*types.Transaction
+			for _, tx := range txs {
+				if tx.Deleted || envelope.IsEncrypted(tx.Value) {
+					continue
+				}
+				if len(ledger.Indexes) > 0 {
+					tx.IndexDoc = types.MakeIndexDoc(tx.Value, ledger.Indexes)
+				}
+				if tx.Value, err = envelope.Encrypt(dataKey, tx.Value); err != nil {
+					return sealed, err
+				}
+				plaintextTxs = append(plaintextTxs, tx)
+			}
+
+			n, err := od.store.SetTransactionValues(ledger.Name, plaintextTxs)
+			if err != nil {
+				return sealed, fmt.Errorf("ledger %s: %s", ledger.Name, err)
+			}
+
+			sealed += n
+			od.sealedUpTo[ledger.Name] = txs[len(txs)-1].Number
+			if len(txs) < sealBatchSize {
+				break
+			}
+		}
+	}
+
+	return sealed, nil
+}
@@ -0,0 +1,239 @@
+package orderer
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	blkch_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/envelope"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+func TestEncryption(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_encryption_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_ENCRYPTION_ADDR", "127.0.0.1:7015")
+	defer impl.Destroy(conStr)
+
+	key1, _ := envelope.GenerateKey()
+	key2, _ := envelope.GenerateKey()
+	keyring, _ := envelope.NewKeyring(key1)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	od.SetKeyProvider(keyring)
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	put := func(cocoonID, ledgerName, key, value string) {
+		result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+			CocoonID:     cocoonID,
+			LedgerName:   ledgerName,
+			Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: key, Value: value}},
+		})
+		So(err, ShouldBeNil)
+		So(result.TxReceipts[0].Err, ShouldBeEmpty)
+	}
+
+	Convey("Orderer encryption", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		internalName := types.MakeLedgerName(cocoonID, ledgerName)
+		internalKey := types.MakeTxKey(cocoonID, "key")
+		_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+		So(err, ShouldBeNil)
+		put(cocoonID, ledgerName, "key", "secret")
+
+		Convey("Should store the values of a private ledger encrypted", func() {
+			tx, err := od.store.Get(internalName, internalKey, false)
+			So(err, ShouldBeNil)
+			So(envelope.IsEncrypted(tx.Value), ShouldBeTrue)
+
+			block, err := od.blockchain.GetBlock(internalName, tx.BlockID)
+			So(err, ShouldBeNil)
+			So(string(block.Transactions), ShouldNotContainSubstring, "secret")
+
+			ledger, err := od.store.GetLedger(internalName)
+			So(err, ShouldBeNil)
+			So(envelope.GetWrappingKeyID(ledger.DataKey), ShouldEqual, keyring.CurrentKeyID())
+		})
+
+		Convey("Should decrypt values transparently", func() {
+			put(cocoonID, ledgerName, "key", "secret2")
+
+			tx, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: ledgerName, Key: "key"})
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "secret2")
+
+			txs, err := od.GetRange(context.Background(), &proto_orderer.GetRangeParams{CocoonID: cocoonID, Ledger: ledgerName, Prefix: "key", Limit: 10})
+			So(err, ShouldBeNil)
+			So(len(txs.Transactions), ShouldEqual, 1)
+			So(txs.Transactions[0].Value, ShouldEqual, "secret2")
+
+			history, err := od.GetHistory(context.Background(), &proto_orderer.GetHistoryParams{CocoonID: cocoonID, Ledger: ledgerName, Key: "key"})
+			So(err, ShouldBeNil)
+			So(len(history.Transactions), ShouldEqual, 2)
+			So(history.Transactions[1].Value, ShouldEqual, "secret")
+		})
+
+		Convey("Should not encrypt the values of a public ledger", func() {
+			publicLedger := util.RandString(5)
+			_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: publicLedger, Public: true})
+			So(err, ShouldBeNil)
+			put(cocoonID, publicLedger, "key", "visible")
+
+			tx, err := od.store.Get(types.MakeLedgerName(cocoonID, publicLedger), internalKey, false)
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "visible")
+		})
+
+		verifyChain := func(ledgerName string) (uint, []*blkch_impl.ChainIssue) {
+			lookupTx := func(id string) (*types.Transaction, error) {
+				tx, err := od.store.GetByID(ledgerName, id)
+				if err != nil || tx == nil {
+					return tx, err
+				}
+				return tx, od.decryptTxs(ledgerName, false, []*types.Transaction{tx})
+			}
+			var issues []*blkch_impl.ChainIssue
+			blockchain := &decryptingBlockchain{Blockchain: od.blockchain, od: od}
+			checked, err := blkch_impl.VerifyChain(blockchain, ledgerName, lookupTx, nil, 0, func(issue *blkch_impl.ChainIssue) error {
+				issues = append(issues, issue)
+				return nil
+			})
+			So(err, ShouldBeNil)
+			return checked, issues
+		}
+
+		Convey("Should keep the chain of an encrypted ledger verifiable", func() {
+			checked, issues := verifyChain(internalName)
+			So(checked, ShouldEqual, 1)
+			So(issues, ShouldBeEmpty)
+		})
+
+		Convey("Should hash transactions before their values are encrypted", func() {
+			tx, err := od.store.Get(internalName, internalKey, false)
+			So(err, ShouldBeNil)
+			So(envelope.IsEncrypted(tx.Value), ShouldBeTrue)
+			So(tx.Hash, ShouldNotEqual, tx.MakeHash())
+			So(od.decryptTxs(internalName, false, []*types.Transaction{tx}), ShouldBeNil)
+			So(tx.Hash, ShouldEqual, tx.MakeHash())
+		})
+
+		Convey("Should return blocks with decrypted values", func() {
+			block, err := od.GetBlockByNumber(context.Background(), &proto_orderer.GetBlockByNumberParams{CocoonID: cocoonID, Ledger: ledgerName, Number: 1})
+			So(err, ShouldBeNil)
+			So(string(block.Transactions), ShouldContainSubstring, "secret")
+
+			blocks, err := od.ListBlocks(context.Background(), &proto_orderer.ListBlocksParams{CocoonID: cocoonID, Ledger: ledgerName, FromNumber: 1})
+			So(err, ShouldBeNil)
+			So(len(blocks.Blocks), ShouldEqual, 1)
+			So(blocks.Blocks[0].Transactions, ShouldResemble, block.Transactions)
+			So(blocks.Blocks[0].Hash, ShouldEqual, block.Hash)
+
+			tx, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: ledgerName, Key: "key"})
+			So(err, ShouldBeNil)
+			So(tx.Block.Transactions, ShouldResemble, block.Transactions)
+
+			result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+				CocoonID:     cocoonID,
+				LedgerName:   ledgerName,
+				Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "key", Value: "secret3"}},
+			})
+			So(err, ShouldBeNil)
+			So(string(result.Block.Transactions), ShouldContainSubstring, "secret3")
+		})
+
+		Convey(".EncryptPlaintextValues", func() {
+
+			plainLedger := util.RandString(5)
+			plainInternalName := types.MakeLedgerName(cocoonID, plainLedger)
+			_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: plainLedger, Chained: true})
+			So(err, ShouldBeNil)
+
+			// values put without a key provider are stored in plaintext
+			od.SetKeyProvider(nil)
+			put(cocoonID, plainLedger, "key", "plain")
+			od.SetKeyProvider(keyring)
+			put(cocoonID, plainLedger, "key2", "sealed")
+
+			tx, err := od.store.Get(plainInternalName, internalKey, false)
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "plain")
+
+			sealed, err := od.EncryptPlaintextValues()
+			So(err, ShouldBeNil)
+			So(sealed, ShouldEqual, 1)
+
+			tx, err = od.store.Get(plainInternalName, internalKey, false)
+			So(err, ShouldBeNil)
+			So(envelope.IsEncrypted(tx.Value), ShouldBeTrue)
+
+			protoTx, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: plainLedger, Key: "key"})
+			So(err, ShouldBeNil)
+			So(protoTx.Value, ShouldEqual, "plain")
+
+			checked, issues := verifyChain(plainInternalName)
+			So(checked, ShouldEqual, 2)
+			So(issues, ShouldBeEmpty)
+
+			Convey("Should not read the checked transactions again", func() {
+				sealed, err := od.EncryptPlaintextValues()
+				So(err, ShouldBeNil)
+				So(sealed, ShouldEqual, 0)
+			})
+		})
+
+		Convey(".RotateDataKeys", func() {
+
+			resetKeys := func(provider envelope.KeyProvider) {
+				od.SetKeyProvider(provider)
+				od.dataKeys = &dataKeyCache{keys: make(map[string][]byte)}
+			}
+			defer resetKeys(keyring)
+
+			rotatedKeyring, _ := envelope.NewKeyring(key2, key1)
+			resetKeys(rotatedKeyring)
+			rotated, err := od.RotateDataKeys()
+			So(err, ShouldBeNil)
+			So(rotated, ShouldBeGreaterThan, 0)
+
+			ledger, err := od.store.GetLedger(internalName)
+			So(err, ShouldBeNil)
+			So(envelope.GetWrappingKeyID(ledger.DataKey), ShouldEqual, rotatedKeyring.CurrentKeyID())
+
+			rotated, err = od.RotateDataKeys()
+			So(err, ShouldBeNil)
+			So(rotated, ShouldEqual, 0)
+
+			Convey("Values should be readable with only the new master key", func() {
+				newKeyring, _ := envelope.NewKeyring(key2)
+				resetKeys(newKeyring)
+				tx, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: ledgerName, Key: "key"})
+				So(err, ShouldBeNil)
+				So(tx.Value, ShouldEqual, "secret")
+			})
+
+			Convey("Should return error when reading encrypted values without a key provider", func() {
+				resetKeys(nil)
+				_, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: ledgerName, Key: "key"})
+				So(err, ShouldEqual, types.ErrNoKeyProvider)
+			})
+		})
+	})
+}
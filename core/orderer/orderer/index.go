@@ -0,0 +1,97 @@
+package orderer
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ncodes/cstructs"
+	context "golang.org/x/net/context"
+)
+
+// maxQueryLimit is the maximum number of transactions returned by Query
+const maxQueryLimit = 100
+
+// makeIndexes copies proto indexes to store indexes
+func makeIndexes(protoIndexes []*proto_orderer.Index) types.Indexes {
+	var indexes types.Indexes
+	for _, index := range protoIndexes {
+		indexes = append(indexes, &types.Index{Name: index.GetName(), Path: index.GetPath()})
+	}
+	return indexes
+}
+
+// Query fetches the most recent transaction of the keys whose value at the path
+// of a secondary index of a ledger matches a query. The query value is JSON encoded.
+// Transactions are ordered by key. At most maxQueryLimit transactions are returned;
+// a limit of zero returns the maximum. NextCursor is set if more keys may match.
+func (od *Orderer) Query(ctx context.Context, params *proto_orderer.QueryParams) (*proto_orderer.Transactions, error) {
+
+	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
+		CocoonID: params.GetCocoonID(),
+		Name:     params.GetLedger(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var afterKey string
+	if len(params.GetCursor()) > 0 {
+		key, err := types.ParseRangeCursor(params.GetCursor())
+		if err != nil {
+			return nil, err
+		}
+		afterKey = types.MakeTxKey(params.GetCocoonID(), key)
+	}
+
+	limit := int(params.GetLimit())
+	if limit <= 0 || limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+
+	txs, err := od.store.Query(ledger.NameInternal, params.GetIndex(), params.GetOp(), params.GetValue(), afterKey, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := od.decryptTxs(ledger.NameInternal, ledger.Public, txs); err != nil {
+		return nil, err
+	}
+
+	var protoTxs = make([]*proto_orderer.Transaction, len(txs))
+	for i, tx := range txs {
+
+		if ledger.Chained {
+			block, err := od.blockchain.GetBlock(ledger.NameInternal, tx.BlockID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to populate block to transaction")
+			} else if block == nil && err == nil {
+				return nil, fmt.Errorf("orphaned transaction")
+			}
+			if block, err = od.decryptBlock(ledger.NameInternal, ledger.Public, block); err != nil {
+				return nil, err
+			}
+
+			tx.Block = block
+			tx.BlockID = ""
+		}
+
+		tx.KeyInternal = tx.Key
+		tx.Key = types.GetActualKeyFromTxKey(tx.Key)
+		tx.LedgerInternal = tx.Ledger
+		tx.Ledger = params.GetLedger()
+		var protoTx = proto_orderer.Transaction{}
+		cstructs.Copy(tx, &protoTx)
+		protoTxs[i] = &protoTx
+	}
+
+	var nextCursor string
+	if len(txs) > 0 && len(txs) == limit {
+		nextCursor = types.MakeRangeCursor(txs[len(txs)-1].Key)
+	}
+
+	return &proto_orderer.Transactions{
+		Transactions: protoTxs,
+		NextCursor:   nextCursor,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package orderer
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/envelope"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+func TestQuery(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_query_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_QUERY_ADDR", "127.0.0.1:7016")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	Convey("Orderer", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		ledger, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+			CocoonID: cocoonID,
+			Name:     ledgerName,
+			Chained:  true,
+			Indexes:  []*proto_orderer.Index{{Name: "by_status", Path: "status"}},
+		})
+		So(err, ShouldBeNil)
+		So(len(ledger.Indexes), ShouldEqual, 1)
+		So(ledger.Indexes[0].Path, ShouldEqual, "status")
+
+		result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+			CocoonID:   cocoonID,
+			LedgerName: ledgerName,
+			Transactions: []*proto_orderer.Transaction{
+				{Id: util.UUID4(), Key: "order:1", Value: `{"status": "paid"}`},
+				{Id: util.UUID4(), Key: "order:2", Value: `{"status": "new"}`},
+				{Id: util.UUID4(), Key: "order:3", Value: `{"status": "paid"}`},
+			},
+		})
+		So(err, ShouldBeNil)
+		So(len(result.TxReceipts), ShouldEqual, 3)
+
+		Convey(".Query", func() {
+
+			Convey("Should page through the matching keys", func() {
+				txs, err := od.Query(context.Background(), &proto_orderer.QueryParams{
+					CocoonID: cocoonID,
+					Ledger:   ledgerName,
+					Index:    "by_status",
+					Op:       types.QueryOpEq,
+					Value:    `"paid"`,
+					Limit:    1,
+				})
+				So(err, ShouldBeNil)
+				So(len(txs.Transactions), ShouldEqual, 1)
+				So(txs.Transactions[0].Key, ShouldEqual, "order:1")
+				So(txs.Transactions[0].Block, ShouldNotBeNil)
+				So(txs.NextCursor, ShouldNotBeEmpty)
+
+				txs, err = od.Query(context.Background(), &proto_orderer.QueryParams{
+					CocoonID: cocoonID,
+					Ledger:   ledgerName,
+					Index:    "by_status",
+					Op:       types.QueryOpEq,
+					Value:    `"paid"`,
+					Cursor:   txs.NextCursor,
+				})
+				So(err, ShouldBeNil)
+				So(len(txs.Transactions), ShouldEqual, 1)
+				So(txs.Transactions[0].Key, ShouldEqual, "order:3")
+				So(txs.NextCursor, ShouldBeEmpty)
+			})
+
+			Convey("Should return error if the index does not exist", func() {
+				_, err := od.Query(context.Background(), &proto_orderer.QueryParams{
+					CocoonID: cocoonID,
+					Ledger:   ledgerName,
+					Index:    "by_total",
+					Op:       types.QueryOpEq,
+					Value:    `1`,
+				})
+				So(err, ShouldEqual, types.ErrIndexNotFound)
+			})
+		})
+
+		Convey("Should query a private ledger whose values are encrypted", func() {
+			key, _ := envelope.GenerateKey()
+			keyring, _ := envelope.NewKeyring(key)
+			od.SetKeyProvider(keyring)
+			defer od.SetKeyProvider(nil)
+
+			encryptedLedger := util.RandString(5)
+			_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+				CocoonID: cocoonID,
+				Name:     encryptedLedger,
+				Indexes:  []*proto_orderer.Index{{Name: "by_status", Path: "status"}},
+			})
+			So(err, ShouldBeNil)
+
+			_, err = od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+				CocoonID:   cocoonID,
+				LedgerName: encryptedLedger,
+				Transactions: []*proto_orderer.Transaction{
+					{Id: util.UUID4(), Key: "order:1", Value: `{"status": "paid", "card": "4242"}`},
+					{Id: util.UUID4(), Key: "order:2", Value: `{"status": "new", "card": "1881"}`},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			tx, err := od.store.Get(types.MakeLedgerName(cocoonID, encryptedLedger), types.MakeTxKey(cocoonID, "order:1"), false)
+			So(err, ShouldBeNil)
+			So(envelope.IsEncrypted(tx.Value), ShouldBeTrue)
+
+			txs, err := od.Query(context.Background(), &proto_orderer.QueryParams{
+				CocoonID: cocoonID,
+				Ledger:   encryptedLedger,
+				Index:    "by_status",
+				Op:       types.QueryOpEq,
+				Value:    `"paid"`,
+			})
+			So(err, ShouldBeNil)
+			So(len(txs.Transactions), ShouldEqual, 1)
+			So(txs.Transactions[0].Key, ShouldEqual, "order:1")
+			So(txs.Transactions[0].Value, ShouldEqual, `{"status": "paid", "card": "4242"}`)
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package orderer
+
+import "github.com/ellcrys/cocoon/core/metrics"
+
+var (
+	// putDuration measures the time taken by Put and PutMulti, including forwarding to a chain leader
+	putDuration = metrics.NewHistogram("cocoon_orderer_put_duration_seconds", "Time taken to store transactions", nil, "method")
+
+	// putReceipts counts the transaction receipts of stored transactions by outcome
+	putReceipts = metrics.NewCounter("cocoon_orderer_put_receipts_total", "Transaction receipts by outcome (committed or rejected)", "outcome")
+
+	// blockCreateRetries counts block creations re-run because another block was concurrently added to the chain
+	blockCreateRetries = metrics.NewCounter("cocoon_orderer_block_create_retries_total", "Block creations re-run after a concurrent block was added to the chain")
+)
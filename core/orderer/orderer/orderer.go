@@ -12,9 +12,14 @@ import (
 
 	"github.com/chuckpreslar/emission"
 	"github.com/ellcrys/util"
+	blkch_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/config"
 	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/envelope"
+	"github.com/ellcrys/cocoon/core/store/impl"
 	"github.com/ellcrys/cocoon/core/types"
 	"github.com/ncodes/cstructs"
 	logging "github.com/op/go-logging"
@@ -31,11 +36,22 @@ func SetLogLevel(l logging.Level) {
 // Orderer defines a transaction ordering, block creation
 // and inclusion module
 type Orderer struct {
-	server       *grpc.Server
-	store        types.Store
-	blockchain   types.Blockchain
-	endedCh      chan bool
-	EventEmitter *emission.Emitter
+	server        *grpc.Server
+	store         types.Store
+	blockchain    types.Blockchain
+	endedCh       chan bool
+	EventEmitter  *emission.Emitter
+	broker        *broker
+	signer        *signer.Signer
+	pruneEvery    time.Duration
+	rotateEvery   time.Duration
+	stopJobsCh    chan bool
+	keyProvider   envelope.KeyProvider
+	dataKeys      *dataKeyCache
+	sealedUpTo    map[string]uint
+	advertiseAddr string
+	leaderTTL     time.Duration
+	elector       *elector
 }
 
 // NewOrderer creates a new Orderer object
@@ -43,13 +59,93 @@ func NewOrderer() *Orderer {
 	o := new(Orderer)
 	o.EventEmitter = emission.NewEmitter()
 	o.EventEmitter.SetMaxListeners(20)
+	o.broker = newBroker()
+	o.stopJobsCh = make(chan bool)
+	o.dataKeys = &dataKeyCache{keys: make(map[string][]byte)}
+	o.sealedUpTo = make(map[string]uint)
+	o.rotateEvery = DefaultKeyRotationInterval
+	o.leaderTTL = DefaultLeaderTTL
 	return o
 }
 
-// Start starts the order service
+// setBackendFromConStr sets the store and blockchain implementations that
+// have not been explicitly set. The embedded bolt backend is selected if the
+// connection string uses the `bolt://` scheme, otherwise postgres is used.
+func (od *Orderer) setBackendFromConStr(storeConStr string) {
+	if common.IsBoltConStr(storeConStr) {
+		if od.store == nil {
+			od.SetStore(new(impl.BoltStore))
+		}
+		if od.blockchain == nil {
+			od.SetBlockchain(new(blkch_impl.BoltBlockchain))
+		}
+		return
+	}
+	if od.store == nil {
+		od.SetStore(new(impl.PostgresStore))
+	}
+	if od.blockchain == nil {
+		od.SetBlockchain(new(blkch_impl.PostgresBlockchain))
+	}
+}
+
+// connectBackend connects to and initializes the blockchain and store
+// backends and signs new blocks with the signer, if one is set.
+func (od *Orderer) connectBackend(storeConStr string) error {
+
+	if od.blockchain == nil {
+		return fmt.Errorf("blockchain implementation not set")
+	}
+
+	if od.signer != nil {
+		log.Infof("Signing blocks with key %s", od.signer.KeyID())
+		od.blockchain.SetSigner(od.signer)
+	} else {
+		log.Warning("No signing key set. Blocks will not be signed")
+	}
+
+	// establish connection to blockchain backend
+	if _, err := od.blockchain.Connect(storeConStr); err != nil {
+		return err
+	}
+
+	// initialize the blockchain
+	if err := od.blockchain.Init(); err != nil {
+		return err
+	}
+
+	// initialize store
+	if od.store == nil {
+		return fmt.Errorf("store implementation not set")
+	}
+
+	// establish connection to store backend
+	if _, err := od.store.Connect(storeConStr); err != nil {
+		return err
+	}
+
+	if len(os.Getenv("DEV_MEM_LOCK")) != 0 {
+		log.Debug("Memory based lock is in use")
+	}
+
+	sysPubLedger := types.MakeLedgerName(types.SystemCocoonID, types.GetSystemPublicLedgerName())
+	sysPrivLedger := types.MakeLedgerName(types.SystemCocoonID, types.GetSystemPrivateLedgerName())
+	od.store.SetBlockchainImplementation(od.blockchain)
+	return od.store.Init(sysPubLedger, sysPrivLedger)
+}
+
+// Start starts the order service. If the store and blockchain
+// implementations have not been set, they are selected
+// based on the scheme of the store connection string.
 func (od *Orderer) Start(addr, storeConStr string, endedCh chan bool) {
 
 	od.endedCh = endedCh
+	od.setBackendFromConStr(storeConStr)
+
+	if len(od.advertiseAddr) == 0 {
+		od.advertiseAddr = addr
+	}
+	od.elector = newElector(od.advertiseAddr, od.leaderTTL, od.blockchain)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s", addr))
 	if err != nil {
@@ -60,61 +156,22 @@ func (od *Orderer) Start(addr, storeConStr string, endedCh chan bool) {
 
 		log.Infof("Started orderer GRPC server on port %s", strings.Split(addr, ":")[1])
 
-		if od.blockchain == nil {
-			log.Error("Blockchain implementation not set")
-			od.Stop()
-			return
-		}
-
-		// establish connection to blockchain backend
-		if od.blockchain != nil {
-			_, err = od.blockchain.Connect(storeConStr)
-			if err != nil {
-				log.Info(err.Error(), storeConStr)
-				od.Stop()
-				return
-			}
-		}
-
-		// initialize the blockchain
-		err = od.blockchain.Init()
-		if err != nil {
+		if err := od.connectBackend(storeConStr); err != nil {
 			log.Info(err.Error())
 			od.Stop()
 			return
 		}
 
-		// initialize store
-		if od.store == nil {
-			log.Error("Store implementation not set")
-			od.Stop()
-			return
-		}
-
-		// establish connection to store backend
-		_, err := od.store.Connect(storeConStr)
-		if err != nil {
-			log.Info(err.Error())
-			od.Stop()
-			return
-		}
+		log.Info("Backend successfully connected")
+		od.EventEmitter.Emit("started")
 
-		if len(os.Getenv("DEV_MEM_LOCK")) != 0 {
-			log.Debug("Memory based lock is in use")
+		if od.pruneEvery > 0 {
+			go od.runPruner(od.pruneEvery, od.stopJobsCh)
 		}
 
-		sysPubLedger := types.MakeLedgerName(types.SystemCocoonID, types.GetSystemPublicLedgerName())
-		sysPrivLedger := types.MakeLedgerName(types.SystemCocoonID, types.GetSystemPrivateLedgerName())
-		od.store.SetBlockchainImplementation(od.blockchain)
-		err = od.store.Init(sysPubLedger, sysPrivLedger)
-		if err != nil {
-			log.Info(err.Error())
-			od.Stop()
-			return
+		if od.keyProvider != nil && od.rotateEvery > 0 {
+			go od.runKeyRotation(od.rotateEvery, od.stopJobsCh)
 		}
-
-		log.Info("Backend successfully connected")
-		od.EventEmitter.Emit("started")
 	})
 
 	od.server = grpc.NewServer()
@@ -122,14 +179,62 @@ func (od *Orderer) Start(addr, storeConStr string, endedCh chan bool) {
 	od.server.Serve(lis)
 }
 
+// Open connects the orderer to its backends without starting the
+// GRPC server, so it can be embedded and called directly. An opened
+// orderer is the only writer of its backends and leads every chain.
+// Close must be called to release the backends.
+func (od *Orderer) Open(storeConStr string) error {
+	od.setBackendFromConStr(storeConStr)
+	return od.connectBackend(storeConStr)
+}
+
+// Close closes the backends of an orderer opened with Open
+func (od *Orderer) Close() {
+	od.store.Close()
+	od.blockchain.Close()
+}
+
 // Stop stops the orderer
 func (od *Orderer) Stop() {
+	close(od.stopJobsCh)
+	od.elector.release()
 	od.server.Stop()
 	od.store.Close()
 	od.blockchain.Close()
 	close(od.endedCh)
 }
 
+// SetSigner sets the signer used to sign new blocks
+func (od *Orderer) SetSigner(s *signer.Signer) {
+	od.signer = s
+}
+
+// SetPruneInterval sets how often ledgers with a retention policy are pruned.
+// Ledgers are not pruned in the background if interval is zero.
+func (od *Orderer) SetPruneInterval(interval time.Duration) {
+	od.pruneEvery = interval
+}
+
+// SetKeyRotationInterval sets how often the data keys of encrypted ledgers are
+// wrapped again with the current master key and the values stored before
+// encryption was enabled are encrypted. Keys are not rotated in the
+// background if interval is zero.
+func (od *Orderer) SetKeyRotationInterval(interval time.Duration) {
+	od.rotateEvery = interval
+}
+
+// SetAdvertiseAddr sets the address other orderers use to forward writes
+// to chains led by this orderer. It defaults to the listening address.
+func (od *Orderer) SetAdvertiseAddr(addr string) {
+	od.advertiseAddr = addr
+}
+
+// SetLeaderTTL sets how long the leadership of a chain
+// is held after the orderer stops appending to it
+func (od *Orderer) SetLeaderTTL(ttl time.Duration) {
+	od.leaderTTL = ttl
+}
+
 // SetStore sets the store implementation to use.
 func (od *Orderer) SetStore(ch types.Store) {
 	log.Infof("Setting store implementation named %s", ch.GetImplementationName())
@@ -142,22 +247,42 @@ func (od *Orderer) SetBlockchain(b types.Blockchain) {
 	od.blockchain = b
 }
 
-// CreateLedger creates a new ledger
+// makeProtoLedger copies a store ledger to a proto ledger
+func makeProtoLedger(ledger *types.Ledger) *proto_orderer.Ledger {
+	var protoLedger proto_orderer.Ledger
+	cstructs.Copy(ledger, &protoLedger)
+	for _, index := range ledger.Indexes {
+		protoLedger.Indexes = append(protoLedger.Indexes, &proto_orderer.Index{Name: index.Name, Path: index.Path})
+	}
+	return &protoLedger
+}
+
+// CreateLedger creates a new ledger. Secondary indexes declared in the params are
+// set on the ledger once created. The indexed values of a private ledger whose values
+// are encrypted are stored unencrypted (see encryptTxs).
 func (od *Orderer) CreateLedger(ctx context.Context, params *proto_orderer.CreateLedgerParams) (*proto_orderer.Ledger, error) {
 
+	indexes := makeIndexes(params.GetIndexes())
+	if err := indexes.Validate(); err != nil {
+		return nil, err
+	}
+
 	internalName := types.MakeLedgerName(params.GetCocoonID(), params.GetName())
 	ledger, err := od.store.CreateLedger(params.CocoonID, internalName, params.GetChained(), params.GetPublic())
 	if err != nil {
 		return nil, err
 	}
 
+	if len(indexes) > 0 {
+		if ledger, err = od.store.SetLedgerIndexes(internalName, indexes); err != nil {
+			return nil, err
+		}
+	}
+
 	ledger.Name = params.GetName()
 	ledger.NameInternal = internalName
 
-	var protoLedger proto_orderer.Ledger
-	cstructs.Copy(ledger, &protoLedger)
-
-	return &protoLedger, nil
+	return makeProtoLedger(ledger), nil
 }
 
 // GetLedger returns a ledger
@@ -174,106 +299,320 @@ func (od *Orderer) GetLedger(ctx context.Context, params *proto_orderer.GetLedge
 	ledger.Name = params.GetName()
 	ledger.NameInternal = internalName
 
-	var protoLedger proto_orderer.Ledger
-	cstructs.Copy(ledger, &protoLedger)
-
-	return &protoLedger, nil
+	return makeProtoLedger(ledger), nil
 }
 
-// Put creates a new transaction
-func (od *Orderer) Put(ctx context.Context, params *proto_orderer.PutTransactionParams) (*proto_orderer.PutResult, error) {
+// SetLedgerRetention sets the retention policy of a ledger. Superseded transactions
+// outside the policy are removed the next time the ledger is pruned. Setting both
+// keepRevisions and keepFor to zero removes the policy.
+func (od *Orderer) SetLedgerRetention(ctx context.Context, params *proto_orderer.SetLedgerRetentionParams) (*proto_orderer.Ledger, error) {
 
-	start := time.Now()
+	if params.GetKeepRevisions() < 0 || params.GetKeepFor() < 0 {
+		return nil, fmt.Errorf("retention values cannot be negative")
+	}
 
-	// check if ledger exists
-	internalLedgerName := types.MakeLedgerName(params.GetCocoonID(), params.GetLedgerName())
-	ledger, err := od.store.GetLedger(internalLedgerName)
+	internalName := types.MakeLedgerName(params.GetCocoonID(), params.GetName())
+	ledger, err := od.store.GetLedger(internalName)
 	if err != nil {
 		return nil, err
-	} else if err == nil && ledger == nil {
+	} else if ledger == nil {
 		return nil, types.ErrLedgerNotFound
 	}
 
-	// copy individual tx from []proto_orderer.Transaction to []types.Transaction
-	// and set transactions key and block id
-	blockID := util.Sha256(util.UUID4())
-	var transactions = make([]*types.Transaction, len(params.GetTransactions()))
-	for i, protoTx := range params.GetTransactions() {
+	ledger, err = od.store.SetLedgerRetention(internalName, uint(params.GetKeepRevisions()), params.GetKeepFor())
+	if err != nil {
+		return nil, err
+	}
+
+	ledger.Name = params.GetName()
+	ledger.NameInternal = internalName
+
+	return makeProtoLedger(ledger), nil
+}
+
+// makeTransactions copies the proto transactions of a ledger to store transactions and
+// namespaces their keys with the cocoon id. The transactions of a chained ledger are
+// assigned the id of the block that will include them.
+func makeTransactions(cocoonID string, ledger *types.Ledger, blockID string, protoTxs []*proto_orderer.Transaction) []*types.Transaction {
+	var transactions = make([]*types.Transaction, len(protoTxs))
+	for i, protoTx := range protoTxs {
 		transactions[i] = &types.Transaction{
 			Ledger:     protoTx.Ledger,
 			ID:         protoTx.Id,
-			Key:        types.MakeTxKey(params.GetCocoonID(), protoTx.Key),
+			Key:        types.MakeTxKey(cocoonID, protoTx.Key),
 			Value:      protoTx.Value,
 			RevisionTo: protoTx.RevisionTo,
+			Deleted:    protoTx.Deleted,
 			CreatedAt:  protoTx.CreatedAt,
 		}
+		if transactions[i].Deleted {
+			transactions[i].Value = ""
+		}
+		for _, read := range protoTx.GetReadSet() {
+			transactions[i].ReadSet = append(transactions[i].ReadSet, &types.KeyRevision{
+				Key:      types.MakeTxKey(cocoonID, read.Key),
+				Revision: read.Revision,
+			})
+		}
 		if ledger.Chained {
 			transactions[i].BlockID = blockID
 		}
 	}
+	return transactions
+}
+
+// createBlock creates a block that includes the valid transactions of a chained ledger.
+// Only the leader of the chain creates blocks, but block creation is still re-run
+// if another block was concurrently added to the chain during a change of leader.
+func (od *Orderer) createBlock(blockchain types.Blockchain, blockID, chainName string, validTransactions []*types.Transaction) (*types.Block, error) {
+
+	if len(validTransactions) == 0 {
+		return nil, fmt.Errorf("no valid transaction to add to block")
+	}
+
+	var block *types.Block
+	var err error
+	var attempts int
+	common.ReRunOnError(func() error {
+		if attempts++; attempts > 1 {
+			blockCreateRetries.Inc()
+		}
+		block, err = blockchain.CreateBlock(blockID, chainName, validTransactions)
+		// If error is not a duplicate previous block hash error, don't re-run.
+		// return nil to end the re-run routine
+		if err != nil && !types.IsDuplicatePrevBlockHashError(err) {
+			return nil
+		}
+		return err
+	}, 5, time.Duration(2)*time.Second)
+
+	return block, err
+}
+
+// makeProtoBlock copies a block to a proto block
+func makeProtoBlock(b *types.Block) *proto_orderer.Block {
+	return &proto_orderer.Block{
+		Id:            b.ID,
+		ChainName:     b.ChainName,
+		Hash:          b.Hash,
+		Number:        int64(b.Number),
+		PrevBlockHash: b.PrevBlockHash,
+		MerkleRoot:    b.MerkleRoot,
+		Transactions:  b.Transactions,
+		CreatedAt:     b.CreatedAt,
+		Signature:     b.Signature,
+		KeyId:         b.KeyID,
+	}
+}
+
+// makePutResult creates the result of a put operation on a ledger and
+// signals the subscribers of the ledger if transactions were committed
+func (od *Orderer) makePutResult(ledgerName string, txReceipts []*types.TxReceipt, block *types.Block) *proto_orderer.PutResult {
+
+	var protoTxReceipts = make([]*proto_orderer.TxReceipt, len(txReceipts))
+	var committed bool
+	for i, r := range txReceipts {
+		protoTxReceipts[i] = &proto_orderer.TxReceipt{ID: r.ID, Err: r.Err}
+		if len(r.Err) == 0 {
+			committed = true
+			putReceipts.Inc("committed")
+		} else {
+			putReceipts.Inc("rejected")
+		}
+	}
+
+	if committed {
+		od.broker.publish(ledgerName)
+	}
+
+	result := &proto_orderer.PutResult{TxReceipts: protoTxReceipts}
+	if block != nil {
+		result.Block = makeProtoBlock(block)
+	}
+
+	return result
+}
+
+// Put creates a new transaction. Puts to a chained ledger
+// are forwarded to the leader of the chain.
+func (od *Orderer) Put(ctx context.Context, params *proto_orderer.PutTransactionParams) (*proto_orderer.PutResult, error) {
+
+	start := time.Now()
+	defer putDuration.ObserveSince(start, "put")
+
+	// check if ledger exists
+	internalLedgerName := types.MakeLedgerName(params.GetCocoonID(), params.GetLedgerName())
+	ledger, err := od.store.GetLedger(internalLedgerName)
+	if err != nil {
+		return nil, err
+	} else if err == nil && ledger == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	if ledger.Chained {
+		leaderAddr, isLeader, err := od.elector.leaderOf(internalLedgerName)
+		if err != nil {
+			return nil, err
+		} else if !isLeader {
+			var result *proto_orderer.PutResult
+			err = od.forwardToLeader(ctx, leaderAddr, func(ctx context.Context, client proto_orderer.OrdererClient) error {
+				result, err = client.Put(ctx, params)
+				return err
+			})
+			return result, err
+		}
+	}
+
+	// copy individual tx from []proto_orderer.Transaction to []types.Transaction
+	// and set transactions key and block id
+	blockID := util.Sha256(util.UUID4())
+	transactions := makeTransactions(params.GetCocoonID(), ledger, blockID, params.GetTransactions())
+	if err := od.encryptTxs(ledger, transactions); err != nil {
+		return nil, err
+	}
 
 	// Create sub routine for PutThen() to create a block that includes all the transactions
 	// that have been successfully stored in transaction created by PutThen().
 	// If an empty transaction list is returned, this means they failed to be included in
 	// the native postgres transactions and as such, we simply return an error which will cause
 	// the PutThen() method to rollback the native Postgres transaction
-	var block *proto_orderer.Block
-	var createBlockFunc func(validTransactions []*types.Transaction) error
-	if ledger.Chained {
-		block = &proto_orderer.Block{}
-		createBlockFunc = func(validTransactions []*types.Transaction) error {
+	// The store and blockchain operations are made in one database transaction so that
+	// a block is never left behind by transactions that failed to be committed.
+	var createdBlock *types.Block
+	var txReceipts []*types.TxReceipt
+	err = od.store.Transact(func(store types.Store, blockchain types.Blockchain) error {
+		var createBlockFunc func(validTransactions []*types.Transaction) error
+		if ledger.Chained {
+			createBlockFunc = func(validTransactions []*types.Transaction) error {
+				if err := od.elector.fence(blockchain, internalLedgerName); err != nil {
+					return err
+				}
+				var err error
+				createdBlock, err = od.createBlock(blockchain, blockID, internalLedgerName, validTransactions)
+				return err
+			}
+		}
+		var err error
+		txReceipts, err = store.PutThen(internalLedgerName, transactions, createBlockFunc)
+		return err
+	})
+	if err != nil {
+		log.Errorf("failed to PUT: %s", err.Error())
+		return nil, err
+	}
+
+	// blocks are returned with the values that were put
+	if createdBlock, err = od.decryptBlock(internalLedgerName, ledger.Public, createdBlock); err != nil {
+		return nil, err
+	}
+
+	result := od.makePutResult(internalLedgerName, txReceipts, createdBlock)
+
+	return result, nil
+}
+
+// PutMulti atomically creates transactions in several ledgers of a cocoon. Either
+// all the transactions are stored or none is. When a transaction fails, every other
+// transaction gets a receipt with a batch aborted error. A block is created for each
+// chained ledger; the results are returned in the order of the ledgers. The batch is
+// forwarded to the leader of the first chained ledger the orderer does not lead; a
+// batch whose chains are led by different orderers fails with ErrNotChainLeader.
+func (od *Orderer) PutMulti(ctx context.Context, params *proto_orderer.PutMultiParams) (*proto_orderer.PutMultiResult, error) {
+
+	start := time.Now()
+	defer putDuration.ObserveSince(start, "put_multi")
+
+	var batch = make([]*types.LedgerTransactions, len(params.GetLedgers()))
+	var ledgers = make([]*types.Ledger, len(params.GetLedgers()))
+	var blockIDs = make([]string, len(params.GetLedgers()))
+	var seen = make(map[string]bool)
+	for i, group := range params.GetLedgers() {
+
+		internalLedgerName := types.MakeLedgerName(params.GetCocoonID(), group.GetLedger())
+		if seen[internalLedgerName] {
+			return nil, fmt.Errorf("ledger %s appears more than once in the batch", group.GetLedger())
+		}
+		seen[internalLedgerName] = true
 
-			var err error
+		ledger, err := od.store.GetLedger(internalLedgerName)
+		if err != nil {
+			return nil, err
+		} else if ledger == nil {
+			return nil, types.ErrLedgerNotFound
+		}
 
-			if len(validTransactions) == 0 {
-				return fmt.Errorf("no valid transaction to add to block")
+		if ledger.Chained {
+			leaderAddr, isLeader, err := od.elector.leaderOf(internalLedgerName)
+			if err != nil {
+				return nil, err
+			} else if !isLeader {
+				var result *proto_orderer.PutMultiResult
+				err = od.forwardToLeader(ctx, leaderAddr, func(ctx context.Context, client proto_orderer.OrdererClient) error {
+					result, err = client.PutMulti(ctx, params)
+					return err
+				})
+				return result, err
 			}
+		}
 
-			common.ReRunOnError(func() error {
-				b, err := od.blockchain.CreateBlock(blockID, internalLedgerName, validTransactions)
-				if b != nil {
-					block.Id = b.ID
-					block.ChainName = b.ChainName
-					block.Hash = b.Hash
-					block.Number = int64(b.Number)
-					block.PrevBlockHash = b.PrevBlockHash
-					block.Transactions = b.Transactions
-					block.CreatedAt = b.CreatedAt
-				}
-				// If error is not a duplicate previous block hash error, don't re-run.
-				// return nil to end the re-run routine
-				if err != nil && !types.IsDuplicatePrevBlockHashError(err) {
-					return nil
-				}
-				return err
-			}, 5, time.Duration(2)*time.Second)
-			return err
+		ledgers[i] = ledger
+		blockIDs[i] = util.Sha256(util.UUID4())
+		batch[i] = &types.LedgerTransactions{
+			Ledger:       internalLedgerName,
+			Transactions: makeTransactions(params.GetCocoonID(), ledger, blockIDs[i], group.GetTransactions()),
+		}
+		if err := od.encryptTxs(ledger, batch[i].Transactions); err != nil {
+			return nil, err
 		}
 	}
 
-	txReceipts, err := od.store.PutThen(internalLedgerName, transactions, createBlockFunc)
+	// create a block for each chained ledger once all the transactions of the
+	// batch have been validated. The blocks are created in the database transaction
+	// of the batch, so none is kept if a block or the batch fails to be committed.
+	var createdBlocks = make([]*types.Block, len(batch))
+	var txReceipts [][]*types.TxReceipt
+	err := od.store.Transact(func(store types.Store, blockchain types.Blockchain) error {
+		createBlocksFunc := func(validBatch []*types.LedgerTransactions) error {
+			for i, group := range validBatch {
+				if !ledgers[i].Chained {
+					continue
+				}
+				if err := od.elector.fence(blockchain, group.Ledger); err != nil {
+					return err
+				}
+				var err error
+				createdBlocks[i], err = od.createBlock(blockchain, blockIDs[i], group.Ledger, group.Transactions)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		var err error
+		txReceipts, err = store.PutMultiThen(batch, createBlocksFunc)
+		return err
+	})
 	if err != nil {
 		log.Errorf("failed to PUT: %s", err.Error())
 		return nil, err
 	}
 
-	var protoTxReceipts = make([]*proto_orderer.TxReceipt, len(txReceipts))
-	for i, r := range txReceipts {
-		protoTxReceipts[i] = &proto_orderer.TxReceipt{ID: r.ID, Err: r.Err}
+	var result = &proto_orderer.PutMultiResult{}
+	for i, group := range batch {
+		if createdBlocks[i], err = od.decryptBlock(group.Ledger, ledgers[i].Public, createdBlocks[i]); err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, od.makePutResult(group.Ledger, txReceipts[i], createdBlocks[i]))
 	}
 
-	log.Debug("Put(): Time taken: ", time.Since(start))
-
-	return &proto_orderer.PutResult{
-		TxReceipts: protoTxReceipts,
-		Block:      block,
-	}, nil
+	return result, nil
 }
 
-// Get returns a transaction with a matching key
+// Get returns a transaction with a matching key. A key whose
+// most recent transaction is a tombstone is treated as missing
+// unless the deleted transaction is explicitly requested.
 func (od *Orderer) Get(ctx context.Context, params *proto_orderer.GetParams) (*proto_orderer.Transaction, error) {
 
-	start := time.Now()
 	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
 		CocoonID: params.GetCocoonID(),
 		Name:     params.GetLedger(),
@@ -283,7 +622,7 @@ func (od *Orderer) Get(ctx context.Context, params *proto_orderer.GetParams) (*p
 	}
 
 	key := types.MakeTxKey(params.GetCocoonID(), params.GetKey())
-	tx, err := od.store.Get(ledger.NameInternal, key)
+	tx, err := od.store.Get(ledger.NameInternal, key, params.GetIncludeDeleted())
 	if err != nil {
 		return nil, err
 	}
@@ -291,6 +630,10 @@ func (od *Orderer) Get(ctx context.Context, params *proto_orderer.GetParams) (*p
 		return nil, types.ErrTxNotFound
 	}
 
+	if err := od.decryptTxs(ledger.NameInternal, ledger.Public, []*types.Transaction{tx}); err != nil {
+		return nil, err
+	}
+
 	tx.Key = params.GetKey()
 	tx.KeyInternal = key
 	tx.Ledger = params.GetLedger()
@@ -302,6 +645,9 @@ func (od *Orderer) Get(ctx context.Context, params *proto_orderer.GetParams) (*p
 		} else if block == nil && err == nil {
 			return nil, fmt.Errorf("orphaned transaction")
 		}
+		if block, err = od.decryptBlock(ledger.NameInternal, ledger.Public, block); err != nil {
+			return nil, err
+		}
 
 		tx.Block = block
 		tx.BlockID = ""
@@ -310,8 +656,6 @@ func (od *Orderer) Get(ctx context.Context, params *proto_orderer.GetParams) (*p
 	var protoTx proto_orderer.Transaction
 	cstructs.Copy(tx, &protoTx)
 
-	log.Debug("Get(): Time taken: ", time.Since(start))
-
 	return &protoTx, nil
 }
 
@@ -333,13 +677,107 @@ func (od *Orderer) GetBlockByID(ctx context.Context, params *proto_orderer.GetBl
 		return nil, types.ErrBlockNotFound
 	}
 
+	if blk, err = od.decryptBlock(ledger.NameInternal, ledger.Public, blk); err != nil {
+		return nil, err
+	}
+
 	var protoBlk proto_orderer.Block
 	cstructs.Copy(blk, &protoBlk)
 
 	return &protoBlk, nil
 }
 
-// GetRange fetches transactions between a range of keys
+// maxListBlocksLimit is the maximum number of blocks returned by ListBlocks
+const maxListBlocksLimit = 100
+
+// getChainedLedger returns a ledger of a cocoon. It returns
+// ErrLedgerNotChained if the ledger has no chain.
+func (od *Orderer) getChainedLedger(ctx context.Context, cocoonID, name string) (*proto_orderer.Ledger, error) {
+	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
+		CocoonID: cocoonID,
+		Name:     name,
+	})
+	if err != nil {
+		return nil, err
+	} else if !ledger.Chained {
+		return nil, types.ErrLedgerNotChained
+	}
+	return ledger, nil
+}
+
+// GetBlockByNumber returns a block of a ledger by its number
+func (od *Orderer) GetBlockByNumber(ctx context.Context, params *proto_orderer.GetBlockByNumberParams) (*proto_orderer.Block, error) {
+
+	ledger, err := od.getChainedLedger(ctx, params.GetCocoonID(), params.GetLedger())
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := od.blockchain.GetBlockByNumber(ledger.NameInternal, uint(params.GetNumber()))
+	if err != nil {
+		return nil, err
+	} else if blk == nil {
+		return nil, types.ErrBlockNotFound
+	}
+
+	if blk, err = od.decryptBlock(ledger.NameInternal, ledger.Public, blk); err != nil {
+		return nil, err
+	}
+
+	return makeProtoBlock(blk), nil
+}
+
+// GetChainHeight returns the number of the last block of a ledger.
+// The height of a chain with no block is zero.
+func (od *Orderer) GetChainHeight(ctx context.Context, params *proto_orderer.GetChainHeightParams) (*proto_orderer.ChainHeight, error) {
+
+	ledger, err := od.getChainedLedger(ctx, params.GetCocoonID(), params.GetLedger())
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := od.blockchain.GetChainHeight(ledger.NameInternal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto_orderer.ChainHeight{Height: int64(height)}, nil
+}
+
+// ListBlocks returns the blocks of a ledger in ascending order of block number,
+// starting from the block with number fromNumber. At most maxListBlocksLimit
+// blocks are returned; a limit of zero returns the maximum.
+func (od *Orderer) ListBlocks(ctx context.Context, params *proto_orderer.ListBlocksParams) (*proto_orderer.Blocks, error) {
+
+	ledger, err := od.getChainedLedger(ctx, params.GetCocoonID(), params.GetLedger())
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(params.GetLimit())
+	if limit <= 0 || limit > maxListBlocksLimit {
+		limit = maxListBlocksLimit
+	}
+
+	blocks, err := od.blockchain.ListBlocks(ledger.NameInternal, uint(params.GetFromNumber()), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = &proto_orderer.Blocks{}
+	for _, blk := range blocks {
+		if blk, err = od.decryptBlock(ledger.NameInternal, ledger.Public, blk); err != nil {
+			return nil, err
+		}
+		result.Blocks = append(result.Blocks, makeProtoBlock(blk))
+	}
+
+	return result, nil
+}
+
+// GetRange fetches transactions between a range of keys or with keys
+// matching a prefix. If the number of transactions returned is equal to
+// the limit, the result includes a cursor for fetching the next page.
 func (od *Orderer) GetRange(ctx context.Context, params *proto_orderer.GetRangeParams) (*proto_orderer.Transactions, error) {
 
 	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
@@ -350,6 +788,22 @@ func (od *Orderer) GetRange(ctx context.Context, params *proto_orderer.GetRangeP
 		return nil, err
 	}
 
+	if len(params.GetPrefix()) > 0 {
+		if len(params.GetStartKey()) > 0 || len(params.GetEndKey()) > 0 {
+			return nil, fmt.Errorf("prefix cannot be combined with a start or end key")
+		}
+		params.StartKey = params.GetPrefix()
+	}
+
+	var afterKey string
+	if len(params.GetCursor()) > 0 {
+		key, err := types.ParseRangeCursor(params.GetCursor())
+		if err != nil {
+			return nil, err
+		}
+		afterKey = types.MakeTxKey(params.GetCocoonID(), key)
+	}
+
 	if len(params.GetStartKey()) > 0 {
 		params.StartKey = types.MakeTxKey(params.GetCocoonID(), params.GetStartKey())
 	}
@@ -362,11 +816,15 @@ func (od *Orderer) GetRange(ctx context.Context, params *proto_orderer.GetRangeP
 		}
 	}
 
-	txs, err := od.store.GetRange(ledger.NameInternal, params.GetStartKey(), params.GetEndKey(), params.GetInclusive(), int(params.GetLimit()), int(params.GetOffset()))
+	txs, err := od.store.GetRange(ledger.NameInternal, params.GetStartKey(), params.GetEndKey(), params.GetInclusive(), params.GetIncludeDeleted(), params.GetReverse(), afterKey, int(params.GetLimit()), int(params.GetOffset()))
 	if err != nil {
 		return nil, err
 	}
 
+	if err := od.decryptTxs(ledger.NameInternal, ledger.Public, txs); err != nil {
+		return nil, err
+	}
+
 	// fetch transaction blocks and copy individual tx from []types.Transaction to []proto_orderer.Transaction
 	var protoTxs = make([]*proto_orderer.Transaction, len(txs))
 	for i, tx := range txs {
@@ -378,6 +836,9 @@ func (od *Orderer) GetRange(ctx context.Context, params *proto_orderer.GetRangeP
 			} else if block == nil && err == nil {
 				return nil, fmt.Errorf("orphaned transaction")
 			}
+			if block, err = od.decryptBlock(ledger.NameInternal, ledger.Public, block); err != nil {
+				return nil, err
+			}
 
 			tx.Block = block
 			tx.BlockID = ""
@@ -392,7 +853,207 @@ func (od *Orderer) GetRange(ctx context.Context, params *proto_orderer.GetRangeP
 		protoTxs[i] = &protoTx
 	}
 
+	var nextCursor string
+	if len(txs) > 0 && len(txs) == int(params.GetLimit()) {
+		nextCursor = types.MakeRangeCursor(txs[len(txs)-1].Key)
+	}
+
+	return &proto_orderer.Transactions{
+		Transactions: protoTxs,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+// GetHistory fetches the transactions of a key, most recent first.
+// Each transaction includes its block if the ledger is chained.
+// To get the next page, pass the number of the last transaction
+// of the page as the cursor.
+func (od *Orderer) GetHistory(ctx context.Context, params *proto_orderer.GetHistoryParams) (*proto_orderer.Transactions, error) {
+
+	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
+		CocoonID: params.GetCocoonID(),
+		Name:     params.GetLedger(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := types.MakeTxKey(params.GetCocoonID(), params.GetKey())
+	txs, err := od.store.GetHistory(ledger.NameInternal, key, int(params.GetLimit()), uint(params.GetCursor()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := od.decryptTxs(ledger.NameInternal, ledger.Public, txs); err != nil {
+		return nil, err
+	}
+
+	var protoTxs = make([]*proto_orderer.Transaction, len(txs))
+	for i, tx := range txs {
+
+		if ledger.Chained {
+			block, err := od.blockchain.GetBlock(ledger.NameInternal, tx.BlockID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to populate block to transaction")
+			} else if block == nil && err == nil {
+				return nil, fmt.Errorf("orphaned transaction")
+			}
+			if block, err = od.decryptBlock(ledger.NameInternal, ledger.Public, block); err != nil {
+				return nil, err
+			}
+			tx.Block = block
+		}
+
+		tx.Key = params.GetKey()
+		tx.KeyInternal = key
+		tx.Ledger = params.GetLedger()
+		tx.LedgerInternal = ledger.NameInternal
+		var protoTx = proto_orderer.Transaction{}
+		cstructs.Copy(tx, &protoTx)
+		protoTxs[i] = &protoTx
+	}
+
 	return &proto_orderer.Transactions{
 		Transactions: protoTxs,
 	}, nil
 }
+
+// GetTxProof returns a proof of the inclusion of a transaction in the
+// block of a chained ledger. The proof can be verified using the merkle package.
+func (od *Orderer) GetTxProof(ctx context.Context, params *proto_orderer.GetTxProofParams) (*proto_orderer.TxProof, error) {
+
+	ledger, err := od.GetLedger(ctx, &proto_orderer.GetLedgerParams{
+		CocoonID: params.GetCocoonID(),
+		Name:     params.GetLedger(),
+	})
+	if err != nil {
+		return nil, err
+	} else if !ledger.Chained {
+		return nil, types.ErrLedgerNotChained
+	}
+
+	tx, err := od.store.GetByID(ledger.NameInternal, params.GetTxID())
+	if err != nil {
+		return nil, err
+	} else if tx == nil && err == nil {
+		return nil, types.ErrTxNotFound
+	}
+
+	block, err := od.blockchain.GetBlock(ledger.NameInternal, tx.BlockID)
+	if err != nil {
+		return nil, err
+	} else if block == nil && err == nil {
+		return nil, fmt.Errorf("orphaned transaction")
+	} else if len(block.MerkleRoot) == 0 {
+		return nil, fmt.Errorf("block has no merkle root")
+	}
+
+	blockTxs, err := block.GetTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	var txIndex = -1
+	var txHashes = make([]string, len(blockTxs))
+	for i, blockTx := range blockTxs {
+		txHashes[i] = blockTx.Hash
+		if blockTx.ID == tx.ID {
+			txIndex = i
+		}
+	}
+
+	path, err := merkle.MakePath(txHashes, txIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &proto_orderer.TxProof{
+		TxId:          tx.ID,
+		TxHash:        txHashes[txIndex],
+		BlockId:       block.ID,
+		BlockNumber:   int64(block.Number),
+		BlockHash:     block.Hash,
+		PrevBlockHash: block.PrevBlockHash,
+		MerkleRoot:    block.MerkleRoot,
+	}
+	for _, node := range path {
+		proof.Path = append(proof.Path, &proto_orderer.ProofNode{Hash: node.Hash, Left: node.Left})
+	}
+
+	return proof, nil
+}
+
+// VerifyChain verifies the integrity of the chain of a chained ledger, including the
+// block signatures. Every block must be signed unless it is numbered below the first
+// signed block set by the caller. Issues found are streamed as they are found. The last
+// message of the stream is a summary of the number of blocks checked.
+func (od *Orderer) VerifyChain(params *proto_orderer.VerifyChainParams, stream proto_orderer.Orderer_VerifyChainServer) error {
+
+	ledger, err := od.GetLedger(stream.Context(), &proto_orderer.GetLedgerParams{
+		CocoonID: params.GetCocoonID(),
+		Name:     params.GetLedger(),
+	})
+	if err != nil {
+		return err
+	} else if !ledger.Chained {
+		return types.ErrLedgerNotChained
+	}
+
+	// transactions are hashed before their values are encrypted, so
+	// blocks and stored transactions are verified with decrypted values
+	lookupTx := func(id string) (*types.Transaction, error) {
+		tx, err := od.store.GetByID(ledger.NameInternal, id)
+		if err != nil || tx == nil {
+			return tx, err
+		}
+		return tx, od.decryptTxs(ledger.NameInternal, ledger.Public, []*types.Transaction{tx})
+	}
+	blockchain := &decryptingBlockchain{Blockchain: od.blockchain, od: od, public: ledger.Public}
+
+	// block signatures are checked against the public key provided
+	// by the caller or the public key of this orderer
+	var publicKey *signer.PublicKey
+	if len(params.GetPublicKey()) > 0 {
+		if publicKey, err = signer.NewPublicKey(params.GetPublicKey()); err != nil {
+			return err
+		}
+	} else if od.signer != nil {
+		publicKey = od.signer.PublicKey()
+	}
+
+	if params.GetSignedFrom() < 0 {
+		return fmt.Errorf("invalid first signed block number")
+	}
+
+	checked, err := blkch_impl.VerifyChain(blockchain, ledger.NameInternal, lookupTx, publicKey, uint(params.GetSignedFrom()), func(issue *blkch_impl.ChainIssue) error {
+		return stream.Send(&proto_orderer.ChainReport{
+			Type:        issue.Type,
+			BlockId:     issue.BlockID,
+			BlockNumber: int64(issue.BlockNumber),
+			TxId:        issue.TxID,
+			Message:     issue.Message,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&proto_orderer.ChainReport{
+		Type:          blkch_impl.ChainReportSummary,
+		Message:       fmt.Sprintf("verified %d blocks", checked),
+		BlocksChecked: int64(checked),
+	})
+}
+
+// GetPublicKey returns the public key that verifies the signatures of the blocks
+// created by this orderer. Use it to check that blocks were created by the orderer.
+func (od *Orderer) GetPublicKey(ctx context.Context, params *proto_orderer.GetPublicKeyParams) (*proto_orderer.PublicKey, error) {
+	if od.signer == nil {
+		return nil, types.ErrNoSigningKey
+	}
+	return &proto_orderer.PublicKey{
+		KeyId:     od.signer.KeyID(),
+		Algorithm: signer.Algorithm,
+		PublicKey: od.signer.PublicKey().Hex(),
+	}, nil
+}
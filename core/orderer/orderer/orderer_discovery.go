@@ -9,9 +9,11 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/ellcrys/cocoon/core/config"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
 	"github.com/ellcrys/cocoon/core/scheduler"
 	"github.com/ellcrys/util"
 	"github.com/hashicorp/consul/api"
+	context "golang.org/x/net/context"
 )
 
 var discoveryLog = config.MakeLogger("orderer.discovery")
@@ -19,6 +21,16 @@ var discoveryLog = config.MakeLogger("orderer.discovery")
 // DiscoveryInterval is the time between each discovery checks
 var DiscoveryInterval = time.Second * 5
 
+// LeaderCacheTTL is how long the leader of the chain of a ledger is cached
+var LeaderCacheTTL = time.Second * 10
+
+// cachedLeader is the cached leader address of the chain of a ledger.
+// The address is empty for ledgers that are not chained.
+type cachedLeader struct {
+	addr string
+	exp  time.Time
+}
+
 // Discovery defines a structure for fetching a list of addresses of orderers
 // accessible in the cluster.
 type Discovery struct {
@@ -27,6 +39,7 @@ type Discovery struct {
 	consulClient *api.Client
 	orderersAddr []string
 	ticker       *time.Ticker
+	leaders      map[string]*cachedLeader
 	OnUpdateFunc func(addrs []string)
 }
 
@@ -47,6 +60,7 @@ func NewDiscovery() (*Discovery, error) {
 	return &Discovery{
 		sd:           scheduler.NewNomadServiceDiscovery(client),
 		consulClient: client,
+		leaders:      make(map[string]*cachedLeader),
 	}, nil
 }
 
@@ -167,6 +181,83 @@ func (od *Discovery) GetGRPConn() (*grpc.ClientConn, error) {
 	return client, nil
 }
 
+// GetLeaderAddr returns the address of the orderer that leads the chain of a
+// ledger. It returns an empty string if the ledger is not chained or the
+// leader could not be determined. Leader addresses are cached for LeaderCacheTTL.
+func (od *Discovery) GetLeaderAddr(cocoonID, ledger string) string {
+
+	cacheKey := cocoonID + "/" + ledger
+	od.Lock()
+	if od.leaders == nil {
+		od.leaders = make(map[string]*cachedLeader)
+	}
+	if leader, ok := od.leaders[cacheKey]; ok && time.Now().Before(leader.exp) {
+		od.Unlock()
+		return leader.addr
+	}
+	od.Unlock()
+
+	conn, err := od.GetGRPConn()
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	leader, err := proto_orderer.NewOrdererClient(conn).GetLeader(context.Background(), &proto_orderer.GetLeaderParams{
+		CocoonID: cocoonID,
+		Ledger:   ledger,
+	})
+	if err != nil {
+		discoveryLog.Debugf("failed to get leader of %s: %s", ledger, err)
+		return ""
+	}
+
+	od.Lock()
+	defer od.Unlock()
+	od.leaders[cacheKey] = &cachedLeader{addr: leader.GetAddr(), exp: time.Now().Add(LeaderCacheTTL)}
+	return leader.GetAddr()
+}
+
+// InvalidateLeader removes the cached leader of the chain of a ledger
+func (od *Discovery) InvalidateLeader(cocoonID, ledger string) {
+	od.Lock()
+	defer od.Unlock()
+	delete(od.leaders, cocoonID+"/"+ledger)
+}
+
+// GetLeaderConn dials the leader of the chain of the first chained ledger
+// in a list of ledgers. A random orderer is dialed if none of the ledgers
+// is chained or the leader is unknown, in which case the orderer forwards
+// writes to the leader itself.
+func (od *Discovery) GetLeaderConn(cocoonID string, ledgers ...string) (*grpc.ClientConn, error) {
+	for _, ledger := range ledgers {
+		if addr := od.GetLeaderAddr(cocoonID, ledger); len(addr) > 0 {
+			return grpc.Dial(addr, grpc.WithInsecure())
+		}
+	}
+	return od.GetGRPConn()
+}
+
+// GetClient returns a client of a random orderer
+// and a function that closes its connection
+func (od *Discovery) GetClient() (proto_orderer.OrdererClient, func(), error) {
+	conn, err := od.GetGRPConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto_orderer.NewOrdererClient(conn), func() { conn.Close() }, nil
+}
+
+// GetLeaderClient returns a client of the orderer dialed by GetLeaderConn
+// and a function that closes its connection
+func (od *Discovery) GetLeaderClient(cocoonID string, ledgers ...string) (proto_orderer.OrdererClient, func(), error) {
+	conn, err := od.GetLeaderConn(cocoonID, ledgers...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto_orderer.NewOrdererClient(conn), func() { conn.Close() }, nil
+}
+
 // Stop stops the discovery ticker
 func (od *Discovery) Stop() {
 	od.ticker.Stop()
@@ -7,11 +7,13 @@ import (
 	"os"
 
 	"os/exec"
+	"path"
 
 	"fmt"
 
 	"github.com/ellcrys/util"
 	blkch_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
 	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
 	"github.com/ellcrys/cocoon/core/store/impl"
 	"github.com/ellcrys/cocoon/core/types"
@@ -262,6 +264,35 @@ func TestOrderer(t *testing.T) {
 						So(tx, ShouldNotBeNil)
 						So(err, ShouldBeNil)
 					})
+
+					Convey("Should hide a deleted key unless deleted transactions are requested", func() {
+						result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+							CocoonID:   "cocoon-abc",
+							LedgerName: ledgerName,
+							Transactions: []*proto_orderer.Transaction{
+								{Ledger: ledgerName, Id: util.UUID4(), Key: key, RevisionTo: id, Deleted: true},
+							},
+						})
+						So(err, ShouldBeNil)
+						So(result.TxReceipts[0].Err, ShouldBeEmpty)
+
+						tx, err := od.Get(context.Background(), &proto_orderer.GetParams{
+							CocoonID: "cocoon-abc",
+							Ledger:   ledgerName,
+							Key:      key,
+						})
+						So(tx, ShouldBeNil)
+						So(err, ShouldResemble, types.ErrTxNotFound)
+
+						tx, err = od.Get(context.Background(), &proto_orderer.GetParams{
+							CocoonID:       "cocoon-abc",
+							Ledger:         ledgerName,
+							Key:            key,
+							IncludeDeleted: true,
+						})
+						So(err, ShouldBeNil)
+						So(tx.Deleted, ShouldBeTrue)
+					})
 				})
 
 				Convey(".GetBlockByID", func() {
@@ -360,6 +391,33 @@ func TestOrderer(t *testing.T) {
 						So(len(txs.Transactions), ShouldEqual, 3)
 					})
 
+					Convey("Should page through keys matching a prefix in reverse order using cursors", func() {
+						txs, err := od.GetRange(context.Background(), &proto_orderer.GetRangeParams{
+							CocoonID: "cocoon-abc",
+							Ledger:   ledgerName,
+							Prefix:   "account",
+							Reverse:  true,
+							Limit:    1,
+						})
+						So(err, ShouldBeNil)
+						So(len(txs.Transactions), ShouldEqual, 1)
+						So(txs.Transactions[0].Key, ShouldEqual, "account.ken")
+						So(txs.NextCursor, ShouldNotBeEmpty)
+
+						txs, err = od.GetRange(context.Background(), &proto_orderer.GetRangeParams{
+							CocoonID: "cocoon-abc",
+							Ledger:   ledgerName,
+							Prefix:   "account",
+							Reverse:  true,
+							Limit:    10,
+							Cursor:   txs.NextCursor,
+						})
+						So(err, ShouldBeNil)
+						So(len(txs.Transactions), ShouldEqual, 1)
+						So(txs.Transactions[0].Key, ShouldEqual, "account.glen")
+						So(txs.NextCursor, ShouldBeEmpty)
+					})
+
 					Reset(func() {
 						impl.Clear(storeConStr)
 					})
@@ -382,3 +440,292 @@ func TestOrderer(t *testing.T) {
 		}
 	})
 }
+
+func TestGetPublicKey(t *testing.T) {
+	Convey("Orderer.GetPublicKey", t, func() {
+
+		od := NewOrderer()
+
+		Convey("Should return error if the orderer has no signing key", func() {
+			_, err := od.GetPublicKey(context.Background(), &proto_orderer.GetPublicKeyParams{})
+			So(err, ShouldEqual, types.ErrNoSigningKey)
+		})
+
+		Convey("Should return the public key of the signing key", func() {
+			blockSigner, _ := signer.Generate()
+			od.SetSigner(blockSigner)
+			key, err := od.GetPublicKey(context.Background(), &proto_orderer.GetPublicKeyParams{})
+			So(err, ShouldBeNil)
+			So(key.KeyId, ShouldEqual, blockSigner.KeyID())
+			So(key.Algorithm, ShouldEqual, signer.Algorithm)
+			So(key.PublicKey, ShouldEqual, blockSigner.PublicKey().Hex())
+		})
+	})
+}
+
+func TestPutMulti(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_put_multi_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_PUT_MULTI_ADDR", "127.0.0.1:7012")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	Convey("Orderer.PutMulti", t, func() {
+
+		cocoonID := util.RandString(5)
+		chainedLedger, ledger := util.RandString(5), util.RandString(5)
+		_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: chainedLedger, Chained: true})
+		So(err, ShouldBeNil)
+		_, err = od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledger})
+		So(err, ShouldBeNil)
+
+		Convey("Should store the transactions of every ledger and create a block for chained ledgers", func() {
+			result, err := od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers: []*proto_orderer.LedgerTransactions{
+					{Ledger: chainedLedger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "alice", Value: "90"}}},
+					{Ledger: ledger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "bob", Value: "110"}}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(len(result.Results), ShouldEqual, 2)
+			So(result.Results[0].TxReceipts[0].Err, ShouldBeEmpty)
+			So(result.Results[0].Block, ShouldNotBeNil)
+			So(result.Results[0].Block.Number, ShouldEqual, 1)
+			So(result.Results[1].TxReceipts[0].Err, ShouldBeEmpty)
+			So(result.Results[1].Block, ShouldBeNil)
+
+			tx, err := od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: ledger, Key: "bob"})
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "110")
+		})
+
+		Convey("Should store nothing if a transaction fails", func() {
+			result, err := od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers: []*proto_orderer.LedgerTransactions{
+					{Ledger: chainedLedger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "alice", Value: "90"}}},
+					{Ledger: ledger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "bob", Value: "110", ReadSet: []*proto_orderer.KeyRevision{{Key: "bob", Revision: "unknown"}}}}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(result.Results[0].TxReceipts[0].Err, ShouldEqual, types.ErrBatchAborted.Error())
+			So(result.Results[0].Block, ShouldBeNil)
+
+			_, err = od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: chainedLedger, Key: "alice"})
+			So(err, ShouldEqual, types.ErrTxNotFound)
+		})
+
+		Convey("Should store no transaction and no block if a block fails to be created", func() {
+			otherChainedLedger := util.RandString(5)
+			_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: otherChainedLedger, Chained: true})
+			So(err, ShouldBeNil)
+			internalChainedLedger := types.MakeLedgerName(cocoonID, chainedLedger)
+			height, err := od.blockchain.GetChainHeight(internalChainedLedger)
+			So(err, ShouldBeNil)
+
+			od.store.SetBlockchainImplementation(&failingBlockchain{Blockchain: od.blockchain, chainName: types.MakeLedgerName(cocoonID, otherChainedLedger)})
+			defer od.store.SetBlockchainImplementation(od.blockchain)
+
+			_, err = od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers: []*proto_orderer.LedgerTransactions{
+					{Ledger: chainedLedger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "alice", Value: "90"}}},
+					{Ledger: otherChainedLedger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "bob", Value: "110"}}},
+				},
+			})
+			So(err, ShouldNotBeNil)
+
+			newHeight, err := od.blockchain.GetChainHeight(internalChainedLedger)
+			So(err, ShouldBeNil)
+			So(newHeight, ShouldEqual, height)
+			_, err = od.Get(context.Background(), &proto_orderer.GetParams{CocoonID: cocoonID, Ledger: chainedLedger, Key: "alice"})
+			So(err, ShouldEqual, types.ErrTxNotFound)
+		})
+
+		Convey("Should return error if a ledger does not exist", func() {
+			_, err := od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers:  []*proto_orderer.LedgerTransactions{{Ledger: "unknown"}},
+			})
+			So(err, ShouldEqual, types.ErrLedgerNotFound)
+		})
+
+		Convey("Should return error if a ledger appears twice", func() {
+			_, err := od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers:  []*proto_orderer.LedgerTransactions{{Ledger: ledger}, {Ledger: ledger}},
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "appears more than once")
+		})
+	})
+}
+
+func TestBlockLookup(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_block_lookup_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_BLOCK_LOOKUP_ADDR", "127.0.0.1:7013")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	Convey("Orderer block lookup", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+		So(err, ShouldBeNil)
+
+		var blocks []*proto_orderer.Block
+		for i := 0; i < 3; i++ {
+			result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+				CocoonID:     cocoonID,
+				LedgerName:   ledgerName,
+				Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "key", Value: "value"}},
+			})
+			So(err, ShouldBeNil)
+			blocks = append(blocks, result.Block)
+		}
+
+		Convey(".GetBlockByNumber", func() {
+
+			Convey("Should return a block by its number", func() {
+				block, err := od.GetBlockByNumber(context.Background(), &proto_orderer.GetBlockByNumberParams{CocoonID: cocoonID, Ledger: ledgerName, Number: 2})
+				So(err, ShouldBeNil)
+				So(block.Id, ShouldEqual, blocks[1].Id)
+				So(block.Hash, ShouldEqual, blocks[1].Hash)
+			})
+
+			Convey("Should return error if the block does not exist", func() {
+				_, err := od.GetBlockByNumber(context.Background(), &proto_orderer.GetBlockByNumberParams{CocoonID: cocoonID, Ledger: ledgerName, Number: 4})
+				So(err, ShouldEqual, types.ErrBlockNotFound)
+			})
+
+			Convey("Should return error if the ledger is not chained", func() {
+				unchained := util.RandString(5)
+				od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: unchained})
+				_, err := od.GetBlockByNumber(context.Background(), &proto_orderer.GetBlockByNumberParams{CocoonID: cocoonID, Ledger: unchained, Number: 1})
+				So(err, ShouldEqual, types.ErrLedgerNotChained)
+			})
+		})
+
+		Convey(".GetChainHeight should return the number of the last block", func() {
+			height, err := od.GetChainHeight(context.Background(), &proto_orderer.GetChainHeightParams{CocoonID: cocoonID, Ledger: ledgerName})
+			So(err, ShouldBeNil)
+			So(height.Height, ShouldEqual, 3)
+		})
+
+		Convey(".ListBlocks should walk the chain in order", func() {
+			result, err := od.ListBlocks(context.Background(), &proto_orderer.ListBlocksParams{CocoonID: cocoonID, Ledger: ledgerName, FromNumber: 1, Limit: 2})
+			So(err, ShouldBeNil)
+			So(len(result.Blocks), ShouldEqual, 2)
+			So(result.Blocks[0].Id, ShouldEqual, blocks[0].Id)
+			So(result.Blocks[1].PrevBlockHash, ShouldEqual, blocks[0].Hash)
+
+			result, err = od.ListBlocks(context.Background(), &proto_orderer.ListBlocksParams{CocoonID: cocoonID, Ledger: ledgerName, FromNumber: 3})
+			So(err, ShouldBeNil)
+			So(len(result.Blocks), ShouldEqual, 1)
+			So(result.Blocks[0].Number, ShouldEqual, 3)
+		})
+	})
+}
+
+// failingBlockchain is a blockchain that fails to create blocks in a chain
+type failingBlockchain struct {
+	types.Blockchain
+	chainName string
+}
+
+func (b *failingBlockchain) WithDBTx(dbTx interface{}) (types.Blockchain, error) {
+	blockchain, err := b.Blockchain.WithDBTx(dbTx)
+	if err != nil {
+		return nil, err
+	}
+	return &failingBlockchain{Blockchain: blockchain, chainName: b.chainName}, nil
+}
+
+func (b *failingBlockchain) CreateBlock(id, chainName string, transactions []*types.Transaction) (*types.Block, error) {
+	if chainName == b.chainName {
+		return nil, fmt.Errorf("failed to create block")
+	}
+	return b.Blockchain.CreateBlock(id, chainName, transactions)
+}
+
+func TestOpen(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_open_"+util.RandString(5)+".db")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	if err := od.Open(conStr); err != nil {
+		t.Fatal(err)
+	}
+	defer od.Close()
+
+	Convey("Orderer opened with Open", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+		So(err, ShouldBeNil)
+
+		Convey("Should lead every chain and append blocks without electing a leader", func() {
+			for i := 1; i <= 2; i++ {
+				result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+					CocoonID:     cocoonID,
+					LedgerName:   ledgerName,
+					Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "key", Value: "value"}},
+				})
+				So(err, ShouldBeNil)
+				So(result.Block.Number, ShouldEqual, i)
+			}
+
+			leader, err := od.GetLeader(context.Background(), &proto_orderer.GetLeaderParams{CocoonID: cocoonID, Ledger: ledgerName})
+			So(err, ShouldBeNil)
+			So(leader.Addr, ShouldBeEmpty)
+
+			chain, err := od.blockchain.GetChain(types.MakeLedgerName(cocoonID, ledgerName))
+			So(err, ShouldBeNil)
+			So(chain.Leader, ShouldBeEmpty)
+		})
+
+		Convey("Should atomically append blocks to several chains", func() {
+			otherLedger := util.RandString(5)
+			_, err = od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: otherLedger, Chained: true})
+			So(err, ShouldBeNil)
+
+			result, err := od.PutMulti(context.Background(), &proto_orderer.PutMultiParams{
+				CocoonID: cocoonID,
+				Ledgers: []*proto_orderer.LedgerTransactions{
+					{Ledger: ledgerName, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "key", Value: "value"}}},
+					{Ledger: otherLedger, Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: "key", Value: "value"}}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(len(result.Results), ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,212 @@
+package orderer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/types"
+)
+
+// pruneBatchSize is the maximum number of transactions
+// removed from an unchained ledger per prune run
+var pruneBatchSize = 1000
+
+// runPruner prunes the ledgers with a retention policy
+// every interval until stopCh is closed
+func (od *Orderer) runPruner(interval time.Duration, stopCh chan bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if pruned, err := od.Prune(); err != nil {
+				log.Errorf("Failed to prune ledgers: %s", err)
+			} else if pruned > 0 {
+				log.Infof("Pruned %d transaction(s)", pruned)
+			}
+		}
+	}
+}
+
+// Prune removes the superseded transactions of the ledgers with a retention
+// policy that fall outside the policy. Transactions of unchained ledgers are
+// deleted. Chained ledgers are compacted into a checkpoint (see compactLedger),
+// so the remaining blocks can still be verified. A chained ledger is only pruned
+// by the leader of its chain and unchained ledgers by a single orderer, the
+// pruner. A ledger that fails to be pruned is logged and skipped. It returns
+// the number of transactions removed.
+func (od *Orderer) Prune() (int, error) {
+
+	ledgers, err := od.store.ListRetainedLedgers()
+	if err != nil {
+		return 0, err
+	}
+
+	pruning, err := od.elector.leadsPruning()
+	if err != nil {
+		log.Errorf("Failed to elect pruner: %s", err)
+	}
+
+	var pruned int
+	now := time.Now().Unix()
+	for _, ledger := range ledgers {
+
+		var before int64
+		if ledger.KeepFor > 0 {
+			before = now - ledger.KeepFor
+		}
+
+		var n int
+		var err error
+		if ledger.Chained {
+			var isLeader bool
+			if _, isLeader, err = od.elector.leaderOf(ledger.Name); err != nil && err != types.ErrChainLeaderUnknown {
+				log.Errorf("Failed to get leader of ledger %s: %s", ledger.Name, err)
+				continue
+			} else if !isLeader {
+				continue
+			}
+			n, err = od.compactLedger(ledger, before)
+		} else if pruning {
+			n, err = od.pruneLedger(ledger, before)
+		}
+		if err != nil {
+			log.Errorf("Failed to prune ledger %s: %s", ledger.Name, err)
+			continue
+		}
+
+		pruned += n
+	}
+
+	return pruned, nil
+}
+
+// pruneLedger deletes the prunable transactions of an unchained ledger
+func (od *Orderer) pruneLedger(ledger *types.Ledger, before int64) (int, error) {
+
+	ids, err := od.store.ListPrunableTransactions(ledger.Name, ledger.KeepRevisions, before, pruneBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return od.store.DeleteTransactions(ledger.Name, ids)
+}
+
+// compactLedger compacts the chain of a ledger and deletes the prunable transactions
+// of the removed blocks. Starting after the checkpoint of the chain, blocks are walked
+// while every transaction they include is either prunable or the most recent revision
+// of its key, and the chain is compacted up to the last of these blocks that includes
+// a prunable transaction. The most recent revisions are kept in the store after their
+// block is removed, so keys that are never revised do not prevent compaction; they
+// can no longer be proven against a block. The last block of the chain is never
+// removed. The chain is compacted and the transactions deleted in one database
+// transaction.
+func (od *Orderer) compactLedger(ledger *types.Ledger, before int64) (int, error) {
+
+	ids, err := od.store.ListPrunableTransactions(ledger.Name, ledger.KeepRevisions, before, 0)
+	if err != nil {
+		return 0, err
+	} else if len(ids) == 0 {
+		return 0, nil
+	}
+
+	prunable := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		prunable[id] = true
+	}
+
+	// isLatest checks whether a transaction is the most recent revision of its key
+	latest := make(map[string]string)
+	isLatest := func(tx *types.Transaction) (bool, error) {
+		id, ok := latest[tx.Key]
+		if !ok {
+			cur, err := od.store.Get(ledger.Name, tx.Key, true)
+			if err != nil {
+				return false, err
+			} else if cur != nil {
+				id = cur.ID
+			}
+			latest[tx.Key] = id
+		}
+		return id == tx.ID, nil
+	}
+
+	height, err := od.blockchain.GetChainHeight(ledger.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	var from uint = 1
+	checkpoint, err := od.blockchain.GetCheckpoint(ledger.Name)
+	if err != nil {
+		return 0, err
+	} else if checkpoint != nil {
+		from = checkpoint.Number + 1
+	}
+
+	var toNumber uint
+	var removedTxIDs, walkedTxIDs []string
+walk:
+	for from < height {
+
+		blocks, err := od.blockchain.ListBlocks(ledger.Name, from, maxListBlocksLimit)
+		if err != nil {
+			return 0, err
+		} else if len(blocks) == 0 {
+			break
+		}
+
+		for _, block := range blocks {
+
+			if block.Number >= height {
+				break walk
+			}
+
+			txs, err := block.GetTransactions()
+			if err != nil {
+				return 0, fmt.Errorf("block %d: %s", block.Number, err)
+			}
+
+			var hasPrunable bool
+			for _, tx := range txs {
+				if prunable[tx.ID] {
+					hasPrunable = true
+					walkedTxIDs = append(walkedTxIDs, tx.ID)
+					continue
+				}
+				if live, err := isLatest(tx); err != nil {
+					return 0, err
+				} else if !live {
+					break walk
+				}
+			}
+
+			if hasPrunable {
+				toNumber = block.Number
+				removedTxIDs = append(removedTxIDs, walkedTxIDs...)
+				walkedTxIDs = nil
+			}
+			from = block.Number + 1
+		}
+	}
+
+	if toNumber == 0 {
+		return 0, nil
+	}
+
+	var deleted int
+	err = od.store.Transact(func(store types.Store, blockchain types.Blockchain) error {
+		if _, err := blockchain.Compact(ledger.Name, toNumber); err != nil {
+			return err
+		}
+		deleted, err = store.DeleteTransactions(ledger.Name, removedTxIDs)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,193 @@
+package orderer
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	blkch_impl "github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+func TestPrune(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_prune_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_PRUNE_ADDR", "127.0.0.1:7014")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	put := func(cocoonID, ledgerName, key string) *proto_orderer.PutResult {
+		result, err := od.Put(context.Background(), &proto_orderer.PutTransactionParams{
+			CocoonID:     cocoonID,
+			LedgerName:   ledgerName,
+			Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: key, Value: util.RandString(5)}},
+		})
+		So(err, ShouldBeNil)
+		return result
+	}
+
+	Convey("Orderer pruning", t, func() {
+
+		cocoonID := util.RandString(5)
+
+		Convey(".SetLedgerRetention", func() {
+
+			Convey("Should set the retention policy of a ledger", func() {
+				ledgerName := util.RandString(5)
+				_, err := od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName})
+				So(err, ShouldBeNil)
+				ledger, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: ledgerName, KeepRevisions: 2, KeepFor: 60})
+				So(err, ShouldBeNil)
+				So(ledger.Name, ShouldEqual, ledgerName)
+				So(ledger.KeepRevisions, ShouldEqual, 2)
+				So(ledger.KeepFor, ShouldEqual, 60)
+			})
+
+			Convey("Should return error if the ledger does not exist", func() {
+				_, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: "unknown", KeepRevisions: 1})
+				So(err, ShouldEqual, types.ErrLedgerNotFound)
+			})
+
+			Convey("Should return error if a retention value is negative", func() {
+				_, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: "unknown", KeepRevisions: -1})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "retention values cannot be negative")
+			})
+		})
+
+		Convey(".Prune", func() {
+
+			Convey("Should delete the superseded transactions of an unchained ledger", func() {
+				ledgerName := util.RandString(5)
+				od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName})
+				for i := 0; i < 3; i++ {
+					put(cocoonID, ledgerName, "a")
+				}
+				put(cocoonID, ledgerName, "b")
+
+				pruned, err := od.Prune()
+				So(err, ShouldBeNil)
+				So(pruned, ShouldEqual, 0)
+
+				_, err = od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: ledgerName, KeepRevisions: 1})
+				So(err, ShouldBeNil)
+				pruned, err = od.Prune()
+				So(err, ShouldBeNil)
+				So(pruned, ShouldEqual, 2)
+
+				internalName := types.MakeLedgerName(cocoonID, ledgerName)
+				txs, err := od.store.GetHistory(internalName, types.MakeTxKey(cocoonID, "a"), 0, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+			})
+
+			Convey("Should compact a chained ledger up to the last block that can be removed", func() {
+				ledgerName := util.RandString(5)
+				od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+				put(cocoonID, ledgerName, "a")
+				put(cocoonID, ledgerName, "a")
+				block3 := put(cocoonID, ledgerName, "b")
+				put(cocoonID, ledgerName, "a")
+
+				_, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: ledgerName, KeepRevisions: 1})
+				So(err, ShouldBeNil)
+				pruned, err := od.Prune()
+				So(err, ShouldBeNil)
+				So(pruned, ShouldEqual, 2)
+
+				internalName := types.MakeLedgerName(cocoonID, ledgerName)
+				checkpoint, err := od.blockchain.GetCheckpoint(internalName)
+				So(err, ShouldBeNil)
+				So(checkpoint.Number, ShouldEqual, 2)
+				So(checkpoint.Hash, ShouldEqual, block3.Block.PrevBlockHash)
+
+				height, err := od.GetChainHeight(context.Background(), &proto_orderer.GetChainHeightParams{CocoonID: cocoonID, Ledger: ledgerName})
+				So(err, ShouldBeNil)
+				So(height.Height, ShouldEqual, 4)
+
+				lookupTx := func(id string) (*types.Transaction, error) {
+					return od.store.GetByID(internalName, id)
+				}
+				var issues []*blkch_impl.ChainIssue
+				checked, err := blkch_impl.VerifyChain(od.blockchain, internalName, lookupTx, nil, 0, func(issue *blkch_impl.ChainIssue) error {
+					issues = append(issues, issue)
+					return nil
+				})
+				So(err, ShouldBeNil)
+				So(checked, ShouldEqual, 2)
+				So(issues, ShouldBeEmpty)
+
+				Convey("and not compact again while no further block can be removed", func() {
+					pruned, err := od.Prune()
+					So(err, ShouldBeNil)
+					So(pruned, ShouldEqual, 0)
+				})
+			})
+
+			Convey("Should compact past blocks whose keys have not been revised", func() {
+				ledgerName := util.RandString(5)
+				od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+				put(cocoonID, ledgerName, "b")
+				put(cocoonID, ledgerName, "a")
+				put(cocoonID, ledgerName, "a")
+				put(cocoonID, ledgerName, "a")
+
+				_, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: ledgerName, KeepRevisions: 1})
+				So(err, ShouldBeNil)
+				pruned, err := od.Prune()
+				So(err, ShouldBeNil)
+				So(pruned, ShouldEqual, 2)
+
+				internalName := types.MakeLedgerName(cocoonID, ledgerName)
+				checkpoint, err := od.blockchain.GetCheckpoint(internalName)
+				So(err, ShouldBeNil)
+				So(checkpoint.Number, ShouldEqual, 3)
+
+				tx, err := od.store.Get(internalName, types.MakeTxKey(cocoonID, "b"), false)
+				So(err, ShouldBeNil)
+				So(tx, ShouldNotBeNil)
+			})
+
+			Convey("Should not compact a chained ledger led by another orderer", func() {
+				ledgerName := util.RandString(5)
+				od.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{CocoonID: cocoonID, Name: ledgerName, Chained: true})
+				put(cocoonID, ledgerName, "a")
+				put(cocoonID, ledgerName, "a")
+				put(cocoonID, ledgerName, "a")
+				_, err := od.SetLedgerRetention(context.Background(), &proto_orderer.SetLedgerRetentionParams{CocoonID: cocoonID, Name: ledgerName, KeepRevisions: 1})
+				So(err, ShouldBeNil)
+
+				internalName := types.MakeLedgerName(cocoonID, ledgerName)
+				od.elector.release()
+				other := newElector("127.0.0.1:7015", DefaultLeaderTTL, od.blockchain)
+				_, isLeader, err := other.leaderOf(internalName)
+				So(err, ShouldBeNil)
+				So(isLeader, ShouldBeTrue)
+				defer other.release()
+
+				_, err = od.Prune()
+				So(err, ShouldBeNil)
+				checkpoint, err := od.blockchain.GetCheckpoint(internalName)
+				So(err, ShouldBeNil)
+				So(checkpoint, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,322 @@
+package orderer
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ncodes/cstructs"
+)
+
+// subscriptionPollInterval is the time between reads of the changes
+// of a subscribed ledger that were not signalled by this orderer
+var subscriptionPollInterval = 500 * time.Millisecond
+
+// subscriptionSettleTime is the time within which a transaction is expected to
+// be committed once it was numbered. Transactions of unchained ledgers are
+// numbered before they are committed, so a transaction with a lower number may
+// be committed after a transaction with a higher number has been read.
+var subscriptionSettleTime = 30 * time.Second
+
+// replayPageSize is the number of blocks or transactions
+// read at a time from the blockchain or store
+var replayPageSize = 100
+
+// ledgerChange describes transactions committed to a
+// ledger and the block that includes them, if any
+type ledgerChange struct {
+	ledger string
+	txs    []*types.Transaction
+	block  *types.Block
+}
+
+// subscriber is signalled when changes are committed to a ledger
+type subscriber struct {
+	ledger string
+	ch     chan struct{}
+}
+
+// broker signals subscribers when this orderer commits changes to their
+// ledger, so that the changes are read without waiting for the next poll.
+type broker struct {
+	sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: make(map[*subscriber]struct{})}
+}
+
+// subscribe creates a subscriber for the changes of a ledger
+func (b *broker) subscribe(ledger string) *subscriber {
+	b.Lock()
+	defer b.Unlock()
+	s := &subscriber{ledger: ledger, ch: make(chan struct{}, 1)}
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+// unsubscribe removes a subscriber
+func (b *broker) unsubscribe(s *subscriber) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.subscribers, s)
+}
+
+// publish signals the subscribers of a changed ledger. Signals
+// are not queued; a subscriber has at most one pending signal.
+func (b *broker) publish(ledger string) {
+	b.Lock()
+	defer b.Unlock()
+	for s := range b.subscribers {
+		if s.ledger != ledger {
+			continue
+		}
+		select {
+		case s.ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ledgerFeed reads the changes committed to a ledger by any orderer
+type ledgerFeed interface {
+	next() ([]*ledgerChange, error)
+}
+
+// blockFeed reads the blocks of a chained ledger by block number.
+// Blocks are committed in the order of their numbers.
+type blockFeed struct {
+	od         *Orderer
+	ledger     *types.Ledger
+	nextNumber uint
+}
+
+// next returns the blocks created since the previous call
+func (f *blockFeed) next() ([]*ledgerChange, error) {
+
+	var changes []*ledgerChange
+	for {
+		blocks, err := f.od.blockchain.ListBlocks(f.ledger.Name, f.nextNumber, replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			txs, err := block.GetTransactions()
+			if err != nil {
+				return nil, err
+			}
+			if err := f.od.decryptTxs(f.ledger.Name, f.ledger.Public, txs); err != nil {
+				return nil, err
+			}
+			changes = append(changes, &ledgerChange{ledger: f.ledger.Name, txs: txs, block: block})
+			f.nextNumber = block.Number + 1
+		}
+		if len(blocks) < replayPageSize {
+			return changes, nil
+		}
+	}
+}
+
+// txFeed reads the transactions of an unchained ledger by transaction number.
+// Since a transaction may be committed after a transaction with a higher number,
+// the transactions read within the settle time are read again on every call and
+// only the ones that have not been returned before are returned.
+type txFeed struct {
+	od       *Orderer
+	ledger   *types.Ledger
+	floor    uint
+	seen     map[string]uint
+	readAt   []txReadTime
+	settleIn time.Duration
+	now      func() time.Time
+}
+
+// txReadTime is the number of a transaction and the time it was first read
+type txReadTime struct {
+	number uint
+	at     time.Time
+}
+
+// next returns the transactions committed since the previous call
+func (f *txFeed) next() ([]*ledgerChange, error) {
+
+	var txs []*types.Transaction
+	for after := f.floor; ; {
+		page, err := f.od.store.ListTransactions(f.ledger.Name, after, replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page {
+			after = tx.Number
+			if _, ok := f.seen[tx.ID]; ok {
+				continue
+			}
+			f.seen[tx.ID] = tx.Number
+			f.readAt = append(f.readAt, txReadTime{number: tx.Number, at: f.now()})
+			txs = append(txs, tx)
+		}
+		if len(page) < replayPageSize {
+			break
+		}
+	}
+
+	// transactions read before the settle time are not read again
+	for len(f.readAt) > 0 && f.now().Sub(f.readAt[0].at) >= f.settleIn {
+		if f.readAt[0].number > f.floor {
+			f.floor = f.readAt[0].number
+		}
+		f.readAt = f.readAt[1:]
+	}
+	for id, number := range f.seen {
+		if number <= f.floor {
+			delete(f.seen, id)
+		}
+	}
+
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	if err := f.od.decryptTxs(f.ledger.Name, f.ledger.Public, txs); err != nil {
+		return nil, err
+	}
+
+	return []*ledgerChange{{ledger: f.ledger.Name, txs: txs}}, nil
+}
+
+// newLedgerFeed creates a feed of the changes of a ledger. The changes of a chained
+// ledger are read from the block with number fromBlock or, if fromBlock is zero, from
+// the next block. The changes of an unchained ledger are read from the next transaction.
+func (od *Orderer) newLedgerFeed(ledger *types.Ledger, fromBlock uint) (ledgerFeed, error) {
+
+	if ledger.Chained {
+		if fromBlock == 0 {
+			height, err := od.blockchain.GetChainHeight(ledger.Name)
+			if err != nil {
+				return nil, err
+			}
+			fromBlock = height + 1
+		}
+		return &blockFeed{od: od, ledger: ledger, nextNumber: fromBlock}, nil
+	}
+
+	lastNumber, err := od.store.GetLastTxNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	return &txFeed{
+		od:       od,
+		ledger:   ledger,
+		floor:    lastNumber,
+		seen:     make(map[string]uint),
+		settleIn: subscriptionSettleTime,
+		now:      time.Now,
+	}, nil
+}
+
+// makeLedgerEvent creates the event sent to subscribers for a ledger change.
+// Only transactions with keys that begin with the prefix are included. It
+// returns nil if a prefix is set and no transaction of the change matches it.
+func makeLedgerEvent(ledgerName, prefix string, change *ledgerChange) *proto_orderer.LedgerEvent {
+
+	event := &proto_orderer.LedgerEvent{Ledger: ledgerName}
+	for _, tx := range change.txs {
+
+		// transactions are shared by subscribers, so they are copied before being modified
+		txCopy := *tx
+		txCopy.KeyInternal = tx.Key
+		txCopy.Key = types.GetActualKeyFromTxKey(tx.Key)
+		txCopy.LedgerInternal = tx.Ledger
+		txCopy.Ledger = ledgerName
+		if !strings.HasPrefix(txCopy.Key, prefix) {
+			continue
+		}
+
+		var protoTx proto_orderer.Transaction
+		cstructs.Copy(&txCopy, &protoTx)
+		event.Transactions = append(event.Transactions, &protoTx)
+	}
+
+	if len(prefix) > 0 && len(event.Transactions) == 0 {
+		return nil
+	}
+
+	// the transactions of the block are not included
+	// as they are already part of the event
+	if change.block != nil {
+		event.Block = &proto_orderer.Block{
+			Id:            change.block.ID,
+			Number:        int64(change.block.Number),
+			ChainName:     change.block.ChainName,
+			PrevBlockHash: change.block.PrevBlockHash,
+			Hash:          change.block.Hash,
+			MerkleRoot:    change.block.MerkleRoot,
+			CreatedAt:     change.block.CreatedAt,
+			Signature:     change.block.Signature,
+			KeyId:         change.block.KeyID,
+		}
+	}
+
+	return event
+}
+
+// Subscribe streams the transactions committed to a ledger and the blocks that include
+// them. Only transactions with keys that begin with the prefix are sent. If fromBlock is
+// set, the blocks of a chained ledger from that block number are sent before new changes.
+// Changes are read from the blockchain or store, so the changes ordered by every orderer
+// are sent. Each event of a chained ledger holds the transactions of a block; each event
+// of an unchained ledger holds the transactions read since the previous event.
+func (od *Orderer) Subscribe(params *proto_orderer.SubscribeParams, stream proto_orderer.Orderer_SubscribeServer) error {
+
+	ledger, err := od.GetLedger(stream.Context(), &proto_orderer.GetLedgerParams{
+		CocoonID: params.GetCocoonID(),
+		Name:     params.GetLedger(),
+	})
+	if err != nil {
+		return err
+	} else if params.GetFromBlock() > 0 && !ledger.Chained {
+		return types.ErrLedgerNotChained
+	}
+
+	sub := od.broker.subscribe(ledger.NameInternal)
+	defer od.broker.unsubscribe(sub)
+
+	feed, err := od.newLedgerFeed(&types.Ledger{
+		Name:    ledger.NameInternal,
+		Chained: ledger.Chained,
+		Public:  ledger.Public,
+	}, uint(params.GetFromBlock()))
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(subscriptionPollInterval)
+	defer ticker.Stop()
+
+	for {
+
+		changes, err := feed.next()
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			if event := makeLedgerEvent(ledger.Name, params.GetPrefix(), change); event != nil {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.ch:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package orderer
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestBroker(t *testing.T) {
+	Convey("Broker", t, func() {
+
+		b := newBroker()
+
+		Convey(".publish", func() {
+
+			Convey("Should signal subscribers of the changed ledger only", func() {
+				s := b.subscribe("ledger1")
+				s2 := b.subscribe("ledger2")
+				b.publish("ledger1")
+				So(len(s.ch), ShouldEqual, 1)
+				So(len(s2.ch), ShouldEqual, 0)
+			})
+
+			Convey("Should keep at most one pending signal", func() {
+				s := b.subscribe("ledger1")
+				b.publish("ledger1")
+				b.publish("ledger1")
+				So(len(s.ch), ShouldEqual, 1)
+			})
+		})
+
+		Convey(".unsubscribe", func() {
+			Convey("Should remove a subscriber", func() {
+				s := b.subscribe("ledger1")
+				b.unsubscribe(s)
+				b.publish("ledger1")
+				So(len(s.ch), ShouldEqual, 0)
+				So(b.subscribers, ShouldNotContainKey, s)
+				So(func() { b.unsubscribe(s) }, ShouldNotPanic)
+			})
+		})
+	})
+}
+
+func TestMakeLedgerEvent(t *testing.T) {
+	Convey("makeLedgerEvent", t, func() {
+
+		change := &ledgerChange{
+			ledger: types.MakeLedgerName("cocoon1", "ledger1"),
+			txs: []*types.Transaction{
+				{ID: "1", Key: types.MakeTxKey("cocoon1", "user/1"), Value: "a"},
+				{ID: "2", Key: types.MakeTxKey("cocoon1", "account/1"), Value: "b"},
+			},
+			block: &types.Block{ID: "block1", Number: 2, Transactions: []byte("[]")},
+		}
+
+		Convey("Should include the actual keys of the transactions and the block without its transactions", func() {
+			event := makeLedgerEvent("ledger1", "", change)
+			So(event.Ledger, ShouldEqual, "ledger1")
+			So(len(event.Transactions), ShouldEqual, 2)
+			So(event.Transactions[0].Key, ShouldEqual, "user/1")
+			So(event.Transactions[0].Ledger, ShouldEqual, "ledger1")
+			So(event.Block.Id, ShouldEqual, "block1")
+			So(event.Block.Number, ShouldEqual, 2)
+			So(event.Block.Transactions, ShouldBeEmpty)
+			So(change.txs[0].Key, ShouldEqual, types.MakeTxKey("cocoon1", "user/1"))
+		})
+
+		Convey("Should include only transactions with keys that begin with the prefix", func() {
+			event := makeLedgerEvent("ledger1", "account/", change)
+			So(len(event.Transactions), ShouldEqual, 1)
+			So(event.Transactions[0].Id, ShouldEqual, "2")
+		})
+
+		Convey("Should return nil if no transaction matches the prefix", func() {
+			So(makeLedgerEvent("ledger1", "unknown/", change), ShouldBeNil)
+		})
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_subscribe_"+util.RandString(5)+".db")
+	addr := util.Env("ORDERER_SUBSCRIBE_ADDR", "127.0.0.1:7011")
+	defer impl.Destroy(conStr)
+
+	SetLogLevel(logging.CRITICAL)
+	od := NewOrderer()
+	endCh := make(chan bool)
+	startedCh := make(chan bool)
+	od.EventEmitter.Once("started", func() { close(startedCh) })
+	go od.Start(addr, conStr, endCh)
+	<-startedCh
+	defer func() {
+		od.Stop()
+		<-endCh
+	}()
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := proto_orderer.NewOrdererClient(conn)
+
+	Convey("Orderer.Subscribe", t, func() {
+
+		cocoonID := util.RandString(5)
+		ledgerName := util.RandString(5)
+		_, err := client.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+			CocoonID: cocoonID,
+			Name:     ledgerName,
+			Chained:  true,
+		})
+		So(err, ShouldBeNil)
+
+		put := func(key string) {
+			_, err := client.Put(context.Background(), &proto_orderer.PutTransactionParams{
+				CocoonID:     cocoonID,
+				LedgerName:   ledgerName,
+				Transactions: []*proto_orderer.Transaction{{Id: util.UUID4(), Key: key, Value: "value"}},
+			})
+			So(err, ShouldBeNil)
+		}
+
+		recv := func(stream proto_orderer.Orderer_SubscribeClient) *proto_orderer.LedgerEvent {
+			event, err := stream.Recv()
+			So(err, ShouldBeNil)
+			return event
+		}
+
+		Convey("Should stream committed transactions with keys that begin with the prefix", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			stream, err := client.Subscribe(ctx, &proto_orderer.SubscribeParams{CocoonID: cocoonID, Ledger: ledgerName, Prefix: "user/"})
+			So(err, ShouldBeNil)
+			time.Sleep(100 * time.Millisecond)
+
+			put("account/1")
+			put("user/1")
+			event := recv(stream)
+			So(event.Ledger, ShouldEqual, ledgerName)
+			So(len(event.Transactions), ShouldEqual, 1)
+			So(event.Transactions[0].Key, ShouldEqual, "user/1")
+			So(event.Block.Number, ShouldEqual, 2)
+
+			Convey("Should replay blocks from a block number before new changes", func() {
+				stream, err := client.Subscribe(ctx, &proto_orderer.SubscribeParams{CocoonID: cocoonID, Ledger: ledgerName, FromBlock: 1})
+				So(err, ShouldBeNil)
+				So(recv(stream).Transactions[0].Key, ShouldEqual, "account/1")
+				So(recv(stream).Transactions[0].Key, ShouldEqual, "user/1")
+
+				put("user/2")
+				event := recv(stream)
+				So(event.Transactions[0].Key, ShouldEqual, "user/2")
+				So(event.Block.Number, ShouldEqual, 3)
+			})
+		})
+
+		Convey("Should stream transactions of an unchained ledger stored by another orderer", func() {
+			unchainedLedger := util.RandString(5)
+			_, err := client.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+				CocoonID: cocoonID,
+				Name:     unchainedLedger,
+			})
+			So(err, ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			stream, err := client.Subscribe(ctx, &proto_orderer.SubscribeParams{CocoonID: cocoonID, Ledger: unchainedLedger})
+			So(err, ShouldBeNil)
+			time.Sleep(100 * time.Millisecond)
+
+			// writing to the store directly does not signal the subscribers of this orderer
+			_, err = od.store.Put(types.MakeLedgerName(cocoonID, unchainedLedger), []*types.Transaction{
+				{ID: util.UUID4(), Key: types.MakeTxKey(cocoonID, "user/1"), Value: "value"},
+			})
+			So(err, ShouldBeNil)
+
+			event := recv(stream)
+			So(event.Ledger, ShouldEqual, unchainedLedger)
+			So(len(event.Transactions), ShouldEqual, 1)
+			So(event.Transactions[0].Key, ShouldEqual, "user/1")
+			So(event.Block, ShouldBeNil)
+		})
+
+		Convey("txFeed", func() {
+			unchainedLedger := types.MakeLedgerName(cocoonID, util.RandString(5))
+			_, err := od.store.CreateLedger(cocoonID, unchainedLedger, false, true)
+			So(err, ShouldBeNil)
+
+			now := time.Now()
+			feed, err := od.newLedgerFeed(&types.Ledger{Name: unchainedLedger, Public: true}, 0)
+			So(err, ShouldBeNil)
+			feed.(*txFeed).now = func() time.Time { return now }
+
+			put := func(key string) {
+				_, err := od.store.Put(unchainedLedger, []*types.Transaction{{ID: util.UUID4(), Key: types.MakeTxKey(cocoonID, key), Value: "value"}})
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Should return each transaction once while transactions within the settle time are read again", func() {
+				put("a")
+				changes, err := feed.next()
+				So(err, ShouldBeNil)
+				So(len(changes), ShouldEqual, 1)
+				So(len(changes[0].txs), ShouldEqual, 1)
+				So(changes[0].block, ShouldBeNil)
+
+				put("b")
+				changes, err = feed.next()
+				So(err, ShouldBeNil)
+				So(len(changes), ShouldEqual, 1)
+				So(types.GetActualKeyFromTxKey(changes[0].txs[0].Key), ShouldEqual, "b")
+				So(len(feed.(*txFeed).seen), ShouldEqual, 2)
+
+				changes, err = feed.next()
+				So(err, ShouldBeNil)
+				So(changes, ShouldBeEmpty)
+
+				Convey("Should stop reading transactions read before the settle time", func() {
+					now = now.Add(subscriptionSettleTime)
+					changes, err = feed.next()
+					So(err, ShouldBeNil)
+					So(changes, ShouldBeEmpty)
+					So(feed.(*txFeed).seen, ShouldBeEmpty)
+					So(feed.(*txFeed).readAt, ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("Should return error if the ledger does not exist", func() {
+			stream, err := client.Subscribe(context.Background(), &proto_orderer.SubscribeParams{CocoonID: cocoonID, Ledger: "unknown"})
+			So(err, ShouldBeNil)
+			_, err = stream.Recv()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "ledger not found")
+		})
+	})
+}
@@ -10,17 +10,44 @@ It is generated from these files:
 
 It has these top-level messages:
 	CreateLedgerParams
+	Index
 	PutTransactionParams
+	LedgerTransactions
+	PutMultiParams
+	PutMultiResult
 	GetLedgerParams
+	SetLedgerRetentionParams
 	GetParams
 	GetBlockParams
+	GetBlockByNumberParams
+	GetChainHeightParams
+	ChainHeight
+	ListBlocksParams
+	Blocks
 	GetRangeParams
+	GetHistoryParams
+	QueryParams
+	GetTxProofParams
+	VerifyChainParams
 	Ledger
 	Transaction
+	KeyRevision
 	Transactions
 	PutResult
 	TxReceipt
 	Block
+	ProofNode
+	TxProof
+	ChainReport
+	ExportParams
+	ArchiveChunk
+	ImportResult
+	SubscribeParams
+	LedgerEvent
+	GetPublicKeyParams
+	PublicKey
+	GetLeaderParams
+	Leader
 */
 package proto_orderer
 
@@ -45,10 +72,11 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type CreateLedgerParams struct {
-	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
-	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Public   bool   `protobuf:"varint,3,opt,name=public,proto3" json:"public,omitempty"`
-	Chained  bool   `protobuf:"varint,4,opt,name=chained,proto3" json:"chained,omitempty"`
+	CocoonID string   `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Name     string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Public   bool     `protobuf:"varint,3,opt,name=public,proto3" json:"public,omitempty"`
+	Chained  bool     `protobuf:"varint,4,opt,name=chained,proto3" json:"chained,omitempty"`
+	Indexes  []*Index `protobuf:"bytes,5,rep,name=indexes" json:"indexes,omitempty"`
 }
 
 func (m *CreateLedgerParams) Reset()                    { *m = CreateLedgerParams{} }
@@ -84,6 +112,37 @@ func (m *CreateLedgerParams) GetChained() bool {
 	return false
 }
 
+func (m *CreateLedgerParams) GetIndexes() []*Index {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+type Index struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (m *Index) Reset()                    { *m = Index{} }
+func (m *Index) String() string            { return proto.CompactTextString(m) }
+func (*Index) ProtoMessage()               {}
+func (*Index) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{1} }
+
+func (m *Index) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Index) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
 type PutTransactionParams struct {
 	CocoonID     string         `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
 	LedgerName   string         `protobuf:"bytes,2,opt,name=ledgerName,proto3" json:"ledgerName,omitempty"`
@@ -94,7 +153,7 @@ type PutTransactionParams struct {
 func (m *PutTransactionParams) Reset()                    { *m = PutTransactionParams{} }
 func (m *PutTransactionParams) String() string            { return proto.CompactTextString(m) }
 func (*PutTransactionParams) ProtoMessage()               {}
-func (*PutTransactionParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{1} }
+func (*PutTransactionParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{2} }
 
 func (m *PutTransactionParams) GetCocoonID() string {
 	if m != nil {
@@ -124,6 +183,70 @@ func (m *PutTransactionParams) GetRevisionID() string {
 	return ""
 }
 
+type LedgerTransactions struct {
+	Ledger       string         `protobuf:"bytes,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Transactions []*Transaction `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
+}
+
+func (m *LedgerTransactions) Reset()                    { *m = LedgerTransactions{} }
+func (m *LedgerTransactions) String() string            { return proto.CompactTextString(m) }
+func (*LedgerTransactions) ProtoMessage()               {}
+func (*LedgerTransactions) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{3} }
+
+func (m *LedgerTransactions) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *LedgerTransactions) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+type PutMultiParams struct {
+	CocoonID string                `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledgers  []*LedgerTransactions `protobuf:"bytes,2,rep,name=ledgers" json:"ledgers,omitempty"`
+}
+
+func (m *PutMultiParams) Reset()                    { *m = PutMultiParams{} }
+func (m *PutMultiParams) String() string            { return proto.CompactTextString(m) }
+func (*PutMultiParams) ProtoMessage()               {}
+func (*PutMultiParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{4} }
+
+func (m *PutMultiParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
+	}
+	return ""
+}
+
+func (m *PutMultiParams) GetLedgers() []*LedgerTransactions {
+	if m != nil {
+		return m.Ledgers
+	}
+	return nil
+}
+
+type PutMultiResult struct {
+	Results []*PutResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *PutMultiResult) Reset()                    { *m = PutMultiResult{} }
+func (m *PutMultiResult) String() string            { return proto.CompactTextString(m) }
+func (*PutMultiResult) ProtoMessage()               {}
+func (*PutMultiResult) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{5} }
+
+func (m *PutMultiResult) GetResults() []*PutResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
 type GetLedgerParams struct {
 	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
 	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -132,7 +255,7 @@ type GetLedgerParams struct {
 func (m *GetLedgerParams) Reset()                    { *m = GetLedgerParams{} }
 func (m *GetLedgerParams) String() string            { return proto.CompactTextString(m) }
 func (*GetLedgerParams) ProtoMessage()               {}
-func (*GetLedgerParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{2} }
+func (*GetLedgerParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{6} }
 
 func (m *GetLedgerParams) GetCocoonID() string {
 	if m != nil {
@@ -148,17 +271,58 @@ func (m *GetLedgerParams) GetName() string {
 	return ""
 }
 
+type SetLedgerRetentionParams struct {
+	CocoonID      string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	KeepRevisions int64  `protobuf:"varint,3,opt,name=keepRevisions,proto3" json:"keepRevisions,omitempty"`
+	KeepFor       int64  `protobuf:"varint,4,opt,name=keepFor,proto3" json:"keepFor,omitempty"`
+}
+
+func (m *SetLedgerRetentionParams) Reset()                    { *m = SetLedgerRetentionParams{} }
+func (m *SetLedgerRetentionParams) String() string            { return proto.CompactTextString(m) }
+func (*SetLedgerRetentionParams) ProtoMessage()               {}
+func (*SetLedgerRetentionParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{7} }
+
+func (m *SetLedgerRetentionParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
+	}
+	return ""
+}
+
+func (m *SetLedgerRetentionParams) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetLedgerRetentionParams) GetKeepRevisions() int64 {
+	if m != nil {
+		return m.KeepRevisions
+	}
+	return 0
+}
+
+func (m *SetLedgerRetentionParams) GetKeepFor() int64 {
+	if m != nil {
+		return m.KeepFor
+	}
+	return 0
+}
+
 type GetParams struct {
-	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
-	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
-	Id       string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
-	Key      string `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	CocoonID       string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger         string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Id             string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Key            string `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	IncludeDeleted bool   `protobuf:"varint,5,opt,name=includeDeleted,proto3" json:"includeDeleted,omitempty"`
 }
 
 func (m *GetParams) Reset()                    { *m = GetParams{} }
 func (m *GetParams) String() string            { return proto.CompactTextString(m) }
 func (*GetParams) ProtoMessage()               {}
-func (*GetParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{3} }
+func (*GetParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{8} }
 
 func (m *GetParams) GetCocoonID() string {
 	if m != nil {
@@ -188,6 +352,13 @@ func (m *GetParams) GetKey() string {
 	return ""
 }
 
+func (m *GetParams) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
 type GetBlockParams struct {
 	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
 	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
@@ -197,7 +368,7 @@ type GetBlockParams struct {
 func (m *GetBlockParams) Reset()                    { *m = GetBlockParams{} }
 func (m *GetBlockParams) String() string            { return proto.CompactTextString(m) }
 func (*GetBlockParams) ProtoMessage()               {}
-func (*GetBlockParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{4} }
+func (*GetBlockParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{9} }
 
 func (m *GetBlockParams) GetCocoonID() string {
 	if m != nil {
@@ -220,379 +391,1214 @@ func (m *GetBlockParams) GetId() string {
 	return ""
 }
 
-type GetRangeParams struct {
-	CocoonID  string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
-	Ledger    string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
-	StartKey  string `protobuf:"bytes,3,opt,name=startKey,proto3" json:"startKey,omitempty"`
-	EndKey    string `protobuf:"bytes,4,opt,name=endKey,proto3" json:"endKey,omitempty"`
-	Inclusive bool   `protobuf:"varint,5,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
-	Limit     int32  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset    int32  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+type GetBlockByNumberParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Number   int64  `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
 }
 
-func (m *GetRangeParams) Reset()                    { *m = GetRangeParams{} }
-func (m *GetRangeParams) String() string            { return proto.CompactTextString(m) }
-func (*GetRangeParams) ProtoMessage()               {}
-func (*GetRangeParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{5} }
+func (m *GetBlockByNumberParams) Reset()                    { *m = GetBlockByNumberParams{} }
+func (m *GetBlockByNumberParams) String() string            { return proto.CompactTextString(m) }
+func (*GetBlockByNumberParams) ProtoMessage()               {}
+func (*GetBlockByNumberParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{10} }
 
-func (m *GetRangeParams) GetCocoonID() string {
+func (m *GetBlockByNumberParams) GetCocoonID() string {
 	if m != nil {
 		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *GetRangeParams) GetLedger() string {
+func (m *GetBlockByNumberParams) GetLedger() string {
 	if m != nil {
 		return m.Ledger
 	}
 	return ""
 }
 
-func (m *GetRangeParams) GetStartKey() string {
+func (m *GetBlockByNumberParams) GetNumber() int64 {
 	if m != nil {
-		return m.StartKey
+		return m.Number
+	}
+	return 0
+}
+
+type GetChainHeightParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+}
+
+func (m *GetChainHeightParams) Reset()                    { *m = GetChainHeightParams{} }
+func (m *GetChainHeightParams) String() string            { return proto.CompactTextString(m) }
+func (*GetChainHeightParams) ProtoMessage()               {}
+func (*GetChainHeightParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{11} }
+
+func (m *GetChainHeightParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *GetRangeParams) GetEndKey() string {
+func (m *GetChainHeightParams) GetLedger() string {
 	if m != nil {
-		return m.EndKey
+		return m.Ledger
 	}
 	return ""
 }
 
-func (m *GetRangeParams) GetInclusive() bool {
+type ChainHeight struct {
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *ChainHeight) Reset()                    { *m = ChainHeight{} }
+func (m *ChainHeight) String() string            { return proto.CompactTextString(m) }
+func (*ChainHeight) ProtoMessage()               {}
+func (*ChainHeight) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{12} }
+
+func (m *ChainHeight) GetHeight() int64 {
 	if m != nil {
-		return m.Inclusive
+		return m.Height
 	}
-	return false
+	return 0
 }
 
-func (m *GetRangeParams) GetLimit() int32 {
+type ListBlocksParams struct {
+	CocoonID   string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger     string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	FromNumber int64  `protobuf:"varint,3,opt,name=fromNumber,proto3" json:"fromNumber,omitempty"`
+	Limit      int32  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *ListBlocksParams) Reset()                    { *m = ListBlocksParams{} }
+func (m *ListBlocksParams) String() string            { return proto.CompactTextString(m) }
+func (*ListBlocksParams) ProtoMessage()               {}
+func (*ListBlocksParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{13} }
+
+func (m *ListBlocksParams) GetCocoonID() string {
 	if m != nil {
-		return m.Limit
+		return m.CocoonID
+	}
+	return ""
+}
+
+func (m *ListBlocksParams) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *ListBlocksParams) GetFromNumber() int64 {
+	if m != nil {
+		return m.FromNumber
 	}
 	return 0
 }
 
-func (m *GetRangeParams) GetOffset() int32 {
+func (m *ListBlocksParams) GetLimit() int32 {
 	if m != nil {
-		return m.Offset
+		return m.Limit
 	}
 	return 0
 }
 
-type Ledger struct {
-	Number       int64  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-	Hash         string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
-	Name         string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	NameInternal string `protobuf:"bytes,4,opt,name=nameInternal,proto3" json:"nameInternal,omitempty"`
-	Public       bool   `protobuf:"varint,5,opt,name=public,proto3" json:"public,omitempty"`
-	Chained      bool   `protobuf:"varint,6,opt,name=chained,proto3" json:"chained,omitempty"`
-	CreatedAt    int64  `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+type Blocks struct {
+	Blocks []*Block `protobuf:"bytes,1,rep,name=blocks" json:"blocks,omitempty"`
 }
 
-func (m *Ledger) Reset()                    { *m = Ledger{} }
-func (m *Ledger) String() string            { return proto.CompactTextString(m) }
-func (*Ledger) ProtoMessage()               {}
-func (*Ledger) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{6} }
+func (m *Blocks) Reset()                    { *m = Blocks{} }
+func (m *Blocks) String() string            { return proto.CompactTextString(m) }
+func (*Blocks) ProtoMessage()               {}
+func (*Blocks) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{14} }
 
-func (m *Ledger) GetNumber() int64 {
+func (m *Blocks) GetBlocks() []*Block {
 	if m != nil {
-		return m.Number
+		return m.Blocks
 	}
-	return 0
+	return nil
 }
 
-func (m *Ledger) GetHash() string {
+type GetRangeParams struct {
+	CocoonID       string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger         string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	StartKey       string `protobuf:"bytes,3,opt,name=startKey,proto3" json:"startKey,omitempty"`
+	EndKey         string `protobuf:"bytes,4,opt,name=endKey,proto3" json:"endKey,omitempty"`
+	Inclusive      bool   `protobuf:"varint,5,opt,name=inclusive,proto3" json:"inclusive,omitempty"`
+	Limit          int32  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset         int32  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	IncludeDeleted bool   `protobuf:"varint,8,opt,name=includeDeleted,proto3" json:"includeDeleted,omitempty"`
+	Prefix         string `protobuf:"bytes,9,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Reverse        bool   `protobuf:"varint,10,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Cursor         string `protobuf:"bytes,11,opt,name=cursor,proto3" json:"cursor,omitempty"`
+}
+
+func (m *GetRangeParams) Reset()                    { *m = GetRangeParams{} }
+func (m *GetRangeParams) String() string            { return proto.CompactTextString(m) }
+func (*GetRangeParams) ProtoMessage()               {}
+func (*GetRangeParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{15} }
+
+func (m *GetRangeParams) GetCocoonID() string {
 	if m != nil {
-		return m.Hash
+		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *Ledger) GetName() string {
+func (m *GetRangeParams) GetLedger() string {
 	if m != nil {
-		return m.Name
+		return m.Ledger
 	}
 	return ""
 }
 
-func (m *Ledger) GetNameInternal() string {
+func (m *GetRangeParams) GetStartKey() string {
 	if m != nil {
-		return m.NameInternal
+		return m.StartKey
 	}
 	return ""
 }
 
-func (m *Ledger) GetPublic() bool {
+func (m *GetRangeParams) GetEndKey() string {
 	if m != nil {
-		return m.Public
+		return m.EndKey
 	}
-	return false
+	return ""
 }
 
-func (m *Ledger) GetChained() bool {
+func (m *GetRangeParams) GetInclusive() bool {
 	if m != nil {
-		return m.Chained
+		return m.Inclusive
 	}
 	return false
 }
 
-func (m *Ledger) GetCreatedAt() int64 {
+func (m *GetRangeParams) GetLimit() int32 {
 	if m != nil {
-		return m.CreatedAt
+		return m.Limit
 	}
 	return 0
 }
 
-type Transaction struct {
-	Number         int64  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-	Ledger         string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
-	LedgerInternal string `protobuf:"bytes,3,opt,name=ledgerInternal,proto3" json:"ledgerInternal,omitempty"`
-	Id             string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
-	Key            string `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
-	KeyInternal    string `protobuf:"bytes,6,opt,name=keyInternal,proto3" json:"keyInternal,omitempty"`
-	Value          string `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`
-	Hash           string `protobuf:"bytes,8,opt,name=hash,proto3" json:"hash,omitempty"`
-	CreatedAt      int64  `protobuf:"varint,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	BlockId        string `protobuf:"bytes,10,opt,name=blockId,proto3" json:"blockId,omitempty"`
-	Block          *Block `protobuf:"bytes,11,opt,name=block" json:"block,omitempty"`
-	RevisionTo     string `protobuf:"bytes,12,opt,name=revisionTo,proto3" json:"revisionTo,omitempty"`
+func (m *GetRangeParams) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
 }
 
-func (m *Transaction) Reset()                    { *m = Transaction{} }
-func (m *Transaction) String() string            { return proto.CompactTextString(m) }
-func (*Transaction) ProtoMessage()               {}
-func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{7} }
-
-func (m *Transaction) GetNumber() int64 {
+func (m *GetRangeParams) GetIncludeDeleted() bool {
 	if m != nil {
-		return m.Number
+		return m.IncludeDeleted
 	}
-	return 0
+	return false
 }
 
-func (m *Transaction) GetLedger() string {
+func (m *GetRangeParams) GetPrefix() string {
 	if m != nil {
-		return m.Ledger
+		return m.Prefix
 	}
 	return ""
 }
 
-func (m *Transaction) GetLedgerInternal() string {
+func (m *GetRangeParams) GetReverse() bool {
 	if m != nil {
-		return m.LedgerInternal
+		return m.Reverse
 	}
-	return ""
+	return false
 }
 
-func (m *Transaction) GetId() string {
+func (m *GetRangeParams) GetCursor() string {
 	if m != nil {
-		return m.Id
+		return m.Cursor
 	}
 	return ""
 }
 
-func (m *Transaction) GetKey() string {
+type GetHistoryParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Key      string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Limit    int32  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor   int64  `protobuf:"varint,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+}
+
+func (m *GetHistoryParams) Reset()                    { *m = GetHistoryParams{} }
+func (m *GetHistoryParams) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryParams) ProtoMessage()               {}
+func (*GetHistoryParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{16} }
+
+func (m *GetHistoryParams) GetCocoonID() string {
 	if m != nil {
-		return m.Key
+		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *Transaction) GetKeyInternal() string {
+func (m *GetHistoryParams) GetLedger() string {
 	if m != nil {
-		return m.KeyInternal
+		return m.Ledger
 	}
 	return ""
 }
 
-func (m *Transaction) GetValue() string {
+func (m *GetHistoryParams) GetKey() string {
 	if m != nil {
-		return m.Value
+		return m.Key
 	}
 	return ""
 }
 
-func (m *Transaction) GetHash() string {
+func (m *GetHistoryParams) GetLimit() int32 {
 	if m != nil {
-		return m.Hash
+		return m.Limit
 	}
-	return ""
+	return 0
 }
 
-func (m *Transaction) GetCreatedAt() int64 {
+func (m *GetHistoryParams) GetCursor() int64 {
 	if m != nil {
-		return m.CreatedAt
+		return m.Cursor
 	}
 	return 0
 }
 
-func (m *Transaction) GetBlockId() string {
+type QueryParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Index    string `protobuf:"bytes,3,opt,name=index,proto3" json:"index,omitempty"`
+	Op       string `protobuf:"bytes,4,opt,name=op,proto3" json:"op,omitempty"`
+	Value    string `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Cursor   string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit    int32  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *QueryParams) Reset()                    { *m = QueryParams{} }
+func (m *QueryParams) String() string            { return proto.CompactTextString(m) }
+func (*QueryParams) ProtoMessage()               {}
+func (*QueryParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{17} }
+
+func (m *QueryParams) GetCocoonID() string {
 	if m != nil {
-		return m.BlockId
+		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *Transaction) GetBlock() *Block {
+func (m *QueryParams) GetLedger() string {
 	if m != nil {
-		return m.Block
+		return m.Ledger
 	}
-	return nil
+	return ""
 }
 
-func (m *Transaction) GetRevisionTo() string {
+func (m *QueryParams) GetIndex() string {
 	if m != nil {
-		return m.RevisionTo
+		return m.Index
 	}
 	return ""
 }
 
-type Transactions struct {
-	Transactions []*Transaction `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+func (m *QueryParams) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
 }
 
-func (m *Transactions) Reset()                    { *m = Transactions{} }
-func (m *Transactions) String() string            { return proto.CompactTextString(m) }
-func (*Transactions) ProtoMessage()               {}
-func (*Transactions) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{8} }
-
+func (m *QueryParams) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *QueryParams) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *QueryParams) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type GetTxProofParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	TxID     string `protobuf:"bytes,3,opt,name=txID,proto3" json:"txID,omitempty"`
+}
+
+func (m *GetTxProofParams) Reset()                    { *m = GetTxProofParams{} }
+func (m *GetTxProofParams) String() string            { return proto.CompactTextString(m) }
+func (*GetTxProofParams) ProtoMessage()               {}
+func (*GetTxProofParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{18} }
+
+func (m *GetTxProofParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
+	}
+	return ""
+}
+
+func (m *GetTxProofParams) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *GetTxProofParams) GetTxID() string {
+	if m != nil {
+		return m.TxID
+	}
+	return ""
+}
+
+type VerifyChainParams struct {
+	CocoonID   string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger     string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	PublicKey  string `protobuf:"bytes,3,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	SignedFrom int64  `protobuf:"varint,4,opt,name=signedFrom,proto3" json:"signedFrom,omitempty"`
+}
+
+func (m *VerifyChainParams) Reset()                    { *m = VerifyChainParams{} }
+func (m *VerifyChainParams) String() string            { return proto.CompactTextString(m) }
+func (*VerifyChainParams) ProtoMessage()               {}
+func (*VerifyChainParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{19} }
+
+func (m *VerifyChainParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
+	}
+	return ""
+}
+
+func (m *VerifyChainParams) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *VerifyChainParams) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *VerifyChainParams) GetSignedFrom() int64 {
+	if m != nil {
+		return m.SignedFrom
+	}
+	return 0
+}
+
+type Ledger struct {
+	Number        int64    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Hash          string   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Name          string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	NameInternal  string   `protobuf:"bytes,4,opt,name=nameInternal,proto3" json:"nameInternal,omitempty"`
+	Public        bool     `protobuf:"varint,5,opt,name=public,proto3" json:"public,omitempty"`
+	Chained       bool     `protobuf:"varint,6,opt,name=chained,proto3" json:"chained,omitempty"`
+	CreatedAt     int64    `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	KeepRevisions int64    `protobuf:"varint,8,opt,name=keepRevisions,proto3" json:"keepRevisions,omitempty"`
+	KeepFor       int64    `protobuf:"varint,9,opt,name=keepFor,proto3" json:"keepFor,omitempty"`
+	Indexes       []*Index `protobuf:"bytes,10,rep,name=indexes" json:"indexes,omitempty"`
+}
+
+func (m *Ledger) Reset()                    { *m = Ledger{} }
+func (m *Ledger) String() string            { return proto.CompactTextString(m) }
+func (*Ledger) ProtoMessage()               {}
+func (*Ledger) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{20} }
+
+func (m *Ledger) GetNumber() int64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Ledger) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Ledger) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Ledger) GetNameInternal() string {
+	if m != nil {
+		return m.NameInternal
+	}
+	return ""
+}
+
+func (m *Ledger) GetPublic() bool {
+	if m != nil {
+		return m.Public
+	}
+	return false
+}
+
+func (m *Ledger) GetChained() bool {
+	if m != nil {
+		return m.Chained
+	}
+	return false
+}
+
+func (m *Ledger) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Ledger) GetKeepRevisions() int64 {
+	if m != nil {
+		return m.KeepRevisions
+	}
+	return 0
+}
+
+func (m *Ledger) GetKeepFor() int64 {
+	if m != nil {
+		return m.KeepFor
+	}
+	return 0
+}
+
+func (m *Ledger) GetIndexes() []*Index {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+type Transaction struct {
+	Number         int64          `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Ledger         string         `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	LedgerInternal string         `protobuf:"bytes,3,opt,name=ledgerInternal,proto3" json:"ledgerInternal,omitempty"`
+	Id             string         `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	Key            string         `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	KeyInternal    string         `protobuf:"bytes,6,opt,name=keyInternal,proto3" json:"keyInternal,omitempty"`
+	Value          string         `protobuf:"bytes,7,opt,name=value,proto3" json:"value,omitempty"`
+	Hash           string         `protobuf:"bytes,8,opt,name=hash,proto3" json:"hash,omitempty"`
+	CreatedAt      int64          `protobuf:"varint,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	BlockId        string         `protobuf:"bytes,10,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	Block          *Block         `protobuf:"bytes,11,opt,name=block" json:"block,omitempty"`
+	RevisionTo     string         `protobuf:"bytes,12,opt,name=revisionTo,proto3" json:"revisionTo,omitempty"`
+	Deleted        bool           `protobuf:"varint,13,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	ReadSet        []*KeyRevision `protobuf:"bytes,14,rep,name=readSet" json:"readSet,omitempty"`
+}
+
+func (m *Transaction) Reset()                    { *m = Transaction{} }
+func (m *Transaction) String() string            { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()               {}
+func (*Transaction) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{21} }
+
+func (m *Transaction) GetNumber() int64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Transaction) GetLedger() string {
+	if m != nil {
+		return m.Ledger
+	}
+	return ""
+}
+
+func (m *Transaction) GetLedgerInternal() string {
+	if m != nil {
+		return m.LedgerInternal
+	}
+	return ""
+}
+
+func (m *Transaction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Transaction) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Transaction) GetKeyInternal() string {
+	if m != nil {
+		return m.KeyInternal
+	}
+	return ""
+}
+
+func (m *Transaction) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *Transaction) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Transaction) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Transaction) GetBlockId() string {
+	if m != nil {
+		return m.BlockId
+	}
+	return ""
+}
+
+func (m *Transaction) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *Transaction) GetRevisionTo() string {
+	if m != nil {
+		return m.RevisionTo
+	}
+	return ""
+}
+
+func (m *Transaction) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *Transaction) GetReadSet() []*KeyRevision {
+	if m != nil {
+		return m.ReadSet
+	}
+	return nil
+}
+
+type KeyRevision struct {
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Revision string `protobuf:"bytes,2,opt,name=revision,proto3" json:"revision,omitempty"`
+}
+
+func (m *KeyRevision) Reset()                    { *m = KeyRevision{} }
+func (m *KeyRevision) String() string            { return proto.CompactTextString(m) }
+func (*KeyRevision) ProtoMessage()               {}
+func (*KeyRevision) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{22} }
+
+func (m *KeyRevision) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KeyRevision) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type Transactions struct {
+	Transactions []*Transaction `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+	NextCursor   string         `protobuf:"bytes,2,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+}
+
+func (m *Transactions) Reset()                    { *m = Transactions{} }
+func (m *Transactions) String() string            { return proto.CompactTextString(m) }
+func (*Transactions) ProtoMessage()               {}
+func (*Transactions) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{23} }
+
 func (m *Transactions) GetTransactions() []*Transaction {
 	if m != nil {
-		return m.Transactions
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *Transactions) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type PutResult struct {
+	TxReceipts []*TxReceipt `protobuf:"bytes,1,rep,name=txReceipts" json:"txReceipts,omitempty"`
+	Block      *Block       `protobuf:"bytes,2,opt,name=block" json:"block,omitempty"`
+}
+
+func (m *PutResult) Reset()                    { *m = PutResult{} }
+func (m *PutResult) String() string            { return proto.CompactTextString(m) }
+func (*PutResult) ProtoMessage()               {}
+func (*PutResult) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{24} }
+
+func (m *PutResult) GetTxReceipts() []*TxReceipt {
+	if m != nil {
+		return m.TxReceipts
+	}
+	return nil
+}
+
+func (m *PutResult) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type TxReceipt struct {
+	ID  string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Err string `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *TxReceipt) Reset()                    { *m = TxReceipt{} }
+func (m *TxReceipt) String() string            { return proto.CompactTextString(m) }
+func (*TxReceipt) ProtoMessage()               {}
+func (*TxReceipt) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{25} }
+
+func (m *TxReceipt) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *TxReceipt) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+type Block struct {
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number        int64  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	ChainName     string `protobuf:"bytes,3,opt,name=chainName,proto3" json:"chainName,omitempty"`
+	PrevBlockHash string `protobuf:"bytes,4,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
+	Hash          string `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
+	Transactions  []byte `protobuf:"bytes,6,opt,name=transactions,proto3" json:"transactions,omitempty"`
+	CreatedAt     int64  `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	MerkleRoot    string `protobuf:"bytes,8,opt,name=merkleRoot,proto3" json:"merkleRoot,omitempty"`
+	Signature     string `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	KeyId         string `protobuf:"bytes,10,opt,name=keyId,proto3" json:"keyId,omitempty"`
+}
+
+func (m *Block) Reset()                    { *m = Block{} }
+func (m *Block) String() string            { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()               {}
+func (*Block) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{26} }
+
+func (m *Block) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Block) GetNumber() int64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *Block) GetChainName() string {
+	if m != nil {
+		return m.ChainName
+	}
+	return ""
+}
+
+func (m *Block) GetPrevBlockHash() string {
+	if m != nil {
+		return m.PrevBlockHash
+	}
+	return ""
+}
+
+func (m *Block) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Block) GetTransactions() []byte {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *Block) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Block) GetMerkleRoot() string {
+	if m != nil {
+		return m.MerkleRoot
+	}
+	return ""
+}
+
+func (m *Block) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *Block) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
+}
+
+type ProofNode struct {
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Left bool   `protobuf:"varint,2,opt,name=left,proto3" json:"left,omitempty"`
+}
+
+func (m *ProofNode) Reset()                    { *m = ProofNode{} }
+func (m *ProofNode) String() string            { return proto.CompactTextString(m) }
+func (*ProofNode) ProtoMessage()               {}
+func (*ProofNode) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{27} }
+
+func (m *ProofNode) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *ProofNode) GetLeft() bool {
+	if m != nil {
+		return m.Left
+	}
+	return false
+}
+
+type TxProof struct {
+	TxId          string       `protobuf:"bytes,1,opt,name=txId,proto3" json:"txId,omitempty"`
+	TxHash        string       `protobuf:"bytes,2,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	BlockId       string       `protobuf:"bytes,3,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	BlockNumber   int64        `protobuf:"varint,4,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	BlockHash     string       `protobuf:"bytes,5,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	PrevBlockHash string       `protobuf:"bytes,6,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
+	MerkleRoot    string       `protobuf:"bytes,7,opt,name=merkleRoot,proto3" json:"merkleRoot,omitempty"`
+	Path          []*ProofNode `protobuf:"bytes,8,rep,name=path" json:"path,omitempty"`
+}
+
+func (m *TxProof) Reset()                    { *m = TxProof{} }
+func (m *TxProof) String() string            { return proto.CompactTextString(m) }
+func (*TxProof) ProtoMessage()               {}
+func (*TxProof) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{28} }
+
+func (m *TxProof) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TxProof) GetTxHash() string {
+	if m != nil {
+		return m.TxHash
+	}
+	return ""
+}
+
+func (m *TxProof) GetBlockId() string {
+	if m != nil {
+		return m.BlockId
+	}
+	return ""
+}
+
+func (m *TxProof) GetBlockNumber() int64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *TxProof) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *TxProof) GetPrevBlockHash() string {
+	if m != nil {
+		return m.PrevBlockHash
+	}
+	return ""
+}
+
+func (m *TxProof) GetMerkleRoot() string {
+	if m != nil {
+		return m.MerkleRoot
+	}
+	return ""
+}
+
+func (m *TxProof) GetPath() []*ProofNode {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type ChainReport struct {
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	BlockId       string `protobuf:"bytes,2,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	BlockNumber   int64  `protobuf:"varint,3,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	TxId          string `protobuf:"bytes,4,opt,name=txId,proto3" json:"txId,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	BlocksChecked int64  `protobuf:"varint,6,opt,name=blocksChecked,proto3" json:"blocksChecked,omitempty"`
+}
+
+func (m *ChainReport) Reset()                    { *m = ChainReport{} }
+func (m *ChainReport) String() string            { return proto.CompactTextString(m) }
+func (*ChainReport) ProtoMessage()               {}
+func (*ChainReport) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{29} }
+
+func (m *ChainReport) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ChainReport) GetBlockId() string {
+	if m != nil {
+		return m.BlockId
+	}
+	return ""
+}
+
+func (m *ChainReport) GetBlockNumber() int64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *ChainReport) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *ChainReport) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ChainReport) GetBlocksChecked() int64 {
+	if m != nil {
+		return m.BlocksChecked
+	}
+	return 0
+}
+
+type ExportParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+}
+
+func (m *ExportParams) Reset()                    { *m = ExportParams{} }
+func (m *ExportParams) String() string            { return proto.CompactTextString(m) }
+func (*ExportParams) ProtoMessage()               {}
+func (*ExportParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{30} }
+
+func (m *ExportParams) GetCocoonID() string {
+	if m != nil {
+		return m.CocoonID
+	}
+	return ""
+}
+
+type ArchiveChunk struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ArchiveChunk) Reset()                    { *m = ArchiveChunk{} }
+func (m *ArchiveChunk) String() string            { return proto.CompactTextString(m) }
+func (*ArchiveChunk) ProtoMessage()               {}
+func (*ArchiveChunk) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{31} }
+
+func (m *ArchiveChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
 	return nil
 }
 
-type PutResult struct {
-	TxReceipts []*TxReceipt `protobuf:"bytes,1,rep,name=txReceipts" json:"txReceipts,omitempty"`
-	Block      *Block       `protobuf:"bytes,2,opt,name=block" json:"block,omitempty"`
+type ImportResult struct {
+	Ledgers      int64 `protobuf:"varint,1,opt,name=ledgers,proto3" json:"ledgers,omitempty"`
+	Blocks       int64 `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Transactions int64 `protobuf:"varint,3,opt,name=transactions,proto3" json:"transactions,omitempty"`
 }
 
-func (m *PutResult) Reset()                    { *m = PutResult{} }
-func (m *PutResult) String() string            { return proto.CompactTextString(m) }
-func (*PutResult) ProtoMessage()               {}
-func (*PutResult) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{9} }
+func (m *ImportResult) Reset()                    { *m = ImportResult{} }
+func (m *ImportResult) String() string            { return proto.CompactTextString(m) }
+func (*ImportResult) ProtoMessage()               {}
+func (*ImportResult) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{32} }
 
-func (m *PutResult) GetTxReceipts() []*TxReceipt {
+func (m *ImportResult) GetLedgers() int64 {
 	if m != nil {
-		return m.TxReceipts
+		return m.Ledgers
 	}
-	return nil
+	return 0
 }
 
-func (m *PutResult) GetBlock() *Block {
+func (m *ImportResult) GetBlocks() int64 {
 	if m != nil {
-		return m.Block
+		return m.Blocks
 	}
-	return nil
+	return 0
 }
 
-type TxReceipt struct {
-	ID  string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Err string `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+func (m *ImportResult) GetTransactions() int64 {
+	if m != nil {
+		return m.Transactions
+	}
+	return 0
 }
 
-func (m *TxReceipt) Reset()                    { *m = TxReceipt{} }
-func (m *TxReceipt) String() string            { return proto.CompactTextString(m) }
-func (*TxReceipt) ProtoMessage()               {}
-func (*TxReceipt) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{10} }
+type SubscribeParams struct {
+	CocoonID  string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger    string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Prefix    string `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	FromBlock int64  `protobuf:"varint,4,opt,name=fromBlock,proto3" json:"fromBlock,omitempty"`
+}
 
-func (m *TxReceipt) GetID() string {
+func (m *SubscribeParams) Reset()                    { *m = SubscribeParams{} }
+func (m *SubscribeParams) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeParams) ProtoMessage()               {}
+func (*SubscribeParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{33} }
+
+func (m *SubscribeParams) GetCocoonID() string {
 	if m != nil {
-		return m.ID
+		return m.CocoonID
 	}
 	return ""
 }
 
-func (m *TxReceipt) GetErr() string {
+func (m *SubscribeParams) GetLedger() string {
 	if m != nil {
-		return m.Err
+		return m.Ledger
 	}
 	return ""
 }
 
-type Block struct {
-	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Number        int64  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
-	ChainName     string `protobuf:"bytes,3,opt,name=chainName,proto3" json:"chainName,omitempty"`
-	PrevBlockHash string `protobuf:"bytes,4,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
-	Hash          string `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`
-	Transactions  []byte `protobuf:"bytes,6,opt,name=transactions,proto3" json:"transactions,omitempty"`
-	CreatedAt     int64  `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+func (m *SubscribeParams) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
 }
 
-func (m *Block) Reset()                    { *m = Block{} }
-func (m *Block) String() string            { return proto.CompactTextString(m) }
-func (*Block) ProtoMessage()               {}
-func (*Block) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{11} }
+func (m *SubscribeParams) GetFromBlock() int64 {
+	if m != nil {
+		return m.FromBlock
+	}
+	return 0
+}
 
-func (m *Block) GetId() string {
+type LedgerEvent struct {
+	Ledger       string         `protobuf:"bytes,1,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	Transactions []*Transaction `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
+	Block        *Block         `protobuf:"bytes,3,opt,name=block" json:"block,omitempty"`
+}
+
+func (m *LedgerEvent) Reset()                    { *m = LedgerEvent{} }
+func (m *LedgerEvent) String() string            { return proto.CompactTextString(m) }
+func (*LedgerEvent) ProtoMessage()               {}
+func (*LedgerEvent) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{34} }
+
+func (m *LedgerEvent) GetLedger() string {
 	if m != nil {
-		return m.Id
+		return m.Ledger
 	}
 	return ""
 }
 
-func (m *Block) GetNumber() int64 {
+func (m *LedgerEvent) GetTransactions() []*Transaction {
 	if m != nil {
-		return m.Number
+		return m.Transactions
 	}
-	return 0
+	return nil
 }
 
-func (m *Block) GetChainName() string {
+func (m *LedgerEvent) GetBlock() *Block {
 	if m != nil {
-		return m.ChainName
+		return m.Block
+	}
+	return nil
+}
+
+type GetPublicKeyParams struct {
+}
+
+func (m *GetPublicKeyParams) Reset()                    { *m = GetPublicKeyParams{} }
+func (m *GetPublicKeyParams) String() string            { return proto.CompactTextString(m) }
+func (*GetPublicKeyParams) ProtoMessage()               {}
+func (*GetPublicKeyParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{35} }
+
+type PublicKey struct {
+	KeyId     string `protobuf:"bytes,1,opt,name=keyId,proto3" json:"keyId,omitempty"`
+	Algorithm string `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	PublicKey string `protobuf:"bytes,3,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+}
+
+func (m *PublicKey) Reset()                    { *m = PublicKey{} }
+func (m *PublicKey) String() string            { return proto.CompactTextString(m) }
+func (*PublicKey) ProtoMessage()               {}
+func (*PublicKey) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{36} }
+
+func (m *PublicKey) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
 	}
 	return ""
 }
 
-func (m *Block) GetPrevBlockHash() string {
+func (m *PublicKey) GetAlgorithm() string {
 	if m != nil {
-		return m.PrevBlockHash
+		return m.Algorithm
 	}
 	return ""
 }
 
-func (m *Block) GetHash() string {
+func (m *PublicKey) GetPublicKey() string {
 	if m != nil {
-		return m.Hash
+		return m.PublicKey
 	}
 	return ""
 }
 
-func (m *Block) GetTransactions() []byte {
+type GetLeaderParams struct {
+	CocoonID string `protobuf:"bytes,1,opt,name=cocoonID,proto3" json:"cocoonID,omitempty"`
+	Ledger   string `protobuf:"bytes,2,opt,name=ledger,proto3" json:"ledger,omitempty"`
+}
+
+func (m *GetLeaderParams) Reset()                    { *m = GetLeaderParams{} }
+func (m *GetLeaderParams) String() string            { return proto.CompactTextString(m) }
+func (*GetLeaderParams) ProtoMessage()               {}
+func (*GetLeaderParams) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{37} }
+
+func (m *GetLeaderParams) GetCocoonID() string {
 	if m != nil {
-		return m.Transactions
+		return m.CocoonID
 	}
-	return nil
+	return ""
 }
 
-func (m *Block) GetCreatedAt() int64 {
+func (m *GetLeaderParams) GetLedger() string {
 	if m != nil {
-		return m.CreatedAt
+		return m.Ledger
 	}
-	return 0
+	return ""
+}
+
+type Leader struct {
+	Addr string `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+}
+
+func (m *Leader) Reset()                    { *m = Leader{} }
+func (m *Leader) String() string            { return proto.CompactTextString(m) }
+func (*Leader) ProtoMessage()               {}
+func (*Leader) Descriptor() ([]byte, []int) { return fileDescriptorServer, []int{38} }
+
+func (m *Leader) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
 }
 
 func init() {
 	proto.RegisterType((*CreateLedgerParams)(nil), "proto_orderer.CreateLedgerParams")
+	proto.RegisterType((*Index)(nil), "proto_orderer.Index")
 	proto.RegisterType((*PutTransactionParams)(nil), "proto_orderer.PutTransactionParams")
+	proto.RegisterType((*LedgerTransactions)(nil), "proto_orderer.LedgerTransactions")
+	proto.RegisterType((*PutMultiParams)(nil), "proto_orderer.PutMultiParams")
+	proto.RegisterType((*PutMultiResult)(nil), "proto_orderer.PutMultiResult")
 	proto.RegisterType((*GetLedgerParams)(nil), "proto_orderer.GetLedgerParams")
+	proto.RegisterType((*SetLedgerRetentionParams)(nil), "proto_orderer.SetLedgerRetentionParams")
 	proto.RegisterType((*GetParams)(nil), "proto_orderer.GetParams")
 	proto.RegisterType((*GetBlockParams)(nil), "proto_orderer.GetBlockParams")
+	proto.RegisterType((*GetBlockByNumberParams)(nil), "proto_orderer.GetBlockByNumberParams")
+	proto.RegisterType((*GetChainHeightParams)(nil), "proto_orderer.GetChainHeightParams")
+	proto.RegisterType((*ChainHeight)(nil), "proto_orderer.ChainHeight")
+	proto.RegisterType((*ListBlocksParams)(nil), "proto_orderer.ListBlocksParams")
+	proto.RegisterType((*Blocks)(nil), "proto_orderer.Blocks")
 	proto.RegisterType((*GetRangeParams)(nil), "proto_orderer.GetRangeParams")
+	proto.RegisterType((*GetHistoryParams)(nil), "proto_orderer.GetHistoryParams")
+	proto.RegisterType((*QueryParams)(nil), "proto_orderer.QueryParams")
+	proto.RegisterType((*GetTxProofParams)(nil), "proto_orderer.GetTxProofParams")
+	proto.RegisterType((*VerifyChainParams)(nil), "proto_orderer.VerifyChainParams")
 	proto.RegisterType((*Ledger)(nil), "proto_orderer.Ledger")
 	proto.RegisterType((*Transaction)(nil), "proto_orderer.Transaction")
+	proto.RegisterType((*KeyRevision)(nil), "proto_orderer.KeyRevision")
 	proto.RegisterType((*Transactions)(nil), "proto_orderer.Transactions")
 	proto.RegisterType((*PutResult)(nil), "proto_orderer.PutResult")
 	proto.RegisterType((*TxReceipt)(nil), "proto_orderer.TxReceipt")
 	proto.RegisterType((*Block)(nil), "proto_orderer.Block")
+	proto.RegisterType((*ProofNode)(nil), "proto_orderer.ProofNode")
+	proto.RegisterType((*TxProof)(nil), "proto_orderer.TxProof")
+	proto.RegisterType((*ChainReport)(nil), "proto_orderer.ChainReport")
+	proto.RegisterType((*ExportParams)(nil), "proto_orderer.ExportParams")
+	proto.RegisterType((*ArchiveChunk)(nil), "proto_orderer.ArchiveChunk")
+	proto.RegisterType((*ImportResult)(nil), "proto_orderer.ImportResult")
+	proto.RegisterType((*SubscribeParams)(nil), "proto_orderer.SubscribeParams")
+	proto.RegisterType((*LedgerEvent)(nil), "proto_orderer.LedgerEvent")
+	proto.RegisterType((*GetPublicKeyParams)(nil), "proto_orderer.GetPublicKeyParams")
+	proto.RegisterType((*PublicKey)(nil), "proto_orderer.PublicKey")
+	proto.RegisterType((*GetLeaderParams)(nil), "proto_orderer.GetLeaderParams")
+	proto.RegisterType((*Leader)(nil), "proto_orderer.Leader")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -605,71 +1611,305 @@ const _ = grpc.SupportPackageIsVersion4
 
 // Client API for Orderer service
 
-type OrdererClient interface {
-	CreateLedger(ctx context.Context, in *CreateLedgerParams, opts ...grpc.CallOption) (*Ledger, error)
-	GetLedger(ctx context.Context, in *GetLedgerParams, opts ...grpc.CallOption) (*Ledger, error)
-	Put(ctx context.Context, in *PutTransactionParams, opts ...grpc.CallOption) (*PutResult, error)
-	Get(ctx context.Context, in *GetParams, opts ...grpc.CallOption) (*Transaction, error)
-	GetBlockByID(ctx context.Context, in *GetBlockParams, opts ...grpc.CallOption) (*Block, error)
-	GetRange(ctx context.Context, in *GetRangeParams, opts ...grpc.CallOption) (*Transactions, error)
+type OrdererClient interface {
+	CreateLedger(ctx context.Context, in *CreateLedgerParams, opts ...grpc.CallOption) (*Ledger, error)
+	GetLedger(ctx context.Context, in *GetLedgerParams, opts ...grpc.CallOption) (*Ledger, error)
+	SetLedgerRetention(ctx context.Context, in *SetLedgerRetentionParams, opts ...grpc.CallOption) (*Ledger, error)
+	Put(ctx context.Context, in *PutTransactionParams, opts ...grpc.CallOption) (*PutResult, error)
+	PutMulti(ctx context.Context, in *PutMultiParams, opts ...grpc.CallOption) (*PutMultiResult, error)
+	Get(ctx context.Context, in *GetParams, opts ...grpc.CallOption) (*Transaction, error)
+	GetBlockByID(ctx context.Context, in *GetBlockParams, opts ...grpc.CallOption) (*Block, error)
+	GetBlockByNumber(ctx context.Context, in *GetBlockByNumberParams, opts ...grpc.CallOption) (*Block, error)
+	GetChainHeight(ctx context.Context, in *GetChainHeightParams, opts ...grpc.CallOption) (*ChainHeight, error)
+	ListBlocks(ctx context.Context, in *ListBlocksParams, opts ...grpc.CallOption) (*Blocks, error)
+	GetRange(ctx context.Context, in *GetRangeParams, opts ...grpc.CallOption) (*Transactions, error)
+	GetHistory(ctx context.Context, in *GetHistoryParams, opts ...grpc.CallOption) (*Transactions, error)
+	Query(ctx context.Context, in *QueryParams, opts ...grpc.CallOption) (*Transactions, error)
+	GetTxProof(ctx context.Context, in *GetTxProofParams, opts ...grpc.CallOption) (*TxProof, error)
+	VerifyChain(ctx context.Context, in *VerifyChainParams, opts ...grpc.CallOption) (Orderer_VerifyChainClient, error)
+	Export(ctx context.Context, in *ExportParams, opts ...grpc.CallOption) (Orderer_ExportClient, error)
+	Import(ctx context.Context, opts ...grpc.CallOption) (Orderer_ImportClient, error)
+	Subscribe(ctx context.Context, in *SubscribeParams, opts ...grpc.CallOption) (Orderer_SubscribeClient, error)
+	GetPublicKey(ctx context.Context, in *GetPublicKeyParams, opts ...grpc.CallOption) (*PublicKey, error)
+	GetLeader(ctx context.Context, in *GetLeaderParams, opts ...grpc.CallOption) (*Leader, error)
+}
+
+type ordererClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOrdererClient(cc *grpc.ClientConn) OrdererClient {
+	return &ordererClient{cc}
+}
+
+func (c *ordererClient) CreateLedger(ctx context.Context, in *CreateLedgerParams, opts ...grpc.CallOption) (*Ledger, error) {
+	out := new(Ledger)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/CreateLedger", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetLedger(ctx context.Context, in *GetLedgerParams, opts ...grpc.CallOption) (*Ledger, error) {
+	out := new(Ledger)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetLedger", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) SetLedgerRetention(ctx context.Context, in *SetLedgerRetentionParams, opts ...grpc.CallOption) (*Ledger, error) {
+	out := new(Ledger)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/SetLedgerRetention", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) Put(ctx context.Context, in *PutTransactionParams, opts ...grpc.CallOption) (*PutResult, error) {
+	out := new(PutResult)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/Put", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) PutMulti(ctx context.Context, in *PutMultiParams, opts ...grpc.CallOption) (*PutMultiResult, error) {
+	out := new(PutMultiResult)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/PutMulti", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) Get(ctx context.Context, in *GetParams, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/Get", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetBlockByID(ctx context.Context, in *GetBlockParams, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetBlockByID", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetBlockByNumber(ctx context.Context, in *GetBlockByNumberParams, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetBlockByNumber", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetChainHeight(ctx context.Context, in *GetChainHeightParams, opts ...grpc.CallOption) (*ChainHeight, error) {
+	out := new(ChainHeight)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetChainHeight", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) ListBlocks(ctx context.Context, in *ListBlocksParams, opts ...grpc.CallOption) (*Blocks, error) {
+	out := new(Blocks)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/ListBlocks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetRange(ctx context.Context, in *GetRangeParams, opts ...grpc.CallOption) (*Transactions, error) {
+	out := new(Transactions)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetRange", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetHistory(ctx context.Context, in *GetHistoryParams, opts ...grpc.CallOption) (*Transactions, error) {
+	out := new(Transactions)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) Query(ctx context.Context, in *QueryParams, opts ...grpc.CallOption) (*Transactions, error) {
+	out := new(Transactions)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/Query", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetTxProof(ctx context.Context, in *GetTxProofParams, opts ...grpc.CallOption) (*TxProof, error) {
+	out := new(TxProof)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetTxProof", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) VerifyChain(ctx context.Context, in *VerifyChainParams, opts ...grpc.CallOption) (Orderer_VerifyChainClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Orderer_serviceDesc.Streams[0], c.cc, "/proto_orderer.Orderer/VerifyChain", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordererVerifyChainClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Orderer_VerifyChainClient interface {
+	Recv() (*ChainReport, error)
+	grpc.ClientStream
+}
+
+type ordererVerifyChainClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordererVerifyChainClient) Recv() (*ChainReport, error) {
+	m := new(ChainReport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ordererClient) Export(ctx context.Context, in *ExportParams, opts ...grpc.CallOption) (Orderer_ExportClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Orderer_serviceDesc.Streams[1], c.cc, "/proto_orderer.Orderer/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordererExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Orderer_ExportClient interface {
+	Recv() (*ArchiveChunk, error)
+	grpc.ClientStream
 }
 
-type ordererClient struct {
-	cc *grpc.ClientConn
+type ordererExportClient struct {
+	grpc.ClientStream
 }
 
-func NewOrdererClient(cc *grpc.ClientConn) OrdererClient {
-	return &ordererClient{cc}
+func (x *ordererExportClient) Recv() (*ArchiveChunk, error) {
+	m := new(ArchiveChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func (c *ordererClient) CreateLedger(ctx context.Context, in *CreateLedgerParams, opts ...grpc.CallOption) (*Ledger, error) {
-	out := new(Ledger)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/CreateLedger", in, out, c.cc, opts...)
+func (c *ordererClient) Import(ctx context.Context, opts ...grpc.CallOption) (Orderer_ImportClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Orderer_serviceDesc.Streams[2], c.cc, "/proto_orderer.Orderer/Import", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &ordererImportClient{stream}
+	return x, nil
 }
 
-func (c *ordererClient) GetLedger(ctx context.Context, in *GetLedgerParams, opts ...grpc.CallOption) (*Ledger, error) {
-	out := new(Ledger)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetLedger", in, out, c.cc, opts...)
-	if err != nil {
+type Orderer_ImportClient interface {
+	Send(*ArchiveChunk) error
+	CloseAndRecv() (*ImportResult, error)
+	grpc.ClientStream
+}
+
+type ordererImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordererImportClient) Send(m *ArchiveChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ordererImportClient) CloseAndRecv() (*ImportResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	return out, nil
+	m := new(ImportResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func (c *ordererClient) Put(ctx context.Context, in *PutTransactionParams, opts ...grpc.CallOption) (*PutResult, error) {
-	out := new(PutResult)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/Put", in, out, c.cc, opts...)
+func (c *ordererClient) Subscribe(ctx context.Context, in *SubscribeParams, opts ...grpc.CallOption) (Orderer_SubscribeClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Orderer_serviceDesc.Streams[3], c.cc, "/proto_orderer.Orderer/Subscribe", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &ordererSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *ordererClient) Get(ctx context.Context, in *GetParams, opts ...grpc.CallOption) (*Transaction, error) {
-	out := new(Transaction)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/Get", in, out, c.cc, opts...)
-	if err != nil {
+type Orderer_SubscribeClient interface {
+	Recv() (*LedgerEvent, error)
+	grpc.ClientStream
+}
+
+type ordererSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordererSubscribeClient) Recv() (*LedgerEvent, error) {
+	m := new(LedgerEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *ordererClient) GetBlockByID(ctx context.Context, in *GetBlockParams, opts ...grpc.CallOption) (*Block, error) {
-	out := new(Block)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetBlockByID", in, out, c.cc, opts...)
+func (c *ordererClient) GetPublicKey(ctx context.Context, in *GetPublicKeyParams, opts ...grpc.CallOption) (*PublicKey, error) {
+	out := new(PublicKey)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetPublicKey", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *ordererClient) GetRange(ctx context.Context, in *GetRangeParams, opts ...grpc.CallOption) (*Transactions, error) {
-	out := new(Transactions)
-	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetRange", in, out, c.cc, opts...)
+func (c *ordererClient) GetLeader(ctx context.Context, in *GetLeaderParams, opts ...grpc.CallOption) (*Leader, error) {
+	out := new(Leader)
+	err := grpc.Invoke(ctx, "/proto_orderer.Orderer/GetLeader", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -681,10 +1921,24 @@ func (c *ordererClient) GetRange(ctx context.Context, in *GetRangeParams, opts .
 type OrdererServer interface {
 	CreateLedger(context.Context, *CreateLedgerParams) (*Ledger, error)
 	GetLedger(context.Context, *GetLedgerParams) (*Ledger, error)
+	SetLedgerRetention(context.Context, *SetLedgerRetentionParams) (*Ledger, error)
 	Put(context.Context, *PutTransactionParams) (*PutResult, error)
+	PutMulti(context.Context, *PutMultiParams) (*PutMultiResult, error)
 	Get(context.Context, *GetParams) (*Transaction, error)
 	GetBlockByID(context.Context, *GetBlockParams) (*Block, error)
+	GetBlockByNumber(context.Context, *GetBlockByNumberParams) (*Block, error)
+	GetChainHeight(context.Context, *GetChainHeightParams) (*ChainHeight, error)
+	ListBlocks(context.Context, *ListBlocksParams) (*Blocks, error)
 	GetRange(context.Context, *GetRangeParams) (*Transactions, error)
+	GetHistory(context.Context, *GetHistoryParams) (*Transactions, error)
+	Query(context.Context, *QueryParams) (*Transactions, error)
+	GetTxProof(context.Context, *GetTxProofParams) (*TxProof, error)
+	VerifyChain(*VerifyChainParams, Orderer_VerifyChainServer) error
+	Export(*ExportParams, Orderer_ExportServer) error
+	Import(Orderer_ImportServer) error
+	Subscribe(*SubscribeParams, Orderer_SubscribeServer) error
+	GetPublicKey(context.Context, *GetPublicKeyParams) (*PublicKey, error)
+	GetLeader(context.Context, *GetLeaderParams) (*Leader, error)
 }
 
 func RegisterOrdererServer(s *grpc.Server, srv OrdererServer) {
@@ -727,6 +1981,24 @@ func _Orderer_GetLedger_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orderer_SetLedgerRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLedgerRetentionParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).SetLedgerRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/SetLedgerRetention",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).SetLedgerRetention(ctx, req.(*SetLedgerRetentionParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Orderer_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PutTransactionParams)
 	if err := dec(in); err != nil {
@@ -745,6 +2017,24 @@ func _Orderer_Put_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orderer_PutMulti_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutMultiParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).PutMulti(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/PutMulti",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).PutMulti(ctx, req.(*PutMultiParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Orderer_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetParams)
 	if err := dec(in); err != nil {
@@ -781,6 +2071,60 @@ func _Orderer_GetBlockByID_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orderer_GetBlockByNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByNumberParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetBlockByNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetBlockByNumber",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetBlockByNumber(ctx, req.(*GetBlockByNumberParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_GetChainHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainHeightParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetChainHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetChainHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetChainHeight(ctx, req.(*GetChainHeightParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_ListBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlocksParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).ListBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/ListBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).ListBlocks(ctx, req.(*ListBlocksParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Orderer_GetRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRangeParams)
 	if err := dec(in); err != nil {
@@ -799,6 +2143,185 @@ func _Orderer_GetRange_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orderer_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetHistory(ctx, req.(*GetHistoryParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).Query(ctx, req.(*QueryParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_GetTxProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxProofParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetTxProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetTxProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetTxProof(ctx, req.(*GetTxProofParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_VerifyChain_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(VerifyChainParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdererServer).VerifyChain(m, &ordererVerifyChainServer{stream})
+}
+
+type Orderer_VerifyChainServer interface {
+	Send(*ChainReport) error
+	grpc.ServerStream
+}
+
+type ordererVerifyChainServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererVerifyChainServer) Send(m *ChainReport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Orderer_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdererServer).Export(m, &ordererExportServer{stream})
+}
+
+type Orderer_ExportServer interface {
+	Send(*ArchiveChunk) error
+	grpc.ServerStream
+}
+
+type ordererExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererExportServer) Send(m *ArchiveChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Orderer_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrdererServer).Import(&ordererImportServer{stream})
+}
+
+type Orderer_ImportServer interface {
+	SendAndClose(*ImportResult) error
+	Recv() (*ArchiveChunk, error)
+	grpc.ServerStream
+}
+
+type ordererImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererImportServer) SendAndClose(m *ImportResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ordererImportServer) Recv() (*ArchiveChunk, error) {
+	m := new(ArchiveChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Orderer_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeParams)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdererServer).Subscribe(m, &ordererSubscribeServer{stream})
+}
+
+type Orderer_SubscribeServer interface {
+	Send(*LedgerEvent) error
+	grpc.ServerStream
+}
+
+type ordererSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererSubscribeServer) Send(m *LedgerEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Orderer_GetPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeyParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetPublicKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetPublicKey(ctx, req.(*GetPublicKeyParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_GetLeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).GetLeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto_orderer.Orderer/GetLeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).GetLeader(ctx, req.(*GetLeaderParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Orderer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto_orderer.Orderer",
 	HandlerType: (*OrdererServer)(nil),
@@ -811,10 +2334,18 @@ var _Orderer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLedger",
 			Handler:    _Orderer_GetLedger_Handler,
 		},
+		{
+			MethodName: "SetLedgerRetention",
+			Handler:    _Orderer_SetLedgerRetention_Handler,
+		},
 		{
 			MethodName: "Put",
 			Handler:    _Orderer_Put_Handler,
 		},
+		{
+			MethodName: "PutMulti",
+			Handler:    _Orderer_PutMulti_Handler,
+		},
 		{
 			MethodName: "Get",
 			Handler:    _Orderer_Get_Handler,
@@ -823,65 +2354,184 @@ var _Orderer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetBlockByID",
 			Handler:    _Orderer_GetBlockByID_Handler,
 		},
+		{
+			MethodName: "GetBlockByNumber",
+			Handler:    _Orderer_GetBlockByNumber_Handler,
+		},
+		{
+			MethodName: "GetChainHeight",
+			Handler:    _Orderer_GetChainHeight_Handler,
+		},
+		{
+			MethodName: "ListBlocks",
+			Handler:    _Orderer_ListBlocks_Handler,
+		},
 		{
 			MethodName: "GetRange",
 			Handler:    _Orderer_GetRange_Handler,
 		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _Orderer_GetHistory_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Orderer_Query_Handler,
+		},
+		{
+			MethodName: "GetTxProof",
+			Handler:    _Orderer_GetTxProof_Handler,
+		},
+		{
+			MethodName: "GetPublicKey",
+			Handler:    _Orderer_GetPublicKey_Handler,
+		},
+		{
+			MethodName: "GetLeader",
+			Handler:    _Orderer_GetLeader_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "VerifyChain",
+			Handler:       _Orderer_VerifyChain_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Export",
+			Handler:       _Orderer_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _Orderer_Import_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Orderer_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "server.proto",
 }
 
 func init() { proto.RegisterFile("server.proto", fileDescriptorServer) }
 
 var fileDescriptorServer = []byte{
-	// 762 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4f, 0x6b, 0xdb, 0x4a,
-	0x10, 0x47, 0x96, 0xe5, 0x58, 0x63, 0x25, 0xef, 0xb1, 0xe4, 0x85, 0xc5, 0xef, 0xbd, 0xe0, 0xa7,
-	0x57, 0x8a, 0x29, 0x34, 0x87, 0xf4, 0x52, 0x28, 0x14, 0x92, 0x98, 0x3a, 0x26, 0x25, 0x35, 0xc2,
-	0xf7, 0x22, 0x4b, 0x93, 0x44, 0x44, 0x96, 0xdc, 0xd5, 0xca, 0xd4, 0x9f, 0xa8, 0xf7, 0x9e, 0x7b,
-	0xec, 0xa5, 0x9f, 0xa0, 0x5f, 0xa7, 0xec, 0x6a, 0xf5, 0xd7, 0x71, 0x9a, 0x92, 0x9e, 0xb4, 0x33,
-	0x9a, 0xfd, 0xed, 0xcc, 0xfc, 0x7e, 0xb3, 0x0b, 0x56, 0x82, 0x6c, 0x85, 0xec, 0x68, 0xc9, 0x62,
-	0x1e, 0x93, 0x5d, 0xf9, 0x79, 0x1f, 0x33, 0x1f, 0x19, 0x32, 0x7b, 0x05, 0xe4, 0x8c, 0xa1, 0xcb,
-	0xf1, 0x2d, 0xfa, 0xd7, 0xc8, 0xa6, 0x2e, 0x73, 0x17, 0x09, 0xe9, 0x43, 0xd7, 0x8b, 0xbd, 0x38,
-	0x8e, 0x26, 0x23, 0xaa, 0x0d, 0xb4, 0xa1, 0xe9, 0x14, 0x36, 0x21, 0xd0, 0x8e, 0xdc, 0x05, 0xd2,
-	0x96, 0xf4, 0xcb, 0x35, 0x39, 0x80, 0xce, 0x32, 0x9d, 0x87, 0x81, 0x47, 0xf5, 0x81, 0x36, 0xec,
-	0x3a, 0xca, 0x22, 0x14, 0x76, 0xbc, 0x1b, 0x37, 0x88, 0xd0, 0xa7, 0x6d, 0xf9, 0x23, 0x37, 0xed,
-	0xcf, 0x1a, 0xec, 0x4f, 0x53, 0x3e, 0x63, 0x6e, 0x94, 0xb8, 0x1e, 0x0f, 0xe2, 0xe8, 0x01, 0x47,
-	0x1f, 0x02, 0x84, 0x32, 0xcd, 0xcb, 0x32, 0x81, 0x8a, 0x87, 0xbc, 0x06, 0x8b, 0x97, 0x80, 0x09,
-	0xd5, 0x07, 0xfa, 0xb0, 0x77, 0xdc, 0x3f, 0xaa, 0x95, 0x7c, 0x54, 0x39, 0xd3, 0xa9, 0xc5, 0x0b,
-	0x7c, 0x86, 0xab, 0x20, 0x09, 0xe4, 0xe9, 0xed, 0x0c, 0xbf, 0xf4, 0xd8, 0x27, 0xf0, 0xc7, 0x18,
-	0xf9, 0x63, 0x3a, 0x65, 0xbb, 0x60, 0x8e, 0x91, 0x3f, 0x60, 0xf3, 0x01, 0x74, 0xb2, 0xca, 0xd4,
-	0x76, 0x65, 0x91, 0x3d, 0x68, 0x05, 0xbe, 0x6c, 0xb3, 0xe9, 0xb4, 0x02, 0x9f, 0xfc, 0x09, 0xfa,
-	0x2d, 0xae, 0x55, 0xb2, 0x62, 0x69, 0xcf, 0x60, 0x6f, 0x8c, 0xfc, 0x34, 0x8c, 0xbd, 0xdb, 0xdf,
-	0x77, 0x8e, 0xfd, 0x55, 0x93, 0xb0, 0x8e, 0x1b, 0x5d, 0xe3, 0x23, 0x60, 0xfb, 0xd0, 0x4d, 0xb8,
-	0xcb, 0xf8, 0x05, 0xae, 0x15, 0x78, 0x61, 0x8b, 0x3d, 0x18, 0xf9, 0x17, 0x45, 0x35, 0xca, 0x22,
-	0xff, 0x80, 0x19, 0x44, 0x5e, 0x98, 0x26, 0xc1, 0x0a, 0xa9, 0x21, 0x75, 0x54, 0x3a, 0xc8, 0x3e,
-	0x18, 0x61, 0xb0, 0x08, 0x38, 0xed, 0x0c, 0xb4, 0xa1, 0xe1, 0x64, 0x86, 0xc0, 0x8a, 0xaf, 0xae,
-	0x12, 0xe4, 0x74, 0x47, 0xba, 0x95, 0x65, 0x7f, 0xd1, 0xa0, 0x93, 0x11, 0x28, 0x42, 0xa2, 0x74,
-	0x31, 0x47, 0x26, 0x93, 0xd7, 0x1d, 0x65, 0x09, 0xda, 0x6e, 0xdc, 0xe4, 0x26, 0xa7, 0x4d, 0xac,
-	0x0b, 0x2a, 0xf5, 0x8a, 0xe8, 0x6d, 0xb0, 0xc4, 0x77, 0x12, 0x71, 0x64, 0x91, 0x1b, 0xaa, 0xa4,
-	0x6b, 0xbe, 0xca, 0x60, 0x18, 0xdb, 0x06, 0xa3, 0x53, 0x1b, 0x0c, 0x51, 0xac, 0x27, 0x07, 0xd2,
-	0x3f, 0xc9, 0x72, 0xd7, 0x9d, 0xd2, 0x61, 0x7f, 0x6f, 0x41, 0xaf, 0xa2, 0xdf, 0xad, 0x35, 0x6c,
-	0x6b, 0xff, 0x53, 0xd8, 0xcb, 0x56, 0x45, 0xd6, 0x59, 0x45, 0x0d, 0xaf, 0x62, 0xbf, 0xdd, 0x54,
-	0x99, 0x51, 0xa8, 0x8c, 0x0c, 0xa0, 0x77, 0x8b, 0xeb, 0x02, 0xa6, 0x23, 0xff, 0x54, 0x5d, 0x82,
-	0x98, 0x95, 0x1b, 0xa6, 0x28, 0xab, 0x30, 0x9d, 0xcc, 0x28, 0xba, 0xdb, 0xad, 0x74, 0xb7, 0x56,
-	0xb3, 0xd9, 0xa8, 0x59, 0xf4, 0x6a, 0x2e, 0xc4, 0x3c, 0xf1, 0x29, 0xc8, 0x4d, 0xb9, 0x49, 0x9e,
-	0x81, 0x21, 0x97, 0xb4, 0x37, 0xd0, 0x86, 0xbd, 0xe3, 0xfd, 0xc6, 0xa0, 0xcb, 0x11, 0x70, 0xb2,
-	0x90, 0xea, 0x6c, 0xcf, 0x62, 0x6a, 0xd5, 0x67, 0x7b, 0x16, 0xdb, 0x97, 0x60, 0xcd, 0xaa, 0x77,
-	0x41, 0xf3, 0x2e, 0xd1, 0x7e, 0xed, 0x2e, 0xb1, 0x3f, 0x80, 0x39, 0x4d, 0xb9, 0x83, 0x49, 0x1a,
-	0x72, 0xf2, 0x12, 0x80, 0x7f, 0x74, 0xd0, 0xc3, 0x60, 0xc9, 0x73, 0x28, 0xda, 0x84, 0xca, 0x03,
-	0x9c, 0x4a, 0x6c, 0x59, 0x62, 0xeb, 0xa7, 0x25, 0xda, 0xcf, 0xc1, 0x2c, 0x40, 0x04, 0x83, 0xc5,
-	0x58, 0xb6, 0x26, 0x23, 0xc1, 0x20, 0xb2, 0x5c, 0x0e, 0x62, 0x69, 0x7f, 0xd3, 0xc0, 0x90, 0xfb,
-	0x15, 0xdb, 0x5a, 0xc1, 0x76, 0xa9, 0xaa, 0x56, 0x4d, 0x55, 0x82, 0x27, 0x21, 0xd3, 0xcb, 0x72,
-	0x14, 0x4a, 0x07, 0x79, 0x02, 0xbb, 0x4b, 0x86, 0x2b, 0x09, 0x79, 0x2e, 0x28, 0xce, 0xe4, 0x53,
-	0x77, 0x16, 0xfc, 0x1b, 0x15, 0xfe, 0xed, 0x46, 0xaf, 0x85, 0x98, 0xac, 0xc6, 0xdd, 0x7c, 0xef,
-	0x5c, 0x1c, 0x7f, 0xd2, 0x61, 0xe7, 0x5d, 0xd6, 0x13, 0x72, 0x0e, 0x56, 0xf5, 0x49, 0x23, 0xff,
-	0x35, 0x7a, 0xb6, 0xf9, 0xde, 0xf5, 0xff, 0x6a, 0x84, 0xa8, 0x9d, 0xa7, 0xf2, 0xb2, 0x56, 0xc6,
-	0x61, 0x23, 0xa6, 0xf1, 0x12, 0x6c, 0xc3, 0x18, 0x81, 0x3e, 0x4d, 0x39, 0xf9, 0xbf, 0xf1, 0xf7,
-	0xae, 0xb7, 0xaf, 0x4f, 0x37, 0x83, 0x94, 0x80, 0x5e, 0x81, 0x3e, 0x46, 0x4e, 0xe8, 0x66, 0x0e,
-	0x6a, 0xeb, 0x3d, 0xc2, 0x24, 0x67, 0x60, 0xe5, 0x0f, 0xc2, 0xe9, 0x7a, 0x32, 0x22, 0xff, 0x6e,
-	0xa2, 0x54, 0x5e, 0x8b, 0xfe, 0x9d, 0x1a, 0x23, 0x6f, 0xa0, 0x9b, 0x5f, 0xff, 0x77, 0x01, 0x54,
-	0xde, 0x85, 0xfe, 0xdf, 0xdb, 0x73, 0x49, 0xe6, 0x1d, 0xf9, 0xef, 0xc5, 0x8f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x32, 0x2a, 0x00, 0x71, 0x96, 0x08, 0x00, 0x00,
+	// 1824 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x18, 0x5d, 0x6f, 0x1b, 0x4b,
+	0x55, 0xeb, 0x8d, 0x3f, 0xf6, 0xd8, 0x4d, 0xcb, 0x28, 0x44, 0x2b, 0x13, 0x42, 0x3a, 0xa5, 0x25,
+	0xaa, 0x4a, 0xa8, 0x5a, 0x84, 0x90, 0x2a, 0x21, 0xda, 0x38, 0x4d, 0xdc, 0xb4, 0x69, 0xba, 0xb5,
+	0x78, 0x40, 0x42, 0x68, 0xe3, 0x9d, 0xc4, 0xab, 0xd8, 0xbb, 0x66, 0x76, 0x6c, 0xd9, 0x82, 0x37,
+	0x04, 0x12, 0xbc, 0xf1, 0x03, 0x78, 0x85, 0x07, 0x78, 0x81, 0x87, 0xfb, 0x76, 0xff, 0xdb, 0xd5,
+	0x7c, 0xec, 0xce, 0xec, 0x87, 0x93, 0x54, 0xbe, 0xf7, 0xc9, 0x73, 0x8e, 0xcf, 0x9e, 0x39, 0x73,
+	0xbe, 0xcf, 0x81, 0x4e, 0x42, 0xe8, 0x9c, 0xd0, 0x83, 0x29, 0x8d, 0x59, 0x8c, 0xee, 0x89, 0x9f,
+	0xdf, 0xc7, 0x34, 0x20, 0x94, 0x50, 0xfc, 0x2f, 0x0b, 0xd0, 0x21, 0x25, 0x3e, 0x23, 0xef, 0x49,
+	0x70, 0x45, 0xe8, 0xb9, 0x4f, 0xfd, 0x49, 0x82, 0xba, 0xd0, 0x1a, 0xc6, 0xc3, 0x38, 0x8e, 0xfa,
+	0x3d, 0xd7, 0xda, 0xb3, 0xf6, 0x1d, 0x2f, 0x83, 0x11, 0x82, 0x8d, 0xc8, 0x9f, 0x10, 0xb7, 0x26,
+	0xf0, 0xe2, 0x8c, 0xb6, 0xa1, 0x31, 0x9d, 0x5d, 0x8c, 0xc3, 0xa1, 0x6b, 0xef, 0x59, 0xfb, 0x2d,
+	0x4f, 0x41, 0xc8, 0x85, 0xe6, 0x70, 0xe4, 0x87, 0x11, 0x09, 0xdc, 0x0d, 0xf1, 0x47, 0x0a, 0xa2,
+	0x03, 0x68, 0x86, 0x51, 0x40, 0x16, 0x24, 0x71, 0xeb, 0x7b, 0xf6, 0x7e, 0xfb, 0xc5, 0xd6, 0x41,
+	0x4e, 0xb2, 0x83, 0x3e, 0xff, 0xd7, 0x4b, 0x89, 0xf0, 0xcf, 0xa0, 0x2e, 0x30, 0xd9, 0xf5, 0x96,
+	0x71, 0x3d, 0x82, 0x8d, 0xa9, 0xcf, 0x46, 0xa9, 0x48, 0xfc, 0x8c, 0xff, 0x6f, 0xc1, 0xd6, 0xf9,
+	0x8c, 0x0d, 0xa8, 0x1f, 0x25, 0xfe, 0x90, 0x85, 0x71, 0x74, 0x87, 0xb7, 0xed, 0x02, 0x8c, 0x85,
+	0x1e, 0xce, 0xf4, 0x0b, 0x0d, 0x0c, 0xfa, 0x15, 0x74, 0x98, 0x66, 0x98, 0xb8, 0xb6, 0x10, 0xbd,
+	0x5b, 0x10, 0xdd, 0xb8, 0xd3, 0xcb, 0xd1, 0x73, 0xfe, 0x94, 0xcc, 0xc3, 0x24, 0x14, 0xb7, 0x6f,
+	0x48, 0xfe, 0x1a, 0x83, 0xc7, 0x80, 0xa4, 0x1d, 0x06, 0xe6, 0x57, 0xdb, 0xd0, 0x90, 0x32, 0x28,
+	0x79, 0x15, 0x54, 0x92, 0xa6, 0xf6, 0x65, 0xd2, 0xe0, 0x10, 0x36, 0xcf, 0x67, 0xec, 0xc3, 0x6c,
+	0xcc, 0xc2, 0x3b, 0xe8, 0xe6, 0x15, 0x34, 0xe5, 0xbd, 0xe9, 0x45, 0x0f, 0x0b, 0x17, 0x95, 0x25,
+	0xf7, 0xd2, 0x2f, 0x70, 0x4f, 0x5f, 0xe5, 0x91, 0x64, 0x36, 0x66, 0xe8, 0x05, 0x34, 0xa9, 0x38,
+	0x25, 0xae, 0x25, 0xd8, 0xb9, 0x05, 0x76, 0xe7, 0x33, 0x26, 0x49, 0xbd, 0x94, 0x10, 0xbf, 0x86,
+	0xfb, 0xc7, 0x84, 0xad, 0xe3, 0xa9, 0xf8, 0xef, 0x16, 0xb8, 0x9f, 0x53, 0x1e, 0x1e, 0x61, 0x24,
+	0xba, 0xa3, 0x6b, 0x54, 0xb9, 0xfd, 0x8f, 0xe1, 0xde, 0x35, 0x21, 0x53, 0x4f, 0x19, 0x30, 0x11,
+	0xde, 0x6f, 0x7b, 0x79, 0x24, 0x0f, 0x02, 0x8e, 0x78, 0x1b, 0x53, 0x61, 0x71, 0xdb, 0x4b, 0x41,
+	0xfc, 0x37, 0x0b, 0x9c, 0x63, 0xc2, 0xee, 0x70, 0xbb, 0x76, 0x81, 0x5a, 0xce, 0x05, 0x36, 0xa1,
+	0x16, 0x06, 0xe2, 0x5a, 0xc7, 0xab, 0x85, 0x01, 0x7a, 0x00, 0xf6, 0x35, 0x59, 0x2a, 0xcf, 0xe2,
+	0x47, 0xf4, 0x04, 0x36, 0xc3, 0x68, 0x38, 0x9e, 0x05, 0xa4, 0x47, 0xc6, 0x84, 0x91, 0xc0, 0xad,
+	0x8b, 0x48, 0x2c, 0x60, 0xf1, 0x00, 0x36, 0x8f, 0x09, 0x7b, 0x33, 0x8e, 0x87, 0xd7, 0xdf, 0x9e,
+	0x3c, 0x38, 0x80, 0xed, 0x94, 0xeb, 0x9b, 0xe5, 0xd9, 0x6c, 0x72, 0x41, 0xe8, 0x1a, 0xdc, 0xb7,
+	0xa1, 0x11, 0x09, 0x1e, 0x4a, 0xd1, 0x0a, 0xc2, 0xef, 0x60, 0xeb, 0x98, 0xb0, 0x43, 0x9e, 0x5a,
+	0x4e, 0x48, 0x78, 0x35, 0x5a, 0x43, 0xa3, 0xf8, 0x31, 0xb4, 0x0d, 0x46, 0x9c, 0x6c, 0x24, 0x4e,
+	0x82, 0x81, 0xed, 0x29, 0x08, 0xff, 0x09, 0x1e, 0xbc, 0x0f, 0x13, 0xf9, 0xb2, 0x64, 0x8d, 0x27,
+	0xed, 0x02, 0x5c, 0xd2, 0x78, 0x72, 0x66, 0x3e, 0xcb, 0xc0, 0xa0, 0x2d, 0xa8, 0x8f, 0xc3, 0x49,
+	0xc8, 0x84, 0x49, 0xeb, 0x9e, 0x04, 0xf0, 0x2f, 0xa0, 0x21, 0x6f, 0x46, 0xcf, 0xa0, 0x71, 0x21,
+	0x4e, 0xae, 0x55, 0x99, 0x46, 0x05, 0x99, 0xa7, 0x68, 0xf0, 0x57, 0x35, 0x61, 0x65, 0xcf, 0x8f,
+	0xae, 0xc8, 0x1a, 0x42, 0x77, 0xa1, 0x95, 0x30, 0x9f, 0xb2, 0x53, 0xb2, 0x54, 0xb6, 0xce, 0x60,
+	0xfe, 0x0d, 0x89, 0x82, 0xd3, 0xcc, 0x09, 0x15, 0x84, 0x76, 0xc0, 0x11, 0x1e, 0x97, 0x84, 0x73,
+	0xa2, 0x5c, 0x50, 0x23, 0xf4, 0x33, 0x1b, 0xc6, 0x33, 0x39, 0xaf, 0xf8, 0xf2, 0x32, 0x21, 0xcc,
+	0x6d, 0x0a, 0xb4, 0x82, 0x2a, 0x7c, 0xba, 0x55, 0xe5, 0xd3, 0xa2, 0x2c, 0x51, 0x72, 0x19, 0x2e,
+	0x5c, 0x47, 0xca, 0x22, 0x21, 0x1e, 0x91, 0x94, 0xcc, 0x09, 0x4d, 0x88, 0x0b, 0xb2, 0x2c, 0x29,
+	0x90, 0x7f, 0x31, 0x9c, 0xd1, 0x24, 0xa6, 0x6e, 0x5b, 0x7e, 0x21, 0x21, 0xfc, 0x57, 0x0b, 0x1e,
+	0x1c, 0x13, 0x76, 0x12, 0x26, 0x2c, 0xa6, 0xcb, 0x35, 0x54, 0xa7, 0x02, 0xd4, 0xd6, 0x01, 0x5a,
+	0x69, 0x61, 0x43, 0x90, 0xba, 0xf4, 0x3b, 0x25, 0xc8, 0x7f, 0x2c, 0x68, 0x7f, 0x9a, 0x91, 0xb5,
+	0x64, 0xd8, 0x82, 0xba, 0x28, 0xab, 0x4a, 0x0a, 0x09, 0xf0, 0xd0, 0x8d, 0xa7, 0xca, 0x68, 0xb5,
+	0x78, 0xca, 0xa9, 0xe6, 0xfe, 0x78, 0x26, 0x8d, 0xe5, 0x78, 0x12, 0x30, 0xe4, 0x6a, 0x98, 0x0a,
+	0xd2, 0xaf, 0x68, 0x9a, 0x7e, 0xfa, 0x5b, 0xa1, 0xb5, 0xc1, 0xe2, 0x9c, 0xc6, 0xf1, 0xe5, 0x1a,
+	0x12, 0x23, 0xd8, 0x60, 0x8b, 0x7e, 0x4f, 0x09, 0x2c, 0xce, 0xf8, 0x2f, 0x16, 0x7c, 0xef, 0x37,
+	0x84, 0x86, 0x97, 0x4b, 0x11, 0xaf, 0x6b, 0x70, 0xdf, 0x01, 0x47, 0xf6, 0x2b, 0xda, 0x9f, 0x35,
+	0x82, 0x47, 0x68, 0x12, 0x5e, 0x45, 0x24, 0x78, 0x4b, 0xe3, 0x89, 0xca, 0xe0, 0x06, 0x06, 0xff,
+	0xb7, 0x06, 0x8d, 0xf7, 0xc5, 0xfc, 0x64, 0x99, 0xf9, 0x89, 0x8b, 0x3f, 0xf2, 0x93, 0xac, 0x3f,
+	0xe1, 0xe7, 0xac, 0x9e, 0xd8, 0x46, 0x3d, 0xc1, 0xd0, 0xe1, 0xbf, 0xfd, 0x88, 0x11, 0x1a, 0xf9,
+	0x63, 0x65, 0x8c, 0x1c, 0xce, 0x68, 0xb5, 0xea, 0xab, 0x5a, 0xad, 0x46, 0xbe, 0xd5, 0xda, 0x01,
+	0x67, 0x28, 0x5a, 0xbc, 0xe0, 0xb5, 0x34, 0x8f, 0xed, 0x69, 0x44, 0xb9, 0x86, 0xb5, 0x6e, 0xa9,
+	0x61, 0x4e, 0xae, 0x86, 0x99, 0x8d, 0x1c, 0xdc, 0xa5, 0x91, 0xfb, 0xb7, 0x0d, 0x6d, 0xa3, 0x47,
+	0x58, 0xa9, 0xb3, 0x55, 0xc6, 0x7a, 0x02, 0x9b, 0xf2, 0x94, 0x69, 0x49, 0x6a, 0xb0, 0x80, 0x55,
+	0x95, 0x68, 0xa3, 0x58, 0x19, 0xeb, 0x3a, 0xf0, 0xf6, 0xa0, 0x7d, 0x4d, 0x96, 0x19, 0x1b, 0xe9,
+	0xcf, 0x26, 0x4a, 0x87, 0x40, 0xd3, 0x0c, 0x81, 0xd4, 0x9a, 0x2d, 0xc3, 0x9a, 0x39, 0x1d, 0x3b,
+	0x45, 0x1d, 0xbb, 0xd0, 0x14, 0x09, 0xb8, 0x1f, 0x88, 0x7c, 0xe3, 0x78, 0x29, 0x88, 0x9e, 0x42,
+	0x5d, 0x1c, 0x45, 0xba, 0x59, 0x95, 0xbd, 0x25, 0x89, 0xd9, 0x3c, 0x0e, 0x62, 0xb7, 0x93, 0x6f,
+	0x1e, 0x07, 0x31, 0xbf, 0x25, 0x50, 0xe9, 0xf0, 0x9e, 0xf4, 0x00, 0x05, 0xa2, 0x9f, 0xf3, 0x7c,
+	0xe7, 0x07, 0x9f, 0x09, 0x73, 0x37, 0x2b, 0x7b, 0xc4, 0x53, 0xb2, 0x4c, 0x6d, 0xed, 0xa5, 0xa4,
+	0xf8, 0x15, 0xb4, 0x0d, 0x7c, 0xaa, 0x40, 0x4b, 0x2b, 0xb0, 0x0b, 0xad, 0xf4, 0x7a, 0x65, 0xa4,
+	0x0c, 0xc6, 0x11, 0x74, 0x72, 0x3d, 0x6c, 0xb1, 0x57, 0xb5, 0xbe, 0xbc, 0x73, 0x8e, 0xc8, 0x82,
+	0x1d, 0xca, 0xdc, 0x23, 0x6f, 0x33, 0x30, 0xf8, 0x0f, 0xe0, 0x64, 0x0d, 0x23, 0xfa, 0x25, 0x00,
+	0x5b, 0x78, 0x64, 0x48, 0xc2, 0xe9, 0xca, 0xf6, 0x72, 0x90, 0x12, 0x78, 0x06, 0xad, 0xb6, 0x47,
+	0xed, 0x56, 0x7b, 0xe0, 0x9f, 0x82, 0x93, 0x31, 0xe1, 0xee, 0x96, 0x65, 0x9c, 0x5a, 0xbf, 0xc7,
+	0xb5, 0x45, 0x68, 0x2a, 0x28, 0x3f, 0xe2, 0x7f, 0xd6, 0xa0, 0x2e, 0xbe, 0x57, 0xae, 0x69, 0x65,
+	0xae, 0xa9, 0x43, 0xa0, 0x96, 0x0b, 0x01, 0xee, 0x54, 0x3c, 0x86, 0xcf, 0x74, 0x9e, 0xd0, 0x08,
+	0x1e, 0xb8, 0x53, 0x4a, 0xe6, 0x82, 0xe5, 0x09, 0xf7, 0x47, 0xe9, 0xeb, 0x79, 0x64, 0xe6, 0xac,
+	0x75, 0xc3, 0x59, 0x71, 0xc1, 0x16, 0xdc, 0xf3, 0x3b, 0x05, 0x7d, 0xdf, 0x9c, 0x34, 0x76, 0x01,
+	0x26, 0x84, 0x5e, 0x8f, 0x89, 0x17, 0xc7, 0x4c, 0x05, 0x82, 0x81, 0xe1, 0x5f, 0xf3, 0x0c, 0xe9,
+	0xb3, 0x19, 0x25, 0xaa, 0xf6, 0x6a, 0x04, 0x0f, 0x2b, 0x1e, 0x65, 0x69, 0x30, 0x48, 0x00, 0xbf,
+	0x04, 0x47, 0x94, 0x89, 0xb3, 0x38, 0xd0, 0x31, 0x66, 0xe5, 0x33, 0xe6, 0x98, 0x5c, 0x32, 0xa1,
+	0xa4, 0x96, 0x27, 0xce, 0xf8, 0xcf, 0x35, 0x68, 0xaa, 0xf2, 0xa2, 0x8a, 0x44, 0xaa, 0x58, 0x71,
+	0xe6, 0xaa, 0x65, 0x8b, 0x13, 0x9d, 0x7b, 0x15, 0x64, 0x46, 0xa4, 0x9d, 0x8f, 0xc8, 0x3d, 0x68,
+	0x8b, 0xa3, 0xea, 0xc8, 0x64, 0xbe, 0x37, 0x51, 0xfc, 0x71, 0x17, 0x99, 0xd2, 0xa5, 0x5e, 0x35,
+	0xa2, 0x6c, 0x96, 0x46, 0x95, 0x59, 0xf2, 0x0a, 0x6c, 0x96, 0x14, 0xf8, 0x4c, 0x4d, 0xb4, 0xad,
+	0xea, 0xd1, 0x28, 0xd5, 0x93, 0x9a, 0x75, 0xff, 0x67, 0xa9, 0xa6, 0xd5, 0x23, 0xd3, 0x98, 0x32,
+	0xa1, 0x89, 0xe5, 0x34, 0x9b, 0x91, 0xf9, 0xd9, 0x7c, 0x71, 0xed, 0xc6, 0x17, 0xdb, 0xe5, 0x17,
+	0xa7, 0x9a, 0xdd, 0x30, 0x34, 0xeb, 0x42, 0x73, 0x42, 0x92, 0xc4, 0xbf, 0x4a, 0x1b, 0x84, 0x14,
+	0xe4, 0x1a, 0x10, 0x1f, 0x27, 0x87, 0x23, 0x32, 0xbc, 0x56, 0xf5, 0xc8, 0xf6, 0xf2, 0x48, 0xfc,
+	0x14, 0x3a, 0x47, 0x0b, 0x2e, 0xed, 0xed, 0x85, 0x1b, 0x63, 0xe8, 0xbc, 0xa6, 0xc3, 0x51, 0x38,
+	0x27, 0x87, 0xa3, 0x59, 0x74, 0xcd, 0xe5, 0x09, 0x7c, 0xe6, 0x0b, 0xba, 0x8e, 0x27, 0xce, 0x38,
+	0x80, 0x4e, 0x7f, 0xc2, 0xf9, 0xa9, 0x1c, 0xe0, 0xea, 0x71, 0x55, 0x16, 0x96, 0x14, 0xe4, 0x3e,
+	0xa1, 0x5a, 0x66, 0x15, 0x6e, 0x12, 0x2a, 0x85, 0x85, 0x54, 0x44, 0x0e, 0x87, 0xff, 0x08, 0xf7,
+	0x3f, 0xcf, 0x2e, 0x92, 0x21, 0x0d, 0x2f, 0xc8, 0x7a, 0x83, 0x8c, 0x6a, 0x4c, 0xed, 0x5c, 0x63,
+	0xba, 0x03, 0x0e, 0xef, 0xfd, 0x85, 0x9f, 0x28, 0xd7, 0xd3, 0x08, 0xfc, 0x0f, 0x0b, 0xda, 0xb2,
+	0xd3, 0x38, 0x9a, 0x93, 0x88, 0x7d, 0x57, 0x7b, 0x01, 0x9d, 0x04, 0xed, 0xdb, 0x93, 0xe0, 0x16,
+	0x20, 0x3e, 0xc1, 0xa6, 0xdd, 0x92, 0xd4, 0x09, 0xfe, 0x1d, 0x38, 0x19, 0x4a, 0x87, 0xbb, 0x65,
+	0x84, 0x3b, 0x7f, 0xaa, 0x3f, 0xbe, 0x8a, 0x69, 0xc8, 0x46, 0x13, 0xa5, 0x1d, 0x8d, 0xb8, 0xb9,
+	0x25, 0xc3, 0x47, 0x6a, 0x0f, 0xe0, 0x07, 0xeb, 0x8c, 0x93, 0x78, 0x07, 0x1a, 0x92, 0x07, 0x77,
+	0x28, 0x3f, 0x08, 0x52, 0x3d, 0x8a, 0xf3, 0x8b, 0xaf, 0xdb, 0xd0, 0xfc, 0x28, 0x9f, 0x8c, 0x4e,
+	0xa0, 0x63, 0x6e, 0xc9, 0x50, 0x71, 0xf5, 0x51, 0x5e, 0xa1, 0x75, 0xbf, 0x5f, 0xb9, 0x1d, 0x41,
+	0x6f, 0xc4, 0xc4, 0xaf, 0x80, 0xdd, 0x02, 0x4d, 0x61, 0xb9, 0xb1, 0x8a, 0xc7, 0x00, 0x50, 0x79,
+	0x85, 0x81, 0x7e, 0x52, 0x20, 0x5e, 0xb5, 0xe5, 0x58, 0xc5, 0xb5, 0x07, 0xf6, 0xf9, 0x8c, 0xa1,
+	0x47, 0xe5, 0x35, 0x4c, 0x69, 0x87, 0xd6, 0x5d, 0xb9, 0xab, 0x41, 0x27, 0xd0, 0x4a, 0x17, 0x3d,
+	0xe8, 0x87, 0x65, 0x2a, 0x63, 0xd9, 0xd4, 0x5d, 0xf5, 0xb7, 0xe2, 0xf4, 0x0a, 0xec, 0x63, 0xc2,
+	0x90, 0x5b, 0xd6, 0x91, 0xfa, 0xfe, 0x06, 0x87, 0x46, 0x87, 0xd0, 0xd1, 0x7b, 0x87, 0x7e, 0xaf,
+	0x24, 0x4a, 0x7e, 0xd5, 0xd1, 0xad, 0x74, 0x71, 0xf4, 0x51, 0x4c, 0x2f, 0xb9, 0xe5, 0x05, 0x7a,
+	0xbc, 0x82, 0x51, 0x7e, 0xbb, 0xb1, 0x82, 0xe1, 0x27, 0x31, 0x7d, 0x9b, 0xeb, 0x85, 0x47, 0x65,
+	0x76, 0xa5, 0x35, 0x46, 0xe9, 0xa1, 0x26, 0x83, 0x1e, 0x80, 0xde, 0x43, 0xa0, 0x1f, 0x15, 0x4d,
+	0x5b, 0x58, 0x51, 0x94, 0x6c, 0xaf, 0xbe, 0x7b, 0x0b, 0xad, 0x74, 0x2d, 0x50, 0xa5, 0x2a, 0x63,
+	0x5f, 0xd0, 0xfd, 0xc1, 0x6a, 0xad, 0x27, 0xe8, 0x1d, 0x80, 0x9e, 0x92, 0x4b, 0xd2, 0x14, 0x07,
+	0xe8, 0x9b, 0x79, 0xfd, 0x1a, 0xea, 0x62, 0xd0, 0x45, 0xc5, 0xe7, 0x1b, 0xe3, 0xef, 0xcd, 0x1c,
+	0x8e, 0x84, 0x34, 0x69, 0x7b, 0x50, 0x21, 0x4d, 0x6e, 0x30, 0xed, 0x6e, 0x97, 0x3a, 0x44, 0xf9,
+	0xe1, 0x07, 0x68, 0x1b, 0x73, 0x26, 0xda, 0x2b, 0x90, 0x95, 0x66, 0xd0, 0x6a, 0x7b, 0xc9, 0xd2,
+	0xfc, 0xdc, 0x42, 0x3d, 0x68, 0xc8, 0xc2, 0x87, 0x8a, 0xc2, 0x9b, 0xf5, 0xb0, 0xf4, 0x32, 0xb3,
+	0x00, 0x4a, 0x2e, 0xfd, 0x49, 0x25, 0x17, 0x93, 0xb0, 0xc4, 0xc5, 0x2c, 0x91, 0xfb, 0x16, 0xea,
+	0x83, 0x93, 0x95, 0xb3, 0x52, 0x36, 0x2a, 0x14, 0xba, 0x6e, 0xb7, 0x32, 0x6f, 0x88, 0x52, 0xf4,
+	0xdc, 0x42, 0xa7, 0x22, 0xe2, 0x74, 0xd6, 0x7f, 0x58, 0x11, 0xb7, 0xf9, 0x2a, 0x51, 0x91, 0x45,
+	0xd2, 0x8f, 0xd3, 0x2c, 0x29, 0x92, 0x73, 0x65, 0x96, 0xd4, 0xa9, 0xbf, 0x22, 0x9f, 0xf1, 0x3f,
+	0x2f, 0x1a, 0x02, 0xfb, 0xf2, 0x9b, 0x01, 0x00, 0x6e, 0x28, 0x29, 0x38, 0x00, 0x19, 0x00, 0x00,
 }
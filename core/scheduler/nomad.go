@@ -82,6 +82,7 @@ func (sc *Nomad) Deploy(jobID, releaseID string, memory, cpuShare int) (*Deploym
 	job.GetSpec().Datacenters = []string{"dc1"}
 	job.GetSpec().TaskGroups[0].Tasks[0].Env["ENV"] = os.Getenv("ENV")
 	job.GetSpec().TaskGroups[0].Tasks[0].Env["COCOON_RELEASE"] = releaseID
+	job.GetSpec().TaskGroups[0].Tasks[0].Env["CONNECTOR_SIGN_KEY"] = os.Getenv("CONNECTOR_SIGN_KEY")
 	job.GetSpec().TaskGroups[0].Tasks[0].Resources.CPU = cpuShare
 	job.GetSpec().TaskGroups[0].Tasks[0].Resources.DiskMB = 1000
 	job.GetSpec().TaskGroups[0].Tasks[0].Resources.MemoryMB = common.Round(0.5 * float64(memory))
@@ -6,6 +6,10 @@ import (
 	"github.com/ellcrys/util"
 )
 
+// ConnectorServiceName is the name of the service that connectors register
+// their RPC server as. Services are tagged with the id of the cocoon.
+const ConnectorServiceName = "cocoons"
+
 // Job defines a nomad job specification
 type Job struct {
 	Region      string
@@ -218,7 +222,7 @@ func NewJob(version, id string, count int) *NomadJob {
 							},
 							Services: []NomadService{
 								NomadService{
-									Name:      fmt.Sprintf("cocoons"),
+									Name:      ConnectorServiceName,
 									Tags:      []string{id},
 									PortLabel: "RPC",
 								},
@@ -247,6 +251,7 @@ func NewJob(version, id string, count int) *NomadJob {
 										DynamicPorts: []DynamicPort{
 											DynamicPort{Label: "RPC"},
 											DynamicPort{Label: "HTTP"},
+											DynamicPort{Label: "METRICS"},
 										},
 									},
 								},
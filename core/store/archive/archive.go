@@ -0,0 +1,80 @@
+// Package archive exports the ledgers of a cocoon along with their transactions
+// and blocks into a portable archive and replays such archives into a store.
+//
+// An archive is a sequence of JSON records separated by new lines. The first
+// record is a header that includes the format version. It is followed by a record
+// for every ledger and, for chained ledgers, a record for every block of the ledger
+// in chain order. A compacted chained ledger begins with a checkpoint record followed
+// by the transactions kept from the removed blocks and the blocks after the checkpoint.
+// Transactions of unchained ledgers are included as individual records in the order
+// they were stored. The last record is a manifest that
+// summarizes every ledger, including the hash of the head block of chained
+// ledgers, and the checksum of the preceding records.
+package archive
+
+import (
+	"github.com/ellcrys/cocoon/core/types"
+)
+
+// Version is the version of the archive format. Archives of
+// earlier versions can still be imported.
+const Version = 2
+
+// Record types
+const (
+	RecordHeader      = "header"
+	RecordLedger      = "ledger"
+	RecordCheckpoint  = "checkpoint"
+	RecordBlock       = "block"
+	RecordTransaction = "transaction"
+	RecordManifest    = "manifest"
+)
+
+// pageSize is the number of blocks or transactions read from the backend at once
+var pageSize = 100
+
+// Record represents a line of an archive. Only the field matching
+// the record type is set.
+type Record struct {
+	Type        string             `json:"type"`
+	Header      *Header            `json:"header,omitempty"`
+	Ledger      *types.Ledger      `json:"ledger,omitempty"`
+	Checkpoint  *types.Checkpoint  `json:"checkpoint,omitempty"`
+	Block       *types.Block       `json:"block,omitempty"`
+	Transaction *types.Transaction `json:"transaction,omitempty"`
+	Manifest    *Manifest          `json:"manifest,omitempty"`
+}
+
+// Header describes an archive
+type Header struct {
+	Version   int    `json:"version"`
+	CocoonID  string `json:"cocoonId"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// LedgerSummary describes the content of a ledger in an archive
+type LedgerSummary struct {
+	Name            string `json:"name"`
+	Chained         bool   `json:"chained,omitempty"`
+	Checkpoint      uint   `json:"checkpoint,omitempty"`
+	Transactions    uint   `json:"transactions"`
+	Blocks          uint   `json:"blocks,omitempty"`
+	HeadBlockNumber uint   `json:"headBlockNumber,omitempty"`
+	HeadBlockHash   string `json:"headBlockHash,omitempty"`
+}
+
+// Manifest summarizes the content of an archive. Checksum is the
+// sha256 hash of all the records that precede the manifest.
+type Manifest struct {
+	Ledgers  []*LedgerSummary `json:"ledgers"`
+	Checksum string           `json:"checksum"`
+}
+
+// Count returns the number of ledgers, blocks and transactions described by the manifest
+func (m *Manifest) Count() (ledgers, blocks, txs uint) {
+	for _, l := range m.Ledgers {
+		blocks += l.Blocks
+		txs += l.Transactions
+	}
+	return uint(len(m.Ledgers)), blocks, txs
+}
@@ -0,0 +1,289 @@
+package archive
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/blockchain/signer"
+	storeimpl "github.com/ellcrys/cocoon/core/store/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newBoltBackend creates a bolt store and blockchain sharing a temporary database
+func newBoltBackend(t *testing.T) (*storeimpl.BoltStore, *impl.BoltBlockchain, func()) {
+	conStr := "bolt://" + path.Join(os.TempDir(), "test_archive_"+util.RandString(5)+".db")
+
+	chain := new(impl.BoltBlockchain)
+	if _, err := chain.Connect(conStr); err != nil {
+		t.Fatal("failed to connect to bolt blockchain")
+	}
+	chain.Init()
+
+	store := new(storeimpl.BoltStore)
+	if _, err := store.Connect(conStr); err != nil {
+		t.Fatal("failed to connect to bolt store")
+	}
+	store.SetBlockchainImplementation(chain)
+	store.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+
+	return store, chain, func() {
+		store.Close()
+		chain.Close()
+		storeimpl.Destroy(conStr)
+	}
+}
+
+// putBlock stores transactions in a chained ledger and creates their block
+func putBlock(store *storeimpl.BoltStore, chain *impl.BoltBlockchain, ledger string, txs []*types.Transaction) {
+	blockID := util.UUID4()
+	for _, tx := range txs {
+		tx.BlockID = blockID
+	}
+	store.PutThen(ledger, txs, func(validTxs []*types.Transaction) error {
+		_, err := chain.CreateBlock(blockID, ledger, validTxs)
+		return err
+	})
+}
+
+// replaceLine replaces a line of an archive
+func replaceLine(archive []byte, index int, replace func(line string) string) []byte {
+	lines := strings.Split(string(archive), "\n")
+	lines[index] = replace(lines[index])
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func TestArchive(t *testing.T) {
+
+	srcStore, srcChain, cleanSrc := newBoltBackend(t)
+	defer cleanSrc()
+
+	srcSigner, _ := signer.Generate()
+	srcChain.SetSigner(srcSigner)
+
+	cocoonID := "cocoon-" + util.RandString(5)
+	chainedLedger := types.MakeLedgerName(cocoonID, "chained")
+	plainLedger := types.MakeLedgerName(cocoonID, "plain")
+	srcStore.CreateLedger(cocoonID, chainedLedger, true, true)
+	srcStore.CreateLedger(cocoonID, plainLedger, false, false)
+
+	for i := 0; i < 3; i++ {
+		putBlock(srcStore, srcChain, chainedLedger, []*types.Transaction{
+			{ID: util.UUID4(), Key: util.RandString(5), Value: util.RandString(5)},
+			{ID: util.UUID4(), Key: util.RandString(5), Value: util.RandString(5)},
+		})
+	}
+
+	tx := &types.Transaction{ID: util.UUID4(), Key: "a", Value: "1"}
+	tx2 := &types.Transaction{ID: util.UUID4(), Key: "b", Value: "2"}
+	srcStore.Put(plainLedger, []*types.Transaction{tx, tx2})
+	srcStore.Put(plainLedger, []*types.Transaction{{ID: util.UUID4(), Key: "a", Value: "3", RevisionTo: tx.ID}})
+	srcStore.Put(plainLedger, []*types.Transaction{{ID: util.UUID4(), Key: "b", RevisionTo: tx2.ID, Deleted: true}})
+
+	Convey("Archive", t, func() {
+
+		var buf bytes.Buffer
+		manifest, err := Export(srcStore, srcChain, cocoonID, &buf)
+		So(err, ShouldBeNil)
+		archive := buf.Bytes()
+
+		Convey(".Export", func() {
+
+			Convey("Should write a manifest that summarizes every ledger", func() {
+				So(len(manifest.Ledgers), ShouldEqual, 2)
+				So(manifest.Ledgers[0].Name, ShouldEqual, chainedLedger)
+				So(manifest.Ledgers[0].Blocks, ShouldEqual, 3)
+				So(manifest.Ledgers[0].Transactions, ShouldEqual, 6)
+				So(manifest.Ledgers[0].HeadBlockNumber, ShouldEqual, 3)
+				So(manifest.Ledgers[1].Name, ShouldEqual, plainLedger)
+				So(manifest.Ledgers[1].Transactions, ShouldEqual, 4)
+				So(manifest.Checksum, ShouldNotBeEmpty)
+
+				ledgers, blocks, txs := manifest.Count()
+				So(ledgers, ShouldEqual, 2)
+				So(blocks, ShouldEqual, 3)
+				So(txs, ShouldEqual, 10)
+			})
+
+			Convey("Should export a compacted ledger from its checkpoint", func() {
+				compactedID := "cocoon-" + util.RandString(5)
+				compactedLedger := types.MakeLedgerName(compactedID, "compacted")
+				srcStore.CreateLedger(compactedID, compactedLedger, true, true)
+
+				a := &types.Transaction{ID: util.UUID4(), Key: "a", Value: "1"}
+				k := &types.Transaction{ID: util.UUID4(), Key: "k", Value: "1"}
+				putBlock(srcStore, srcChain, compactedLedger, []*types.Transaction{a, k})
+				putBlock(srcStore, srcChain, compactedLedger, []*types.Transaction{{ID: util.UUID4(), Key: "a", Value: "2", RevisionTo: a.ID}})
+				putBlock(srcStore, srcChain, compactedLedger, []*types.Transaction{{ID: util.UUID4(), Key: "c", Value: "1"}})
+				checkpoint, err := srcChain.Compact(compactedLedger, 1)
+				So(err, ShouldBeNil)
+				_, err = srcStore.DeleteTransactions(compactedLedger, []string{a.ID})
+				So(err, ShouldBeNil)
+
+				var buf bytes.Buffer
+				manifest, err := Export(srcStore, srcChain, compactedID, &buf)
+				So(err, ShouldBeNil)
+				So(manifest.Ledgers[0].Checkpoint, ShouldEqual, 1)
+				So(manifest.Ledgers[0].Blocks, ShouldEqual, 2)
+				So(manifest.Ledgers[0].Transactions, ShouldEqual, 3)
+				So(manifest.Ledgers[0].HeadBlockNumber, ShouldEqual, 3)
+
+				Convey("and import it", func() {
+					dstStore, dstChain, cleanDst := newBoltBackend(t)
+					defer cleanDst()
+
+					imported, err := Import(dstStore, dstChain, bytes.NewReader(buf.Bytes()))
+					So(err, ShouldBeNil)
+					So(imported, ShouldResemble, manifest)
+
+					dstCheckpoint, err := dstChain.GetCheckpoint(compactedLedger)
+					So(err, ShouldBeNil)
+					So(dstCheckpoint.Number, ShouldEqual, checkpoint.Number)
+					So(dstCheckpoint.Hash, ShouldEqual, checkpoint.Hash)
+					height, err := dstChain.GetChainHeight(compactedLedger)
+					So(err, ShouldBeNil)
+					So(height, ShouldEqual, 3)
+
+					kept, err := dstStore.Get(compactedLedger, "k", false)
+					So(err, ShouldBeNil)
+					So(kept.ID, ShouldEqual, k.ID)
+					latest, err := dstStore.Get(compactedLedger, "a", false)
+					So(err, ShouldBeNil)
+					So(latest.Value, ShouldEqual, "2")
+				})
+			})
+
+			Convey("Should include ledgers of the cocoon only", func() {
+				var buf bytes.Buffer
+				manifest, err := Export(srcStore, srcChain, "unknown", &buf)
+				So(err, ShouldBeNil)
+				So(manifest.Ledgers, ShouldBeEmpty)
+			})
+		})
+
+		Convey(".Import", func() {
+
+			dstStore, dstChain, cleanDst := newBoltBackend(t)
+			defer cleanDst()
+
+			dstSigner, _ := signer.Generate()
+			dstChain.SetSigner(dstSigner)
+
+			Convey("Should replay an archive", func() {
+				imported, err := Import(dstStore, dstChain, bytes.NewReader(archive))
+				So(err, ShouldBeNil)
+				So(imported, ShouldResemble, manifest)
+
+				srcHead, _ := srcChain.ListBlocks(chainedLedger, 3, 1)
+				dstHead, _ := dstChain.ListBlocks(chainedLedger, 3, 1)
+				So(len(dstHead), ShouldEqual, 1)
+				So(dstHead[0].Hash, ShouldEqual, srcHead[0].Hash)
+				So(dstHead[0].Signature, ShouldEqual, srcHead[0].Signature)
+				So(dstHead[0].KeyID, ShouldEqual, srcSigner.KeyID())
+				So(dstHead[0].CreatedAt, ShouldEqual, srcHead[0].CreatedAt)
+
+				a, err := dstStore.Get(plainLedger, "a", false)
+				So(err, ShouldBeNil)
+				So(a.Value, ShouldEqual, "3")
+				b, err := dstStore.Get(plainLedger, "b", false)
+				So(err, ShouldBeNil)
+				So(b, ShouldBeNil)
+				history, _ := dstStore.GetHistory(plainLedger, "a", 0, 0)
+				So(len(history), ShouldEqual, 2)
+
+				Convey("Should fail if a ledger already exists", func() {
+					_, err := Import(dstStore, dstChain, bytes.NewReader(archive))
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldContainSubstring, "ledger with matching name already exists")
+				})
+			})
+
+			Convey("Should not write anything if the import fails", func() {
+				_, err := dstStore.CreateLedger(cocoonID, plainLedger, false, false)
+				So(err, ShouldBeNil)
+				_, err = Import(dstStore, dstChain, bytes.NewReader(archive))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "ledger with matching name already exists")
+
+				ledger, err := dstStore.GetLedger(chainedLedger)
+				So(err, ShouldBeNil)
+				So(ledger, ShouldBeNil)
+				chain, err := dstChain.GetChain(chainedLedger)
+				So(err, ShouldBeNil)
+				So(chain, ShouldBeNil)
+			})
+
+			Convey("Should return error if the archive was modified", func() {
+				modified := replaceLine(archive, 2, func(line string) string {
+					return strings.Replace(line, `"number":1`, `"number":2`, 1)
+				})
+				_, err := Import(dstStore, dstChain, bytes.NewReader(modified))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "block 2: expected block number 1")
+			})
+
+			Convey("Should return error if the checksum does not match", func() {
+				modified := replaceLine(archive, 0, func(line string) string {
+					return strings.Replace(line, `"version":2`, `"version":2 `, 1)
+				})
+				_, err := Import(dstStore, dstChain, bytes.NewReader(modified))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "archive checksum does not match the manifest")
+			})
+
+			Convey("Should return error if the archive has no manifest", func() {
+				lines := strings.Split(strings.TrimSpace(string(archive)), "\n")
+				truncated := strings.Join(lines[:len(lines)-1], "\n")
+				_, err := Import(dstStore, dstChain, strings.NewReader(truncated))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "archive has no manifest")
+			})
+
+			Convey("Should return error if the archive version is not supported", func() {
+				modified := replaceLine(archive, 0, func(line string) string {
+					return strings.Replace(line, `"version":2`, `"version":100`, 1)
+				})
+				_, err := Import(dstStore, dstChain, bytes.NewReader(modified))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unsupported archive version (100)")
+			})
+		})
+
+		Convey(".checkBlock", func() {
+
+			ledger := &LedgerSummary{Name: chainedLedger, Chained: true}
+			txs := []*types.Transaction{{ID: util.UUID4(), Key: "a", Value: "1"}}
+			txs[0].Hash = txs[0].MakeHash()
+			txsJSON, _ := util.ToJSON(txs)
+
+			Convey("Should accept a block created before merkle roots were introduced", func() {
+				block := &types.Block{
+					Number:        1,
+					PrevBlockHash: impl.MakeGenesisBlockHash(chainedLedger),
+					Hash:          impl.MakeTxsHash(txs),
+					Transactions:  txsJSON,
+				}
+				numTxs, err := checkBlock(ledger, block)
+				So(err, ShouldBeNil)
+				So(numTxs, ShouldEqual, 1)
+			})
+
+			Convey("Should return error if the hash of a block without merkle root does not match its transactions", func() {
+				block := &types.Block{
+					Number:        1,
+					PrevBlockHash: impl.MakeGenesisBlockHash(chainedLedger),
+					Hash:          util.Sha256("other"),
+					Transactions:  txsJSON,
+				}
+				_, err := checkBlock(ledger, block)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "block hash does not match its transactions")
+			})
+		})
+	})
+}
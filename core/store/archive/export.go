@@ -0,0 +1,250 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// recordWriter writes records as lines and keeps
+// a running checksum of the lines written
+type recordWriter struct {
+	w        io.Writer
+	checksum hash.Hash
+}
+
+func (rw *recordWriter) write(r *Record) error {
+	line, err := util.ToJSON(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	rw.checksum.Write(line)
+	_, err = rw.w.Write(line)
+	return err
+}
+
+// Export writes the ledgers of a cocoon, their blocks and transactions to w.
+// It returns the manifest written at the end of the archive. Encrypted values
+// are exported as they are stored, along with the wrapped data key of their
+// ledger; they can only be read by an orderer that holds the master key.
+func Export(store types.Store, blockchain types.Blockchain, cocoonID string, w io.Writer) (*Manifest, error) {
+
+	rw := &recordWriter{w: w, checksum: sha256.New()}
+	err := rw.write(&Record{Type: RecordHeader, Header: &Header{
+		Version:   Version,
+		CocoonID:  cocoonID,
+		CreatedAt: time.Now().Unix(),
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write header. %s", err)
+	}
+
+	ledgers, err := store.ListLedgers(cocoonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest = &Manifest{}
+	for _, ledger := range ledgers {
+
+		if err := rw.write(&Record{Type: RecordLedger, Ledger: ledger}); err != nil {
+			return nil, fmt.Errorf("failed to write ledger (%s). %s", ledger.Name, err)
+		}
+
+		var summary *LedgerSummary
+		if ledger.Chained {
+			summary, err = exportBlocks(rw, store, blockchain, ledger)
+		} else {
+			summary, err = exportTransactions(rw, store, ledger)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Ledgers = append(manifest.Ledgers, summary)
+	}
+
+	manifest.Checksum = hex.EncodeToString(rw.checksum.Sum(nil))
+	if err := rw.write(&Record{Type: RecordManifest, Manifest: manifest}); err != nil {
+		return nil, fmt.Errorf("failed to write manifest. %s", err)
+	}
+
+	return manifest, nil
+}
+
+// exportBlocks writes the blocks of a chained ledger in chain order. Transactions
+// are not written separately as every transaction of a chained ledger is included
+// in a block, except in a compacted ledger. Its checkpoint is written first, followed
+// by the transactions kept from the removed blocks and then by the remaining blocks.
+func exportBlocks(rw *recordWriter, store types.Store, blockchain types.Blockchain, ledger *types.Ledger) (*LedgerSummary, error) {
+
+	checkpoint, err := blockchain.GetCheckpoint(ledger.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &LedgerSummary{Name: ledger.Name, Chained: true}
+	var from uint = 1
+	var to uint
+	if checkpoint != nil {
+
+		if err := rw.write(&Record{Type: RecordCheckpoint, Checkpoint: checkpoint}); err != nil {
+			return nil, fmt.Errorf("failed to write checkpoint (%s). %s", ledger.Name, err)
+		}
+		summary.Checkpoint = checkpoint.Number
+		summary.HeadBlockNumber = checkpoint.Number
+		summary.HeadBlockHash = checkpoint.Hash
+		from = checkpoint.Number + 1
+
+		// transactions are stored along with their block, so the blocks listed
+		// after the last transaction number is read include every transaction
+		// up to that number that was not kept from a removed block. Blocks
+		// created afterwards are not exported.
+		lastTxNumber, err := store.GetLastTxNumber()
+		if err != nil {
+			return nil, err
+		}
+		var blockIDs map[string]bool
+		blockIDs, to, err = listBlockIDs(blockchain, ledger, from)
+		if err != nil {
+			return nil, err
+		}
+
+		kept, err := exportKeptTransactions(rw, store, ledger, blockIDs, lastTxNumber)
+		if err != nil {
+			return nil, err
+		}
+		summary.Transactions += kept
+	}
+
+	for {
+
+		blocks, err := blockchain.ListBlocks(ledger.Name, from, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range blocks {
+			if to > 0 && block.Number > to {
+				return summary, nil
+			}
+			txs, err := block.GetTransactions()
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %s", block.Number, err)
+			}
+			if err := rw.write(&Record{Type: RecordBlock, Block: block}); err != nil {
+				return nil, fmt.Errorf("failed to write block (%s). %s", block.ID, err)
+			}
+			summary.Blocks++
+			summary.Transactions += uint(len(txs))
+			summary.HeadBlockNumber = block.Number
+			summary.HeadBlockHash = block.Hash
+		}
+
+		if len(blocks) < pageSize {
+			break
+		}
+		from = summary.HeadBlockNumber + 1
+	}
+
+	return summary, nil
+}
+
+// listBlockIDs returns the ids of the blocks of a chained ledger starting
+// from the block with number from, and the number of the last block
+func listBlockIDs(blockchain types.Blockchain, ledger *types.Ledger, from uint) (map[string]bool, uint, error) {
+
+	blockIDs := make(map[string]bool)
+	var last uint
+	for {
+
+		blocks, err := blockchain.ListBlocks(ledger.Name, from, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, block := range blocks {
+			blockIDs[block.ID] = true
+			last = block.Number
+		}
+
+		if len(blocks) < pageSize {
+			break
+		}
+		from = last + 1
+	}
+
+	return blockIDs, last, nil
+}
+
+// exportKeptTransactions writes the transactions of a compacted chained ledger
+// whose block has been removed, in the order they were stored. blockIDs are the
+// ids of the blocks that have not been removed. Transactions with a number
+// greater than lastTxNumber are not written.
+func exportKeptTransactions(rw *recordWriter, store types.Store, ledger *types.Ledger, blockIDs map[string]bool, lastTxNumber uint) (uint, error) {
+
+	var kept uint
+	var after uint
+	for {
+
+		txs, err := store.ListTransactions(ledger.Name, after, pageSize)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, tx := range txs {
+			if tx.Number > lastTxNumber {
+				return kept, nil
+			}
+			after = tx.Number
+			if blockIDs[tx.BlockID] {
+				continue
+			}
+			if err := rw.write(&Record{Type: RecordTransaction, Transaction: tx}); err != nil {
+				return 0, fmt.Errorf("failed to write transaction (%s). %s", tx.ID, err)
+			}
+			kept++
+		}
+
+		if len(txs) < pageSize {
+			break
+		}
+	}
+
+	return kept, nil
+}
+
+// exportTransactions writes every transaction of an unchained ledger in the order they were stored
+func exportTransactions(rw *recordWriter, store types.Store, ledger *types.Ledger) (*LedgerSummary, error) {
+
+	summary := &LedgerSummary{Name: ledger.Name}
+	var after uint
+	for {
+
+		txs, err := store.ListTransactions(ledger.Name, after, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tx := range txs {
+			if err := rw.write(&Record{Type: RecordTransaction, Transaction: tx}); err != nil {
+				return nil, fmt.Errorf("failed to write transaction (%s). %s", tx.ID, err)
+			}
+			summary.Transactions++
+			after = tx.Number
+		}
+
+		if len(txs) < pageSize {
+			break
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,331 @@
+package archive
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// recordReader reads the records of an archive
+type recordReader struct {
+	r *bufio.Reader
+}
+
+// next returns the next record and its line. It returns
+// io.EOF when there are no more records to read.
+func (rr *recordReader) next() (*Record, []byte, error) {
+	for {
+		line, err := rr.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, nil, err
+		}
+		if len(line) == 0 || (len(line) == 1 && line[0] == '\n') {
+			continue
+		}
+		var r Record
+		if err := util.FromJSON(line, &r); err != nil {
+			return nil, nil, fmt.Errorf("malformed record. %s", err)
+		}
+		return &r, line, nil
+	}
+}
+
+// Import replays an archive into a store and blockchain. The archive is checked
+// before anything is written: the checksum and summaries of the manifest must
+// match the records, and every transaction and block hash must match its content.
+// The archive is replayed in a single database transaction, so nothing is written
+// if it fails. Blocks are stored as they were archived, keeping their signatures, and
+// the hashes computed by the store are compared with the archived hashes. Ledgers
+// in the archive must not exist.
+func Import(store types.Store, blockchain types.Blockchain, r io.Reader) (*Manifest, error) {
+
+	// the archive is spooled to a temporary file as it is checked
+	// so that it can be read again when it is replayed
+	spool, err := ioutil.TempFile("", "cocoon-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file. %s", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	manifest, err := check(io.TeeReader(r, spool))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := spool.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	err = store.Transact(func(store types.Store, blockchain types.Blockchain) error {
+		return replay(store, blockchain, spool)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// check reads an archive and verifies its records against
+// the manifest. It returns the manifest if the archive is valid.
+func check(r io.Reader) (*Manifest, error) {
+
+	rr := &recordReader{r: bufio.NewReader(r)}
+	checksum := sha256.New()
+
+	record, line, err := rr.next()
+	if err == io.EOF {
+		return nil, fmt.Errorf("archive is empty")
+	} else if err != nil {
+		return nil, err
+	} else if record.Type != RecordHeader || record.Header == nil {
+		return nil, fmt.Errorf("archive does not begin with a header")
+	} else if record.Header.Version < 1 || record.Header.Version > Version {
+		return nil, fmt.Errorf("unsupported archive version (%d)", record.Header.Version)
+	}
+	checksum.Write(line)
+
+	var manifest *Manifest
+	var summaries []*LedgerSummary
+	var cur *LedgerSummary
+	for {
+
+		record, line, err := rr.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		} else if manifest != nil {
+			return nil, fmt.Errorf("unexpected record after manifest")
+		}
+
+		switch record.Type {
+		case RecordLedger:
+			if record.Ledger == nil {
+				return nil, fmt.Errorf("ledger record has no ledger")
+			}
+			cur = &LedgerSummary{Name: record.Ledger.Name, Chained: record.Ledger.Chained}
+			summaries = append(summaries, cur)
+
+		case RecordCheckpoint:
+			if cur == nil || !cur.Chained || cur.Checkpoint > 0 || cur.Blocks > 0 || record.Checkpoint == nil {
+				return nil, fmt.Errorf("unexpected checkpoint record")
+			} else if record.Checkpoint.Number == 0 {
+				return nil, fmt.Errorf("%s: checkpoint has no block number", cur.Name)
+			}
+			cur.Checkpoint = record.Checkpoint.Number
+			cur.HeadBlockNumber = record.Checkpoint.Number
+			cur.HeadBlockHash = record.Checkpoint.Hash
+
+		case RecordBlock:
+			if cur == nil || !cur.Chained || record.Block == nil {
+				return nil, fmt.Errorf("unexpected block record")
+			}
+			numTxs, err := checkBlock(cur, record.Block)
+			if err != nil {
+				return nil, fmt.Errorf("%s: block %d: %s", cur.Name, record.Block.Number, err)
+			}
+			cur.Blocks++
+			cur.Transactions += numTxs
+			cur.HeadBlockNumber = record.Block.Number
+			cur.HeadBlockHash = record.Block.Hash
+
+		case RecordTransaction:
+			// transactions of a chained ledger are only kept
+			// from the blocks removed before its checkpoint
+			if cur == nil || (cur.Chained && (cur.Checkpoint == 0 || cur.Blocks > 0)) || record.Transaction == nil {
+				return nil, fmt.Errorf("unexpected transaction record")
+			}
+			if _, valid := impl.VerifyTxs([]*types.Transaction{record.Transaction}); !valid {
+				return nil, fmt.Errorf("%s: transaction %s: hash does not match its content", cur.Name, record.Transaction.ID)
+			}
+			cur.Transactions++
+
+		case RecordManifest:
+			if record.Manifest == nil {
+				return nil, fmt.Errorf("manifest record has no manifest")
+			}
+			manifest = record.Manifest
+			continue
+
+		default:
+			return nil, fmt.Errorf("unknown record type (%s)", record.Type)
+		}
+
+		checksum.Write(line)
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("archive has no manifest")
+	}
+
+	if manifest.Checksum != hex.EncodeToString(checksum.Sum(nil)) {
+		return nil, fmt.Errorf("archive checksum does not match the manifest")
+	}
+
+	if len(summaries) != len(manifest.Ledgers) {
+		return nil, fmt.Errorf("number of ledgers does not match the manifest")
+	}
+	for i, summary := range summaries {
+		if !reflect.DeepEqual(summary, manifest.Ledgers[i]) {
+			return nil, fmt.Errorf("%s: ledger does not match the manifest", summary.Name)
+		}
+	}
+
+	return manifest, nil
+}
+
+// checkBlock checks that a block follows the previous block or the checkpoint
+// of the ledger and that its hash matches its transactions. Blocks created
+// before merkle roots were introduced are accepted. It returns the number
+// of transactions in the block.
+func checkBlock(ledger *LedgerSummary, block *types.Block) (uint, error) {
+
+	prevBlockHash := ledger.HeadBlockHash
+	if ledger.HeadBlockNumber == 0 {
+		prevBlockHash = impl.MakeGenesisBlockHash(ledger.Name)
+	}
+
+	if block.Number != ledger.HeadBlockNumber+1 {
+		return 0, fmt.Errorf("expected block number %d", ledger.HeadBlockNumber+1)
+	} else if block.PrevBlockHash != prevBlockHash {
+		return 0, fmt.Errorf("previous block hash does not match the hash of block %d", ledger.HeadBlockNumber)
+	}
+
+	txs, err := block.GetTransactions()
+	if err != nil {
+		return 0, err
+	}
+
+	if failedTx, valid := impl.VerifyTxs(txs); !valid {
+		return 0, fmt.Errorf("transaction %s: hash does not match its content", failedTx.ID)
+	}
+
+	if !impl.VerifyBlockHash(block, txs) {
+		return 0, fmt.Errorf("block hash does not match its transactions")
+	}
+
+	return uint(len(txs)), nil
+}
+
+// replay writes the ledgers, blocks and transactions of a checked archive
+func replay(store types.Store, blockchain types.Blockchain, r io.Reader) error {
+
+	rr := &recordReader{r: bufio.NewReader(r)}
+	var ledger *types.Ledger
+	var batch []*types.Transaction
+
+	for {
+
+		record, _, err := rr.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// transactions of unchained ledgers are stored in batches
+		if record.Type != RecordTransaction || len(batch) == pageSize {
+			if err := putTransactions(store, ledger, batch, nil); err != nil {
+				return err
+			}
+			batch = nil
+		}
+
+		switch record.Type {
+		case RecordLedger:
+			ledger = record.Ledger
+			if _, err := store.CreateLedger(ledger.CocoonID, ledger.Name, ledger.Chained, ledger.Public); err != nil {
+				return fmt.Errorf("%s: %s", ledger.Name, err)
+			}
+			if ledger.HasRetention() {
+				if _, err := store.SetLedgerRetention(ledger.Name, ledger.KeepRevisions, ledger.KeepFor); err != nil {
+					return fmt.Errorf("%s: %s", ledger.Name, err)
+				}
+			}
+			if len(ledger.DataKey) > 0 {
+				if _, err := store.SetLedgerDataKey(ledger.Name, "", ledger.DataKey); err != nil {
+					return fmt.Errorf("%s: %s", ledger.Name, err)
+				}
+			}
+			if len(ledger.Indexes) > 0 {
+				if _, err := store.SetLedgerIndexes(ledger.Name, ledger.Indexes); err != nil {
+					return fmt.Errorf("%s: %s", ledger.Name, err)
+				}
+			}
+
+		case RecordCheckpoint:
+			if _, err := blockchain.ImportCheckpoint(ledger.Name, record.Checkpoint); err != nil {
+				return fmt.Errorf("%s: %s", ledger.Name, err)
+			}
+
+		case RecordBlock:
+			block := record.Block
+			txs, _ := block.GetTransactions()
+			err := putTransactions(store, ledger, txs, func(validTxs []*types.Transaction) error {
+				if _, err := blockchain.ImportBlock(ledger.Name, block); err != nil {
+					return fmt.Errorf("block %d: %s", block.Number, err)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+		case RecordTransaction:
+			batch = append(batch, record.Transaction)
+		}
+	}
+
+	return nil
+}
+
+// putTransactions stores archived transactions and calls thenFunc if all
+// transactions are stored and their hashes match the archived hashes.
+func putTransactions(store types.Store, ledger *types.Ledger, txs []*types.Transaction, thenFunc func([]*types.Transaction) error) error {
+
+	if len(txs) == 0 {
+		return nil
+	}
+
+	var hashes = make([]string, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash
+		tx.Number = 0
+	}
+
+	receipts, err := store.PutThen(ledger.Name, txs, func(validTxs []*types.Transaction) error {
+		if len(validTxs) != len(txs) {
+			return fmt.Errorf("failed to store all transactions")
+		}
+		for i, tx := range validTxs {
+			if tx.Hash != hashes[i] {
+				return fmt.Errorf("transaction %s: hash does not match the archived transaction", tx.ID)
+			}
+		}
+		if thenFunc != nil {
+			return thenFunc(validTxs)
+		}
+		return nil
+	})
+	for _, receipt := range receipts {
+		if len(receipt.Err) > 0 {
+			return fmt.Errorf("%s: transaction %s: %s", ledger.Name, receipt.ID, receipt.Err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %s", ledger.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package envelope encrypts the values of private ledgers with envelope encryption.
+// Each ledger has a random data key that encrypts its values. The data key is stored
+// with the ledger, wrapped by a master key held by a key provider. Rotating the master
+// key only requires the data keys to be wrapped again; encrypted values are unchanged.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// valuePrefix marks an encrypted value. Values without
+// it were stored before encryption was enabled.
+const valuePrefix = "enc:v1:"
+
+// DataKeySize is the size of data and master keys in bytes (AES-256)
+const DataKeySize = 32
+
+// KeyProvider holds the master keys that wrap the data keys of ledgers.
+// A key management service can implement it by wrapping and unwrapping
+// data keys remotely.
+type KeyProvider interface {
+
+	// CurrentKeyID returns the id of the master key that wraps new data keys
+	CurrentKeyID() string
+
+	// WrapKey encrypts a data key with the master key with the given id
+	WrapKey(keyID string, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data key wrapped by the master key with the given id
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// seal encrypts plaintext with AES-GCM. The nonce is prepended to the ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext created by seal
+func open(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewDataKey creates a random data key and wraps it with the current master key
+// of the provider. It returns the data key and its wrapped encoding, which is the
+// id of the master key and the base64 encoded wrapped key separated by a colon.
+func NewDataKey(provider KeyProvider) ([]byte, string, error) {
+	dataKey := make([]byte, DataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, "", fmt.Errorf("failed to create data key. %s", err)
+	}
+	wrapped, err := wrapDataKey(provider, dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return dataKey, wrapped, nil
+}
+
+// wrapDataKey wraps a data key with the current master key of the provider
+func wrapDataKey(provider KeyProvider, dataKey []byte) (string, error) {
+	keyID := provider.CurrentKeyID()
+	wrapped, err := provider.WrapKey(keyID, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key. %s", err)
+	}
+	return keyID + ":" + base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+// GetWrappingKeyID returns the id of the master key that wrapped a data key
+func GetWrappingKeyID(wrappedDataKey string) string {
+	return strings.SplitN(wrappedDataKey, ":", 2)[0]
+}
+
+// OpenDataKey unwraps a data key created by NewDataKey
+func OpenDataKey(provider KeyProvider, wrappedDataKey string) ([]byte, error) {
+	parts := strings.SplitN(wrappedDataKey, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed data key")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed data key")
+	}
+	dataKey, err := provider.UnwrapKey(parts[0], wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key. %s", err)
+	}
+	return dataKey, nil
+}
+
+// RewrapDataKey wraps a data key again with the current master key of the
+// provider. It returns false if the data key is already wrapped by it.
+func RewrapDataKey(provider KeyProvider, wrappedDataKey string) (string, bool, error) {
+	if GetWrappingKeyID(wrappedDataKey) == provider.CurrentKeyID() {
+		return wrappedDataKey, false, nil
+	}
+	dataKey, err := OpenDataKey(provider, wrappedDataKey)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := wrapDataKey(provider, dataKey)
+	if err != nil {
+		return "", false, err
+	}
+	return rewrapped, true, nil
+}
+
+// IsEncrypted checks whether a value was encrypted by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, valuePrefix)
+}
+
+// Encrypt encrypts a value with a data key
+func Encrypt(dataKey []byte, value string) (string, error) {
+	ciphertext, err := seal(dataKey, []byte(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value. %s", err)
+	}
+	return valuePrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt. Values that are
+// not encrypted are returned unchanged.
+func Decrypt(dataKey []byte, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(value[len(valuePrefix):])
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value. malformed value")
+	}
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value. %s", err)
+	}
+	return string(plaintext), nil
+}
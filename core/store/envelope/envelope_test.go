@@ -0,0 +1,146 @@
+package envelope
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEnvelope(t *testing.T) {
+	Convey("Envelope", t, func() {
+
+		key1, _ := GenerateKey()
+		key2, _ := GenerateKey()
+		keyring, err := NewKeyring(key1)
+		So(err, ShouldBeNil)
+
+		Convey(".Encrypt and .Decrypt", func() {
+			dataKey, _, err := NewDataKey(keyring)
+			So(err, ShouldBeNil)
+
+			Convey("Should decrypt an encrypted value", func() {
+				encrypted, err := Encrypt(dataKey, "secret")
+				So(err, ShouldBeNil)
+				So(IsEncrypted(encrypted), ShouldBeTrue)
+				So(encrypted, ShouldNotContainSubstring, "secret")
+
+				value, err := Decrypt(dataKey, encrypted)
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "secret")
+			})
+
+			Convey("Should return values that are not encrypted unchanged", func() {
+				value, err := Decrypt(dataKey, "plaintext")
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "plaintext")
+			})
+
+			Convey("Should fail to decrypt with another data key", func() {
+				otherDataKey, _, _ := NewDataKey(keyring)
+				encrypted, _ := Encrypt(dataKey, "secret")
+				_, err := Decrypt(otherDataKey, encrypted)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey(".NewDataKey and .OpenDataKey", func() {
+			Convey("Should unwrap a data key", func() {
+				dataKey, wrapped, err := NewDataKey(keyring)
+				So(err, ShouldBeNil)
+				So(GetWrappingKeyID(wrapped), ShouldEqual, MakeKeyID(key1))
+
+				opened, err := OpenDataKey(keyring, wrapped)
+				So(err, ShouldBeNil)
+				So(opened, ShouldResemble, dataKey)
+			})
+
+			Convey("Should fail if the master key is unknown", func() {
+				_, wrapped, _ := NewDataKey(keyring)
+				otherKeyring, _ := NewKeyring(key2)
+				_, err := OpenDataKey(otherKeyring, wrapped)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "unknown master key")
+			})
+		})
+
+		Convey(".RewrapDataKey", func() {
+			dataKey, wrapped, _ := NewDataKey(keyring)
+
+			Convey("Should not change a data key wrapped by the current key", func() {
+				_, changed, err := RewrapDataKey(keyring, wrapped)
+				So(err, ShouldBeNil)
+				So(changed, ShouldBeFalse)
+			})
+
+			Convey("Should wrap a data key with the new current key", func() {
+				rotated, _ := NewKeyring(key2, key1)
+				rewrapped, changed, err := RewrapDataKey(rotated, wrapped)
+				So(err, ShouldBeNil)
+				So(changed, ShouldBeTrue)
+				So(GetWrappingKeyID(rewrapped), ShouldEqual, MakeKeyID(key2))
+
+				newOnly, _ := NewKeyring(key2)
+				opened, err := OpenDataKey(newOnly, rewrapped)
+				So(err, ShouldBeNil)
+				So(opened, ShouldResemble, dataKey)
+			})
+		})
+
+		Convey(".ParseKeyring", func() {
+			Convey("Should use the first key as the current key", func() {
+				kr, err := ParseKeyring("# master keys\n" + hex.EncodeToString(key2) + "\n\n" + hex.EncodeToString(key1) + "\n")
+				So(err, ShouldBeNil)
+				So(kr.CurrentKeyID(), ShouldEqual, MakeKeyID(key2))
+				So(len(kr.keys), ShouldEqual, 2)
+			})
+
+			Convey("Should accept comma separated keys", func() {
+				kr, err := ParseKeyring(hex.EncodeToString(key1) + "," + hex.EncodeToString(key2))
+				So(err, ShouldBeNil)
+				So(kr.CurrentKeyID(), ShouldEqual, MakeKeyID(key1))
+			})
+
+			Convey("Should return error if a key is invalid", func() {
+				_, err := ParseKeyring("not_hex")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "key 1: invalid hex encoding")
+
+				_, err = ParseKeyring("abcd")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "key 1: master keys must be 32 bytes long")
+
+				_, err = ParseKeyring("# no key")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "keyring has no key")
+			})
+		})
+
+		Convey(".LoadKeyFile and .LoadEnv", func() {
+			Convey("Should load a keyring from a file", func() {
+				file := path.Join(os.TempDir(), "test_keyring_"+util.RandString(5))
+				defer os.Remove(file)
+				ioutil.WriteFile(file, []byte(hex.EncodeToString(key1)+"\n"), 0600)
+				kr, err := LoadKeyFile(file)
+				So(err, ShouldBeNil)
+				So(kr.CurrentKeyID(), ShouldEqual, MakeKeyID(key1))
+			})
+
+			Convey("Should load a keyring from an environment variable", func() {
+				name := "TEST_KEYRING_" + util.RandString(5)
+				os.Setenv(name, hex.EncodeToString(key2)+","+hex.EncodeToString(key1))
+				defer os.Unsetenv(name)
+				kr, err := LoadEnv(name)
+				So(err, ShouldBeNil)
+				So(kr.CurrentKeyID(), ShouldEqual, MakeKeyID(key2))
+
+				_, err = LoadEnv("TEST_KEYRING_UNSET")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
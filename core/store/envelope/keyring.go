@@ -0,0 +1,116 @@
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ellcrys/util"
+)
+
+// MakeKeyID creates the id of a master key. It is the first
+// 16 characters of the hex encoded SHA256 hash of the key.
+func MakeKeyID(key []byte) string {
+	return util.Sha256(hex.EncodeToString(key))[:16]
+}
+
+// Keyring is a key provider that holds its master keys in memory.
+// The first key of the keyring wraps new data keys; the other keys
+// are kept to unwrap data keys wrapped before the keys were rotated.
+// It implements KeyProvider.
+type Keyring struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+// NewKeyring creates a keyring from master keys. The first key is the current key.
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring has no key")
+	}
+	kr := &Keyring{keys: make(map[string][]byte)}
+	for i, key := range keys {
+		if len(key) != DataKeySize {
+			return nil, fmt.Errorf("key %d: master keys must be %d bytes long", i+1, DataKeySize)
+		}
+		id := MakeKeyID(key)
+		if i == 0 {
+			kr.currentKeyID = id
+		}
+		kr.keys[id] = key
+	}
+	return kr, nil
+}
+
+// ParseKeyring creates a keyring from hex encoded master keys separated
+// by new lines or commas. The first key is the current key. Empty lines
+// and lines starting with `#` are ignored.
+func ParseKeyring(encoded string) (*Keyring, error) {
+	var keys [][]byte
+	for _, line := range strings.FieldsFunc(encoded, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: invalid hex encoding", len(keys)+1)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyring(keys...)
+}
+
+// LoadKeyFile creates a keyring from a file of hex encoded master keys
+func LoadKeyFile(file string) (*Keyring, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file. %s", err)
+	}
+	return ParseKeyring(string(data))
+}
+
+// LoadEnv creates a keyring from the hex encoded master keys held by an
+// environment variable. Keys are separated by commas.
+func LoadEnv(name string) (*Keyring, error) {
+	encoded := os.Getenv(name)
+	if len(encoded) == 0 {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	return ParseKeyring(encoded)
+}
+
+// GenerateKey creates a random master key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, DataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// CurrentKeyID returns the id of the key that wraps new data keys
+func (kr *Keyring) CurrentKeyID() string {
+	return kr.currentKeyID
+}
+
+// WrapKey encrypts a data key with the master key with the given id
+func (kr *Keyring) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	key, ok := kr.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key (%s)", keyID)
+	}
+	return seal(key, dataKey)
+}
+
+// UnwrapKey decrypts a data key wrapped by the master key with the given id
+func (kr *Keyring) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := kr.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key (%s)", keyID)
+	}
+	return open(key, wrappedKey)
+}
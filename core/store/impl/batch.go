@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"sort"
+
+	"github.com/ellcrys/cocoon/core/types"
+)
+
+// getBatchKeys returns the sorted, unique keys written or
+// read by a batch of transactions
+func getBatchKeys(txs []*types.Transaction) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, tx := range txs {
+		add(tx.Key)
+		for _, read := range tx.ReadSet {
+			add(read.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isReadSetStale checks whether a key in the read-set of a transaction has
+// been revised since it was read. latest maps keys to their most recent revision.
+func isReadSetStale(tx *types.Transaction, latest map[string]string) bool {
+	for _, read := range tx.ReadSet {
+		if latest[read.Key] != read.Revision {
+			return true
+		}
+	}
+	return false
+}
+
+// abortBatch replaces the receipts of the valid transactions of
+// a failed atomic batch with an ErrBatchAborted receipt
+func abortBatch(txReceipts [][]*types.TxReceipt) {
+	for _, receipts := range txReceipts {
+		for _, receipt := range receipts {
+			if len(receipt.Err) == 0 {
+				receipt.Err = types.ErrBatchAborted.Error()
+			}
+		}
+	}
+}
@@ -0,0 +1,1361 @@
+package impl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// TransactionIDIndexBucketName is the name of the bucket that maps
+// transaction ids to the location of the transaction.
+const TransactionIDIndexBucketName = "transaction_ids"
+
+// TransactionRevisionIndexBucketName is the name of the bucket that maps
+// revision ids to the id of the transaction that revised them.
+const TransactionRevisionIndexBucketName = "transaction_revisions"
+
+// IndexBucketName is the name of the bucket that holds the entries of
+// the secondary indexes of ledgers. Each ledger has its own bucket.
+const IndexBucketName = "ledger_indexes"
+
+// storeMetaBucketName is the name of the bucket holding store counters
+const storeMetaBucketName = "store_meta"
+
+// lastTxNumberKey is the key of the number of the last stored transaction
+var lastTxNumberKey = []byte("last_tx_number")
+
+// boltStoreBuckets are the top-level buckets owned by the store
+var boltStoreBuckets = []string{
+	LedgerTableName,
+	TransactionTableName,
+	TransactionIDIndexBucketName,
+	TransactionRevisionIndexBucketName,
+	IndexBucketName,
+	storeMetaBucketName,
+}
+
+// BoltStore defines a store implementation on an embedded single-file
+// bolt database. It implements the Store interface and is intended for
+// development and tests where a postgres server is not available.
+//
+// Transactions of a ledger are kept in a ledger bucket under keys made of the
+// transaction key and number, so that all revisions of a key are adjacent and
+// ordered. Writes are serialized, therefore no distributed lock is needed
+// to guard transaction keys.
+type BoltStore struct {
+	db         *bolt.DB
+	tx         *bolt.Tx
+	blockchain types.Blockchain
+	writeMu    sync.Mutex
+}
+
+// SetBlockchainImplementation sets sets a reference of the blockchain implementation
+func (s *BoltStore) SetBlockchainImplementation(b types.Blockchain) {
+	s.blockchain = b
+}
+
+// update runs fn in a read-write transaction or in
+// the transaction the store is bound to by Transact
+func (s *BoltStore) update(fn func(*bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	return s.db.Update(fn)
+}
+
+// view runs fn in a read-only transaction or in
+// the transaction the store is bound to by Transact
+func (s *BoltStore) view(fn func(*bolt.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	return s.db.View(fn)
+}
+
+// GetImplementationName returns the name of this store implementation
+func (s *BoltStore) GetImplementationName() string {
+	return "bolt.store"
+}
+
+// Connect opens the bolt database referenced by the connection string.
+// The connection string must use the `bolt://` scheme followed by the
+// path of the database file.
+func (s *BoltStore) Connect(dbAddr string) (interface{}, error) {
+	var err error
+	s.db, err = common.OpenBoltDB(dbAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to store backend. %s", err)
+	}
+	return s.db, nil
+}
+
+// Init initializes the store. Creates the necessary buckets and
+// the public and private system ledgers
+func (s *BoltStore) Init(systemPublicLedgerName, systemPrivateLedgerName string) error {
+
+	err := s.update(func(tx *bolt.Tx) error {
+		for _, name := range boltStoreBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create `%s` bucket. %s", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// create system ledgers
+	var systemLedgers = [][]interface{}{
+		[]interface{}{systemPublicLedgerName, true},   // public
+		[]interface{}{systemPrivateLedgerName, false}, // private
+	}
+
+	for _, ledger := range systemLedgers {
+		existing, err := s.GetLedger(ledger[0].(string))
+		if err != nil {
+			return fmt.Errorf("failed to check existence of ledger named %s: %s", ledger[0].(string), err)
+		}
+		if existing == nil {
+			_, err := s.CreateLedger(types.SystemCocoonID, ledger[0].(string), true, ledger[1].(bool))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// clearBolt removes all ledgers and transactions from a bolt database.
+func clearBolt(dbAddr string) error {
+
+	db, err := common.OpenBoltDB(dbAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to store backend")
+	}
+	defer common.CloseBoltDB(db)
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltStoreBuckets {
+			if err := tx.DeleteBucket([]byte(name)); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+			if _, err := tx.CreateBucket([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateLedgerThen creates a new ledger and accepts an additional operation (via the thenFunc) to be
+// executed before the ledger is stored. If the thenFunc returns an error, the
+// ledger is not stored and the error is returned
+func (s *BoltStore) CreateLedgerThen(cocoonID, name string, chained, public bool, thenFunc func() error) (*types.Ledger, error) {
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	existing, err := s.GetLedger(name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, fmt.Errorf("ledger with matching name already exists")
+	}
+
+	newLedger := &types.Ledger{
+		Name:      name,
+		Public:    public,
+		Chained:   chained,
+		CocoonID:  cocoonID,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	// create chain
+	if chained {
+		_, err := s.blockchain.CreateChain(name, public)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// run the companion function and abort
+	// the ledger creation if error was returned
+	if thenFunc != nil {
+		if err := thenFunc(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.update(func(tx *bolt.Tx) error {
+		ledgers, err := tx.CreateBucketIfNotExists([]byte(LedgerTableName))
+		if err != nil {
+			return err
+		}
+		seq, _ := ledgers.NextSequence()
+		newLedger.Number = uint(seq)
+		ledgerJSON, _ := util.ToJSON(newLedger)
+		return ledgers.Put([]byte(name), ledgerJSON)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newLedger, nil
+}
+
+// CreateLedger creates a new ledger.
+func (s *BoltStore) CreateLedger(cocoonID, name string, chained, public bool) (*types.Ledger, error) {
+	return s.CreateLedgerThen(cocoonID, name, chained, public, nil)
+}
+
+// GetLedger fetches a ledger meta information
+func (s *BoltStore) GetLedger(name string) (*types.Ledger, error) {
+
+	var l *types.Ledger
+	err := s.view(func(tx *bolt.Tx) error {
+		ledgers := tx.Bucket([]byte(LedgerTableName))
+		if ledgers == nil {
+			return nil
+		}
+		ledgerJSON := ledgers.Get([]byte(name))
+		if ledgerJSON == nil {
+			return nil
+		}
+		l = &types.Ledger{}
+		return util.FromJSON(ledgerJSON, l)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger. %s", err)
+	}
+
+	return l, nil
+}
+
+// ListLedgers returns the ledgers owned by a cocoon in the order they were created
+func (s *BoltStore) ListLedgers(cocoonID string) ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(LedgerTableName))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var l types.Ledger
+			if err := util.FromJSON(v, &l); err != nil {
+				return err
+			}
+			if l.CocoonID == cocoonID {
+				ledgers = append(ledgers, &l)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	sort.Sort(ledgersByNumber(ledgers))
+
+	return ledgers, nil
+}
+
+// makeTxEntryKey creates the key of a transaction in its ledger bucket.
+// It is made up of the transaction key and the big endian transaction
+// number separated by a null byte.
+func makeTxEntryKey(key string, number uint) []byte {
+	num := make([]byte, 8)
+	binary.BigEndian.PutUint64(num, uint64(number))
+	return append([]byte(key+"\x00"), num...)
+}
+
+// getTxKeyFromEntryKey returns the transaction key of a ledger bucket key
+func getTxKeyFromEntryKey(entryKey []byte) string {
+	return string(entryKey[:len(entryKey)-9])
+}
+
+// txEntry is a raw ledger bucket entry and its transaction number
+type txEntry struct {
+	number uint64
+	value  []byte
+}
+
+// txEntriesByNumber sorts ledger bucket entries by transaction number
+type txEntriesByNumber []*txEntry
+
+func (e txEntriesByNumber) Len() int           { return len(e) }
+func (e txEntriesByNumber) Less(i, j int) bool { return e[i].number < e[j].number }
+func (e txEntriesByNumber) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+
+// ledgersByNumber sorts ledgers in the order they were created
+type ledgersByNumber []*types.Ledger
+
+func (l ledgersByNumber) Len() int           { return len(l) }
+func (l ledgersByNumber) Less(i, j int) bool { return l[i].Number < l[j].Number }
+func (l ledgersByNumber) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+
+// validateTxs validates the transactions to be stored in a ledger against the stored
+// transactions and the transactions already validated in the same write, and assigns
+// numbers to the valid ones. It returns a receipt for each transaction and the valid
+// transactions. lastNumber is the number of the last transaction assigned a number.
+func validateTxs(dbTx *bolt.Tx, ledgerName string, txs, batchTxs []*types.Transaction, lastNumber *uint64) ([]*types.TxReceipt, []*types.Transaction, error) {
+
+	var validTxs []*types.Transaction
+	txReceipts := []*types.TxReceipt{}
+
+	ids := dbTx.Bucket([]byte(TransactionIDIndexBucketName))
+	revisions := dbTx.Bucket([]byte(TransactionRevisionIndexBucketName))
+	if ids == nil || revisions == nil {
+		return nil, nil, fmt.Errorf("store is not initialized")
+	}
+
+	// get the revisions of the keys read by the transactions
+	latest := make(map[string]string)
+	ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledgerName))
+	for _, tx := range txs {
+		for _, read := range tx.ReadSet {
+			if _, ok := latest[read.Key]; ok || ledgerBucket == nil {
+				continue
+			}
+			if _, v := getLatestTxEntry(ledgerBucket, read.Key); v != nil {
+				var storedTx types.Transaction
+				if err := util.FromJSON(v, &storedTx); err != nil {
+					return nil, nil, err
+				}
+				latest[read.Key] = storedTx.ID
+			}
+		}
+	}
+
+	for _, tx := range txs {
+
+		txReceipt := &types.TxReceipt{ID: tx.ID}
+		txReceipts = append(txReceipts, txReceipt)
+
+		isDuplicate := ids.Get([]byte(tx.ID)) != nil
+		isStale := (len(tx.RevisionTo) > 0 && revisions.Get([]byte(tx.RevisionTo)) != nil) || isReadSetStale(tx, latest)
+		for _, vTx := range append(batchTxs, validTxs...) {
+			if tx.ID == vTx.ID {
+				isDuplicate = true
+			}
+			if len(tx.RevisionTo) > 0 && tx.RevisionTo == vTx.RevisionTo {
+				isStale = true
+			}
+		}
+
+		if isDuplicate {
+			log.Errorf("Failed to create transaction (%s): duplicate id", tx.ID)
+			txReceipt.Err = "transaction with matching id already exists"
+			continue
+		} else if isStale {
+			txReceipt.Err = "stale object"
+			continue
+		}
+
+		*lastNumber++
+		tx.Number = uint(*lastNumber)
+		if len(tx.Hash) == 0 {
+			tx.Hash = tx.MakeHash()
+		}
+		tx.Ledger = ledgerName
+		tx.ReadSet = nil
+		validTxs = append(validTxs, tx)
+		latest[tx.Key] = tx.ID
+	}
+
+	return txReceipts, validTxs, nil
+}
+
+// writeTxs writes validated transactions to a ledger and
+// updates the secondary index entries of their keys
+func writeTxs(dbTx *bolt.Tx, ledgerName string, validTxs []*types.Transaction) error {
+
+	txsBucket := dbTx.Bucket([]byte(TransactionTableName))
+	ledgerBucket, err := txsBucket.CreateBucketIfNotExists([]byte(ledgerName))
+	if err != nil {
+		return err
+	}
+
+	ids := dbTx.Bucket([]byte(TransactionIDIndexBucketName))
+	revisions := dbTx.Bucket([]byte(TransactionRevisionIndexBucketName))
+	for _, tx := range validTxs {
+		entryKey := makeTxEntryKey(tx.Key, tx.Number)
+		if err := ledgerBucket.Put(entryKey, encodeTx(tx)); err != nil {
+			return err
+		}
+		if err := ids.Put([]byte(tx.ID), append([]byte(ledgerName+"\x00"), entryKey...)); err != nil {
+			return err
+		}
+		if len(tx.RevisionTo) > 0 {
+			if err := revisions.Put([]byte(tx.RevisionTo), []byte(tx.ID)); err != nil {
+				return err
+			}
+		}
+	}
+
+	indexes, err := getLedgerIndexes(dbTx, ledgerName)
+	if err != nil {
+		return err
+	}
+
+	return indexTxs(dbTx, ledgerName, indexes, validTxs)
+}
+
+// makeIndexEntryKey creates the key of a secondary index entry in the index
+// bucket of a ledger. It is made up of the index name and the transaction key
+// separated by a null byte, so that the entries of an index are ordered by key.
+func makeIndexEntryKey(index, key string) []byte {
+	return []byte(index + "\x00" + key)
+}
+
+// getLedgerIndexes returns the secondary indexes of a ledger
+func getLedgerIndexes(dbTx *bolt.Tx, ledgerName string) (types.Indexes, error) {
+	ledgerJSON := dbTx.Bucket([]byte(LedgerTableName)).Get([]byte(ledgerName))
+	if ledgerJSON == nil {
+		return nil, nil
+	}
+	var l types.Ledger
+	if err := util.FromJSON(ledgerJSON, &l); err != nil {
+		return nil, err
+	}
+	return l.Indexes, nil
+}
+
+// storedTx is the encoding of a transaction in its ledger bucket. It includes
+// the index document, which is not part of the JSON encoding of a transaction.
+type storedTx struct {
+	*types.Transaction
+	IndexDoc string `json:"indexDoc,omitempty"`
+}
+
+// encodeTx encodes a transaction to store it in its ledger bucket
+func encodeTx(tx *types.Transaction) []byte {
+	txJSON, _ := util.ToJSON(&storedTx{Transaction: tx, IndexDoc: tx.IndexDoc})
+	return txJSON
+}
+
+// decodeTx decodes a transaction stored in its ledger bucket, including its index document
+func decodeTx(txJSON []byte, tx *types.Transaction) error {
+	stored := storedTx{Transaction: tx}
+	if err := util.FromJSON(txJSON, &stored); err != nil {
+		return err
+	}
+	tx.IndexDoc = stored.IndexDoc
+	return nil
+}
+
+// getIndexSource returns the JSON document the secondary indexes of a transaction
+// are extracted from. It is the index document of a transaction whose value is
+// encrypted (see types.MakeIndexDoc), otherwise the value of the transaction.
+func getIndexSource(tx *types.Transaction) string {
+	if len(tx.IndexDoc) > 0 {
+		return tx.IndexDoc
+	}
+	return tx.Value
+}
+
+// indexTxs sets the entry of each secondary index to the value at the index path of
+// the value of each transaction. Transactions must be in the order they are stored.
+// The entries of tombstones and values that do not include the path are removed.
+func indexTxs(dbTx *bolt.Tx, ledgerName string, indexes types.Indexes, txs []*types.Transaction) error {
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	indexBucket, err := dbTx.Bucket([]byte(IndexBucketName)).CreateBucketIfNotExists([]byte(ledgerName))
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		for _, index := range indexes {
+			entryKey := makeIndexEntryKey(index.Name, tx.Key)
+			value, ok := types.GetIndexValue(getIndexSource(tx), index.GetPath())
+			if tx.Deleted || !ok {
+				if err := indexBucket.Delete(entryKey); err != nil {
+					return err
+				}
+				continue
+			}
+			valueJSON, _ := util.ToJSON(value)
+			if err := indexBucket.Put(entryKey, valueJSON); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// getLastTxNumber returns the number of the last stored transaction
+func getLastTxNumber(dbTx *bolt.Tx) uint64 {
+	if n := dbTx.Bucket([]byte(storeMetaBucketName)).Get(lastTxNumberKey); n != nil {
+		return binary.BigEndian.Uint64(n)
+	}
+	return 0
+}
+
+// setLastTxNumber stores the number of the last stored transaction
+func setLastTxNumber(dbTx *bolt.Tx, number uint64) error {
+	num := make([]byte, 8)
+	binary.BigEndian.PutUint64(num, number)
+	return dbTx.Bucket([]byte(storeMetaBucketName)).Put(lastTxNumberKey, num)
+}
+
+// PutThen adds transactions to the store and returns a list of transaction receipts.
+// Any transaction that failed to be created will result in an error receipt being created
+// and returned along with success receipts of they successfully added transactions.
+// However, no transaction is stored if the `thenFunc` returns error. Only the
+// transaction that are successfully validated will be passed to the thenFunc.
+// The hash of a transaction is computed unless it is already set, as it is for
+// values that are encrypted after being hashed.
+func (s *BoltStore) PutThen(ledgerName string, txs []*types.Transaction, thenFunc func(validTxs []*types.Transaction) error) ([]*types.TxReceipt, error) {
+
+	var validTxs []*types.Transaction
+	var txReceipts []*types.TxReceipt
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	// validate the transactions against the stored transactions and
+	// assign numbers to the valid ones. Since writes are serialized, the
+	// stored transactions cannot change before the valid ones are written.
+	var lastNumber uint64
+	err := s.view(func(dbTx *bolt.Tx) error {
+		var err error
+		lastNumber = getLastTxNumber(dbTx)
+		txReceipts, validTxs, err = validateTxs(dbTx, ledgerName, txs, nil, &lastNumber)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// run the companion functions. Abort
+	// the transactions only if error was returned
+	if thenFunc != nil {
+		if err = thenFunc(validTxs); err != nil {
+			return txReceipts, err
+		}
+	}
+
+	err = s.update(func(dbTx *bolt.Tx) error {
+		if err := writeTxs(dbTx, ledgerName, validTxs); err != nil {
+			return err
+		}
+		return setLastTxNumber(dbTx, lastNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txReceipts, nil
+}
+
+// PutMultiThen atomically adds transactions to several ledgers and returns the
+// transaction receipts of each ledger in the order of the batch. If any transaction
+// fails, no transaction is stored and the valid transactions get an ErrBatchAborted
+// receipt. No transaction is stored if the `thenFunc` returns error.
+func (s *BoltStore) PutMultiThen(batch []*types.LedgerTransactions, thenFunc func(batch []*types.LedgerTransactions) error) ([][]*types.TxReceipt, error) {
+
+	txReceipts := make([][]*types.TxReceipt, len(batch))
+	validBatch := make([]*types.LedgerTransactions, len(batch))
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var lastNumber uint64
+	var failed bool
+	err := s.view(func(dbTx *bolt.Tx) error {
+		var batchTxs []*types.Transaction
+		lastNumber = getLastTxNumber(dbTx)
+		for i, group := range batch {
+			receipts, validTxs, err := validateTxs(dbTx, group.Ledger, group.Transactions, batchTxs, &lastNumber)
+			if err != nil {
+				return err
+			}
+			txReceipts[i] = receipts
+			validBatch[i] = &types.LedgerTransactions{Ledger: group.Ledger, Transactions: validTxs}
+			batchTxs = append(batchTxs, validTxs...)
+			failed = failed || len(validTxs) != len(group.Transactions)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if failed {
+		abortBatch(txReceipts)
+		return txReceipts, nil
+	}
+
+	if thenFunc != nil {
+		if err = thenFunc(validBatch); err != nil {
+			return txReceipts, err
+		}
+	}
+
+	err = s.update(func(dbTx *bolt.Tx) error {
+		for _, group := range validBatch {
+			if err := writeTxs(dbTx, group.Ledger, group.Transactions); err != nil {
+				return err
+			}
+		}
+		return setLastTxNumber(dbTx, lastNumber)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txReceipts, nil
+}
+
+// PutMulti atomically adds transactions to several ledgers.
+// Returns the transaction receipts of each ledger and a general error.
+func (s *BoltStore) PutMulti(batch []*types.LedgerTransactions) ([][]*types.TxReceipt, error) {
+	return s.PutMultiThen(batch, nil)
+}
+
+// Put creates one or more transactions associated to a ledger.
+// Returns a list of transaction receipts and a general error.
+func (s *BoltStore) Put(ledgerName string, txs []*types.Transaction) ([]*types.TxReceipt, error) {
+	return s.PutThen(ledgerName, txs, nil)
+}
+
+// getLatestTxEntry returns the ledger bucket entry of the most recent
+// transaction of a key. It returns nil if the key has no transaction.
+func getLatestTxEntry(ledgerBucket *bolt.Bucket, key string) ([]byte, []byte) {
+
+	// seek past the last possible entry of the key and step back
+	prefix := []byte(key + "\x00")
+	c := ledgerBucket.Cursor()
+	k, v := c.Seek(makeTxEntryKey(key, ^uint(0)))
+	if k == nil {
+		k, v = c.Last()
+	} else {
+		k, v = c.Prev()
+	}
+
+	if k == nil || !bytes.HasPrefix(k, prefix) || len(k) != len(prefix)+8 {
+		return nil, nil
+	}
+
+	return k, v
+}
+
+// Get fetches the most recent transaction of a key. If the most recent transaction
+// is a tombstone, nil is returned unless includeDeleted is true.
+func (s *BoltStore) Get(ledger, key string, includeDeleted bool) (*types.Transaction, error) {
+
+	var tx *types.Transaction
+	err := s.view(func(dbTx *bolt.Tx) error {
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		_, v := getLatestTxEntry(ledgerBucket, key)
+		if v == nil {
+			return nil
+		}
+
+		tx = &types.Transaction{}
+		return util.FromJSON(v, tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction. %s", err)
+	}
+
+	if tx != nil && tx.Deleted && !includeDeleted {
+		return nil, nil
+	}
+
+	return tx, nil
+}
+
+// GetByID fetches a transaction by its ledger and id
+func (s *BoltStore) GetByID(ledger, id string) (*types.Transaction, error) {
+
+	var tx *types.Transaction
+	err := s.view(func(dbTx *bolt.Tx) error {
+
+		// the index value is the ledger name and the ledger bucket key separated by a null byte
+		loc := dbTx.Bucket([]byte(TransactionIDIndexBucketName)).Get([]byte(id))
+		if loc == nil || !bytes.HasPrefix(loc, []byte(ledger+"\x00")) {
+			return nil
+		}
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		txJSON := ledgerBucket.Get(loc[len(ledger)+1:])
+		if txJSON == nil {
+			return nil
+		}
+
+		tx = &types.Transaction{}
+		return util.FromJSON(txJSON, tx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction. %s", err)
+	}
+
+	return tx, nil
+}
+
+// likeToRegexp converts an SQL LIKE pattern to an anchored regular expression
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var parts = strings.Split(pattern, "%")
+	for i, part := range parts {
+		parts[i] = strings.Replace(regexp.QuoteMeta(part), "_", ".", -1)
+	}
+	return regexp.MustCompile("(?s)^" + strings.Join(parts, ".*") + "$")
+}
+
+// GetRange fetches transactions with keys included in a specified range.
+// Only the most recent transaction of each key is returned, ordered by key.
+// If only the start key is provided, keys beginning with the start key are
+// matched. If only the end key is provided, it is used as an SQL LIKE pattern
+// to remain compatible with the postgres implementation. Keys whose most recent
+// transaction is a tombstone are excluded unless includeDeleted is true. Keys are
+// returned in descending order if reverse is true. If afterKey is set, only keys
+// after it in the requested order are returned.
+func (s *BoltStore) GetRange(ledger, startKey, endKey string, inclusive, includeDeleted, reverse bool, afterKey string, limit, offset int) ([]*types.Transaction, error) {
+
+	var match func(key string) bool
+	if len(startKey) > 0 && len(endKey) > 0 {
+		match = func(key string) bool {
+			if inclusive {
+				return key >= startKey && key <= endKey
+			}
+			return key >= startKey && key < endKey
+		}
+	} else if len(startKey) > 0 && len(endKey) == 0 {
+		match = func(key string) bool {
+			return strings.HasPrefix(key, startKey)
+		}
+	} else {
+		endKeyExpr := likeToRegexp(endKey)
+		match = func(key string) bool {
+			return endKeyExpr.MatchString(key)
+		}
+	}
+
+	// collect the most recent transaction of every matching key
+	latest := map[string][]byte{}
+	err := s.view(func(dbTx *bolt.Tx) error {
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+		return ledgerBucket.ForEach(func(k, v []byte) error {
+			if key := getTxKeyFromEntryKey(k); match(key) {
+				latest[key] = v
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions. %s", err)
+	}
+
+	var keys []string
+	for key := range latest {
+		if len(afterKey) > 0 && ((!reverse && key <= afterKey) || (reverse && key >= afterKey)) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if reverse {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+
+	var txs []*types.Transaction
+	var skipped int
+	for _, key := range keys {
+		if len(txs) == limit {
+			break
+		}
+		var tx types.Transaction
+		if err := util.FromJSON(latest[key], &tx); err != nil {
+			return nil, fmt.Errorf("failed to get transactions. %s", err)
+		}
+		if tx.Deleted && !includeDeleted {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		txs = append(txs, &tx)
+	}
+
+	return txs, nil
+}
+
+// GetHistory fetches all the transactions of a key, most recent first.
+// Set cursor to the number of the last transaction of a previously fetched
+// page to get older transactions. A cursor of zero starts from the most recent
+// transaction. If limit is zero or less, all transactions are returned.
+func (s *BoltStore) GetHistory(ledger, key string, limit int, cursor uint) ([]*types.Transaction, error) {
+
+	if cursor == 0 {
+		cursor = ^uint(0)
+	}
+
+	var txs []*types.Transaction
+	err := s.view(func(dbTx *bolt.Tx) error {
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		// seek to the cursor entry and walk back through older entries of the key
+		prefix := []byte(key + "\x00")
+		c := ledgerBucket.Cursor()
+		k, v := c.Seek(makeTxEntryKey(key, cursor))
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, prefix) && len(k) == len(prefix)+8; k, v = c.Prev() {
+			if limit > 0 && len(txs) == limit {
+				break
+			}
+			var tx types.Transaction
+			if err := util.FromJSON(v, &tx); err != nil {
+				return err
+			}
+			txs = append(txs, &tx)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history. %s", err)
+	}
+
+	return txs, nil
+}
+
+// GetLastTxNumber returns the number of the most recently
+// stored transaction of any ledger or zero if there is none
+func (s *BoltStore) GetLastTxNumber() (uint, error) {
+
+	var number uint64
+	err := s.view(func(dbTx *bolt.Tx) error {
+		number = getLastTxNumber(dbTx)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last transaction number. %s", err)
+	}
+
+	return uint(number), nil
+}
+
+// ListTransactions returns all transactions of a ledger, including previous
+// revisions and tombstones, in the order they were stored. Only transactions
+// with a number greater than afterNumber are returned. If limit is zero or
+// negative, all matching transactions are returned.
+func (s *BoltStore) ListTransactions(ledger string, afterNumber uint, limit int) ([]*types.Transaction, error) {
+
+	// entries of a ledger bucket are ordered by key, so
+	// they are collected and sorted by transaction number
+	var entries txEntriesByNumber
+	err := s.view(func(dbTx *bolt.Tx) error {
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+		return ledgerBucket.ForEach(func(k, v []byte) error {
+			if number := binary.BigEndian.Uint64(k[len(k)-8:]); number > uint64(afterNumber) {
+				entries = append(entries, &txEntry{number, v})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions. %s", err)
+	}
+
+	sort.Sort(entries)
+
+	var txs []*types.Transaction
+	for _, e := range entries {
+		if limit > 0 && len(txs) == limit {
+			break
+		}
+		var tx types.Transaction
+		if err := util.FromJSON(e.value, &tx); err != nil {
+			return nil, fmt.Errorf("failed to list transactions. %s", err)
+		}
+		txs = append(txs, &tx)
+	}
+
+	return txs, nil
+}
+
+// SetLedgerRetention sets the retention policy of a ledger. A zero
+// keepRevisions and keepFor removes the policy.
+func (s *BoltStore) SetLedgerRetention(name string, keepRevisions uint, keepFor int64) (*types.Ledger, error) {
+
+	var l *types.Ledger
+	err := s.update(func(tx *bolt.Tx) error {
+		ledgers := tx.Bucket([]byte(LedgerTableName))
+		ledgerJSON := ledgers.Get([]byte(name))
+		if ledgerJSON == nil {
+			return types.ErrLedgerNotFound
+		}
+		l = &types.Ledger{}
+		if err := util.FromJSON(ledgerJSON, l); err != nil {
+			return err
+		}
+		l.KeepRevisions = keepRevisions
+		l.KeepFor = keepFor
+		ledgerJSON, _ = util.ToJSON(l)
+		return ledgers.Put([]byte(name), ledgerJSON)
+	})
+	if err == types.ErrLedgerNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to set ledger retention. %s", err)
+	}
+
+	return l, nil
+}
+
+// ListRetainedLedgers returns the ledgers that have a retention policy
+func (s *BoltStore) ListRetainedLedgers() ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(LedgerTableName)).ForEach(func(k, v []byte) error {
+			var l types.Ledger
+			if err := util.FromJSON(v, &l); err != nil {
+				return err
+			}
+			if l.HasRetention() {
+				ledgers = append(ledgers, &l)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	sort.Sort(ledgersByNumber(ledgers))
+
+	return ledgers, nil
+}
+
+// ListPrunableTransactions returns the ids of the superseded transactions of a ledger
+// that are outside its retention policy, in the order they were stored. A transaction
+// is prunable if more than keepRevisions newer revisions of its key exist or it was created
+// before the unix time before. A zero keepRevisions or before disables the respective check.
+// The most recent revision of a key is never returned. If limit is zero or negative,
+// all prunable transactions are returned.
+func (s *BoltStore) ListPrunableTransactions(ledger string, keepRevisions uint, before int64, limit int) ([]string, error) {
+
+	// the entries of a key are adjacent and ordered by transaction number,
+	// so each key's revisions are ranked once its last entry is reached
+	var prunable txEntriesByNumber
+	err := s.view(func(dbTx *bolt.Tx) error {
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		var curKey string
+		var revisions []*types.Transaction
+		rankRevisions := func() {
+			for i, tx := range revisions {
+				rank := uint(len(revisions) - i)
+				if rank == 1 {
+					continue
+				}
+				if (keepRevisions > 0 && rank > keepRevisions) || tx.CreatedAt < before {
+					prunable = append(prunable, &txEntry{uint64(tx.Number), []byte(tx.ID)})
+				}
+			}
+		}
+
+		err := ledgerBucket.ForEach(func(k, v []byte) error {
+			if key := getTxKeyFromEntryKey(k); key != curKey {
+				rankRevisions()
+				curKey, revisions = key, nil
+			}
+			var tx types.Transaction
+			if err := util.FromJSON(v, &tx); err != nil {
+				return err
+			}
+			revisions = append(revisions, &tx)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		rankRevisions()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prunable transactions. %s", err)
+	}
+
+	sort.Sort(prunable)
+
+	var ids []string
+	for _, e := range prunable {
+		if limit > 0 && len(ids) == limit {
+			break
+		}
+		ids = append(ids, string(e.value))
+	}
+
+	return ids, nil
+}
+
+// DeleteTransactions removes transactions of a ledger by their ids.
+// It returns the number of transactions removed.
+func (s *BoltStore) DeleteTransactions(ledger string, ids []string) (int, error) {
+
+	var deleted int
+	err := s.update(func(dbTx *bolt.Tx) error {
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		idIndex := dbTx.Bucket([]byte(TransactionIDIndexBucketName))
+		revisions := dbTx.Bucket([]byte(TransactionRevisionIndexBucketName))
+		for _, id := range ids {
+			loc := idIndex.Get([]byte(id))
+			if loc == nil || !bytes.HasPrefix(loc, []byte(ledger+"\x00")) {
+				continue
+			}
+
+			// the revision index entry added by the transaction is removed
+			// like the revision_to column of a deleted postgres row
+			var tx types.Transaction
+			if err := util.FromJSON(ledgerBucket.Get(loc[len(ledger)+1:]), &tx); err != nil {
+				return err
+			}
+			if len(tx.RevisionTo) > 0 {
+				if err := revisions.Delete([]byte(tx.RevisionTo)); err != nil {
+					return err
+				}
+			}
+
+			if err := ledgerBucket.Delete(loc[len(ledger)+1:]); err != nil {
+				return err
+			}
+			if err := idIndex.Delete([]byte(id)); err != nil {
+				return err
+			}
+			deleted++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete transactions. %s", err)
+	}
+
+	return deleted, nil
+}
+
+// SetTransactionValues replaces the values and index documents of stored transactions of
+// a ledger with the ones of the given transactions with the same ids. The hashes of the
+// transactions are unchanged, so it must only be used to change the encoding of values,
+// such as to encrypt them. It returns the number of transactions updated.
+func (s *BoltStore) SetTransactionValues(ledger string, txs []*types.Transaction) (int, error) {
+
+	var updated int
+	err := s.update(func(dbTx *bolt.Tx) error {
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		idIndex := dbTx.Bucket([]byte(TransactionIDIndexBucketName))
+		for _, t := range txs {
+			loc := idIndex.Get([]byte(t.ID))
+			if loc == nil || !bytes.HasPrefix(loc, []byte(ledger+"\x00")) {
+				continue
+			}
+
+			var tx types.Transaction
+			if err := decodeTx(ledgerBucket.Get(loc[len(ledger)+1:]), &tx); err != nil {
+				return err
+			}
+			tx.Value = t.Value
+			tx.IndexDoc = t.IndexDoc
+			if err := ledgerBucket.Put(loc[len(ledger)+1:], encodeTx(&tx)); err != nil {
+				return err
+			}
+			updated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to set transaction values. %s", err)
+	}
+
+	return updated, nil
+}
+
+// SetLedgerDataKey replaces the wrapped data key of a ledger if the current data key
+// matches oldDataKey. It returns false if the data key was changed by another writer.
+func (s *BoltStore) SetLedgerDataKey(name, oldDataKey, newDataKey string) (bool, error) {
+
+	var updated bool
+	err := s.update(func(tx *bolt.Tx) error {
+		ledgers := tx.Bucket([]byte(LedgerTableName))
+		ledgerJSON := ledgers.Get([]byte(name))
+		if ledgerJSON == nil {
+			return nil
+		}
+		var l types.Ledger
+		if err := util.FromJSON(ledgerJSON, &l); err != nil {
+			return err
+		}
+		if l.DataKey != oldDataKey {
+			return nil
+		}
+		l.DataKey = newDataKey
+		ledgerJSON, _ = util.ToJSON(l)
+		updated = true
+		return ledgers.Put([]byte(name), ledgerJSON)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to set ledger data key. %s", err)
+	}
+
+	return updated, nil
+}
+
+// ListEncryptedLedgers returns the ledgers that have a data key
+func (s *BoltStore) ListEncryptedLedgers() ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(LedgerTableName)).ForEach(func(k, v []byte) error {
+			var l types.Ledger
+			if err := util.FromJSON(v, &l); err != nil {
+				return err
+			}
+			if len(l.DataKey) > 0 {
+				ledgers = append(ledgers, &l)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	sort.Sort(ledgersByNumber(ledgers))
+
+	return ledgers, nil
+}
+
+// SetLedgerIndexes sets the secondary indexes of a ledger. The index entries
+// of the ledger are rebuilt from the most recent transaction of each key. Index
+// documents are not stored, so encrypted values are only indexed when put.
+func (s *BoltStore) SetLedgerIndexes(name string, indexes types.Indexes) (*types.Ledger, error) {
+
+	if err := indexes.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var l *types.Ledger
+	err := s.update(func(dbTx *bolt.Tx) error {
+
+		ledgers := dbTx.Bucket([]byte(LedgerTableName))
+		ledgerJSON := ledgers.Get([]byte(name))
+		if ledgerJSON == nil {
+			return types.ErrLedgerNotFound
+		}
+		l = &types.Ledger{}
+		if err := util.FromJSON(ledgerJSON, l); err != nil {
+			return err
+		}
+		l.Indexes = indexes
+		ledgerJSON, _ = util.ToJSON(l)
+		if err := ledgers.Put([]byte(name), ledgerJSON); err != nil {
+			return err
+		}
+
+		if err := dbTx.Bucket([]byte(IndexBucketName)).DeleteBucket([]byte(name)); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(name))
+		if ledgerBucket == nil {
+			return nil
+		}
+
+		// entries are ordered by key and number, so the
+		// last entry of each key is its most recent transaction
+		var latest []*types.Transaction
+		var lastKey string
+		err := ledgerBucket.ForEach(func(k, v []byte) error {
+			var tx types.Transaction
+			if err := decodeTx(v, &tx); err != nil {
+				return err
+			}
+			if key := getTxKeyFromEntryKey(k); len(latest) > 0 && key == lastKey {
+				latest[len(latest)-1] = &tx
+			} else {
+				latest = append(latest, &tx)
+				lastKey = key
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return indexTxs(dbTx, name, indexes, latest)
+	})
+	if err == types.ErrLedgerNotFound {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to set ledger indexes. %s", err)
+	}
+
+	return l, nil
+}
+
+// Query fetches the most recent transaction of the keys whose value at the path of
+// a secondary index matches a query. value is JSON encoded and is compared to the
+// indexed values as described by types.MatchQuery. Tombstones are excluded.
+// Transactions are ordered by key. If afterKey is set, only keys after it are returned.
+func (s *BoltStore) Query(ledger, index, op, value, afterKey string, limit int) ([]*types.Transaction, error) {
+
+	queryValue, err := types.ValidateQuery(op, value)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.GetLedger(ledger)
+	if err != nil {
+		return nil, err
+	} else if l == nil {
+		return nil, types.ErrLedgerNotFound
+	} else if l.Indexes.Get(index) == nil {
+		return nil, types.ErrIndexNotFound
+	}
+
+	var txs []*types.Transaction
+	err = s.view(func(dbTx *bolt.Tx) error {
+
+		indexBucket := dbTx.Bucket([]byte(IndexBucketName)).Bucket([]byte(ledger))
+		ledgerBucket := dbTx.Bucket([]byte(TransactionTableName)).Bucket([]byte(ledger))
+		if indexBucket == nil || ledgerBucket == nil {
+			return nil
+		}
+
+		prefix := makeIndexEntryKey(index, "")
+		c := indexBucket.Cursor()
+		for k, v := c.Seek(makeIndexEntryKey(index, afterKey)); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+
+			if len(txs) == limit {
+				break
+			}
+
+			key := string(k[len(prefix):])
+			if key <= afterKey {
+				continue
+			}
+
+			var indexed interface{}
+			if err := util.FromJSON(v, &indexed); err != nil {
+				return err
+			}
+			if !types.MatchQuery(indexed, op, queryValue) {
+				continue
+			}
+
+			_, txJSON := getLatestTxEntry(ledgerBucket, key)
+			if txJSON == nil {
+				continue
+			}
+			var tx types.Transaction
+			if err := util.FromJSON(txJSON, &tx); err != nil {
+				return err
+			}
+			if !tx.Deleted {
+				txs = append(txs, &tx)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions. %s", err)
+	}
+
+	return txs, nil
+}
+
+// Transact calls fn with a store and blockchain whose operations are made in a
+// single read-write transaction. The transaction is committed if fn returns nil and
+// rolled back otherwise. The blockchain implementation must use the database of the
+// store. Bolt has no savepoints, so a failed operation must fail the transaction.
+func (s *BoltStore) Transact(fn func(store types.Store, blockchain types.Blockchain) error) error {
+
+	if s.tx != nil {
+		return fn(s, s.blockchain)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		blockchain, err := s.blockchain.WithDBTx(tx)
+		if err != nil {
+			return err
+		}
+		return fn(&BoltStore{db: s.db, tx: tx, blockchain: blockchain}, blockchain)
+	})
+}
+
+// Close releases the database handle
+func (s *BoltStore) Close() error {
+	if s.db != nil {
+		return common.CloseBoltDB(s.db)
+	}
+	return nil
+}
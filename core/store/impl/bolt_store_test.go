@@ -0,0 +1,940 @@
+package impl
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var boltConStr = "bolt://" + path.Join(os.TempDir(), "test_store_"+util.RandString(5)+".db")
+
+func TestBoltStore(t *testing.T) {
+
+	os.Setenv("ENV", "test")
+	defer Destroy(boltConStr)
+
+	boltStore := new(BoltStore)
+	_, err := boltStore.Connect(boltConStr)
+	if err != nil {
+		t.Fatal("failed to connect to bolt store")
+	}
+	defer boltStore.Close()
+
+	boltChain := new(impl.BoltBlockchain)
+	_, err = boltChain.Connect(boltConStr)
+	if err != nil {
+		t.Fatal("failed to connect to bolt blockchain")
+	}
+	defer boltChain.Close()
+
+	err = boltChain.Init()
+	if err != nil {
+		t.Fatal("failed to initialize bolt blockchain")
+	}
+
+	Convey("BoltStore", t, func() {
+
+		boltStore.SetBlockchainImplementation(boltChain)
+
+		Convey(".Init", func() {
+			err := boltStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+			So(err, ShouldBeNil)
+			ledger, err := boltStore.GetLedger(types.GetSystemPublicLedgerName())
+			So(err, ShouldBeNil)
+			So(ledger, ShouldNotBeNil)
+			So(ledger.Public, ShouldEqual, true)
+			ledger, err = boltStore.GetLedger(types.GetSystemPrivateLedgerName())
+			So(err, ShouldBeNil)
+			So(ledger, ShouldNotBeNil)
+			So(ledger.Public, ShouldEqual, false)
+		})
+
+		Convey(".CreateLedger", func() {
+
+			var ledgerName = util.RandString(10)
+
+			Convey("should successfully create a ledger", func() {
+				ledger, err := boltStore.CreateLedger(util.RandString(10), ledgerName, true, true)
+				So(err, ShouldBeNil)
+				So(ledger, ShouldNotBeNil)
+				So(ledger.Chained, ShouldEqual, true)
+				So(ledger.Public, ShouldEqual, true)
+
+				chain, err := boltChain.GetChain(ledgerName)
+				So(err, ShouldBeNil)
+				So(chain, ShouldNotBeNil)
+
+				Convey("should return error since a ledger with same name already exists", func() {
+					_, err := boltStore.CreateLedger(util.RandString(10), ledgerName, false, false)
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, `ledger with matching name already exists`)
+				})
+			})
+		})
+
+		Convey(".CreateLedgerThen", func() {
+			var ledgerName = util.RandString(10)
+
+			Convey("should fail to create a ledger if thenFunction returns an error", func() {
+				var ErrFromThenFunc = fmt.Errorf("thenFunc error")
+				ledger, err := boltStore.CreateLedgerThen("cocoon_id", ledgerName, false, true, func() error {
+					return ErrFromThenFunc
+				})
+				So(err, ShouldNotBeNil)
+				So(err, ShouldResemble, ErrFromThenFunc)
+				So(ledger, ShouldBeNil)
+
+				ledger, err = boltStore.GetLedger(ledgerName)
+				So(err, ShouldBeNil)
+				So(ledger, ShouldBeNil)
+			})
+
+			Convey("should successfully create a ledger if then function does not return error", func() {
+				ledger, err := boltStore.CreateLedgerThen(util.RandString(5), ledgerName, true, true, func() error {
+					return nil
+				})
+				So(err, ShouldBeNil)
+				So(ledger, ShouldNotBeNil)
+				So(ledger.Chained, ShouldEqual, true)
+				So(ledger.Public, ShouldEqual, true)
+			})
+		})
+
+		Convey(".GetLedger", func() {
+
+			Convey("should return nil when ledger does not exist", func() {
+				ledger, err := boltStore.GetLedger("wrong_name")
+				So(ledger, ShouldBeNil)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("should return existing ledger", func() {
+				name := util.RandString(10)
+				ledger, err := boltStore.CreateLedger("abc", name, true, true)
+				So(err, ShouldBeNil)
+				So(ledger, ShouldNotBeNil)
+
+				found, err := boltStore.GetLedger(name)
+				So(found, ShouldNotBeNil)
+				So(err, ShouldBeNil)
+				So(found.Number, ShouldEqual, ledger.Number)
+				So(found.CocoonID, ShouldEqual, ledger.CocoonID)
+			})
+		})
+
+		Convey(".Put", func() {
+
+			Convey("expects new transaction to be stored with expected fields set", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(len(receipts), ShouldEqual, 1)
+				So(receipts[0].Err, ShouldBeEmpty)
+
+				stored, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(stored.Hash, ShouldNotEqual, "")
+				So(stored.ID, ShouldEqual, tx.ID)
+				So(stored.Ledger, ShouldEqual, ledger)
+				So(stored.Key, ShouldEqual, "key")
+				So(stored.Value, ShouldEqual, "value")
+			})
+
+			Convey("expects a transaction with an already used id to fail", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				tx2 := &types.Transaction{ID: tx.ID, Key: "key", Value: "value2"}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				So(receipts[1].Err, ShouldEqual, "transaction with matching id already exists")
+			})
+
+			Convey("expects a transaction revising an already revised transaction to be stale", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value2", RevisionTo: tx.ID}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value3", RevisionTo: tx.ID}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx2, tx3})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				So(receipts[1].Err, ShouldEqual, "stale object")
+
+				tx4 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value4", RevisionTo: tx.ID}
+				receipts, err = boltStore.Put(ledger, []*types.Transaction{tx4})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldEqual, "stale object")
+
+				stored, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(stored.Value, ShouldEqual, "value2")
+			})
+		})
+
+		Convey(".PutThen", func() {
+
+			ledger := util.RandString(5)
+			_, err = boltStore.CreateLedger(util.RandString(5), ledger, true, true)
+			So(err, ShouldBeNil)
+
+			Convey("Should fail and store nothing if thenFunc returns error", func() {
+				var ErrThenFunc = fmt.Errorf("thenFunc error")
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := boltStore.PutThen(ledger, []*types.Transaction{tx}, func([]*types.Transaction) error {
+					return ErrThenFunc
+				})
+				So(err, ShouldResemble, ErrThenFunc)
+
+				stored, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(stored, ShouldBeNil)
+			})
+
+			Convey("Should successfully add transaction and create a block if thenFunc does not return error", func() {
+				var block *types.Block
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				txs, err := boltStore.PutThen(ledger, []*types.Transaction{tx}, func(validTxs []*types.Transaction) error {
+					var err error
+					block, err = boltChain.CreateBlock(util.RandString(10), ledger, validTxs)
+					return err
+				})
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Err, ShouldBeEmpty)
+				So(txs[0].ID, ShouldEqual, tx.ID)
+				So(block, ShouldNotBeNil)
+				So(block.Number, ShouldEqual, 1)
+			})
+		})
+
+		Convey(".PutMultiThen", func() {
+
+			ledgerA, ledgerB := util.RandString(5), util.RandString(5)
+			debit := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "alice", Value: "90"}
+			credit := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "bob", Value: "110"}
+
+			Convey("Should store the transactions of every ledger", func() {
+				var validBatch []*types.LedgerTransactions
+				receipts, err := boltStore.PutMultiThen([]*types.LedgerTransactions{
+					{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+					{Ledger: ledgerB, Transactions: []*types.Transaction{credit}},
+				}, func(batch []*types.LedgerTransactions) error {
+					validBatch = batch
+					return nil
+				})
+				So(err, ShouldBeNil)
+				So(len(receipts), ShouldEqual, 2)
+				So(receipts[0][0].Err, ShouldBeEmpty)
+				So(receipts[1][0].Err, ShouldBeEmpty)
+				So(len(validBatch), ShouldEqual, 2)
+				So(validBatch[1].Ledger, ShouldEqual, ledgerB)
+
+				stored, _ := boltStore.Get(ledgerA, "alice", false)
+				So(stored.Value, ShouldEqual, "90")
+				stored, _ = boltStore.Get(ledgerB, "bob", false)
+				So(stored.Value, ShouldEqual, "110")
+			})
+
+			Convey("Should store nothing if a transaction in one ledger fails", func() {
+				_, err := boltStore.Put(ledgerB, []*types.Transaction{{ID: util.Sha256(util.UUID4()), Key: "bob", Value: "100"}})
+				So(err, ShouldBeNil)
+				credit.ReadSet = []*types.KeyRevision{{Key: "bob", Revision: ""}}
+
+				receipts, err := boltStore.PutMulti([]*types.LedgerTransactions{
+					{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+					{Ledger: ledgerB, Transactions: []*types.Transaction{credit}},
+				})
+				So(err, ShouldBeNil)
+				So(receipts[0][0].Err, ShouldEqual, types.ErrBatchAborted.Error())
+				So(receipts[1][0].Err, ShouldEqual, "stale object")
+
+				stored, _ := boltStore.Get(ledgerA, "alice", false)
+				So(stored, ShouldBeNil)
+				stored, _ = boltStore.GetByID(ledgerA, debit.ID)
+				So(stored, ShouldBeNil)
+			})
+
+			Convey("Should store nothing if thenFunc returns error", func() {
+				_, err := boltStore.PutMultiThen([]*types.LedgerTransactions{
+					{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+					{Ledger: ledgerB, Transactions: []*types.Transaction{credit}},
+				}, func([]*types.LedgerTransactions) error {
+					return fmt.Errorf("thenFunc error")
+				})
+				So(err, ShouldNotBeNil)
+				stored, _ := boltStore.Get(ledgerB, "bob", false)
+				So(stored, ShouldBeNil)
+			})
+
+			Convey("Should reject a transaction id used twice in the batch", func() {
+				dup := &types.Transaction{ID: debit.ID, Key: "carol", Value: "1"}
+				receipts, err := boltStore.PutMulti([]*types.LedgerTransactions{
+					{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+					{Ledger: ledgerB, Transactions: []*types.Transaction{dup}},
+				})
+				So(err, ShouldBeNil)
+				So(receipts[1][0].Err, ShouldEqual, "transaction with matching id already exists")
+			})
+		})
+
+		Convey("Read-sets", func() {
+
+			ledger := util.RandString(5)
+			tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "10"}
+			_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+			So(err, ShouldBeNil)
+
+			Convey("Should store a transaction whose read-set is current", func() {
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{
+					{Key: "balance", Revision: tx.ID},
+					{Key: "unknown", Revision: ""},
+				}}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx2})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				stored, _ := boltStore.Get(ledger, "total", false)
+				So(stored.ReadSet, ShouldBeNil)
+			})
+
+			Convey("Should return a stale object receipt if a key was revised after it was read", func() {
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20", RevisionTo: tx.ID}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx2})
+				So(err, ShouldBeNil)
+
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{{Key: "balance", Revision: tx.ID}}}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx3})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldEqual, "stale object")
+			})
+
+			Convey("Should return a stale object receipt if a key read by a transaction is written earlier in the batch", func() {
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20", RevisionTo: tx.ID}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{{Key: "balance", Revision: tx.ID}}}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx2, tx3})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				So(receipts[1].Err, ShouldEqual, "stale object")
+			})
+
+			Convey("Should return a stale object receipt if a key that was absent when read has been written", func() {
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{{Key: "balance", Revision: ""}}}
+				receipts, err := boltStore.Put(ledger, []*types.Transaction{tx2})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldEqual, "stale object")
+			})
+		})
+
+		Convey(".Get", func() {
+
+			Convey("should return nil when transaction does not exist", func() {
+				tx, err := boltStore.Get(types.GetSystemPublicLedgerName(), "wrong_key", false)
+				So(tx, ShouldBeNil)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("should return the most recent transaction of a key", func() {
+				ledger := util.Sha256(util.RandString(5))
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key.b", Value: "value.b"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2})
+				So(err, ShouldBeNil)
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value2"}
+				_, err = boltStore.Put(ledger, []*types.Transaction{tx3})
+				So(err, ShouldBeNil)
+
+				found, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				So(found.Hash, ShouldEqual, tx3.Hash)
+			})
+			Convey("should hide a deleted key unless deleted transactions are included", func() {
+				ledger := util.Sha256(util.RandString(5))
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				tombstone := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", RevisionTo: tx.ID, Deleted: true}
+				_, err = boltStore.Put(ledger, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+
+				found, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+
+				found, err = boltStore.Get(ledger, "key", true)
+				So(err, ShouldBeNil)
+				So(found.ID, ShouldEqual, tombstone.ID)
+				So(found.Deleted, ShouldBeTrue)
+			})
+		})
+
+		Convey(".GetRange", func() {
+
+			Convey("Should successfully return expected transactions and exclude end key when `includeEndKey` is false", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.glen", Value: "200"}
+				tx4 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "z", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3, tx4})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "a", "z", false, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 3)
+			})
+
+			Convey("Should successfully return expected transactions and include end key when `includeEndKey` is true", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.glen", Value: "200"}
+				tx4 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "z", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3, tx4})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "account", "z", true, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 4)
+			})
+
+			Convey("Should successfully return transactions with matching startKey if only startKey is provided", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.glen", Value: "200"}
+				tx4 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "z", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3, tx4})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "account", "", true, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 3)
+			})
+
+			Convey("Should successfully return transactions with matching endKey if only endKey is provided", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "ben.account", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "glen.account", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "", "%account", true, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+			})
+
+			Convey("Should successfully return expected transaction limit ", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "ben.account", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "glen.account", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "", "%account", true, false, false, "", 1, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "ben.account")
+
+				txs, err = boltStore.GetRange(ledger, "", "%account", true, false, false, "", 1, 1)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "glen.account")
+			})
+
+			Convey("Should exclude deleted keys unless deleted transactions are included", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.glen", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+				tombstone := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", RevisionTo: tx.ID, Deleted: true}
+				_, err = boltStore.Put(ledger, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "account", "", false, false, false, "", 1, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "account.glen")
+
+				txs, err = boltStore.GetRange(ledger, "account", "", false, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+
+				txs, err = boltStore.GetRange(ledger, "account", "", false, true, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 3)
+				So(txs[0].Deleted, ShouldBeTrue)
+			})
+
+			Convey("Should return transactions in descending key order when reverse is true", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:1", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:2", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:3", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "order:", "", false, false, true, "", 2, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Key, ShouldEqual, "order:3")
+				So(txs[1].Key, ShouldEqual, "order:2")
+			})
+
+			Convey("Should return only transactions after the given key", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:1", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:2", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:3", Value: "200"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetRange(ledger, "order:", "", false, false, false, "order:1", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Key, ShouldEqual, "order:2")
+
+				txs, err = boltStore.GetRange(ledger, "order:", "", false, false, true, "order:2", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "order:1")
+			})
+		})
+
+		Convey(".GetByID", func() {
+
+			Convey("Should return the transaction with matching id in the ledger", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				found, err := boltStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				So(found.Value, ShouldEqual, "value")
+
+				found, err = boltStore.GetByID(util.Sha256(util.UUID4()), tx.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+			})
+		})
+
+		Convey(".GetHistory", func() {
+
+			Convey("Should return all transactions of a key, most recent first", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value1"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key2", Value: "value"}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx, tx2})
+				So(err, ShouldBeNil)
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value2", RevisionTo: tx.ID}
+				_, err = boltStore.Put(ledger, []*types.Transaction{tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := boltStore.GetHistory(ledger, "key", 0, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].ID, ShouldEqual, tx3.ID)
+				So(txs[1].ID, ShouldEqual, tx.ID)
+			})
+
+			Convey("Should return transactions older than the cursor", func() {
+				ledger := util.Sha256(util.UUID4())
+				for _, v := range []string{"a", "b", "c"} {
+					_, err := boltStore.Put(ledger, []*types.Transaction{{ID: util.Sha256(util.UUID4()), Key: "key", Value: v}})
+					So(err, ShouldBeNil)
+				}
+
+				txs, err := boltStore.GetHistory(ledger, "key", 2, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Value, ShouldEqual, "c")
+				So(txs[1].Value, ShouldEqual, "b")
+
+				txs, err = boltStore.GetHistory(ledger, "key", 2, txs[1].Number)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Value, ShouldEqual, "a")
+			})
+
+			Convey("Should return empty result if key does not exist", func() {
+				txs, err := boltStore.GetHistory(util.Sha256(util.UUID4()), "unknown", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 0)
+			})
+		})
+
+		Convey(".ListLedgers", func() {
+			Convey("Should return the ledgers of a cocoon in the order they were created", func() {
+				cocoonID := util.RandString(10)
+				_, err := boltStore.CreateLedger(cocoonID, util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+				ledger2, err := boltStore.CreateLedger(cocoonID, util.RandString(10), false, true)
+				So(err, ShouldBeNil)
+				_, err = boltStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+
+				ledgers, err := boltStore.ListLedgers(cocoonID)
+				So(err, ShouldBeNil)
+				So(len(ledgers), ShouldEqual, 2)
+				So(ledgers[1].Name, ShouldEqual, ledger2.Name)
+			})
+		})
+
+		Convey(".ListTransactions", func() {
+			Convey("Should return all transactions of a ledger in the order they were stored", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "b", Value: "1"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: "2"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "b", Value: "3", RevisionTo: tx.ID}
+				for _, t := range []*types.Transaction{tx, tx2, tx3} {
+					_, err := boltStore.Put(ledger, []*types.Transaction{t})
+					So(err, ShouldBeNil)
+				}
+
+				txs, err := boltStore.ListTransactions(ledger, 0, 2)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].ID, ShouldEqual, tx.ID)
+				So(txs[1].ID, ShouldEqual, tx2.ID)
+
+				txs, err = boltStore.ListTransactions(ledger, txs[1].Number, 2)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].ID, ShouldEqual, tx3.ID)
+			})
+		})
+
+		Convey(".SetTransactionValues", func() {
+			Convey("Should replace the values of transactions and keep their hashes", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: "1"}
+				tx.Hash = tx.MakeHash()
+				hash := tx.Hash
+				tx.Value = "sealed"
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				stored, err := boltStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(stored.Hash, ShouldEqual, hash)
+
+				updated, err := boltStore.SetTransactionValues(ledger, []*types.Transaction{{ID: tx.ID, Value: "resealed"}, {ID: util.Sha256(util.UUID4()), Value: "x"}})
+				So(err, ShouldBeNil)
+				So(updated, ShouldEqual, 1)
+
+				stored, err = boltStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(stored.Value, ShouldEqual, "resealed")
+				So(stored.Hash, ShouldEqual, hash)
+			})
+		})
+
+		Convey(".SetLedgerRetention", func() {
+			Convey("Should set the retention policy of a ledger", func() {
+				ledger, err := boltStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+				ledger, err = boltStore.SetLedgerRetention(ledger.Name, 2, 3600)
+				So(err, ShouldBeNil)
+				So(ledger.KeepRevisions, ShouldEqual, 2)
+				So(ledger.KeepFor, ShouldEqual, 3600)
+
+				ledgers, err := boltStore.ListRetainedLedgers()
+				So(err, ShouldBeNil)
+				So(ledgers[len(ledgers)-1].Name, ShouldEqual, ledger.Name)
+			})
+
+			Convey("Should return error if ledger does not exist", func() {
+				_, err := boltStore.SetLedgerRetention("unknown", 2, 0)
+				So(err, ShouldEqual, types.ErrLedgerNotFound)
+			})
+		})
+
+		Convey(".ListPrunableTransactions and .DeleteTransactions", func() {
+
+			ledger := util.Sha256(util.UUID4())
+			var revisions []*types.Transaction
+			for i := 0; i < 4; i++ {
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: fmt.Sprintf("%d", i), CreatedAt: int64(100 + i)}
+				if i > 0 {
+					tx.RevisionTo = revisions[i-1].ID
+				}
+				_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				revisions = append(revisions, tx)
+			}
+			other := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "b", Value: "1", CreatedAt: 50}
+			_, err := boltStore.Put(ledger, []*types.Transaction{other})
+			So(err, ShouldBeNil)
+
+			Convey("Should return revisions beyond the number of revisions to keep", func() {
+				ids, err := boltStore.ListPrunableTransactions(ledger, 2, 0, 0)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{revisions[0].ID, revisions[1].ID})
+			})
+
+			Convey("Should return superseded revisions created before a time", func() {
+				ids, err := boltStore.ListPrunableTransactions(ledger, 0, 102, 0)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{revisions[0].ID, revisions[1].ID})
+
+				ids, err = boltStore.ListPrunableTransactions(ledger, 0, 1000, 1)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{revisions[0].ID})
+			})
+
+			Convey("Should delete transactions and keep the most recent revision", func() {
+				ids, err := boltStore.ListPrunableTransactions(ledger, 1, 0, 0)
+				So(err, ShouldBeNil)
+				So(len(ids), ShouldEqual, 3)
+
+				deleted, err := boltStore.DeleteTransactions(ledger, ids)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldEqual, 3)
+
+				txs, err := boltStore.GetHistory(ledger, "a", 0, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].ID, ShouldEqual, revisions[3].ID)
+
+				found, err := boltStore.GetByID(ledger, revisions[0].ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+
+				deleted, err = boltStore.DeleteTransactions(ledger, ids)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldEqual, 0)
+			})
+
+			Convey("Should remove the revision index entries of deleted transactions", func() {
+				ids, err := boltStore.ListPrunableTransactions(ledger, 1, 0, 0)
+				So(err, ShouldBeNil)
+				_, err = boltStore.DeleteTransactions(ledger, ids)
+				So(err, ShouldBeNil)
+
+				boltStore.db.View(func(dbTx *bolt.Tx) error {
+					revisionIndex := dbTx.Bucket([]byte(TransactionRevisionIndexBucketName))
+					So(revisionIndex.Get([]byte(revisions[0].ID)), ShouldBeNil)
+					So(revisionIndex.Get([]byte(revisions[1].ID)), ShouldBeNil)
+					So(string(revisionIndex.Get([]byte(revisions[2].ID))), ShouldEqual, revisions[3].ID)
+					return nil
+				})
+			})
+		})
+
+		Convey(".SetLedgerIndexes and .Query", func() {
+
+			ledger, err := boltStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+			So(err, ShouldBeNil)
+
+			put := func(key, value string) *types.Transaction {
+				tx := &types.Transaction{ID: util.UUID4(), Key: key, Value: value}
+				receipts, err := boltStore.Put(ledger.Name, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				return tx
+			}
+
+			query := func(index, op, value, afterKey string, limit int) []string {
+				txs, err := boltStore.Query(ledger.Name, index, op, value, afterKey, limit)
+				So(err, ShouldBeNil)
+				var keys []string
+				for _, tx := range txs {
+					keys = append(keys, tx.Key)
+				}
+				return keys
+			}
+
+			// values stored before the indexes are set must be indexed
+			put("a", `{"age": 30, "owner": {"name": "ann"}}`)
+			put("b", `{"age": 20, "owner": {"name": "ben"}}`)
+			put("c", "not json")
+
+			_, err = boltStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "age"}, {Name: "by_owner", Path: "owner.name"}})
+			So(err, ShouldBeNil)
+
+			put("d", `{"age": "30"}`)
+			put("e", `{"age": 40}`)
+
+			Convey("Should return the keys whose indexed value matches the query", func() {
+				So(query("by_age", ">", "25", "", 10), ShouldResemble, []string{"a", "e"})
+				So(query("by_age", "=", "30", "", 10), ShouldResemble, []string{"a"})
+				So(query("by_age", "=", `"30"`, "", 10), ShouldResemble, []string{"d"})
+				So(query("by_age", "<=", "20", "", 10), ShouldResemble, []string{"b"})
+				So(query("by_owner", "=", `"ben"`, "", 10), ShouldResemble, []string{"b"})
+			})
+
+			Convey("Should only match the most recent value of a key", func() {
+				put("a", `{"age": 10}`)
+				So(query("by_age", ">", "25", "", 10), ShouldResemble, []string{"e"})
+				So(query("by_age", "<", "25", "", 10), ShouldResemble, []string{"a", "b"})
+				So(query("by_owner", "=", `"ann"`, "", 10), ShouldBeEmpty)
+			})
+
+			Convey("Should not match deleted keys", func() {
+				b, _ := boltStore.Get(ledger.Name, "b", false)
+				tombstone := &types.Transaction{ID: util.UUID4(), Key: "b", RevisionTo: b.ID, Deleted: true}
+				_, err := boltStore.Put(ledger.Name, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+				So(query("by_age", "<", "25", "", 10), ShouldBeEmpty)
+			})
+
+			Convey("Should compare strings and order keys by their bytes", func() {
+				put("f", `{"owner": {"name": "Zed"}}`)
+				put("B", `{"age": 50}`)
+				So(query("by_owner", "<", `"ann"`, "", 10), ShouldResemble, []string{"f"})
+				So(query("by_owner", ">=", `"ann"`, "", 10), ShouldResemble, []string{"a", "b"})
+				So(query("by_age", ">=", "40", "", 10), ShouldResemble, []string{"B", "e"})
+			})
+
+			Convey("Should index the index document of a transaction whose value is encrypted", func() {
+				tx := &types.Transaction{ID: util.UUID4(), Key: "g", Value: "enc:v1:c2VjcmV0", IndexDoc: `{"age": 60}`}
+				_, err := boltStore.Put(ledger.Name, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(query("by_age", "=", "60", "", 10), ShouldResemble, []string{"g"})
+			})
+
+			Convey("Should index the index document set with the encrypted value of a transaction", func() {
+				tx := put("g", `{"age": 60}`)
+				indexes := types.Indexes{{Name: "by_age", Path: "age"}, {Name: "by_owner", Path: "owner.name"}}
+				updated, err := boltStore.SetTransactionValues(ledger.Name, []*types.Transaction{{ID: tx.ID, Value: "enc:v1:c2VjcmV0", IndexDoc: types.MakeIndexDoc(tx.Value, indexes)}})
+				So(err, ShouldBeNil)
+				So(updated, ShouldEqual, 1)
+
+				// re-index the values of the ledger
+				_, err = boltStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "age"}})
+				So(err, ShouldBeNil)
+				So(query("by_age", "=", "60", "", 10), ShouldResemble, []string{"g"})
+			})
+
+			Convey("Should page through matching keys", func() {
+				So(query("by_age", ">=", "20", "", 2), ShouldResemble, []string{"a", "b"})
+				So(query("by_age", ">=", "20", "b", 2), ShouldResemble, []string{"e"})
+			})
+
+			Convey("Should return error if the index does not exist or the query is invalid", func() {
+				_, err := boltStore.Query(ledger.Name, "unknown", "=", "1", "", 10)
+				So(err, ShouldEqual, types.ErrIndexNotFound)
+				_, err = boltStore.Query(ledger.Name, "by_age", "~", "1", "", 10)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unknown query operator: ~")
+				_, err = boltStore.Query(ledger.Name, "by_age", ">", "{}", "", 10)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should return error if an index is invalid", func() {
+				_, err := boltStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "age"}, {Name: "by_age", Path: "x"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "index by_age: duplicate index name")
+				_, err = boltStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "a..b"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "index by_age: invalid path")
+			})
+		})
+
+		Convey(".SetLedgerDataKey", func() {
+			Convey("Should only replace the data key if it matches the expected data key", func() {
+				ledger, err := boltStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+
+				updated, err := boltStore.SetLedgerDataKey(ledger.Name, "", "key1:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeTrue)
+
+				updated, err = boltStore.SetLedgerDataKey(ledger.Name, "", "key2:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeFalse)
+
+				updated, err = boltStore.SetLedgerDataKey(ledger.Name, "key1:wrapped", "key2:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeTrue)
+
+				found, err := boltStore.GetLedger(ledger.Name)
+				So(err, ShouldBeNil)
+				So(found.DataKey, ShouldEqual, "key2:wrapped")
+
+				ledgers, err := boltStore.ListEncryptedLedgers()
+				So(err, ShouldBeNil)
+				So(ledgers[len(ledgers)-1].Name, ShouldEqual, ledger.Name)
+			})
+		})
+
+		Convey(".Transact", func() {
+
+			Convey("Should commit the transactions and blocks of all operations", func() {
+				ledger := util.Sha256(util.UUID4())
+				_, err := boltStore.CreateLedger(types.SystemCocoonID, ledger, true, true)
+				So(err, ShouldBeNil)
+
+				err = boltStore.Transact(func(store types.Store, blockchain types.Blockchain) error {
+					tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+					_, err := store.PutThen(ledger, []*types.Transaction{tx}, func(validTxs []*types.Transaction) error {
+						_, err := blockchain.CreateBlock(util.UUID4(), ledger, validTxs)
+						return err
+					})
+					return err
+				})
+				So(err, ShouldBeNil)
+
+				found, err := boltStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				height, err := boltChain.GetChainHeight(ledger)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 1)
+			})
+
+			Convey("Should write nothing if fn returns an error", func() {
+				ledger := util.Sha256(util.UUID4())
+				err := boltStore.Transact(func(store types.Store, blockchain types.Blockchain) error {
+					if _, err := store.CreateLedger(types.SystemCocoonID, ledger, true, true); err != nil {
+						return err
+					}
+					tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+					if _, err := store.Put(ledger, []*types.Transaction{tx}); err != nil {
+						return err
+					}
+					return fmt.Errorf("abort")
+				})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "abort")
+
+				found, err := boltStore.GetLedger(ledger)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+				chain, err := boltChain.GetChain(ledger)
+				So(err, ShouldBeNil)
+				So(chain, ShouldBeNil)
+			})
+		})
+
+		Convey(".Clear", func() {
+			ledger := util.Sha256(util.UUID4())
+			tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+			_, err := boltStore.Put(ledger, []*types.Transaction{tx})
+			So(err, ShouldBeNil)
+
+			err = Clear(boltConStr)
+			So(err, ShouldBeNil)
+
+			found, err := boltStore.Get(ledger, "key", false)
+			So(err, ShouldBeNil)
+			So(found, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/cocoon/core/store/migration"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/jinzhu/gorm"
+)
+
+// storeSchemaComponent is the name under which the migrations of the store are recorded
+const storeSchemaComponent = "store"
+
+// storeMigrations are the schema migrations of the postgres store.
+// New migrations must be appended with the next version number.
+var storeMigrations = []*migration.Migration{
+	{
+		Version:     1,
+		Description: "create ledgers and transactions tables",
+		Up: func(tx *gorm.DB) error {
+			if has, err := migration.HasTable(tx, LedgerTableName); err != nil {
+				return err
+			} else if !has {
+				if err := tx.CreateTable(&types.Ledger{}).Error; err != nil {
+					return fmt.Errorf("failed to create `%s` table. %s", LedgerTableName, err)
+				}
+			}
+			if has, err := migration.HasTable(tx, TransactionTableName); err != nil {
+				return err
+			} else if !has {
+				if err := tx.CreateTable(&types.Transaction{}).
+					AddIndex("idx_name_ledger_key_created_at", "ledger", "key", "created_at").Error; err != nil {
+					return fmt.Errorf("failed to create `%s` table. %s", TransactionTableName, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "add deleted column to transactions",
+		Up: func(tx *gorm.DB) error {
+			return migration.AddColumn(tx, TransactionTableName, "deleted", "boolean DEFAULT false")
+		},
+	},
+	{
+		Version:     3,
+		Description: "add keep_revisions and keep_for columns to ledgers",
+		Up: func(tx *gorm.DB) error {
+			if err := migration.AddColumn(tx, LedgerTableName, "keep_revisions", "integer DEFAULT 0"); err != nil {
+				return err
+			}
+			return migration.AddColumn(tx, LedgerTableName, "keep_for", "bigint DEFAULT 0")
+		},
+	},
+	{
+		Version:     4,
+		Description: "add data_key column to ledgers",
+		Up: func(tx *gorm.DB) error {
+			return migration.AddColumn(tx, LedgerTableName, "data_key", "text")
+		},
+	},
+	{
+		Version:     5,
+		Description: "add indexes column to ledgers and the json path function of secondary indexes",
+		Up: func(tx *gorm.DB) error {
+			if err := migration.AddColumn(tx, LedgerTableName, "indexes", "text"); err != nil {
+				return err
+			}
+			if err := tx.Exec(createJSONPathFunc).Error; err != nil {
+				return fmt.Errorf("failed to create `%s` function. %s", jsonPathFuncName, err)
+			}
+			return nil
+		},
+	},
+	{
+		Version:     6,
+		Description: "add index_doc column to transactions and extract indexed values from it",
+		Up: func(tx *gorm.DB) error {
+			if err := migration.AddColumn(tx, TransactionTableName, "index_doc", "text"); err != nil {
+				return err
+			}
+
+			// the expression indexes of existing secondary indexes are
+			// recreated with the expression that reads the index document
+			var ledgers []*types.Ledger
+			if err := tx.Where("COALESCE(indexes, '') != ''").Find(&ledgers).Error; err != nil && err != gorm.ErrRecordNotFound {
+				return fmt.Errorf("failed to list indexed ledgers. %s", err)
+			}
+			for _, ledger := range ledgers {
+				for _, index := range ledger.Indexes {
+					if err := tx.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS %s`, makeJSONIndexName(ledger.Name, index.Name))).Error; err != nil {
+						return fmt.Errorf("failed to drop index %s. %s", index.Name, err)
+					}
+					if err := createJSONIndex(tx, ledger.Name, index); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// Migrator returns the migrator of the schema of the store
+func (s *PostgresStore) Migrator() *migration.Migrator {
+	return migration.New(s.db, storeSchemaComponent, storeMigrations)
+}
@@ -0,0 +1,205 @@
+package impl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/blockchain/impl"
+	"github.com/ellcrys/cocoon/core/store/migration"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	"github.com/jinzhu/gorm"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// legacyLedger is the ledger table as created by Init before migrations were introduced
+type legacyLedger struct {
+	Number    uint   `gorm:"primary_key"`
+	Name      string `gorm:"type:varchar(128);unique_index:idx_name_name"`
+	CocoonID  string `gorm:"index:idx_name_cocoon_id"`
+	Public    bool
+	Chained   bool
+	CreatedAt int64 `gorm:"index:idx_name_created_at"`
+}
+
+func (legacyLedger) TableName() string { return LedgerTableName }
+
+// legacyTransaction is the transaction table as created by Init before migrations were introduced
+type legacyTransaction struct {
+	Number     uint   `gorm:"primary_key"`
+	Ledger     string `gorm:"type:varchar(128);index:idx_name_ledger_name"`
+	ID         string `gorm:"type:varchar(64);unique_index:idx_name_id"`
+	Key        string `gorm:"type:varchar(128);index:idx_name_key"`
+	Value      string `gorm:"type:text"`
+	Hash       string `gorm:"type:varchar(64);unique_index:idx_name_hash"`
+	BlockID    string
+	RevisionTo string `gorm:"type:varchar(64);unique_index:idx_name_revision_to" sql:"DEFAULT:NULL"`
+	CreatedAt  int64  `gorm:"index:idx_name_created_at"`
+}
+
+func (legacyTransaction) TableName() string { return TransactionTableName }
+
+// legacyBlock is the block table as created by Init before migrations were introduced
+type legacyBlock struct {
+	PK            uint   `gorm:"primary_key"`
+	ID            string `gorm:"type:varchar(64);unique_index:idx_name_id"`
+	Number        uint   `sql:"DEFAULT:0"`
+	ChainName     string `gorm:"index:idx_name_chain_name"`
+	PrevBlockHash string `gorm:"type:varchar(64);unique_index:idx_name_prev_block_hash"`
+	Hash          string `gorm:"type:varchar(64);unique_index:idx_name_hash"`
+	Transactions  []byte
+	CreatedAt     int64 `gorm:"index:idx_name_created_at"`
+}
+
+func (legacyBlock) TableName() string { return impl.BlockTableName }
+
+// createLegacySchema creates the tables of the store and blockchain as
+// they were created by Init before migrations were introduced
+func createLegacySchema(db *gorm.DB) {
+	db.CreateTable(&legacyLedger{})
+	db.CreateTable(&legacyTransaction{}).AddIndex("idx_name_ledger_key_created_at", "ledger", "key", "created_at")
+	db.CreateTable(&types.Chain{})
+	db.CreateTable(&legacyBlock{}).AddIndex("idx_name_chain_name_id", "chain_name", "id")
+}
+
+func TestPostgresMigrations(t *testing.T) {
+
+	migrationDBName := "test_migrations_" + strings.ToLower(util.RandString(5))
+	if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s;", migrationDBName)); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec(fmt.Sprintf("DROP DATABASE %s;", migrationDBName))
+
+	migrationConStr := util.Env("STORE_CON_STR", "host=localhost database="+migrationDBName+" user=ned sslmode=disable password=")
+
+	pgStore := new(PostgresStore)
+	dbCon, err := pgStore.Connect(migrationConStr)
+	if err != nil {
+		t.Fatal("failed to connect to pg store")
+	}
+	defer pgStore.Close()
+	gormDB := dbCon.(*gorm.DB)
+
+	pgChain := new(impl.PostgresBlockchain)
+	if _, err = pgChain.Connect(migrationConStr); err != nil {
+		t.Fatal("failed to connect to pg blockchain")
+	}
+	defer pgChain.Close()
+	pgStore.SetBlockchainImplementation(pgChain)
+
+	resetDB := func() {
+		gormDB.DropTableIfExists(&types.Ledger{}, &types.Transaction{}, &types.Chain{}, &types.Block{}, &types.Checkpoint{}, &migration.SchemaVersion{})
+	}
+
+	Convey("Postgres migrations", t, func() {
+
+		resetDB()
+
+		Convey("On a new database", func() {
+
+			Convey("Init should create the tables and record every migration", func() {
+				So(pgChain.Init(), ShouldBeNil)
+				So(pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName()), ShouldBeNil)
+
+				version, err := pgStore.Migrator().Version()
+				So(err, ShouldBeNil)
+				So(version, ShouldEqual, storeMigrations[len(storeMigrations)-1].Version)
+				version, err = pgChain.Migrator().Version()
+				So(err, ShouldBeNil)
+				So(version, ShouldEqual, 5)
+
+				pending, err := pgStore.Migrator().Pending()
+				So(err, ShouldBeNil)
+				So(pending, ShouldBeEmpty)
+				So(gormDB.Dialect().HasColumn(TransactionTableName, "deleted"), ShouldBeTrue)
+			})
+		})
+
+		Convey("On a database created before migrations were introduced", func() {
+
+			createLegacySchema(gormDB)
+
+			Convey("Init should fail until the migrations are applied", func() {
+				err := pgChain.Init()
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "blockchain schema is out of date: 5 migration(s) pending. Run `orderer migrate` to apply them")
+				err = pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "store schema is out of date")
+			})
+
+			Convey("A dry run should report the pending migrations without changing the database", func() {
+				applied, err := pgStore.Migrator().Migrate(true)
+				So(err, ShouldBeNil)
+				So(len(applied), ShouldEqual, 6)
+				So(applied[1].Description, ShouldEqual, "add deleted column to transactions")
+
+				version, _ := pgStore.Migrator().Version()
+				So(version, ShouldEqual, 0)
+				So(gormDB.Dialect().HasColumn(TransactionTableName, "deleted"), ShouldBeFalse)
+			})
+
+			Convey("Migrate should add the new columns and allow Init to succeed", func() {
+				applied, err := pgChain.Migrator().Migrate(false)
+				So(err, ShouldBeNil)
+				So(len(applied), ShouldEqual, 5)
+				applied, err = pgStore.Migrator().Migrate(false)
+				So(err, ShouldBeNil)
+				So(len(applied), ShouldEqual, 6)
+
+				So(gormDB.Dialect().HasColumn(TransactionTableName, "deleted"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(LedgerTableName, "keep_revisions"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(LedgerTableName, "data_key"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(LedgerTableName, "indexes"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(TransactionTableName, "index_doc"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(impl.CheckpointTableName, "number"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(impl.ChainTableName, "leader"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(impl.BlockTableName, "merkle_root"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(impl.BlockTableName, "signature"), ShouldBeTrue)
+				So(gormDB.Dialect().HasColumn(impl.BlockTableName, "key_id"), ShouldBeTrue)
+
+				So(pgChain.Init(), ShouldBeNil)
+				So(pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName()), ShouldBeNil)
+
+				Convey("Should store and read tombstones", func() {
+					ledger := util.RandString(5)
+					_, err := pgStore.CreateLedger(types.SystemCocoonID, ledger, false, false)
+					So(err, ShouldBeNil)
+					tx := &types.Transaction{ID: util.UUID4(), Key: "key", Value: "value"}
+					_, err = pgStore.Put(ledger, []*types.Transaction{tx})
+					So(err, ShouldBeNil)
+					_, err = pgStore.Put(ledger, []*types.Transaction{{ID: util.UUID4(), Key: "key", RevisionTo: tx.ID, Deleted: true}})
+					So(err, ShouldBeNil)
+					found, err := pgStore.Get(ledger, "key", false)
+					So(err, ShouldBeNil)
+					So(found, ShouldBeNil)
+				})
+
+				Convey("Migrate should do nothing if the schema is up to date", func() {
+					applied, err := pgStore.Migrator().Migrate(false)
+					So(err, ShouldBeNil)
+					So(applied, ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("A failed migration should roll back every pending migration", func() {
+			migrator := migration.New(gormDB, "test", []*migration.Migration{
+				{Version: 2, Description: "fails", Up: func(tx *gorm.DB) error {
+					return fmt.Errorf("bad migration")
+				}},
+				{Version: 1, Description: "creates a table", Up: func(tx *gorm.DB) error {
+					return tx.Exec("CREATE TABLE migration_test (id int)").Error
+				}},
+			})
+			_, err := migrator.Migrate(false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "migration 2 (fails) failed. bad migration")
+			So(gormDB.HasTable("migration_test"), ShouldBeFalse)
+			version, err := migrator.Version()
+			So(err, ShouldBeNil)
+			So(version, ShouldEqual, 0)
+		})
+	})
+}
@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/store/migration"
 	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres" // gorm requires it
-	"github.com/kr/pretty"
 	logging "github.com/op/go-logging"
 )
 
@@ -31,11 +34,23 @@ const TransactionTableName = "transactions"
 // PostgresStore defines a store implementation
 // on the postgres database. It implements the Store interface
 type PostgresStore struct {
-	db         *gorm.DB
-	blockchain types.Blockchain
-	locker     types.Lock
+	db          *gorm.DB
+	blockchain  types.Blockchain
+	locker      types.Lock
+	concurrency string
 }
 
+// Concurrency control modes of the postgres store
+const (
+	// ConcurrencyLock guards the key of each transaction
+	// with a distributed lock while it is stored
+	ConcurrencyLock = "lock"
+
+	// ConcurrencyMVCC validates the revisions read and revised by a batch of
+	// transactions within the database transaction that stores them
+	ConcurrencyMVCC = "mvcc"
+)
+
 // SetBlockchainImplementation sets sets a reference of the blockchain implementation
 func (s *PostgresStore) SetBlockchainImplementation(b types.Blockchain) {
 	s.blockchain = b
@@ -57,26 +72,32 @@ func (s *PostgresStore) Connect(dbAddr string) (interface{}, error) {
 
 	s.db.LogMode(false)
 
+	if err = s.SetConcurrency(util.Env("STORE_CONCURRENCY", ConcurrencyLock)); err != nil {
+		return nil, err
+	}
+
 	return s.db, nil
 }
 
+// SetConcurrency sets how concurrent writes to the same key are controlled.
+// Accepted modes are ConcurrencyLock and ConcurrencyMVCC.
+func (s *PostgresStore) SetConcurrency(mode string) error {
+	if mode != ConcurrencyLock && mode != ConcurrencyMVCC {
+		return fmt.Errorf("unknown concurrency mode: %s", mode)
+	}
+	s.concurrency = mode
+	return nil
+}
+
 // Init initializes the types. Creates the necessary tables such as the
-// the tables and public and private system ledgers
+// the tables and public and private system ledgers. The tables of an existing
+// database are not changed; Init fails if the database has pending migrations.
 func (s *PostgresStore) Init(systemPublicLedgerName, systemPrivateLedgerName string) error {
 
-	// create ledger table if not existing
-	if !s.db.HasTable(LedgerTableName) {
-		if err := s.db.CreateTable(&types.Ledger{}).Error; err != nil {
-			return fmt.Errorf("failed to create `%s` table. %s", LedgerTableName, err)
-		}
-	}
-
-	// create transaction table if not existing
-	if !s.db.HasTable(TransactionTableName) {
-		if err := s.db.CreateTable(&types.Transaction{}).
-			AddIndex("idx_name_ledger_key_created_at", "ledger", "key", "created_at").Error; err != nil {
-			return fmt.Errorf("failed to create `%s` table. %s", TransactionTableName, err)
-		}
+	// create the tables of a new database or ensure
+	// the schema of an existing database is up to date
+	if err := s.Migrator().Ensure(!s.db.HasTable(LedgerTableName)); err != nil {
+		return err
 	}
 
 	// create system ledgers
@@ -101,7 +122,8 @@ func (s *PostgresStore) Init(systemPublicLedgerName, systemPrivateLedgerName str
 	return nil
 }
 
-// Destroy removes the database tables.
+// Destroy removes the database tables. For the embedded
+// bolt backend, the database file is removed.
 // Will only work in a test environment (Test Only!!!)
 func Destroy(dbAddr string) error {
 
@@ -109,15 +131,28 @@ func Destroy(dbAddr string) error {
 		return fmt.Errorf("Cowardly refusing to do it! Can only call Destroy() in test environment")
 	}
 
+	if common.IsBoltConStr(dbAddr) {
+		return os.Remove(common.GetBoltDBPath(dbAddr))
+	}
+
 	db, err := gorm.Open("postgres", dbAddr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to store backend. %s", err)
 	}
 
-	return db.DropTable(types.Ledger{}, types.Transaction{}).Error
+	if err := db.DropTable(types.Ledger{}, types.Transaction{}).Error; err != nil {
+		return err
+	}
+
+	// forget the migrations of the dropped tables
+	if db.HasTable(migration.VersionTableName) {
+		return db.Where("component = ?", storeSchemaComponent).Delete(&migration.SchemaVersion{}).Error
+	}
+
+	return nil
 }
 
-// Clear the database tables.
+// Clear the database tables. Also supports the embedded bolt backend.
 // Will only work in a test environment (Test Only!!!)
 func Clear(dbAddr string) error {
 
@@ -125,6 +160,10 @@ func Clear(dbAddr string) error {
 		return fmt.Errorf("Cowardly refusing to do it! Can only call Destroy() in test environment")
 	}
 
+	if common.IsBoltConStr(dbAddr) {
+		return clearBolt(dbAddr)
+	}
+
 	db, err := gorm.Open("postgres", dbAddr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to store backend")
@@ -148,7 +187,7 @@ func Clear(dbAddr string) error {
 // transaction is rolled back and error returned
 func (s *PostgresStore) CreateLedgerThen(cocoonID, name string, chained, public bool, thenFunc func() error) (*types.Ledger, error) {
 
-	tx := s.db.Begin()
+	tx := common.BeginPgTx(s.db)
 
 	newLedger := &types.Ledger{
 		Name:      name,
@@ -186,7 +225,9 @@ func (s *PostgresStore) CreateLedgerThen(cocoonID, name string, chained, public
 		}
 	}
 
-	tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return newLedger, nil
 }
@@ -211,24 +252,234 @@ func (s *PostgresStore) GetLedger(name string) (*types.Ledger, error) {
 	return &l, nil
 }
 
+// ListLedgers returns the ledgers owned by a cocoon in the order they were created
+func (s *PostgresStore) ListLedgers(cocoonID string) ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.db.Where("cocoon_id = ?", cocoonID).Order("number asc").Find(&ledgers).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	return ledgers, nil
+}
+
 // makeTxLockKey constructs a lock key using a transaction key and ledger name
 func makeTxLockKey(ledgerName, key string) string {
 	return fmt.Sprintf("tx/key/%s/%s", ledgerName, key)
 }
 
+// getLatestRevisions returns the id of the most recent transaction of each key
+// in a ledger. Keys that have never been written are not included.
+func getLatestRevisions(db *gorm.DB, ledgerName string, keys []string) (map[string]string, error) {
+
+	latest := make(map[string]string)
+	if len(keys) == 0 {
+		return latest, nil
+	}
+
+	rows, err := db.Raw(`SELECT DISTINCT ON (key) key, id FROM "transactions" WHERE ledger = ? AND key IN (?) ORDER BY key, created_at desc, number desc`, ledgerName, keys).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest revisions. %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, fmt.Errorf("failed to get latest revisions. %s", err)
+		}
+		latest[key] = id
+	}
+
+	return latest, rows.Err()
+}
+
+// lockLatestRevisions locks the most recent transaction of each key with
+// `SELECT ... FOR UPDATE` and returns the revisions found once the locks are held.
+// Writers and readers of a key lock the same row, so a transaction whose read-set
+// was validated cannot be invalidated by a concurrent revision before it commits.
+// Keys that have never been written have no row to lock.
+func lockLatestRevisions(db *gorm.DB, ledgerName string, keys []string) (map[string]string, error) {
+
+	latest, err := getLatestRevisions(db, ledgerName, keys)
+	if err != nil || len(latest) == 0 {
+		return latest, err
+	}
+
+	var ids []string
+	for _, id := range latest {
+		ids = append(ids, id)
+	}
+
+	// rows are locked in id order so that concurrent writers cannot deadlock
+	if err := db.Exec(`SELECT id FROM "transactions" WHERE ledger = ? AND id IN (?) ORDER BY id FOR UPDATE`, ledgerName, ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock revisions. %s", err)
+	}
+
+	// a key may have been revised while waiting for its lock
+	return getLatestRevisions(db, ledgerName, keys)
+}
+
+// isRevisedInBatch checks whether a valid transaction of the batch
+// already revises the revision a transaction revises
+func isRevisedInBatch(tx *types.Transaction, validTxs []*types.Transaction) bool {
+	if len(tx.RevisionTo) == 0 {
+		return false
+	}
+	for _, vTx := range validTxs {
+		if tx.RevisionTo == vTx.RevisionTo {
+			return true
+		}
+	}
+	return false
+}
+
+// makeCreateTxErr returns the receipt error of a transaction that failed to be inserted
+func makeCreateTxErr(err error) string {
+	switch {
+	case common.IsUniqueConstraintError(err, "revision_to"):
+		return "stale object"
+	case common.IsUniqueConstraintError(err, "id"):
+		return "transaction with matching id already exists"
+	default:
+		return "failed to create transaction"
+	}
+}
+
 // PutThen adds transactions to the store and returns a list of transaction receipts.
 // Any transaction that failed to be created will result in an error receipt being created
 // and returned along with success receipts of they successfully added transactions.
 // However, all transactions will be rolled back if the `thenFunc` returns error. Only the
 // transaction that are successfully added will be passed to the thenFunc.
 // Future work may allow the caller to determine the behaviour via an additional parameter.
+//
+// A transaction whose read-set includes a key that has been revised since it was
+// read is not stored and gets a `stale object` receipt. How writers to the same key
+// are isolated depends on the concurrency mode of the store (see SetConcurrency).
+// The hash of a transaction is computed unless it is already set, as it is for
+// values that are encrypted after being hashed.
 func (s *PostgresStore) PutThen(ledgerName string, txs []*types.Transaction, thenFunc func(validTxss []*types.Transaction) error) ([]*types.TxReceipt, error) {
 
-	var err error
+	dbTx := common.BeginPgTx(s.db)
+
+	txReceipts, validTxs, err := s.putTxs(dbTx.DB, []*types.LedgerTransactions{{Ledger: ledgerName, Transactions: txs}})
+	if err != nil {
+		dbTx.Rollback()
+		return nil, err
+	}
+
+	// run the companion functions. Rollback
+	// the transactions only if error was returned
+	if thenFunc != nil {
+		if err = thenFunc(validTxs[0]); err != nil {
+			dbTx.Rollback()
+			return txReceipts[0], err
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return txReceipts[0], nil
+}
+
+// PutMultiThen atomically adds transactions to several ledgers and returns the
+// transaction receipts of each ledger in the order of the batch. If any transaction
+// fails, the database transaction is rolled back and the valid transactions get an
+// ErrBatchAborted receipt. All transactions are also rolled back if the `thenFunc`
+// returns error.
+func (s *PostgresStore) PutMultiThen(batch []*types.LedgerTransactions, thenFunc func(batch []*types.LedgerTransactions) error) ([][]*types.TxReceipt, error) {
+
+	dbTx := common.BeginPgTx(s.db)
+
+	txReceipts, validTxs, err := s.putTxs(dbTx.DB, batch)
+	if err != nil {
+		dbTx.Rollback()
+		return nil, err
+	}
+
+	validBatch := make([]*types.LedgerTransactions, len(batch))
+	for i, group := range batch {
+		if len(validTxs[i]) != len(group.Transactions) {
+			dbTx.Rollback()
+			abortBatch(txReceipts)
+			return txReceipts, nil
+		}
+		validBatch[i] = &types.LedgerTransactions{Ledger: group.Ledger, Transactions: validTxs[i]}
+	}
+
+	if thenFunc != nil {
+		if err = thenFunc(validBatch); err != nil {
+			dbTx.Rollback()
+			return txReceipts, err
+		}
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return txReceipts, nil
+}
+
+// PutMulti atomically adds transactions to several ledgers.
+// Returns the transaction receipts of each ledger and a general error.
+func (s *PostgresStore) PutMulti(batch []*types.LedgerTransactions) ([][]*types.TxReceipt, error) {
+	return s.PutMultiThen(batch, nil)
+}
+
+// putTxs creates the transactions of each ledger of a batch in a database transaction
+// using the concurrency mode of the store. It returns the receipts and the valid
+// transactions of each ledger.
+func (s *PostgresStore) putTxs(dbTx *gorm.DB, batch []*types.LedgerTransactions) ([][]*types.TxReceipt, [][]*types.Transaction, error) {
+
+	txReceipts := make([][]*types.TxReceipt, len(batch))
+	validTxs := make([][]*types.Transaction, len(batch))
+
+	// in MVCC mode, the keys of all ledgers are locked in sorted
+	// order so that concurrent batches sharing keys cannot deadlock
+	if s.concurrency == ConcurrencyMVCC {
+		var lockKeys []string
+		for _, group := range batch {
+			for _, key := range getBatchKeys(group.Transactions) {
+				lockKeys = append(lockKeys, makeTxLockKey(group.Ledger, key))
+			}
+		}
+		if err := lockKeysMVCC(dbTx, lockKeys); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i, group := range batch {
+		var err error
+		if s.concurrency == ConcurrencyMVCC {
+			txReceipts[i], validTxs[i], err = putTxsMVCC(dbTx, group.Ledger, group.Transactions)
+		} else {
+			txReceipts[i], validTxs[i], err = putTxsLocked(dbTx, group.Ledger, group.Transactions)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return txReceipts, validTxs, nil
+}
+
+// putTxsLocked creates transactions after acquiring a distributed lock on the key
+// of each transaction. The latest revisions of the written and read keys are also
+// locked in the database until it commits, so that read-sets checked here cannot be
+// invalidated by a concurrent writer (see lockLatestRevisions).
+func putTxsLocked(dbTx *gorm.DB, ledgerName string, txs []*types.Transaction) ([]*types.TxReceipt, []*types.Transaction, error) {
+
 	var validTxs []*types.Transaction
 	txReceipts := []*types.TxReceipt{}
 
-	dbTx := s.db.Begin()
+	latest, err := lockLatestRevisions(dbTx, ledgerName, getBatchKeys(txs))
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// create transactions and add transaction receipts for
 	// successfully stored transactions
@@ -237,8 +488,7 @@ func (s *PostgresStore) PutThen(ledgerName string, txs []*types.Transaction, the
 		// acquire lock on the transaction via its key
 		lock, err := common.NewLock(makeTxLockKey(ledgerName, tx.Key))
 		if err != nil {
-			dbTx.Rollback()
-			return nil, err
+			return nil, nil, err
 		}
 		if err := lock.Acquire(); err != nil {
 			if err == types.ErrLockAlreadyAcquired {
@@ -252,32 +502,25 @@ func (s *PostgresStore) PutThen(ledgerName string, txs []*types.Transaction, the
 		// For transactions requiring explit pessimistic locking, ensure the current transaction is not stale
 		// when checked with the already processed transactions. If this is not done, before we call the tx.Create
 		// the entire transaction be considered failed when we call tx.Commit
-		isStale := false
-		for _, vTx := range validTxs {
-			if len(tx.RevisionTo) > 0 && tx.RevisionTo == vTx.RevisionTo && tx.KeyInternal == vTx.KeyInternal {
-				isStale = true
-				break
-			}
-		}
-
-		if isStale {
+		if isReadSetStale(tx, latest) || isRevisedInBatch(tx, validTxs) {
 			txReceipts = append(txReceipts, &types.TxReceipt{ID: tx.ID, Err: "stale object"})
 			lock.Release()
 			continue
 		}
 
-		tx.Hash = tx.MakeHash()
+		if len(tx.Hash) == 0 {
+			tx.Hash = tx.MakeHash()
+		}
 		tx.Ledger = ledgerName
+		tx.ReadSet = nil
 		txReceipt := &types.TxReceipt{ID: tx.ID}
 		err = dbTx.Create(tx).Error
 		if err != nil {
 			log.Errorf("Failed to create transaction (%s): %s", tx.ID, err)
-			txReceipt.Err = err.Error()
-			if common.CompareErr(err, fmt.Errorf(`pq: duplicate key value violates unique constraint "idx_name_revision_to"`)) == 0 {
-				txReceipt.Err = "stale object"
-			}
+			txReceipt.Err = makeCreateTxErr(err)
 		} else {
 			validTxs = append(validTxs, tx)
+			latest[tx.Key] = tx.ID
 		}
 
 		if err = lock.Release(); err != nil {
@@ -287,20 +530,78 @@ func (s *PostgresStore) PutThen(ledgerName string, txs []*types.Transaction, the
 		txReceipts = append(txReceipts, txReceipt)
 	}
 
-	// run the companion functions. Rollback
-	// the transactions only if error was returned
-	if thenFunc != nil {
-		if err = thenFunc(validTxs); err != nil {
-			return txReceipts, err
-		}
+	return txReceipts, validTxs, nil
+}
+
+// lockKeysMVCC serializes writers to keys with transaction-level advisory
+// locks that are released when the database transaction ends. The locks
+// are acquired in sorted order with a single statement.
+func lockKeysMVCC(dbTx *gorm.DB, lockKeys []string) error {
+
+	if len(lockKeys) == 0 {
+		return nil
 	}
 
-	if err := dbTx.Debug().Commit().Error; err != nil {
-		pretty.Println(dbTx.GetErrors())
-		return nil, err
+	sort.Strings(lockKeys)
+	var lockCalls []string
+	var args []interface{}
+	for _, key := range lockKeys {
+		lockCalls = append(lockCalls, "pg_advisory_xact_lock(hashtext(?))")
+		args = append(args, key)
 	}
 
-	return txReceipts, nil
+	if err := dbTx.Exec("SELECT "+strings.Join(lockCalls, ", "), args...).Error; err != nil {
+		return fmt.Errorf("failed to lock keys. %s", err)
+	}
+
+	return nil
+}
+
+// putTxsMVCC creates transactions without distributed locks. The keys of the
+// transactions must have been locked with lockKeysMVCC. The latest revisions of
+// the keys are fetched in one query and the read-set of every transaction is
+// validated against them. As in lock mode, a revision is only stale if another
+// transaction already revises it.
+func putTxsMVCC(dbTx *gorm.DB, ledgerName string, txs []*types.Transaction) ([]*types.TxReceipt, []*types.Transaction, error) {
+
+	var validTxs []*types.Transaction
+	txReceipts := []*types.TxReceipt{}
+
+	latest, err := getLatestRevisions(dbTx, ledgerName, getBatchKeys(txs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, tx := range txs {
+
+		if isReadSetStale(tx, latest) || isRevisedInBatch(tx, validTxs) {
+			txReceipts = append(txReceipts, &types.TxReceipt{ID: tx.ID, Err: "stale object"})
+			continue
+		}
+
+		if len(tx.Hash) == 0 {
+			tx.Hash = tx.MakeHash()
+		}
+		tx.Ledger = ledgerName
+		tx.ReadSet = nil
+
+		// a failed insert aborts the database transaction, so the
+		// insert is guarded by a savepoint that can be rolled back
+		dbTx.Exec("SAVEPOINT put_tx")
+		txReceipt := &types.TxReceipt{ID: tx.ID}
+		if err = dbTx.Create(tx).Error; err != nil {
+			log.Errorf("Failed to create transaction (%s): %s", tx.ID, err)
+			txReceipt.Err = makeCreateTxErr(err)
+			dbTx.Exec("ROLLBACK TO SAVEPOINT put_tx")
+		} else {
+			validTxs = append(validTxs, tx)
+			latest[tx.Key] = tx.ID
+		}
+
+		txReceipts = append(txReceipts, txReceipt)
+	}
+
+	return txReceipts, validTxs, nil
 }
 
 // Put creates one or more transactions associated to a ledger.
@@ -309,8 +610,9 @@ func (s *PostgresStore) Put(ledgerName string, txs []*types.Transaction) ([]*typ
 	return s.PutThen(ledgerName, txs, nil)
 }
 
-// Get fetches a transaction by its ledger and key
-func (s *PostgresStore) Get(ledger, key string) (*types.Transaction, error) {
+// Get fetches the most recent transaction of a key. If the most recent transaction
+// is a tombstone, nil is returned unless includeDeleted is true.
+func (s *PostgresStore) Get(ledger, key string, includeDeleted bool) (*types.Transaction, error) {
 	var tx types.Transaction
 
 	// acquire lock on the transaction via its key
@@ -334,16 +636,43 @@ func (s *PostgresStore) Get(ledger, key string) (*types.Transaction, error) {
 		return nil, nil
 	}
 
+	if tx.Deleted && !includeDeleted {
+		return nil, nil
+	}
+
 	return &tx, nil
 }
 
-// GetRange fetches transactions with keys included in a specified range.
+// GetByID fetches a transaction by its ledger and id.
 // No lock is acquired in this operation.
-func (s *PostgresStore) GetRange(ledger, startKey, endKey string, inclusive bool, limit, offset int) ([]*types.Transaction, error) {
+func (s *PostgresStore) GetByID(ledger, id string) (*types.Transaction, error) {
+	var tx types.Transaction
+	err := s.db.Where("ledger = ? AND id = ?", ledger, id).First(&tx).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get transaction. %s", err)
+	} else if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &tx, nil
+}
+
+// likeEscaper escapes the pattern characters of a LIKE expression
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes a string so that it matches itself in a LIKE expression
+func escapeLike(str string) string {
+	return likeEscaper.Replace(str)
+}
+
+// GetRange fetches transactions with keys included in a specified range.
+// Keys whose most recent transaction is a tombstone are excluded unless
+// includeDeleted is true. Transactions are ordered by key, in descending
+// order if reverse is true. If afterKey is set, only keys after it in the
+// requested order are returned. No lock is acquired in this operation.
+func (s *PostgresStore) GetRange(ledger, startKey, endKey string, inclusive, includeDeleted, reverse bool, afterKey string, limit, offset int) ([]*types.Transaction, error) {
 
 	var err error
 	var txs []*types.Transaction
-	var db = s.db.Debug()
 
 	sql := `SELECT DISTINCT ON (key) * FROM "transactions"  WHERE `
 	args := []interface{}{}
@@ -357,8 +686,8 @@ func (s *PostgresStore) GetRange(ledger, startKey, endKey string, inclusive bool
 			args = append(args, ledger, startKey+"%", endKey+"%")
 		}
 	} else if len(startKey) > 0 && len(endKey) == 0 {
-		sql += `ledger = ? AND key like ?`
-		args = append(args, ledger, startKey+"%")
+		sql += `ledger = ? AND key like ? ESCAPE '\'`
+		args = append(args, ledger, escapeLike(startKey)+"%")
 	} else {
 		// setting endKey only is a little tricky as the call code may construct
 		// through a secondary process or rule, so add the '%' operator will most likely
@@ -367,8 +696,32 @@ func (s *PostgresStore) GetRange(ledger, startKey, endKey string, inclusive bool
 		args = append(args, ledger, endKey)
 	}
 
+	sql += ` ORDER BY key, created_at desc, number desc`
+
+	// tombstones and keys before the cursor can only be excluded
+	// after the most recent transaction of each key is selected
+	sql = `SELECT * FROM (` + sql + `) AS latest WHERE true`
+	if !includeDeleted {
+		sql += ` AND deleted = false`
+	}
+
+	if len(afterKey) > 0 {
+		if reverse {
+			sql += ` AND key < ?`
+		} else {
+			sql += ` AND key > ?`
+		}
+		args = append(args, afterKey)
+	}
+
+	if reverse {
+		sql += ` ORDER BY key desc`
+	} else {
+		sql += ` ORDER BY key`
+	}
+
 	args = append(args, limit, offset)
-	err = db.Raw(sql+` ORDER BY key, created_at desc LIMIT ? OFFSET ?`, args...).Scan(&txs).Error
+	err = s.db.Raw(sql+` LIMIT ? OFFSET ?`, args...).Scan(&txs).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, fmt.Errorf("failed to get transactions. %s", err)
 	} else if err == gorm.ErrRecordNotFound {
@@ -378,6 +731,378 @@ func (s *PostgresStore) GetRange(ledger, startKey, endKey string, inclusive bool
 	return txs, nil
 }
 
+// GetHistory fetches all the transactions of a key, most recent first.
+// Set cursor to the number of the last transaction of a previously fetched
+// page to get older transactions. A cursor of zero starts from the most recent
+// transaction. If limit is zero or less, all transactions are returned.
+// No lock is acquired in this operation.
+func (s *PostgresStore) GetHistory(ledger, key string, limit int, cursor uint) ([]*types.Transaction, error) {
+
+	var txs []*types.Transaction
+	q := s.db.Where("ledger = ? AND key = ?", ledger, key)
+	if cursor > 0 {
+		q = q.Where("number < ?", cursor)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	err := q.Order("number desc").Find(&txs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get transaction history. %s", err)
+	}
+
+	return txs, nil
+}
+
+// GetLastTxNumber returns the number of the most recently
+// stored transaction of any ledger or zero if there is none
+func (s *PostgresStore) GetLastTxNumber() (uint, error) {
+
+	var number uint
+	err := s.db.Model(&types.Transaction{}).Select("COALESCE(MAX(number), 0)").Row().Scan(&number)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last transaction number. %s", err)
+	}
+
+	return number, nil
+}
+
+// ListTransactions returns all transactions of a ledger, including previous
+// revisions and tombstones, in the order they were stored. Only transactions
+// with a number greater than afterNumber are returned. If limit is zero or
+// negative, all matching transactions are returned.
+func (s *PostgresStore) ListTransactions(ledger string, afterNumber uint, limit int) ([]*types.Transaction, error) {
+
+	var txs []*types.Transaction
+	q := s.db.Where("ledger = ? AND number > ?", ledger, afterNumber)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	err := q.Order("number asc").Find(&txs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to list transactions. %s", err)
+	}
+
+	return txs, nil
+}
+
+// SetLedgerRetention sets the retention policy of a ledger. A zero
+// keepRevisions and keepFor removes the policy.
+func (s *PostgresStore) SetLedgerRetention(name string, keepRevisions uint, keepFor int64) (*types.Ledger, error) {
+
+	err := s.db.Model(&types.Ledger{}).Where("name = ?", name).Updates(map[string]interface{}{
+		"keep_revisions": keepRevisions,
+		"keep_for":       keepFor,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to set ledger retention. %s", err)
+	}
+
+	l, err := s.GetLedger(name)
+	if err != nil {
+		return nil, err
+	} else if l == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	return l, nil
+}
+
+// ListRetainedLedgers returns the ledgers that have a retention policy
+func (s *PostgresStore) ListRetainedLedgers() ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.db.Where("keep_revisions > 0 OR keep_for > 0").Order("number asc").Find(&ledgers).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	return ledgers, nil
+}
+
+// ListPrunableTransactions returns the ids of the superseded transactions of a ledger
+// that are outside its retention policy, in the order they were stored. A transaction
+// is prunable if more than keepRevisions newer revisions of its key exist or it was created
+// before the unix time before. A zero keepRevisions or before disables the respective check.
+// The most recent revision of a key is never returned. If limit is zero or negative,
+// all prunable transactions are returned.
+func (s *PostgresStore) ListPrunableTransactions(ledger string, keepRevisions uint, before int64, limit int) ([]string, error) {
+
+	q := `SELECT id FROM (
+		SELECT id, number, created_at, row_number() OVER (PARTITION BY key ORDER BY created_at desc, number desc) AS rank
+		FROM "transactions" WHERE ledger = ?
+	) revs WHERE rank > 1 AND ((? > 0 AND rank > ?) OR created_at < ?) ORDER BY number asc`
+	args := []interface{}{ledger, keepRevisions, keepRevisions, before}
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Raw(q, args...).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prunable transactions. %s", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to list prunable transactions. %s", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// DeleteTransactions removes transactions of a ledger by their ids.
+// It returns the number of transactions removed.
+func (s *PostgresStore) DeleteTransactions(ledger string, ids []string) (int, error) {
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	res := s.db.Where("ledger = ? AND id IN (?)", ledger, ids).Delete(&types.Transaction{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete transactions. %s", res.Error)
+	}
+
+	return int(res.RowsAffected), nil
+}
+
+// SetTransactionValues replaces the values and index documents of stored transactions of
+// a ledger with the ones of the given transactions with the same ids. The hashes of the
+// transactions are unchanged, so it must only be used to change the encoding of values,
+// such as to encrypt them. It returns the number of transactions updated.
+func (s *PostgresStore) SetTransactionValues(ledger string, txs []*types.Transaction) (int, error) {
+
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	dbTx := common.BeginPgTx(s.db)
+
+	var updated int
+	for _, tx := range txs {
+		res := dbTx.Model(&types.Transaction{}).Where("ledger = ? AND id = ?", ledger, tx.ID).Updates(map[string]interface{}{
+			"value":     tx.Value,
+			"index_doc": tx.IndexDoc,
+		})
+		if res.Error != nil {
+			dbTx.Rollback()
+			return 0, fmt.Errorf("failed to set transaction values. %s", res.Error)
+		}
+		updated += int(res.RowsAffected)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		dbTx.Rollback()
+		return 0, fmt.Errorf("failed to set transaction values. %s", err)
+	}
+
+	return updated, nil
+}
+
+// SetLedgerDataKey replaces the wrapped data key of a ledger if the current data key
+// matches oldDataKey. It returns false if the data key was changed by another writer.
+func (s *PostgresStore) SetLedgerDataKey(name, oldDataKey, newDataKey string) (bool, error) {
+
+	res := s.db.Model(&types.Ledger{}).Where("name = ? AND COALESCE(data_key, '') = ?", name, oldDataKey).
+		Update("data_key", newDataKey)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to set ledger data key. %s", res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ListEncryptedLedgers returns the ledgers that have a data key
+func (s *PostgresStore) ListEncryptedLedgers() ([]*types.Ledger, error) {
+
+	var ledgers []*types.Ledger
+	err := s.db.Where("COALESCE(data_key, '') != ''").Order("number asc").Find(&ledgers).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to list ledgers. %s", err)
+	}
+
+	return ledgers, nil
+}
+
+// jsonPathFuncName is the name of the function that extracts the
+// indexed value of a transaction value
+const jsonPathFuncName = "cocoon_json_path"
+
+// createJSONPathFunc creates the function used by the expression indexes of secondary
+// indexes. Unlike a plain cast, it returns NULL for values that are not valid JSON so
+// that such values can be stored in an indexed ledger.
+const createJSONPathFunc = `CREATE OR REPLACE FUNCTION ` + jsonPathFuncName + `(value text, path text[]) RETURNS jsonb AS $$
+BEGIN
+	RETURN value::jsonb #> path;
+EXCEPTION WHEN others THEN
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE`
+
+// makeJSONIndexName creates the name of the expression index of a secondary index
+func makeJSONIndexName(ledger, index string) string {
+	return "idx_json_" + util.Sha256(ledger + ";" + index)[:24]
+}
+
+// makeJSONPathExpr creates the expression that extracts the value of a secondary
+// index. Index paths are validated, so they can be included in the statement; the
+// expression must be identical in the index and in queries for the index to be used.
+// The value is extracted from the index document of a transaction whose value is
+// encrypted (see types.MakeIndexDoc).
+func makeJSONPathExpr(index *types.Index) string {
+	return fmt.Sprintf("%s(COALESCE(NULLIF(index_doc, ''), value), '{%s}')", jsonPathFuncName, strings.Join(index.GetPath(), ","))
+}
+
+// createJSONIndex creates the partial expression index of a secondary index of a ledger
+func createJSONIndex(db *gorm.DB, ledger string, index *types.Index) error {
+	stmt := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON "transactions" (key, (%s)) WHERE ledger = '%s'`,
+		makeJSONIndexName(ledger, index.Name), makeJSONPathExpr(index), strings.Replace(ledger, "'", "''", -1))
+	if err := db.Exec(stmt).Error; err != nil {
+		return fmt.Errorf("failed to create index %s. %s", index.Name, err)
+	}
+	return nil
+}
+
+// SetLedgerIndexes sets the secondary indexes of a ledger. A partial JSONB expression
+// index is created on the transactions of the ledger for each new index and the
+// expression indexes of the indexes not included are dropped.
+func (s *PostgresStore) SetLedgerIndexes(name string, indexes types.Indexes) (*types.Ledger, error) {
+
+	if err := indexes.Validate(); err != nil {
+		return nil, err
+	}
+
+	ledger, err := s.GetLedger(name)
+	if err != nil {
+		return nil, err
+	} else if ledger == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	dbTx := common.BeginPgTx(s.db)
+
+	for _, index := range ledger.Indexes {
+		if cur := indexes.Get(index.Name); cur != nil && cur.Path == index.Path {
+			continue
+		}
+		if err := dbTx.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS %s`, makeJSONIndexName(name, index.Name))).Error; err != nil {
+			dbTx.Rollback()
+			return nil, fmt.Errorf("failed to drop index %s. %s", index.Name, err)
+		}
+	}
+
+	for _, index := range indexes {
+		if err := createJSONIndex(dbTx.DB, name, index); err != nil {
+			dbTx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := dbTx.Model(&types.Ledger{}).Where("name = ?", name).Update("indexes", indexes).Error; err != nil {
+		dbTx.Rollback()
+		return nil, fmt.Errorf("failed to set ledger indexes. %s", err)
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to set ledger indexes. %s", err)
+	}
+
+	ledger.Indexes = indexes
+	return ledger, nil
+}
+
+// Query fetches the most recent transaction of the keys whose value at the path of a
+// secondary index matches a query. value is JSON encoded and is compared to the indexed
+// values of the same JSON type as described by types.MatchQuery; strings and keys are
+// compared by their bytes, like the bolt store. Tombstones are excluded. Transactions
+// are ordered by key. If afterKey is set, only keys after it are returned.
+func (s *PostgresStore) Query(ledger, index, op, value, afterKey string, limit int) ([]*types.Transaction, error) {
+
+	decoded, err := types.ValidateQuery(op, value)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := s.GetLedger(ledger)
+	if err != nil {
+		return nil, err
+	} else if l == nil {
+		return nil, types.ErrLedgerNotFound
+	}
+
+	idx := l.Indexes.Get(index)
+	if idx == nil {
+		return nil, types.ErrIndexNotFound
+	}
+
+	// candidates are found through the expression index; only
+	// the most recent transaction of each candidate key is kept.
+	// Strings are compared as text in the "C" collation, which
+	// orders them by their bytes, unlike the JSONB ordering.
+	expr := makeJSONPathExpr(idx)
+	cond, arg := fmt.Sprintf(`%s %s ?::jsonb`, expr, op), interface{}(value)
+	if str, ok := decoded.(string); ok {
+		cond, arg = fmt.Sprintf(`(%s #>> '{}') COLLATE "C" %s ?`, expr, op), str
+	}
+	sql := fmt.Sprintf(`SELECT * FROM "transactions" t WHERE ledger = ? AND %s AND jsonb_typeof(%s) = jsonb_typeof(?::jsonb)
+		AND deleted = false AND key COLLATE "C" > ? AND NOT EXISTS (
+			SELECT 1 FROM "transactions" n WHERE n.ledger = t.ledger AND n.key = t.key
+			AND (n.created_at > t.created_at OR (n.created_at = t.created_at AND n.number > t.number))
+		) ORDER BY key COLLATE "C" LIMIT ?`, cond, expr)
+
+	var txs []*types.Transaction
+	err = s.db.Raw(sql, ledger, arg, value, afterKey, limit).Scan(&txs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to query transactions. %s", err)
+	}
+
+	return txs, nil
+}
+
+// Transact calls fn with a store and blockchain whose operations are made in a
+// single database transaction. The transaction is committed if fn returns nil and
+// rolled back otherwise. The blockchain implementation must use the database of
+// the store. If the store is already in a transaction, a savepoint is used.
+func (s *PostgresStore) Transact(fn func(store types.Store, blockchain types.Blockchain) error) error {
+
+	dbTx := common.BeginPgTx(s.db)
+	if dbTx.Error != nil {
+		return fmt.Errorf("failed to begin transaction. %s", dbTx.Error)
+	}
+
+	blockchain, err := s.blockchain.WithDBTx(dbTx.DB)
+	if err != nil {
+		dbTx.Rollback()
+		return err
+	}
+
+	store := &PostgresStore{
+		db:          dbTx.DB,
+		blockchain:  blockchain,
+		locker:      s.locker,
+		concurrency: s.concurrency,
+	}
+
+	if err := fn(store, blockchain); err != nil {
+		dbTx.Rollback()
+		return err
+	}
+
+	if err := dbTx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction. %s", err)
+	}
+
+	return nil
+}
+
 // Close releases any resource held
 func (s *PostgresStore) Close() error {
 	if s.db != nil {
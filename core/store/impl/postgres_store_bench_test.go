@@ -0,0 +1,76 @@
+package impl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// BenchmarkPutThen compares the lock-based and MVCC concurrency modes of
+// the postgres store. Parallel writers update a small set of keys, reading
+// the current revision of a key before revising it. Failed reads and stale
+// writes are counted as conflicts. It requires a postgres
+// server; DEV_MEM_LOCK is set so the lock-based mode does not need consul.
+func BenchmarkPutThen(b *testing.B) {
+
+	os.Setenv("DEV_MEM_LOCK", "1")
+
+	benchDBName := "bench_" + strings.ToLower(util.RandString(5))
+	if _, err := db.Exec(fmt.Sprintf("CREATE DATABASE %s;", benchDBName)); err != nil {
+		b.Fatal(err)
+	}
+	defer db.Exec(fmt.Sprintf("DROP DATABASE %s;", benchDBName))
+
+	pgStore := new(PostgresStore)
+	if _, err := pgStore.Connect(util.Env("STORE_CON_STR", "host=localhost database="+benchDBName+" user=ned sslmode=disable password=")); err != nil {
+		b.Fatal("failed to connect to pg store")
+	}
+	defer pgStore.Close()
+
+	if err := pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName()); err != nil {
+		b.Fatal(err)
+	}
+
+	numKeys := 16
+	for _, mode := range []string{ConcurrencyLock, ConcurrencyMVCC} {
+		b.Run(mode, func(b *testing.B) {
+
+			pgStore.SetConcurrency(mode)
+			ledger := util.RandString(5)
+			var conflicts, counter int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					key := fmt.Sprintf("key_%d", atomic.AddInt64(&counter, 1)%int64(numKeys))
+					cur, err := pgStore.Get(ledger, key, true)
+					if err != nil {
+						// in lock mode, reads fail while the key is locked by a writer
+						atomic.AddInt64(&conflicts, 1)
+						continue
+					}
+
+					tx := &types.Transaction{ID: util.UUID4(), Key: key, Value: util.RandString(10)}
+					if cur != nil {
+						tx.RevisionTo = cur.ID
+					}
+
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx})
+					if err != nil {
+						b.Error(err)
+						return
+					} else if receipts[0].Err != "" {
+						atomic.AddInt64(&conflicts, 1)
+					}
+				}
+			})
+
+			b.ReportMetric(float64(conflicts)/float64(b.N), "conflicts/op")
+		})
+	}
+}
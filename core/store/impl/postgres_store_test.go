@@ -228,13 +228,130 @@ func TestPostgresStore(t *testing.T) {
 			})
 		})
 
+		for _, mode := range []string{ConcurrencyLock, ConcurrencyMVCC} {
+
+			Convey("Read-sets in "+mode+" mode", func() {
+
+				err := pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+				So(err, ShouldBeNil)
+				So(pgStore.SetConcurrency(mode), ShouldBeNil)
+				defer pgStore.SetConcurrency(ConcurrencyLock)
+
+				ledger := util.RandString(5)
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "10"}
+				_, err = pgStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				Convey("Should store a transaction whose read-set is current", func() {
+					tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{{Key: "balance", Revision: tx.ID}}}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx2})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldBeEmpty)
+				})
+
+				Convey("Should return a stale object receipt if a key was revised after it was read", func() {
+					tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20", RevisionTo: tx.ID}
+					tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "total", Value: "10", ReadSet: []*types.KeyRevision{{Key: "balance", Revision: tx.ID}}}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx2, tx3})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldBeEmpty)
+					So(receipts[1].Err, ShouldEqual, "stale object")
+
+					stored, err := pgStore.Get(ledger, "total", false)
+					So(err, ShouldBeNil)
+					So(stored, ShouldBeNil)
+				})
+
+				Convey("Should return a stale object receipt for a revision of an outdated revision", func() {
+					tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20", RevisionTo: tx.ID}
+					tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "30", RevisionTo: tx.ID}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx2, tx3})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldBeEmpty)
+					So(receipts[1].Err, ShouldEqual, "stale object")
+				})
+
+				Convey("Should store a revision of a revision that no transaction revises", func() {
+					tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20"}
+					tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "30", RevisionTo: tx.ID}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx2, tx3})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldBeEmpty)
+					So(receipts[1].Err, ShouldBeEmpty)
+				})
+
+				Convey("Should return a stale object receipt for a revision already stored", func() {
+					tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "20", RevisionTo: tx.ID}
+					_, err := pgStore.Put(ledger, []*types.Transaction{tx2})
+					So(err, ShouldBeNil)
+					tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "balance", Value: "30", RevisionTo: tx.ID}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{tx3})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldEqual, "stale object")
+				})
+
+				Convey("Should return an error receipt for a duplicate transaction id", func() {
+					dup := &types.Transaction{ID: tx.ID, Key: "other", Value: "20"}
+					receipts, err := pgStore.Put(ledger, []*types.Transaction{dup})
+					So(err, ShouldBeNil)
+					So(receipts[0].Err, ShouldEqual, "transaction with matching id already exists")
+				})
+			})
+		}
+
+		for _, mode := range []string{ConcurrencyLock, ConcurrencyMVCC} {
+
+			Convey(".PutMulti in "+mode+" mode", func() {
+
+				err := pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+				So(err, ShouldBeNil)
+				So(pgStore.SetConcurrency(mode), ShouldBeNil)
+				defer pgStore.SetConcurrency(ConcurrencyLock)
+
+				ledgerA, ledgerB := util.RandString(5), util.RandString(5)
+				debit := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "alice", Value: "90"}
+				credit := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "bob", Value: "110"}
+
+				Convey("Should store the transactions of every ledger", func() {
+					receipts, err := pgStore.PutMulti([]*types.LedgerTransactions{
+						{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+						{Ledger: ledgerB, Transactions: []*types.Transaction{credit}},
+					})
+					So(err, ShouldBeNil)
+					So(receipts[0][0].Err, ShouldBeEmpty)
+					So(receipts[1][0].Err, ShouldBeEmpty)
+					stored, _ := pgStore.Get(ledgerB, "bob", false)
+					So(stored.Value, ShouldEqual, "110")
+				})
+
+				Convey("Should store nothing if a transaction in one ledger fails", func() {
+					credit.ReadSet = []*types.KeyRevision{{Key: "bob", Revision: "unknown"}}
+					receipts, err := pgStore.PutMulti([]*types.LedgerTransactions{
+						{Ledger: ledgerA, Transactions: []*types.Transaction{debit}},
+						{Ledger: ledgerB, Transactions: []*types.Transaction{credit}},
+					})
+					So(err, ShouldBeNil)
+					So(receipts[0][0].Err, ShouldEqual, types.ErrBatchAborted.Error())
+					So(receipts[1][0].Err, ShouldEqual, "stale object")
+					stored, _ := pgStore.Get(ledgerA, "alice", false)
+					So(stored, ShouldBeNil)
+				})
+			})
+		}
+
+		Convey(".SetConcurrency", func() {
+			err := pgStore.SetConcurrency("unknown")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unknown concurrency mode: unknown")
+		})
+
 		Convey(".Get", func() {
 
 			err := pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
 			So(err, ShouldBeNil)
 
 			Convey("should return nil when transaction does not exist", func() {
-				tx, err := pgStore.Get(types.GetSystemPublicLedgerName(), "wrong_key")
+				tx, err := pgStore.Get(types.GetSystemPublicLedgerName(), "wrong_key", false)
 				So(tx, ShouldBeNil)
 				So(err, ShouldBeNil)
 			})
@@ -253,11 +370,30 @@ func TestPostgresStore(t *testing.T) {
 				So(txs[0].Err, ShouldBeEmpty)
 				So(txs[0].ID, ShouldEqual, tx.ID)
 
-				tx2, err := pgStore.Get(ledger, key)
+				tx2, err := pgStore.Get(ledger, key, false)
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 				So(tx2.Hash, ShouldEqual, tx.Hash)
 			})
+
+			Convey("should hide a deleted key unless deleted transactions are included", func() {
+				ledger := util.Sha256(util.RandString(5))
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				tombstone := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", RevisionTo: tx.ID, Deleted: true}
+				_, err = pgStore.Put(ledger, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+
+				found, err := pgStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+
+				found, err = pgStore.Get(ledger, "key", true)
+				So(err, ShouldBeNil)
+				So(found.ID, ShouldEqual, tombstone.ID)
+				So(found.Deleted, ShouldBeTrue)
+			})
 		})
 
 		Convey(".GetRange", func() {
@@ -275,11 +411,31 @@ func TestPostgresStore(t *testing.T) {
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				txs, err := pgStore.GetRange(ledger, "a", "z", false, 10, 0)
+				txs, err := pgStore.GetRange(ledger, "a", "z", false, false, false, "", 10, 0)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 3)
 			})
 
+			Convey("Should match the prefix literally when it contains LIKE pattern characters", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "user_1", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "userA1", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "rate%10", Value: "200"}
+				tx4 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "rate110", Value: "200"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx, tx2, tx3, tx4})
+				So(err, ShouldBeNil)
+
+				txs, err := pgStore.GetRange(ledger, "user_", "", false, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "user_1")
+
+				txs, err = pgStore.GetRange(ledger, "rate%", "", false, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "rate%10")
+			})
+
 			Convey("Should successfully return expected transactions and include end key when `includeEndKey` is true", func() {
 				ledger := util.Sha256(util.UUID4())
 				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "100"}
@@ -290,7 +446,7 @@ func TestPostgresStore(t *testing.T) {
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				txs, err := pgStore.GetRange(ledger, "account", "z", true, 10, 0)
+				txs, err := pgStore.GetRange(ledger, "account", "z", true, false, false, "", 10, 0)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 4)
 			})
@@ -305,7 +461,7 @@ func TestPostgresStore(t *testing.T) {
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				txs, err := pgStore.GetRange(ledger, "account", "", true, 10, 0)
+				txs, err := pgStore.GetRange(ledger, "account", "", true, false, false, "", 10, 0)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 3)
 			})
@@ -319,7 +475,7 @@ func TestPostgresStore(t *testing.T) {
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				txs, err := pgStore.GetRange(ledger, "", "%account", true, 10, 0)
+				txs, err := pgStore.GetRange(ledger, "", "%account", true, false, false, "", 10, 0)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 2)
 			})
@@ -333,16 +489,437 @@ func TestPostgresStore(t *testing.T) {
 				So(tx, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				txs, err := pgStore.GetRange(ledger, "", "%account", true, 1, 0)
+				txs, err := pgStore.GetRange(ledger, "", "%account", true, false, false, "", 1, 0)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 1)
 				So(txs[0].Key, ShouldEqual, "ben.account")
 
-				txs, err = pgStore.GetRange(ledger, "", "%account", true, 1, 1)
+				txs, err = pgStore.GetRange(ledger, "", "%account", true, false, false, "", 1, 1)
 				So(err, ShouldBeNil)
 				So(len(txs), ShouldEqual, 1)
 				So(txs[0].Key, ShouldEqual, "glen.account")
 			})
+
+			Convey("Should exclude deleted keys unless deleted transactions are included", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.glen", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ken", Value: "200"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+				tombstone := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "account.ben", RevisionTo: tx.ID, Deleted: true}
+				_, err = pgStore.Put(ledger, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+
+				txs, err := pgStore.GetRange(ledger, "account", "", false, false, false, "", 1, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "account.glen")
+
+				txs, err = pgStore.GetRange(ledger, "account", "", false, false, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+
+				txs, err = pgStore.GetRange(ledger, "account", "", false, true, false, "", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 3)
+				So(txs[0].Deleted, ShouldBeTrue)
+			})
+
+			Convey("Should return transactions in descending key order when reverse is true", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:1", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:2", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:3", Value: "200"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := pgStore.GetRange(ledger, "order:", "", false, false, true, "", 2, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Key, ShouldEqual, "order:3")
+				So(txs[1].Key, ShouldEqual, "order:2")
+			})
+
+			Convey("Should return only transactions after the given key", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:1", Value: "100"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:2", Value: "110"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "order:3", Value: "200"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx, tx2, tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := pgStore.GetRange(ledger, "order:", "", false, false, false, "order:1", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Key, ShouldEqual, "order:2")
+
+				txs, err = pgStore.GetRange(ledger, "order:", "", false, false, true, "order:2", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Key, ShouldEqual, "order:1")
+			})
+		})
+		Convey(".GetByID", func() {
+
+			Convey("Should return the transaction with matching id in the ledger", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				found, err := pgStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				So(found.Value, ShouldEqual, "value")
+
+				found, err = pgStore.GetByID(util.Sha256(util.UUID4()), tx.ID)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+			})
+		})
+
+		Convey(".GetHistory", func() {
+
+			Convey("Should return all transactions of a key, most recent first", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value1"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key2", Value: "value"}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx, tx2})
+				So(err, ShouldBeNil)
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value2", RevisionTo: tx.ID}
+				_, err = pgStore.Put(ledger, []*types.Transaction{tx3})
+				So(err, ShouldBeNil)
+
+				txs, err := pgStore.GetHistory(ledger, "key", 0, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].ID, ShouldEqual, tx3.ID)
+				So(txs[1].ID, ShouldEqual, tx.ID)
+			})
+
+			Convey("Should return transactions older than the cursor", func() {
+				ledger := util.Sha256(util.UUID4())
+				for _, v := range []string{"a", "b", "c"} {
+					_, err := pgStore.Put(ledger, []*types.Transaction{{ID: util.Sha256(util.UUID4()), Key: "key", Value: v}})
+					So(err, ShouldBeNil)
+				}
+
+				txs, err := pgStore.GetHistory(ledger, "key", 2, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].Value, ShouldEqual, "c")
+				So(txs[1].Value, ShouldEqual, "b")
+
+				txs, err = pgStore.GetHistory(ledger, "key", 2, txs[1].Number)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].Value, ShouldEqual, "a")
+			})
+
+			Convey("Should return empty result if key does not exist", func() {
+				txs, err := pgStore.GetHistory(util.Sha256(util.UUID4()), "unknown", 10, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 0)
+			})
+		})
+
+		Convey(".ListLedgers", func() {
+			Convey("Should return the ledgers of a cocoon in the order they were created", func() {
+				cocoonID := util.RandString(10)
+				_, err := pgStore.CreateLedger(cocoonID, util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+				ledger2, err := pgStore.CreateLedger(cocoonID, util.RandString(10), false, true)
+				So(err, ShouldBeNil)
+				_, err = pgStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+
+				ledgers, err := pgStore.ListLedgers(cocoonID)
+				So(err, ShouldBeNil)
+				So(len(ledgers), ShouldEqual, 2)
+				So(ledgers[1].Name, ShouldEqual, ledger2.Name)
+			})
+		})
+
+		Convey(".ListTransactions", func() {
+			Convey("Should return all transactions of a ledger in the order they were stored", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "b", Value: "1"}
+				tx2 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: "2"}
+				tx3 := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "b", Value: "3", RevisionTo: tx.ID}
+				for _, t := range []*types.Transaction{tx, tx2, tx3} {
+					_, err := pgStore.Put(ledger, []*types.Transaction{t})
+					So(err, ShouldBeNil)
+				}
+
+				txs, err := pgStore.ListTransactions(ledger, 0, 2)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 2)
+				So(txs[0].ID, ShouldEqual, tx.ID)
+				So(txs[1].ID, ShouldEqual, tx2.ID)
+
+				txs, err = pgStore.ListTransactions(ledger, txs[1].Number, 2)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].ID, ShouldEqual, tx3.ID)
+			})
+		})
+
+		Convey(".SetTransactionValues", func() {
+			Convey("Should replace the values of transactions and keep their hashes", func() {
+				ledger := util.Sha256(util.UUID4())
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: "1"}
+				tx.Hash = tx.MakeHash()
+				hash := tx.Hash
+				tx.Value = "sealed"
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+
+				stored, err := pgStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(stored.Hash, ShouldEqual, hash)
+
+				updated, err := pgStore.SetTransactionValues(ledger, []*types.Transaction{{ID: tx.ID, Value: "resealed"}, {ID: util.Sha256(util.UUID4()), Value: "x"}})
+				So(err, ShouldBeNil)
+				So(updated, ShouldEqual, 1)
+
+				stored, err = pgStore.GetByID(ledger, tx.ID)
+				So(err, ShouldBeNil)
+				So(stored.Value, ShouldEqual, "resealed")
+				So(stored.Hash, ShouldEqual, hash)
+			})
+		})
+
+		Convey(".SetLedgerRetention", func() {
+			Convey("Should set the retention policy of a ledger", func() {
+				ledger, err := pgStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+				ledger, err = pgStore.SetLedgerRetention(ledger.Name, 2, 3600)
+				So(err, ShouldBeNil)
+				So(ledger.KeepRevisions, ShouldEqual, 2)
+				So(ledger.KeepFor, ShouldEqual, 3600)
+
+				ledgers, err := pgStore.ListRetainedLedgers()
+				So(err, ShouldBeNil)
+				So(ledgers[len(ledgers)-1].Name, ShouldEqual, ledger.Name)
+			})
+
+			Convey("Should return error if ledger does not exist", func() {
+				_, err := pgStore.SetLedgerRetention("unknown", 2, 0)
+				So(err, ShouldEqual, types.ErrLedgerNotFound)
+			})
+		})
+
+		Convey(".ListPrunableTransactions and .DeleteTransactions", func() {
+
+			ledger := util.Sha256(util.UUID4())
+			var revisions []*types.Transaction
+			for i := 0; i < 4; i++ {
+				tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "a", Value: fmt.Sprintf("%d", i), CreatedAt: int64(100 + i)}
+				if i > 0 {
+					tx.RevisionTo = revisions[i-1].ID
+				}
+				_, err := pgStore.Put(ledger, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				revisions = append(revisions, tx)
+			}
+
+			Convey("Should return revisions beyond the number of revisions to keep", func() {
+				ids, err := pgStore.ListPrunableTransactions(ledger, 2, 0, 0)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{revisions[0].ID, revisions[1].ID})
+			})
+
+			Convey("Should return superseded revisions created before a time", func() {
+				ids, err := pgStore.ListPrunableTransactions(ledger, 0, 102, 0)
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{revisions[0].ID, revisions[1].ID})
+			})
+
+			Convey("Should delete transactions and keep the most recent revision", func() {
+				ids, err := pgStore.ListPrunableTransactions(ledger, 1, 0, 0)
+				So(err, ShouldBeNil)
+				deleted, err := pgStore.DeleteTransactions(ledger, ids)
+				So(err, ShouldBeNil)
+				So(deleted, ShouldEqual, 3)
+
+				txs, err := pgStore.GetHistory(ledger, "a", 0, 0)
+				So(err, ShouldBeNil)
+				So(len(txs), ShouldEqual, 1)
+				So(txs[0].ID, ShouldEqual, revisions[3].ID)
+			})
+		})
+
+		Convey(".SetLedgerIndexes and .Query", func() {
+
+			ledger, err := pgStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+			So(err, ShouldBeNil)
+
+			put := func(key, value string) *types.Transaction {
+				tx := &types.Transaction{ID: util.UUID4(), Key: key, Value: value}
+				receipts, err := pgStore.Put(ledger.Name, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(receipts[0].Err, ShouldBeEmpty)
+				return tx
+			}
+
+			query := func(index, op, value, afterKey string, limit int) []string {
+				txs, err := pgStore.Query(ledger.Name, index, op, value, afterKey, limit)
+				So(err, ShouldBeNil)
+				var keys []string
+				for _, tx := range txs {
+					keys = append(keys, tx.Key)
+				}
+				return keys
+			}
+
+			// values stored before the indexes are set must be indexed
+			put("a", `{"age": 30, "owner": {"name": "ann"}}`)
+			put("b", `{"age": 20, "owner": {"name": "ben"}}`)
+			put("c", "not json")
+
+			_, err = pgStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "age"}, {Name: "by_owner", Path: "owner.name"}})
+			So(err, ShouldBeNil)
+
+			put("d", `{"age": "30"}`)
+			put("e", `{"age": 40}`)
+
+			Convey("Should return the keys whose indexed value matches the query", func() {
+				So(query("by_age", ">", "25", "", 10), ShouldResemble, []string{"a", "e"})
+				So(query("by_age", "=", "30", "", 10), ShouldResemble, []string{"a"})
+				So(query("by_age", "=", `"30"`, "", 10), ShouldResemble, []string{"d"})
+				So(query("by_age", "<=", "20", "", 10), ShouldResemble, []string{"b"})
+				So(query("by_owner", "=", `"ben"`, "", 10), ShouldResemble, []string{"b"})
+			})
+
+			Convey("Should only match the most recent value of a key", func() {
+				put("a", `{"age": 10}`)
+				So(query("by_age", ">", "25", "", 10), ShouldResemble, []string{"e"})
+				So(query("by_age", "<", "25", "", 10), ShouldResemble, []string{"a", "b"})
+				So(query("by_owner", "=", `"ann"`, "", 10), ShouldBeEmpty)
+			})
+
+			Convey("Should not match deleted keys", func() {
+				b, _ := pgStore.Get(ledger.Name, "b", false)
+				tombstone := &types.Transaction{ID: util.UUID4(), Key: "b", RevisionTo: b.ID, Deleted: true}
+				_, err := pgStore.Put(ledger.Name, []*types.Transaction{tombstone})
+				So(err, ShouldBeNil)
+				So(query("by_age", "<", "25", "", 10), ShouldBeEmpty)
+			})
+
+			Convey("Should compare strings and order keys by their bytes", func() {
+				put("f", `{"owner": {"name": "Zed"}}`)
+				put("B", `{"age": 50}`)
+				So(query("by_owner", "<", `"ann"`, "", 10), ShouldResemble, []string{"f"})
+				So(query("by_owner", ">=", `"ann"`, "", 10), ShouldResemble, []string{"a", "b"})
+				So(query("by_age", ">=", "40", "", 10), ShouldResemble, []string{"B", "e"})
+			})
+
+			Convey("Should index the index document of a transaction whose value is encrypted", func() {
+				tx := &types.Transaction{ID: util.UUID4(), Key: "g", Value: "enc:v1:c2VjcmV0", IndexDoc: `{"age": 60}`}
+				_, err := pgStore.Put(ledger.Name, []*types.Transaction{tx})
+				So(err, ShouldBeNil)
+				So(query("by_age", "=", "60", "", 10), ShouldResemble, []string{"g"})
+			})
+
+			Convey("Should page through matching keys", func() {
+				So(query("by_age", ">=", "20", "", 2), ShouldResemble, []string{"a", "b"})
+				So(query("by_age", ">=", "20", "b", 2), ShouldResemble, []string{"e"})
+			})
+
+			Convey("Should return error if the index does not exist or the query is invalid", func() {
+				_, err := pgStore.Query(ledger.Name, "unknown", "=", "1", "", 10)
+				So(err, ShouldEqual, types.ErrIndexNotFound)
+				_, err = pgStore.Query(ledger.Name, "by_age", "~", "1", "", 10)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "unknown query operator: ~")
+				_, err = pgStore.Query(ledger.Name, "by_age", ">", "{}", "", 10)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Should return error if an index is invalid", func() {
+				_, err := pgStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "age"}, {Name: "by_age", Path: "x"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "index by_age: duplicate index name")
+				_, err = pgStore.SetLedgerIndexes(ledger.Name, types.Indexes{{Name: "by_age", Path: "a..b"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "index by_age: invalid path")
+			})
+		})
+
+		Convey(".SetLedgerDataKey", func() {
+			Convey("Should only replace the data key if it matches the expected data key", func() {
+				ledger, err := pgStore.CreateLedger(util.RandString(10), util.RandString(10), false, false)
+				So(err, ShouldBeNil)
+
+				updated, err := pgStore.SetLedgerDataKey(ledger.Name, "", "key1:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeTrue)
+
+				updated, err = pgStore.SetLedgerDataKey(ledger.Name, "", "key2:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeFalse)
+
+				updated, err = pgStore.SetLedgerDataKey(ledger.Name, "key1:wrapped", "key2:wrapped")
+				So(err, ShouldBeNil)
+				So(updated, ShouldBeTrue)
+
+				found, err := pgStore.GetLedger(ledger.Name)
+				So(err, ShouldBeNil)
+				So(found.DataKey, ShouldEqual, "key2:wrapped")
+
+				ledgers, err := pgStore.ListEncryptedLedgers()
+				So(err, ShouldBeNil)
+				So(ledgers[len(ledgers)-1].Name, ShouldEqual, ledger.Name)
+			})
+		})
+
+		Convey(".Transact", func() {
+
+			err := pgStore.Init(types.GetSystemPublicLedgerName(), types.GetSystemPrivateLedgerName())
+			So(err, ShouldBeNil)
+
+			Convey("Should commit the transactions and blocks of all operations", func() {
+				ledger := util.Sha256(util.UUID4())
+				err := pgStore.Transact(func(store types.Store, blockchain types.Blockchain) error {
+					if _, err := store.CreateLedger(types.SystemCocoonID, ledger, true, true); err != nil {
+						return err
+					}
+					tx := &types.Transaction{ID: util.Sha256(util.UUID4()), Key: "key", Value: "value"}
+					_, err := store.PutThen(ledger, []*types.Transaction{tx}, func(validTxs []*types.Transaction) error {
+						_, err := blockchain.CreateBlock(util.UUID4(), ledger, validTxs)
+						return err
+					})
+					return err
+				})
+				So(err, ShouldBeNil)
+
+				found, err := pgStore.Get(ledger, "key", false)
+				So(err, ShouldBeNil)
+				So(found, ShouldNotBeNil)
+				height, err := pgChain.GetChainHeight(ledger)
+				So(err, ShouldBeNil)
+				So(height, ShouldEqual, 1)
+			})
+
+			Convey("Should write nothing if fn returns an error", func() {
+				ledger := util.Sha256(util.UUID4())
+				err := pgStore.Transact(func(store types.Store, blockchain types.Blockchain) error {
+					if _, err := store.CreateLedger(types.SystemCocoonID, ledger, true, true); err != nil {
+						return err
+					}
+					return fmt.Errorf("abort")
+				})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "abort")
+
+				found, err := pgStore.GetLedger(ledger)
+				So(err, ShouldBeNil)
+				So(found, ShouldBeNil)
+				chain, err := pgChain.GetChain(ledger)
+				So(err, ShouldBeNil)
+				So(chain, ShouldBeNil)
+			})
 		})
 	})
 }
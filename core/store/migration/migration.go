@@ -0,0 +1,204 @@
+// Package migration applies ordered schema migrations to a postgres database. The
+// version of the schema of each component (e.g the store or the blockchain) is kept
+// in a version table so that only migrations that have not been applied are run.
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// VersionTableName is the name of the table where applied migrations are recorded
+const VersionTableName = "schema_migrations"
+
+// Migration describes a change to the schema of a component. Up must be
+// safe to run against tables created before the migration was added.
+type Migration struct {
+	Version     uint
+	Description string
+	Up          func(tx *gorm.DB) error
+}
+
+// SchemaVersion records a migration applied to a component
+type SchemaVersion struct {
+	Component   string `gorm:"type:varchar(64);primary_key"`
+	Version     uint   `gorm:"primary_key"`
+	Description string `gorm:"type:text"`
+	AppliedAt   int64
+}
+
+// TableName returns the name of the version table
+func (SchemaVersion) TableName() string {
+	return VersionTableName
+}
+
+type byVersion []*Migration
+
+func (m byVersion) Len() int           { return len(m) }
+func (m byVersion) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m byVersion) Less(i, j int) bool { return m[i].Version < m[j].Version }
+
+// Migrator applies the migrations of a component
+type Migrator struct {
+	db         *gorm.DB
+	component  string
+	migrations []*Migration
+}
+
+// New creates a migrator for the migrations of a component.
+// The migrations are applied in order of version.
+func New(db *gorm.DB, component string, migrations []*Migration) *Migrator {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Sort(byVersion(sorted))
+	return &Migrator{db: db, component: component, migrations: sorted}
+}
+
+// GetComponent returns the name of the component
+func (m *Migrator) GetComponent() string {
+	return m.component
+}
+
+// init creates the version table if it does not exist
+func (m *Migrator) init() error {
+	if !m.db.HasTable(VersionTableName) {
+		if err := m.db.CreateTable(&SchemaVersion{}).Error; err != nil {
+			return fmt.Errorf("failed to create `%s` table. %s", VersionTableName, err)
+		}
+	}
+	return nil
+}
+
+// Version returns the version of the most recent migration applied to the component.
+// It returns zero if no migration has been applied.
+func (m *Migrator) Version() (uint, error) {
+
+	if err := m.init(); err != nil {
+		return 0, err
+	}
+
+	var version SchemaVersion
+	err := m.db.Where("component = ?", m.component).Order("version desc").First(&version).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return 0, fmt.Errorf("failed to get schema version. %s", err)
+	}
+
+	return version.Version, nil
+}
+
+// Pending returns the migrations that have not been applied, in the order they will be applied
+func (m *Migrator) Pending() ([]*Migration, error) {
+
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Migrate applies the pending migrations and returns them. The migrations are applied
+// in a single database transaction; if one fails, none is applied. In dry-run mode, the
+// migrations are run and then rolled back so that problems are found without changing
+// the database.
+func (m *Migrator) Migrate(dryRun bool) ([]*Migration, error) {
+
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	} else if len(pending) == 0 {
+		return nil, nil
+	}
+
+	tx := m.db.Begin()
+	for _, migration := range pending {
+
+		if err := migration.Up(tx); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("migration %d (%s) failed. %s", migration.Version, migration.Description, err)
+		}
+
+		err = tx.Create(&SchemaVersion{
+			Component:   m.component,
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now().Unix(),
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record migration %d. %s", migration.Version, err)
+		}
+	}
+
+	if dryRun {
+		if err := tx.Rollback().Error; err != nil {
+			return nil, fmt.Errorf("failed to roll back migrations. %s", err)
+		}
+		return pending, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit migrations. %s", err)
+	}
+
+	return pending, nil
+}
+
+// Ensure brings the schema of a new database up to date. A new database is one that has
+// none of the tables of the component. It returns an error if an existing database has
+// pending migrations; these must be applied explicitly with the orderer's migrate command.
+func (m *Migrator) Ensure(newDatabase bool) error {
+
+	if newDatabase {
+		_, err := m.Migrate(false)
+		return err
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	} else if len(pending) > 0 {
+		return fmt.Errorf("%s schema is out of date: %d migration(s) pending. Run `orderer migrate` to apply them", m.component, len(pending))
+	}
+
+	return nil
+}
+
+// HasTable checks whether a table exists. Unlike gorm's HasTable, it
+// uses the given database transaction so that tables created earlier
+// in the transaction are found.
+func HasTable(tx *gorm.DB, table string) (bool, error) {
+	var count int
+	err := tx.Raw("SELECT count(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?", table).
+		Row().Scan(&count)
+	return count > 0, err
+}
+
+// HasColumn checks whether a table has a column, using the given database transaction
+func HasColumn(tx *gorm.DB, table, column string) (bool, error) {
+	var count int
+	err := tx.Raw("SELECT count(*) FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?", table, column).
+		Row().Scan(&count)
+	return count > 0, err
+}
+
+// AddColumn adds a column to a table if the table does not have it
+func AddColumn(tx *gorm.DB, table, column, definition string) error {
+	has, err := HasColumn(tx, table, column)
+	if err != nil {
+		return err
+	} else if has {
+		return nil
+	}
+	return tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)).Error
+}
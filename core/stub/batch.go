@@ -0,0 +1,155 @@
+package stub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// Batch collects writes to one or more ledgers of a cocoon so that they are
+// committed atomically. Either every transaction of the batch is stored or none is.
+// A batch is created with Link.Batch and its writes are sent when Commit is called.
+// The first error returned by a write is returned by Commit.
+type Batch struct {
+	link   *Link
+	groups []*types.LedgerTransactions
+	txs    []*types.Transaction
+	err    error
+}
+
+// Batch creates an atomic multi-ledger write
+func (link *Link) Batch() *Batch {
+	return &Batch{link: link}
+}
+
+// add adds a transaction to the group of its ledger
+func (b *Batch) add(ledgerName string, tx *types.Transaction) *Batch {
+
+	if b.err != nil {
+		return b
+	}
+
+	if !common.IsValidResName(tx.Key) {
+		b.err = types.ErrInvalidResourceName
+		return b
+	}
+
+	// only the transactions created by Emit are added to the event ledger
+	if ledgerName == types.EventLedgerName && tx.Ledger != types.EventLedgerName {
+		b.err = ErrEventLedgerWrite
+		return b
+	}
+
+	tx.ID = util.UUID4()
+	tx.Ledger = ledgerName
+	tx.LedgerInternal = types.MakeLedgerName(b.link.GetCocoonID(), ledgerName)
+	tx.KeyInternal = types.MakeTxKey(b.link.GetCocoonID(), tx.Key)
+	tx.CreatedAt = time.Now().Unix()
+	tx.Hash = tx.MakeHash()
+
+	var group *types.LedgerTransactions
+	for _, g := range b.groups {
+		if g.Ledger == ledgerName {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		group = &types.LedgerTransactions{Ledger: ledgerName}
+		b.groups = append(b.groups, group)
+	}
+
+	group.Transactions = append(group.Transactions, tx)
+	b.txs = append(b.txs, tx)
+	return b
+}
+
+// Put adds a write of a key to the batch
+func (b *Batch) Put(ledgerName, key string, value []byte) *Batch {
+	return b.add(ledgerName, &types.Transaction{Key: key, Value: string(value)})
+}
+
+// PutSafe adds a write of a key to the batch that fails if a previous
+// transaction references the revision id (see Link.PutSafe)
+func (b *Batch) PutSafe(revisionID, ledgerName, key string, value []byte) *Batch {
+	return b.add(ledgerName, &types.Transaction{Key: key, Value: string(value), RevisionTo: revisionID})
+}
+
+// PutIfUnchanged adds a write of a key to the batch that fails if any
+// key of the read-set has changed since it was read (see Link.PutIfUnchanged)
+func (b *Batch) PutIfUnchanged(readSet []*types.KeyRevision, ledgerName, key string, value []byte) *Batch {
+	return b.add(ledgerName, &types.Transaction{Key: key, Value: string(value), ReadSet: readSet})
+}
+
+// Delete adds the deletion of a key to the batch. The most recent
+// transaction of the key is fetched when the deletion is added.
+func (b *Batch) Delete(ledgerName, key string) *Batch {
+
+	if b.err != nil {
+		return b
+	}
+
+	curTx, err := b.link.Get(ledgerName, key)
+	if err != nil {
+		b.err = err
+		return b
+	} else if curTx == nil {
+		b.err = fmt.Errorf("failed to delete key: %s", types.ErrTxNotFound)
+		return b
+	}
+
+	return b.add(ledgerName, &types.Transaction{Key: key, RevisionTo: curTx.ID, Deleted: true})
+}
+
+// Commit atomically stores the transactions of the batch. It returns the stored
+// transactions in the order they were added; transactions of chained ledgers
+// include the block of their ledger. If any transaction fails, nothing is stored
+// and the error of the failed transaction is returned.
+func (b *Batch) Commit() ([]*types.Transaction, error) {
+
+	if b.err != nil {
+		return nil, b.err
+	} else if len(b.txs) == 0 {
+		return nil, fmt.Errorf("batch has no transaction")
+	}
+
+	batchJSON, _ := util.ToJSON(b.groups)
+	resultBs, err := sendLedgerOp(b.link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxPutMulti,
+		LinkTo: b.link.GetCocoonID(),
+		Body:   batchJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit batch: %s", err)
+	}
+
+	var result types.PutMultiResult
+	if err := util.FromJSON(resultBs, &result); err != nil {
+		return nil, common.JSONCoerceErr("putMultiResult", err)
+	}
+
+	// find the transaction that caused the batch to fail
+	// and set the block of the transactions of chained ledgers
+	for i, group := range b.groups {
+		if i >= len(result.Results) {
+			return nil, fmt.Errorf("failed to commit batch: missing result of ledger %s", group.Ledger)
+		}
+		for _, receipt := range result.Results[i].TxReceipts {
+			if len(receipt.Err) > 0 && receipt.Err != types.ErrBatchAborted.Error() {
+				return nil, fmt.Errorf("failed to commit batch: tx %s: %s", receipt.ID, receipt.Err)
+			}
+		}
+		if block := result.Results[i].Block; block != nil && len(block.ID) > 0 {
+			for _, tx := range group.Transactions {
+				tx.Block = block
+			}
+		}
+	}
+
+	return b.txs, nil
+}
@@ -0,0 +1,45 @@
+package stub
+
+import (
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/types"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBatch(t *testing.T) {
+	Convey("Batch", t, func() {
+
+		link := NewLink("cocoon-123")
+
+		Convey("Should group transactions by ledger in the order the ledgers were first written", func() {
+			b := link.Batch().
+				Put("accounts", "alice", []byte("90")).
+				Put("audit", "log1", []byte("debit")).
+				PutSafe("rev1", "accounts", "bob", []byte("110"))
+			So(b.err, ShouldBeNil)
+			So(len(b.groups), ShouldEqual, 2)
+			So(b.groups[0].Ledger, ShouldEqual, "accounts")
+			So(len(b.groups[0].Transactions), ShouldEqual, 2)
+			So(b.groups[0].Transactions[1].RevisionTo, ShouldEqual, "rev1")
+			So(b.groups[1].Ledger, ShouldEqual, "audit")
+			So(len(b.txs), ShouldEqual, 3)
+			So(b.txs[0].KeyInternal, ShouldEqual, types.MakeTxKey("cocoon-123", "alice"))
+			So(b.txs[0].Hash, ShouldEqual, b.txs[0].MakeHash())
+		})
+
+		Convey("Should return the first error on commit", func() {
+			_, err := link.Batch().
+				Put("accounts", "invalid key!", []byte("90")).
+				Put("accounts", "alice", []byte("90")).
+				Commit()
+			So(err, ShouldEqual, types.ErrInvalidResourceName)
+		})
+
+		Convey("Should return error if the batch is empty", func() {
+			_, err := link.Batch().Commit()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "batch has no transaction")
+		})
+	})
+}
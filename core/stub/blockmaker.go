@@ -116,7 +116,7 @@ func (b *blockMaker) sendToEntries(_entries []*entry, msg interface{}) {
 // transactions are collected and grouped into separate sub entries and passed to the commit function.
 // The commit function determines how the blocks of entries passed to it are included in the blockchain
 // The return value of the committer function is passed to all entry's/transaction's response channel
-// before the channel is closed.
+// before the channel is closed. It returns when the block maker is stopped.
 func (b *blockMaker) Begin(committerFunc func([]*entry) interface{}) {
 	b.t = time.NewTicker(b.interval)
 	for {
@@ -136,13 +136,13 @@ func (b *blockMaker) Begin(committerFunc func([]*entry) interface{}) {
 			}
 		case <-b.stop:
 			b.t.Stop()
+			return
 		}
 	}
 }
 
 // Stop stops the block maker
 func (b *blockMaker) Stop() {
-	b.t.Stop()
 	close(b.stop)
 }
 
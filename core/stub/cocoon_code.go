@@ -1,8 +1,20 @@
 package stub
 
+import (
+	context "golang.org/x/net/context"
+)
+
 // CocoonCode defines the interface of a cocoon code.
 type CocoonCode interface {
 	OnInit() error
 	OnInvoke(header Metadata, function string, params []string) ([]byte, error)
 	OnStop()
 }
+
+// ContextCocoonCode is implemented by cocoon codes that receive the context of
+// their invocations. The context carries the deadline of the invocation and is
+// canceled when the invoker goes away. When implemented, OnInvokeContext is called
+// instead of OnInvoke. Use Link.WithContext to bind the operations of a link to it.
+type ContextCocoonCode interface {
+	OnInvokeContext(ctx context.Context, header Metadata, function string, params []string) ([]byte, error)
+}
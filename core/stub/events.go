@@ -0,0 +1,131 @@
+package stub
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/common"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+)
+
+// newEventTx creates the transaction that records an event of
+// a cocoon. The payload is encoded to JSON.
+func newEventTx(cocoonID, name string, payload interface{}) (*types.Transaction, error) {
+
+	if !common.IsValidResName(name) {
+		return nil, types.ErrInvalidResourceName
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event payload: %s", err)
+	}
+
+	key := util.UUID4()
+	tx := &types.Transaction{
+		ID:             util.UUID4(),
+		Ledger:         types.EventLedgerName,
+		LedgerInternal: types.MakeLedgerName(cocoonID, types.EventLedgerName),
+		Key:            key,
+		KeyInternal:    types.MakeTxKey(cocoonID, key),
+		Value:          types.MakeEventValue(name, payloadJSON),
+		CreatedAt:      time.Now().Unix(),
+	}
+	tx.Hash = tx.MakeHash()
+	return tx, nil
+}
+
+// Emit records an event of the cocoon in its event ledger. The payload is encoded
+// to JSON. The event ledger is a chained ledger of its own: the event is added to
+// a block of the event ledger by the block maker and Emit returns when that block
+// is created. The event is not atomic with the writes of the invocation; it is
+// recorded even if they fail and may be recorded before they are. Use Batch.Emit
+// to record events only if the writes of a batch are stored.
+func Emit(name string, payload interface{}) (*types.Event, error) {
+
+	tx, err := newEventTx(GetCocoonID(), name, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	respChan := make(chan interface{})
+	defaultBlockMaker.Add(&entry{
+		Tx:       tx,
+		RespChan: respChan,
+		LinkTo:   GetCocoonID(),
+	})
+
+	switch v := (<-respChan).(type) {
+	case error:
+		return nil, fmt.Errorf("failed to emit event: %s", v)
+	case *types.Block:
+		tx.Block = v
+		return types.GetEvents(GetCocoonID(), &types.LedgerEvent{
+			Transactions: []*types.Transaction{tx},
+			Block:        v,
+		})[0], nil
+	default:
+		return nil, fmt.Errorf("unexpected response")
+	}
+}
+
+// Emit adds an event of the cocoon to the batch. The event is recorded
+// in a block of the event ledger, in the same store transaction as the
+// writes of the batch, so it is recorded if and only if the batch is
+// committed. The batch must be created on a link to the current cocoon.
+func (b *Batch) Emit(name string, payload interface{}) *Batch {
+
+	if b.err != nil {
+		return b
+	}
+
+	tx, err := newEventTx(b.link.GetCocoonID(), name, payload)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	return b.add(types.EventLedgerName, tx)
+}
+
+// WatchEvents subscribes to the events of the linked cocoon. Only events with one
+// of the names are sent, or all events if no name is given. If fromBlock is set,
+// the events recorded from that block of the event ledger are sent before new
+// events; use it to resume a previous subscription from the BlockNumber of the
+// last event received, skipping the events of that block with an Index lower
+// than or equal to the Index of the last event. Call the returned function to
+// end the subscription. The channel is closed when the subscription ends.
+func (link *Link) WatchEvents(fromBlock uint, names ...string) (<-chan *types.Event, func(), error) {
+
+	ledgerEvents, cancel, err := link.WatchFrom(types.EventLedgerName, "", fromBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan *types.Event)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+		for ledgerEvent := range ledgerEvents {
+			for _, event := range types.GetEvents(link.GetCocoonID(), ledgerEvent, names...) {
+				select {
+				case events <- event:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+		})
+	}, nil
+}
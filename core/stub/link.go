@@ -2,17 +2,25 @@ package stub
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/ellcrys/util"
+	"github.com/ellcrys/cocoon/core/blockchain/merkle"
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
 	"github.com/ellcrys/cocoon/core/types"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 // ErrObjectLocked reprents an error about an object or transaction locked by another process
 var ErrObjectLocked = fmt.Errorf("failed to acquire lock. object has been locked by another process")
 
+// ErrEventLedgerWrite represents an error about a write to the event ledger other than by emitting an event
+var ErrEventLedgerWrite = fmt.Errorf("events can only be recorded by emitting them")
+
 // Metadata defines a structure for storing link related header information
 type Metadata map[string]string
 
@@ -31,11 +39,26 @@ func (m Metadata) Has(key string) bool {
 	return m[key] != ""
 }
 
+// CallerCocoonID returns the id of the cocoon that invoked the function
+// through Link.Invoke. It is empty if the function was invoked through the
+// connector's HTTP API.
+func (m Metadata) CallerCocoonID() string {
+	return m[types.HeaderCallerCocoonID]
+}
+
+// ScheduledJob returns the name of the job of the cocoon schedule for
+// which the connector invoked the function. It is empty if the function
+// was not invoked by the schedule.
+func (m Metadata) ScheduledJob() string {
+	return m[types.HeaderScheduledJob]
+}
+
 // Link provides access to all platform services available to
 // the cocoon code.
 type Link struct {
 	cocoonID   string
 	native     bool
+	ctx        context.Context
 	InMetadata Metadata // incoming metadata
 }
 
@@ -60,6 +83,26 @@ func (link *Link) IsNative() bool {
 	return link.native
 }
 
+// WithContext returns a copy of the link whose operations are bound to ctx,
+// such as the context of an invocation received by OnInvokeContext. An
+// operation fails if the context is canceled or its deadline passes before
+// it completes. A write to a chained ledger is not canceled once it is
+// passed to the block maker.
+func (link *Link) WithContext(ctx context.Context) *Link {
+	l := *link
+	l.ctx = ctx
+	return &l
+}
+
+// Context returns the context of the link.
+// It is an empty context unless set through WithContext.
+func (link *Link) Context() context.Context {
+	if link.ctx == nil {
+		return context.Background()
+	}
+	return link.ctx
+}
+
 // GetCocoonID returns the cocoon id attached to this link
 func (link *Link) GetCocoonID() string {
 	return link.cocoonID
@@ -78,7 +121,29 @@ func (link *Link) GetInvokeID() string {
 
 // NewRangeGetter creates an instance of a RangeGetter for a specified ledger.
 func (link *Link) NewRangeGetter(ledgerName, start, end string, inclusive bool) *RangeGetter {
-	return NewRangeGetter(ledgerName, link.GetCocoonID(), start, end, inclusive)
+	rg := NewRangeGetter(ledgerName, link.GetCocoonID(), start, end, inclusive)
+	rg.ctx = link.ctx
+	return rg
+}
+
+// NewPrefixRangeGetter creates an instance of a RangeGetter that
+// traverses the keys of a specified ledger that begin with a prefix.
+func (link *Link) NewPrefixRangeGetter(ledgerName, prefix string) *RangeGetter {
+	rg := NewPrefixRangeGetter(ledgerName, link.GetCocoonID(), prefix)
+	rg.ctx = link.ctx
+	return rg
+}
+
+// Query creates an iterator over the keys of a ledger whose value at the path of
+// a secondary index matches a query. The value is encoded to JSON and compared to
+// the indexed values of the same JSON type with op, which is one of types.QueryOpEq,
+// types.QueryOpLt, types.QueryOpLte, types.QueryOpGt or types.QueryOpGte.
+// Numbers are compared numerically and strings by their bytes. Keys are
+// returned in ascending order with their most recent transaction.
+func (link *Link) Query(ledgerName, index, op string, value interface{}) *QueryIterator {
+	qi := NewQueryIterator(ledgerName, link.GetCocoonID(), index, op, value)
+	qi.ctx = link.ctx
+	return qi
 }
 
 // NewLedger creates a new ledger by sending an
@@ -86,17 +151,29 @@ func (link *Link) NewRangeGetter(ledgerName, start, end string, inclusive bool)
 // If chained is set to true, a blockchain is created and subsequent
 // PUT operations to the ledger will be included in the types. Otherwise,
 // PUT operations will only be included in the types.
-func (link *Link) NewLedger(name string, chained, public bool) (*types.Ledger, error) {
+// Secondary indexes on JSON paths of the values of the ledger can be
+// declared and used to find keys by their value with Query.
+func (link *Link) NewLedger(name string, chained, public bool, indexes ...*types.Index) (*types.Ledger, error) {
 
 	if !common.IsValidResName(name) {
 		return nil, types.ErrInvalidResourceName
 	}
 
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	if err := types.Indexes(indexes).Validate(); err != nil {
+		return nil, err
+	}
+
+	params := []string{name, fmt.Sprintf("%t", chained), fmt.Sprintf("%t", public)}
+	if len(indexes) > 0 {
+		indexesJSON, _ := util.ToJSON(indexes)
+		params = append(params, string(indexesJSON))
+	}
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxNewLedger,
 		LinkTo: link.GetCocoonID(),
-		Params: []string{name, fmt.Sprintf("%t", chained), fmt.Sprintf("%t", public)},
+		Params: params,
 	})
 
 	if err != nil {
@@ -114,7 +191,7 @@ func (link *Link) NewLedger(name string, chained, public bool) (*types.Ledger, e
 // GetLedger fetches a ledger
 func (link *Link) GetLedger(ledgerName string) (*types.Ledger, error) {
 
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxGetLedger,
 		LinkTo: link.GetCocoonID(),
@@ -133,14 +210,16 @@ func (link *Link) GetLedger(ledgerName string) (*types.Ledger, error) {
 	return &ledger, nil
 }
 
-func (link *Link) put(revisionID, ledgerName, key string, value []byte) (*types.Transaction, error) {
-
-	start := time.Now()
+func (link *Link) put(revisionID, ledgerName, key string, value []byte, readSet []*types.KeyRevision) (*types.Transaction, error) {
 
 	if !common.IsValidResName(key) {
 		return nil, types.ErrInvalidResourceName
 	}
 
+	if ledgerName == types.EventLedgerName {
+		return nil, ErrEventLedgerWrite
+	}
+
 	ledger, err := link.GetLedger(ledgerName)
 	if err != nil {
 		return nil, err
@@ -155,11 +234,15 @@ func (link *Link) put(revisionID, ledgerName, key string, value []byte) (*types.
 		KeyInternal:    types.MakeTxKey(link.GetCocoonID(), key),
 		Value:          string(value),
 		CreatedAt:      time.Now().Unix(),
+		ReadSet:        readSet,
 	}
 
 	tx.Hash = tx.MakeHash()
 
 	if ledger.Chained {
+		if err := link.Context().Err(); err != nil {
+			return nil, err
+		}
 		respChan := make(chan interface{})
 		defaultBlockMaker.Add(&entry{
 			Tx:       tx,
@@ -168,8 +251,6 @@ func (link *Link) put(revisionID, ledgerName, key string, value []byte) (*types.
 		})
 		result := <-respChan
 
-		log.Debug("Put(): Time taken: ", time.Since(start))
-
 		switch v := result.(type) {
 		case error:
 			if common.CompareErr(v, ErrObjectLocked) == 0 {
@@ -185,7 +266,7 @@ func (link *Link) put(revisionID, ledgerName, key string, value []byte) (*types.
 	}
 
 	txJSON, _ := util.ToJSON([]*types.Transaction{tx})
-	putTxResultBs, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	putTxResultBs, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxPut,
 		LinkTo: link.GetCocoonID(),
@@ -212,14 +293,12 @@ func (link *Link) put(revisionID, ledgerName, key string, value []byte) (*types.
 		}
 	}
 
-	log.Debug("Put(): Time taken: ", time.Since(start))
-
 	return tx, nil
 }
 
 // Put puts a transaction in a ledger
 func (link *Link) Put(ledgerName, key string, value []byte) (*types.Transaction, error) {
-	return link.put("", ledgerName, key, value)
+	return link.put("", ledgerName, key, value, nil)
 }
 
 // PutSafe puts a transaction and also ensures that no previous transaction references the revision
@@ -228,17 +307,104 @@ func (link *Link) Put(ledgerName, key string, value []byte) (*types.Transaction,
 // previous transactions to ensure updates are not made based on records that may have been updated by
 // another process.
 func (link *Link) PutSafe(revisionID, ledgerName, key string, value []byte) (*types.Transaction, error) {
-	return link.put(revisionID, ledgerName, key, value)
+	return link.put(revisionID, ledgerName, key, value, nil)
 }
 
-// Get gets a transaction from a ledger
+// PutIfUnchanged puts a transaction only if the keys it was computed from have not changed
+// since they were read. The read-set holds the ID of the most recent transaction of each
+// key that was read (see GetIncludeDeleted), or an empty revision for keys that did not exist.
+// If any of the keys has been revised, a stale object error is returned.
+func (link *Link) PutIfUnchanged(readSet []*types.KeyRevision, ledgerName, key string, value []byte) (*types.Transaction, error) {
+	return link.put("", ledgerName, key, value, readSet)
+}
+
+// Delete deletes a key from a ledger by adding a tombstone transaction that
+// references the most recent transaction of the key. The key is hidden from
+// Get and range operations but its history is preserved. On chained ledgers,
+// the tombstone is included in a block.
+func (link *Link) Delete(ledgerName, key string) (*types.Transaction, error) {
+
+	if !common.IsValidResName(key) {
+		return nil, types.ErrInvalidResourceName
+	}
+
+	ledger, err := link.GetLedger(ledgerName)
+	if err != nil {
+		return nil, err
+	}
+
+	curTx, err := link.Get(ledgerName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &types.Transaction{
+		ID:             util.UUID4(),
+		RevisionTo:     curTx.ID,
+		Ledger:         ledger.Name,
+		LedgerInternal: types.MakeLedgerName(link.GetCocoonID(), ledger.Name),
+		Key:            key,
+		KeyInternal:    types.MakeTxKey(link.GetCocoonID(), key),
+		Deleted:        true,
+		CreatedAt:      time.Now().Unix(),
+	}
+
+	tx.Hash = tx.MakeHash()
+
+	txJSON, _ := util.ToJSON([]*types.Transaction{tx})
+	putTxResultBs, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxDelete,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName},
+		Body:   txJSON,
+	})
+
+	if err != nil {
+		if common.CompareErr(err, ErrObjectLocked) == 0 {
+			return nil, ErrObjectLocked
+		}
+		return nil, fmt.Errorf("failed to delete key: %s", err)
+	}
+
+	var putTxResult types.PutResult
+	if err := util.FromJSON(putTxResultBs, &putTxResult); err != nil {
+		return nil, common.JSONCoerceErr("putTxResult", err)
+	}
+
+	// ensure transaction was successful
+	for _, txResult := range putTxResult.TxReceipts {
+		if txResult.ID == tx.ID && len(txResult.Err) > 0 {
+			return nil, fmt.Errorf("failed to delete key: %s", txResult.Err)
+		}
+	}
+
+	if putTxResult.Block != nil && len(putTxResult.Block.ID) > 0 {
+		tx.Block = putTxResult.Block
+	}
+
+	return tx, nil
+}
+
+// Get gets a transaction from a ledger. If the key has been deleted,
+// an error is returned.
 func (link *Link) Get(ledgerName, key string) (*types.Transaction, error) {
+	return link.get(ledgerName, key, false)
+}
+
+// GetIncludeDeleted gets a transaction from a ledger. Unlike Get, it
+// returns the tombstone transaction of a deleted key.
+func (link *Link) GetIncludeDeleted(ledgerName, key string) (*types.Transaction, error) {
+	return link.get(ledgerName, key, true)
+}
+
+func (link *Link) get(ledgerName, key string, includeDeleted bool) (*types.Transaction, error) {
 
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxGet,
 		LinkTo: link.GetCocoonID(),
-		Params: []string{ledgerName, key},
+		Params: []string{ledgerName, key, strconv.FormatBool(includeDeleted)},
 	})
 
 	if err != nil {
@@ -263,7 +429,7 @@ func (link *Link) Get(ledgerName, key string) (*types.Transaction, error) {
 // GetBlock gets a block from a ledger by a block id
 func (link *Link) GetBlock(ledgerName, id string) (*types.Block, error) {
 
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxGetBlockByID,
 		LinkTo: link.GetCocoonID(),
@@ -282,6 +448,192 @@ func (link *Link) GetBlock(ledgerName, id string) (*types.Block, error) {
 	return &blk, nil
 }
 
+// GetBlockByNumber gets a block of a chained ledger by its number.
+// Block numbers start from 1.
+func (link *Link) GetBlockByNumber(ledgerName string, number uint) (*types.Block, error) {
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxGetBlockByNumber,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName, strconv.FormatUint(uint64(number), 10)},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %s", err)
+	}
+
+	var blk types.Block
+	if err = util.FromJSON(result, &blk); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response data")
+	}
+
+	return &blk, nil
+}
+
+// GetChainHeight gets the number of the last block of a chained ledger.
+// It returns zero if the ledger has no block.
+func (link *Link) GetChainHeight(ledgerName string) (uint, error) {
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxGetChainHeight,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName},
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get chain height: %s", err)
+	}
+
+	height, err := strconv.ParseUint(string(result), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse chain height")
+	}
+
+	return uint(height), nil
+}
+
+// ListBlocks gets the blocks of a chained ledger in ascending order of block number,
+// starting from the block with number fromNumber. The orderer returns at most 100
+// blocks; a limit of zero returns the maximum. To walk a chain, call ListBlocks
+// again from the number after the last block returned until no block is returned.
+func (link *Link) ListBlocks(ledgerName string, fromNumber uint, limit int) ([]*types.Block, error) {
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxListBlocks,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName, strconv.FormatUint(uint64(fromNumber), 10), strconv.Itoa(limit)},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocks: %s", err)
+	}
+
+	var blocks []*types.Block
+	if err = util.FromJSON(result, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response data")
+	}
+
+	return blocks, nil
+}
+
+// GetHistory gets the transactions of a key from a ledger, most recent first.
+// Each transaction includes its block id and creation time. To fetch the next page,
+// pass the number of the last transaction returned as the cursor. A cursor of
+// zero starts from the most recent transaction.
+func (link *Link) GetHistory(ledgerName, key string, limit int, cursor uint) ([]*types.Transaction, error) {
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxGetHistory,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName, key, strconv.Itoa(limit), strconv.FormatUint(uint64(cursor), 10)},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction history: %s", err)
+	}
+
+	var txs []*types.Transaction
+	if err = util.FromJSON(result, &txs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response data")
+	}
+
+	for _, tx := range txs {
+		if tx.Block != nil && tx.Block.ID == "" {
+			tx.Block = nil
+		}
+	}
+
+	return txs, nil
+}
+
+// GetTxProof gets a proof of the inclusion of a transaction in its block. The ledger
+// must be chained. Use the Verify method of the proof to check it against a block hash.
+func (link *Link) GetTxProof(ledgerName, txID string) (*merkle.Proof, error) {
+
+	result, err := sendLedgerOp(link.Context(), &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxGetTxProof,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName, txID},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction proof: %s", err)
+	}
+
+	var proof merkle.Proof
+	if err = util.FromJSON(result, &proof); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response data")
+	}
+
+	return &proof, nil
+}
+
+// Watch subscribes to the changes of a ledger. Transactions with keys that begin with
+// the prefix are sent to the returned channel as they are committed. Call the returned
+// function to end the subscription. The channel is closed when the subscription ends.
+func (link *Link) Watch(ledgerName, prefix string) (<-chan *types.LedgerEvent, func(), error) {
+	return link.WatchFrom(ledgerName, prefix, 0)
+}
+
+// WatchFrom is like Watch but first sends the blocks of a chained ledger
+// beginning from a block number. Use it to resume a previous subscription
+// from the block after the last block received.
+func (link *Link) WatchFrom(ledgerName, prefix string, fromBlock uint) (<-chan *types.LedgerEvent, func(), error) {
+
+	client, err := grpc.Dial(connectorRPCAddr, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(link.Context())
+	stream, err := proto_connector.NewConnectorClient(client).Subscribe(ctx, &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxSubscribe,
+		LinkTo: link.GetCocoonID(),
+		Params: []string{ledgerName, prefix, strconv.FormatUint(uint64(fromBlock), 10)},
+	})
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, nil, fmt.Errorf("failed to subscribe to ledger: %s", common.GetRPCErrDesc(err))
+	}
+
+	events := make(chan *types.LedgerEvent)
+
+	go func() {
+		defer close(events)
+		defer client.Close()
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					log.Errorf("ledger subscription ended: %s", common.GetRPCErrDesc(err))
+				}
+				return
+			}
+
+			var event types.LedgerEvent
+			if err := util.FromJSON(resp.GetBody(), &event); err != nil {
+				log.Errorf("failed to unmarshal ledger event: %s", err)
+				continue
+			}
+
+			select {
+			case events <- &event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, func() { cancel() }, nil
+}
+
 // Lock acquires a lock on the specified key within the scope of the
 // linked cocoon code. An error is returned if it failed to acquire the lock.
 func (link *Link) Lock(key string, ttl time.Duration) (*Lock, error) {
@@ -289,8 +641,53 @@ func (link *Link) Lock(key string, ttl time.Duration) (*Lock, error) {
 	if err != nil {
 		return nil, err
 	}
+	lock.ctx = link.ctx
 	if err := lock.Acquire(); err != nil {
 		return nil, err
 	}
 	return lock, nil
 }
+
+// Invoke calls a function of the linked cocoon code and returns its result.
+// The call fails if it does not complete within InvokeTimeout.
+func (link *Link) Invoke(function string, params []string) ([]byte, error) {
+	return link.InvokeWithTimeout(InvokeTimeout, function, params)
+}
+
+// InvokeWithTimeout calls a function of the linked cocoon code and returns its result.
+// The invoked cocoon code receives the id of the current cocoon as the caller and its
+// ACL must grant the invoke privilege on the function to the current cocoon, unless it
+// is natively linked. An error returned by the function with a code is returned as an *Error.
+// The body of a raw response (see Response) is returned, unless its status is 400 or above.
+func (link *Link) InvokeWithTimeout(timeout time.Duration, function string, params []string) ([]byte, error) {
+
+	resp, err := transact(link.Context(), &proto_connector.Request{
+		OpType: proto_connector.OpType_CocoonCodeOp,
+		CocoonCodeOp: &proto_connector.CocoonCodeOperation{
+			ID:       util.UUID4(),
+			Function: function,
+			Params:   params,
+			LinkTo:   link.cocoonID,
+		},
+	}, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetRaw() {
+		if resp.GetStatus() >= 400 {
+			return nil, fmt.Errorf("function responded with status %d: %s", resp.GetStatus(), resp.GetBody())
+		}
+		return resp.GetBody(), nil
+	}
+
+	if resp.GetStatus() != 200 {
+		var invokeErr Error
+		if err := util.FromJSON(resp.GetBody(), &invokeErr); err != nil || len(invokeErr.Code) == 0 {
+			return nil, fmt.Errorf("%s", resp.GetBody())
+		}
+		return nil, &invokeErr
+	}
+
+	return resp.GetBody(), nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ellcrys/cocoon/core/common"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
 	"github.com/ellcrys/cocoon/core/types"
+	context "golang.org/x/net/context"
 )
 
 // ErrLockNotAcquired defines an error about a lock not acquired
@@ -17,6 +18,7 @@ var ErrLockNotAcquired = fmt.Errorf("lock not acquired")
 
 // Lock defines a structure for a platform-wide lock mechanism
 type Lock struct {
+	ctx           context.Context
 	cocoonID      string
 	key           string
 	lockSessionID string
@@ -52,7 +54,7 @@ func newLock(cocoonID, key string, ttl time.Duration) (*Lock, error) {
 // cocoon scope defined in the lock instance. If successful, Lock object is returned.
 // An acquired lock will also be enforced in other linked cocoon codes.
 func (l *Lock) Acquire() error {
-	lockSessionID, err := sendLockOp(&proto_connector.LockOperation{
+	lockSessionID, err := sendLockOp(l.ctx, &proto_connector.LockOperation{
 		Name:   types.OpLockAcquire,
 		Params: []string{l.cocoonID, l.key, strconv.Itoa(l.ttl), l.lockSessionID},
 		LinkTo: l.cocoonID,
@@ -75,7 +77,7 @@ func (l *Lock) IsAcquirer() error {
 		return fmt.Errorf("lock session not set")
 	}
 
-	_, err := sendLockOp(&proto_connector.LockOperation{
+	_, err := sendLockOp(l.ctx, &proto_connector.LockOperation{
 		Name:   types.OpLockCheckAcquire,
 		Params: []string{l.cocoonID, l.key, l.lockSessionID},
 		LinkTo: l.cocoonID,
@@ -98,7 +100,7 @@ func (l *Lock) Release() error {
 		return nil
 	}
 
-	_, err := sendLockOp(&proto_connector.LockOperation{
+	_, err := sendLockOp(l.ctx, &proto_connector.LockOperation{
 		Name:   types.OpLockRelease,
 		Params: []string{l.cocoonID, l.key, l.lockSessionID},
 		LinkTo: l.cocoonID,
@@ -101,9 +101,11 @@ func (m *InvokeParam) GetHeader() map[string]string {
 }
 
 type InvokeResponse struct {
-	ID     string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Status int32  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
-	Body   []byte `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	ID     string            `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Status int32             `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Body   []byte            `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Header map[string]string `protobuf:"bytes,4,rep,name=header" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Raw    bool              `protobuf:"varint,5,opt,name=raw,proto3" json:"raw,omitempty"`
 }
 
 func (m *InvokeResponse) Reset()                    { *m = InvokeResponse{} }
@@ -132,6 +134,20 @@ func (m *InvokeResponse) GetBody() []byte {
 	return nil
 }
 
+func (m *InvokeResponse) GetHeader() map[string]string {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *InvokeResponse) GetRaw() bool {
+	if m != nil {
+		return m.Raw
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Void)(nil), "proto_runtime.Void")
 	proto.RegisterType((*Ok)(nil), "proto_runtime.Ok")
@@ -280,25 +296,27 @@ var _Stub_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("server.proto", fileDescriptorServer) }
 
 var fileDescriptorServer = []byte{
-	// 315 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xc1, 0x4a, 0x33, 0x31,
-	0x10, 0xc7, 0x49, 0x76, 0xbb, 0x7c, 0x9d, 0xf6, 0x2b, 0x3a, 0x8a, 0x2c, 0x41, 0x61, 0xd9, 0x83,
-	0xec, 0x69, 0x91, 0xf6, 0xa2, 0x1e, 0xbc, 0xb4, 0x82, 0x05, 0xa1, 0x12, 0xc1, 0xab, 0x6c, 0xdb,
-	0x48, 0xcb, 0xb6, 0xc9, 0x92, 0x64, 0x0b, 0x7d, 0x2d, 0x9f, 0xc3, 0x87, 0x92, 0x4d, 0x97, 0x5a,
-	0xdb, 0x7a, 0xca, 0xfc, 0xff, 0x99, 0x99, 0xfc, 0x66, 0x02, 0x6d, 0x23, 0xf4, 0x4a, 0xe8, 0xb4,
-	0xd0, 0xca, 0x2a, 0xfc, 0xef, 0x8e, 0x77, 0x5d, 0x4a, 0x3b, 0x5f, 0x8a, 0x38, 0x00, 0xff, 0x4d,
-	0xcd, 0xa7, 0xf1, 0x25, 0xd0, 0x51, 0x8e, 0x17, 0x10, 0x18, 0x9b, 0xd9, 0xd2, 0x84, 0x24, 0x22,
-	0x49, 0x83, 0xd7, 0x2a, 0xfe, 0x22, 0xd0, 0x1a, 0xca, 0x95, 0xca, 0xc5, 0x4b, 0xa6, 0xb3, 0x25,
-	0x76, 0x80, 0x0e, 0x07, 0x2e, 0xa7, 0xc9, 0xe9, 0x70, 0x80, 0x0c, 0xfe, 0x7d, 0x94, 0x72, 0x62,
-	0xe7, 0x4a, 0x86, 0xd4, 0xb9, 0x5b, 0x5d, 0xf5, 0x2c, 0xaa, 0x22, 0x13, 0x7a, 0x91, 0x97, 0x34,
-	0x79, 0xad, 0xf0, 0x01, 0x82, 0x99, 0xc8, 0xa6, 0x42, 0x87, 0x7e, 0xe4, 0x25, 0xad, 0xee, 0x75,
-	0xfa, 0x8b, 0x2c, 0xdd, 0x79, 0x2f, 0x7d, 0x72, 0x89, 0x8f, 0xd2, 0xea, 0x35, 0xaf, 0xab, 0xd8,
-	0x1d, 0xb4, 0x76, 0x6c, 0x3c, 0x01, 0x2f, 0x17, 0xeb, 0x9a, 0xa9, 0x0a, 0xf1, 0x1c, 0x1a, 0xab,
-	0x6c, 0x51, 0x8a, 0x9a, 0x68, 0x23, 0xee, 0xe9, 0x2d, 0x89, 0x9f, 0xa1, 0xb3, 0xe9, 0xce, 0x85,
-	0x29, 0x94, 0x34, 0xe2, 0x60, 0xa0, 0x9f, 0x45, 0xd0, 0xdd, 0x45, 0x20, 0x82, 0x3f, 0x56, 0xd3,
-	0x75, 0xe8, 0x45, 0x24, 0x69, 0x73, 0x17, 0x77, 0x3f, 0x09, 0xf8, 0xaf, 0xb6, 0x1c, 0x63, 0xcf,
-	0x11, 0x2d, 0xec, 0xac, 0x3f, 0x13, 0x93, 0x1c, 0x4f, 0xf7, 0x06, 0x1a, 0xe5, 0xec, 0xd0, 0xc2,
-	0x3e, 0x04, 0x1b, 0x16, 0x64, 0x7f, 0x2f, 0x80, 0x5d, 0x1d, 0xbd, 0xdb, 0xe2, 0xdf, 0x54, 0x04,
-	0xaa, 0xc0, 0xb3, 0xbd, 0xb4, 0xea, 0x6b, 0xd9, 0x31, 0x73, 0x1c, 0x38, 0xaf, 0xf7, 0x1d, 0x00,
-	0x00, 0xff, 0xff, 0x38, 0xac, 0x1b, 0xb0, 0x1d, 0x02, 0x00, 0x00,
+	// 341 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xc1, 0x4a, 0xfb, 0x40,
+	0x10, 0xc6, 0xd9, 0x24, 0x0d, 0xed, 0xb4, 0xff, 0xf2, 0x77, 0x14, 0x09, 0x41, 0x21, 0xe4, 0x20,
+	0xf1, 0x12, 0xa4, 0xbd, 0xa8, 0x07, 0x41, 0x5a, 0xc1, 0x9e, 0x2a, 0x2b, 0x78, 0x95, 0xb4, 0x5d,
+	0x69, 0x49, 0x9b, 0x0d, 0xbb, 0x9b, 0x4a, 0x5f, 0xcb, 0xe7, 0xf0, 0x11, 0x7c, 0x18, 0xd9, 0x6d,
+	0xd4, 0xda, 0x46, 0x2f, 0x9e, 0x32, 0xdf, 0xc7, 0xec, 0xec, 0xef, 0x9b, 0x2c, 0xb4, 0x24, 0x13,
+	0x4b, 0x26, 0xe2, 0x5c, 0x70, 0xc5, 0xf1, 0x9f, 0xf9, 0x3c, 0x8a, 0x22, 0x53, 0xb3, 0x05, 0x0b,
+	0x5d, 0x70, 0x1e, 0xf8, 0x6c, 0x12, 0x1e, 0x81, 0x35, 0x4c, 0xf1, 0x10, 0x5c, 0xa9, 0x12, 0x55,
+	0x48, 0x8f, 0x04, 0x24, 0xaa, 0xd1, 0x52, 0x85, 0xaf, 0x04, 0x9a, 0x83, 0x6c, 0xc9, 0x53, 0x76,
+	0x97, 0x88, 0x64, 0x81, 0x6d, 0xb0, 0x06, 0x7d, 0xd3, 0xd3, 0xa0, 0xd6, 0xa0, 0x8f, 0x3e, 0xd4,
+	0x9f, 0x8a, 0x6c, 0xac, 0x66, 0x3c, 0xf3, 0x2c, 0xe3, 0x7e, 0x6a, 0x3d, 0x33, 0xd7, 0x87, 0xa4,
+	0x67, 0x07, 0x76, 0xd4, 0xa0, 0xa5, 0xc2, 0x2b, 0x70, 0xa7, 0x2c, 0x99, 0x30, 0xe1, 0x39, 0x81,
+	0x1d, 0x35, 0x3b, 0x27, 0xf1, 0x37, 0xb2, 0x78, 0xe3, 0xbe, 0xf8, 0xd6, 0x34, 0xde, 0x64, 0x4a,
+	0xac, 0x68, 0x79, 0xca, 0xbf, 0x80, 0xe6, 0x86, 0x8d, 0xff, 0xc1, 0x4e, 0xd9, 0xaa, 0x64, 0xd2,
+	0x25, 0x1e, 0x40, 0x6d, 0x99, 0xcc, 0x0b, 0x56, 0x12, 0xad, 0xc5, 0xa5, 0x75, 0x4e, 0xc2, 0x37,
+	0x02, 0xed, 0xf5, 0x78, 0xca, 0x64, 0xce, 0x33, 0xc9, 0x76, 0x12, 0x7d, 0x6d, 0xc2, 0xda, 0xdc,
+	0x04, 0x22, 0x38, 0x23, 0x3e, 0x59, 0x79, 0x76, 0x40, 0xa2, 0x16, 0x35, 0x35, 0x5e, 0x6f, 0x25,
+	0x39, 0xad, 0x4c, 0xf2, 0x71, 0x55, 0x55, 0x18, 0x4d, 0x2f, 0x92, 0x67, 0xaf, 0x16, 0x90, 0xa8,
+	0x4e, 0x75, 0xf9, 0x87, 0x78, 0x9d, 0x17, 0x02, 0xce, 0xbd, 0x2a, 0x46, 0xd8, 0x35, 0x33, 0xe6,
+	0x6a, 0xda, 0x9b, 0xb2, 0x71, 0x8a, 0x7b, 0x5b, 0x5c, 0xc3, 0xd4, 0xdf, 0xb5, 0xb0, 0x07, 0xee,
+	0x1a, 0x18, 0xfd, 0x9f, 0xff, 0x88, 0x7f, 0xfc, 0x6b, 0x46, 0x3c, 0xd3, 0x04, 0x3c, 0xc7, 0xfd,
+	0xad, 0x36, 0xfd, 0xd6, 0xfc, 0x2a, 0x73, 0xe4, 0x1a, 0xaf, 0xfb, 0x3e, 0x00, 0xed, 0x0f, 0xf2,
+	0xa8, 0xae, 0x02, 0x00, 0x00,
 }
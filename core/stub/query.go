@@ -0,0 +1,130 @@
+package stub
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	context "golang.org/x/net/context"
+)
+
+// QueryIterator defines a means to page through the keys of a
+// ledger that match a query on a secondary index. Like RangeGetter,
+// it supports resumable iteration through cursors.
+type QueryIterator struct {
+	ctx         context.Context
+	to          string
+	ledgerName  string
+	index       string
+	op          string
+	value       interface{}
+	limit       int
+	txs         []*types.Transaction
+	cursor      string
+	startCursor string
+	lastKey     string
+	done        bool
+	Error       error
+}
+
+// NewQueryIterator creates a new iterator over the keys of a ledger
+// whose value at the path of an index matches a query.
+func NewQueryIterator(ledgerName, to, index, op string, value interface{}) *QueryIterator {
+	return &QueryIterator{
+		to:         to,
+		ledgerName: ledgerName,
+		index:      index,
+		op:         op,
+		value:      value,
+		limit:      50,
+	}
+}
+
+// SetCursor sets the cursor to continue a previous iteration from.
+// The cursor must have been returned by the Cursor method.
+func (qi *QueryIterator) SetCursor(cursor string) *QueryIterator {
+	qi.cursor = cursor
+	qi.startCursor = cursor
+	return qi
+}
+
+// Cursor returns an opaque token that can be passed to SetCursor
+// to continue iterating after the last transaction returned by Next.
+func (qi *QueryIterator) Cursor() string {
+	if len(qi.lastKey) == 0 {
+		return qi.startCursor
+	}
+	return types.MakeRangeCursor(qi.lastKey)
+}
+
+// fetch transactions
+func (qi *QueryIterator) fetch() error {
+
+	valueJSON, err := util.ToJSON(qi.value)
+	if err != nil {
+		return fmt.Errorf("failed to encode query value. %s", err)
+	}
+
+	result, err := sendLedgerOp(qi.ctx, &proto_connector.LedgerOperation{
+		ID:     util.UUID4(),
+		Name:   types.TxQuery,
+		LinkTo: qi.to,
+		Params: []string{
+			qi.ledgerName,
+			qi.index,
+			qi.op,
+			string(valueJSON),
+			strconv.Itoa(qi.limit),
+			qi.cursor,
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to query transactions. %s", err)
+	}
+
+	var queryResult types.RangeResult
+	if err = util.FromJSON(result, &queryResult); err != nil {
+		return fmt.Errorf("failed to unmarshall response data")
+	}
+
+	qi.txs = append(qi.txs, queryResult.Transactions...)
+	qi.cursor = queryResult.NextCursor
+	qi.done = len(qi.cursor) == 0
+	return nil
+}
+
+// HasNext determines whether more rows exists.
+func (qi *QueryIterator) HasNext() bool {
+
+	if len(qi.txs) == 0 {
+		if qi.done {
+			qi.Error = ErrNoTransaction
+			return false
+		}
+		if err := qi.fetch(); err != nil {
+			qi.Error = err
+			return false
+		}
+		if len(qi.txs) == 0 {
+			qi.Error = ErrNoTransaction
+			return false
+		}
+	}
+
+	return true
+}
+
+// Next returns a transaction if available or nil
+func (qi *QueryIterator) Next() *types.Transaction {
+	if len(qi.txs) == 0 {
+		return nil
+	}
+
+	tx := qi.txs[0]
+	qi.txs = qi.txs[1:]
+	qi.lastKey = tx.Key
+	return tx
+}
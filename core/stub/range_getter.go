@@ -7,6 +7,7 @@ import (
 	"github.com/ellcrys/util"
 	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
 	"github.com/ellcrys/cocoon/core/types"
+	context "golang.org/x/net/context"
 )
 
 var (
@@ -15,19 +16,29 @@ var (
 )
 
 // RangeGetter defines a means to fetch keys of
-// a specific range with support for
+// a specific range with support for prefix scans,
+// descending order and resumable iteration through cursors.
 type RangeGetter struct {
-	to         string
-	ledgerName string
-	start      string
-	end        string
-	inclusive  bool
-	limit      int
-	offset     int
-	txs        []*types.Transaction
-	curIndex   int
-	curPage    int
-	Error      error
+	ctx         context.Context
+	to          string
+	ledgerName  string
+	start       string
+	end         string
+	prefix      string
+	inclusive   bool
+	limit       int
+	txs         []*types.Transaction
+	cursor      string
+	startCursor string
+	lastKey     string
+	done        bool
+	Error       error
+
+	// IncludeDeleted includes keys whose most recent transaction is a tombstone
+	IncludeDeleted bool
+
+	// Reverse returns keys in descending order
+	Reverse bool
 }
 
 // NewRangeGetter creates a new range getter to traverse a specific ledger.
@@ -42,34 +53,70 @@ func NewRangeGetter(ledgerName, to, start, end string, inclusive bool) *RangeGet
 		end:        end,
 		inclusive:  inclusive,
 		limit:      50,
-		offset:     0,
-		curPage:    0,
 	}
 }
 
+// NewPrefixRangeGetter creates a new range getter to traverse
+// the keys of a specific ledger that begin with a prefix.
+func NewPrefixRangeGetter(ledgerName, to, prefix string) *RangeGetter {
+	return &RangeGetter{
+		to:         to,
+		ledgerName: ledgerName,
+		prefix:     prefix,
+		limit:      50,
+	}
+}
+
+// SetCursor sets the cursor to continue a previous iteration from.
+// The cursor must have been returned by the Cursor method.
+func (rg *RangeGetter) SetCursor(cursor string) *RangeGetter {
+	rg.cursor = cursor
+	rg.startCursor = cursor
+	return rg
+}
+
+// Cursor returns an opaque token that can be passed to SetCursor
+// to continue iterating after the last transaction returned by Next.
+func (rg *RangeGetter) Cursor() string {
+	if len(rg.lastKey) == 0 {
+		return rg.startCursor
+	}
+	return types.MakeRangeCursor(rg.lastKey)
+}
+
 // fetch transactions
 func (rg *RangeGetter) fetch() error {
 
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+	result, err := sendLedgerOp(rg.ctx, &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
 		Name:   types.TxRangeGet,
 		LinkTo: rg.to,
-		Params: []string{rg.ledgerName, rg.start, rg.end, strconv.FormatBool(rg.inclusive), strconv.Itoa(rg.limit), strconv.Itoa(rg.offset)},
+		Params: []string{
+			rg.ledgerName,
+			rg.start,
+			rg.end,
+			strconv.FormatBool(rg.inclusive),
+			strconv.Itoa(rg.limit),
+			"0",
+			strconv.FormatBool(rg.IncludeDeleted),
+			rg.prefix,
+			strconv.FormatBool(rg.Reverse),
+			rg.cursor,
+		},
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to get transactions. %s", err)
 	}
 
-	var txs []*types.Transaction
-	if err = util.FromJSON(result, &txs); err != nil {
+	var rangeResult types.RangeResult
+	if err = util.FromJSON(result, &rangeResult); err != nil {
 		return fmt.Errorf("failed to unmarshall response data")
 	}
 
-	rg.txs = append(rg.txs, txs...)
-
-	rg.curPage++
-	rg.offset = rg.curPage * rg.limit
+	rg.txs = append(rg.txs, rangeResult.Transactions...)
+	rg.cursor = rangeResult.NextCursor
+	rg.done = len(rg.cursor) == 0
 	return nil
 }
 
@@ -77,6 +124,10 @@ func (rg *RangeGetter) fetch() error {
 func (rg *RangeGetter) HasNext() bool {
 
 	if len(rg.txs) == 0 {
+		if rg.done {
+			rg.Error = ErrNoTransaction
+			return false
+		}
 		if err := rg.fetch(); err != nil {
 			rg.Error = err
 			return false
@@ -98,6 +149,7 @@ func (rg *RangeGetter) Next() *types.Transaction {
 
 	tx := rg.txs[0]
 	rg.txs = rg.txs[1:]
+	rg.lastKey = tx.Key
 	return tx
 }
 
@@ -105,8 +157,8 @@ func (rg *RangeGetter) Next() *types.Transaction {
 func (rg *RangeGetter) Reset() {
 	rg.txs = []*types.Transaction{}
 	rg.limit = 10
-	rg.offset = 0
-	rg.curPage = 0
-	rg.curIndex = 0
+	rg.cursor = rg.startCursor
+	rg.lastKey = ""
+	rg.done = false
 	rg.Error = nil
 }
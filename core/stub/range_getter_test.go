@@ -3,11 +3,38 @@ package stub
 import (
 	"testing"
 
+	"github.com/ellcrys/cocoon/core/types"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestRangeGetter(t *testing.T) {
 	Convey("RangeGetter", t, func() {
 
+		Convey(".Cursor", func() {
+
+			Convey("Should return the cursor set if no transaction has been returned", func() {
+				rg := NewPrefixRangeGetter("ledger", "cocoon-abc", "order:").SetCursor("cursor")
+				So(rg.Cursor(), ShouldEqual, "cursor")
+			})
+
+			Convey("Should return a cursor that continues after the last transaction returned", func() {
+				rg := NewPrefixRangeGetter("ledger", "cocoon-abc", "order:")
+				rg.txs = []*types.Transaction{{Key: "order:3"}, {Key: "order:2"}}
+				So(rg.HasNext(), ShouldBeTrue)
+				So(rg.Next().Key, ShouldEqual, "order:3")
+				key, err := types.ParseRangeCursor(rg.Cursor())
+				So(err, ShouldBeNil)
+				So(key, ShouldEqual, "order:3")
+			})
+		})
+
+		Convey(".HasNext", func() {
+			Convey("Should not fetch more transactions if the last page has been fetched", func() {
+				rg := NewRangeGetter("ledger", "cocoon-abc", "a", "z", false)
+				rg.done = true
+				So(rg.HasNext(), ShouldBeFalse)
+				So(rg.Error, ShouldEqual, ErrNoTransaction)
+			})
+		})
 	})
 }
@@ -0,0 +1,83 @@
+package stub
+
+import (
+	"github.com/ellcrys/cocoon/core/stub/proto_runtime"
+	"github.com/ellcrys/util"
+	context "golang.org/x/net/context"
+)
+
+// ErrCodeInvalidStatus is the code of the error returned to the
+// invoker when a response has a status that is not a valid HTTP status
+const ErrCodeInvalidStatus = "invalid_status"
+
+// Response describes the HTTP response to an invocation. Unless it is created
+// by the router for a result that is not a Response, the connector writes the
+// status, headers and body as they are, instead of writing the body in its
+// default JSON envelope. A zero status is written as 200. A status outside
+// 100-999 is replaced with an error with the ErrCodeInvalidStatus code.
+type Response struct {
+	Status   int
+	Header   map[string]string
+	Body     []byte
+	envelope bool
+}
+
+// Responder is implemented by cocoon codes that control the HTTP response
+// to their invocations. When implemented, OnInvokeResponse is called instead
+// of OnInvoke or OnInvokeContext with the context of the invocation.
+type Responder interface {
+	OnInvokeResponse(ctx context.Context, header Metadata, function string, params []string) (*Response, error)
+}
+
+// NewResponse creates a response
+func NewResponse(status int, body []byte) *Response {
+	return &Response{Status: status, Header: make(map[string]string), Body: body}
+}
+
+// Redirect creates a response that redirects the client to a url
+func Redirect(url string, status int) *Response {
+	return NewResponse(status, nil).SetHeader("Location", url)
+}
+
+// SetHeader sets a header of the response
+func (r *Response) SetHeader(key, value string) *Response {
+	if r.Header == nil {
+		r.Header = make(map[string]string)
+	}
+	r.Header[key] = value
+	return r
+}
+
+// SetContentType sets the content type of the response
+func (r *Response) SetContentType(contentType string) *Response {
+	return r.SetHeader("Content-Type", contentType)
+}
+
+// Raw checks whether the response is written as it is by the connector
+func (r *Response) Raw() bool {
+	return !r.envelope
+}
+
+// IsValidStatus checks whether a status can be written as an HTTP status
+func IsValidStatus(status int) bool {
+	return status >= 100 && status <= 999
+}
+
+// toInvokeResponse creates the invoke response of the stub. A
+// response with an invalid status is returned as a 500 error.
+func (r *Response) toInvokeResponse(id string) *proto_runtime.InvokeResponse {
+	status := int32(r.Status)
+	if status == 0 {
+		status = 200
+	} else if !IsValidStatus(r.Status) {
+		body, _ := util.ToJSON(NewError(ErrCodeInvalidStatus, "cocoon code returned an invalid status: %d", r.Status))
+		return &proto_runtime.InvokeResponse{ID: id, Status: 500, Body: body}
+	}
+	return &proto_runtime.InvokeResponse{
+		ID:     id,
+		Status: status,
+		Header: r.Header,
+		Body:   r.Body,
+		Raw:    r.Raw(),
+	}
+}
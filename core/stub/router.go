@@ -0,0 +1,406 @@
+package stub
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	context "golang.org/x/net/context"
+)
+
+// Error codes of the errors returned by a Router
+const (
+	// ErrCodeUnknownFunction is the code of an error about a function that is not registered
+	ErrCodeUnknownFunction = "unknown_function"
+
+	// ErrCodeInvalidParams is the code of an error about parameters that do not match a function
+	ErrCodeInvalidParams = "invalid_params"
+)
+
+// Error is an invocation error with a code that the invoker can handle.
+// It is returned by the connector to the invoker as an invoke error.
+// Functions can return an Error to report an expected failure.
+type Error struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// NewError creates an invocation error
+func NewError(code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// Function input types
+const (
+	// InputArgs describes a function that takes positional parameters
+	InputArgs = "args"
+
+	// InputJSON describes a function that takes a JSON object as its only parameter
+	InputJSON = "json"
+)
+
+// ParamSpec describes a parameter of a function
+type ParamSpec struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// FunctionSpec describes a function registered on a router
+type FunctionSpec struct {
+	Name   string       `json:"name"`
+	Doc    string       `json:"doc,omitempty"`
+	Input  string       `json:"input"`
+	Params []*ParamSpec `json:"params"`
+}
+
+// FunctionCatalog is implemented by cocoon codes that publish the
+// functions they support. The catalog is returned by the
+// types.SysFuncListFunctions system function.
+type FunctionCatalog interface {
+	Functions() []*FunctionSpec
+}
+
+var (
+	metadataType = reflect.TypeOf(Metadata{})
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// isContextType checks whether a type is a context interface. Both
+// the standard library and golang.org/x/net context types are accepted.
+func isContextType(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.Implements(contextType) && contextType.Implements(t)
+}
+
+// validator is implemented by JSON inputs that validate themselves
+type validator interface {
+	Validate() error
+}
+
+// route is a registered function
+type route struct {
+	spec     *FunctionSpec
+	fn       reflect.Value
+	withCtx  bool
+	withMeta bool
+	args     []reflect.Type
+}
+
+// Router dispatches invocations to typed handlers. A cocoon code can embed
+// a router to use its OnInvoke method and publish its function catalog.
+//
+// A handler is a function that optionally takes the context of the invocation
+// and then the Metadata of the invocation as its first parameters and returns
+// an error or a result and an error. Results of type []byte or string are returned as is; other results
+// are encoded to JSON. A handler that returns a *Response controls the status,
+// headers and body of the HTTP response to the invocation.
+type Router struct {
+	routes map[string]*route
+	names  []string
+}
+
+// NewRouter creates a router
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]*route)}
+}
+
+// HandleArgs registers a function whose parameters are converted to the types
+// of the parameters of the handler. Parameters can be strings, booleans,
+// integers or floats and are named by paramNames in the catalog. It panics if
+// the handler is not valid or the function is already registered.
+func (r *Router) HandleArgs(name, doc string, handler interface{}, paramNames ...string) {
+	rt := r.newRoute(name, doc, InputArgs, handler)
+	for i, t := range rt.args {
+		if paramType(t) == "" {
+			panic(fmt.Sprintf("stub: parameter %d of function %s has unsupported type %s", i, name, t))
+		}
+		paramName := fmt.Sprintf("arg%d", i)
+		if i < len(paramNames) {
+			paramName = paramNames[i]
+		}
+		rt.spec.Params = append(rt.spec.Params, &ParamSpec{Name: paramName, Type: paramType(t)})
+	}
+	r.add(rt)
+}
+
+// HandleJSON registers a function whose only parameter is a JSON object
+// decoded to the struct, or pointer to struct, taken by the handler. The
+// decoded value is validated if it implements `Validate() error`. It panics
+// if the handler is not valid or the function is already registered.
+func (r *Router) HandleJSON(name, doc string, handler interface{}) {
+	rt := r.newRoute(name, doc, InputJSON, handler)
+	if len(rt.args) != 1 || indirect(rt.args[0]).Kind() != reflect.Struct {
+		panic(fmt.Sprintf("stub: handler of function %s must take a struct", name))
+	}
+	rt.spec.Params = structParams(indirect(rt.args[0]))
+	r.add(rt)
+}
+
+// newRoute checks the signature of a handler and creates its route
+func (r *Router) newRoute(name, doc, input string, handler interface{}) *route {
+
+	if len(name) == 0 || strings.HasPrefix(name, "@@") {
+		panic(fmt.Sprintf("stub: invalid function name %q", name))
+	}
+
+	fn := reflect.ValueOf(handler)
+	t := fn.Type()
+	if t.Kind() != reflect.Func || t.IsVariadic() {
+		panic(fmt.Sprintf("stub: handler of function %s must be a non-variadic function", name))
+	}
+	if t.NumOut() == 0 || t.NumOut() > 2 || t.Out(t.NumOut()-1) != errorType {
+		panic(fmt.Sprintf("stub: handler of function %s must return an error or a result and an error", name))
+	}
+
+	rt := &route{
+		spec: &FunctionSpec{Name: name, Doc: doc, Input: input, Params: []*ParamSpec{}},
+		fn:   fn,
+	}
+
+	i := 0
+	if i < t.NumIn() && isContextType(t.In(i)) {
+		rt.withCtx = true
+		i++
+	}
+	if i < t.NumIn() && t.In(i) == metadataType {
+		rt.withMeta = true
+		i++
+	}
+	for ; i < t.NumIn(); i++ {
+		rt.args = append(rt.args, t.In(i))
+	}
+	return rt
+}
+
+// add adds a route
+func (r *Router) add(rt *route) {
+	if _, ok := r.routes[rt.spec.Name]; ok {
+		panic(fmt.Sprintf("stub: function %s is already registered", rt.spec.Name))
+	}
+	r.routes[rt.spec.Name] = rt
+	r.names = append(r.names, rt.spec.Name)
+}
+
+// Functions returns the specification of the registered functions in the order they were registered
+func (r *Router) Functions() []*FunctionSpec {
+	var specs = make([]*FunctionSpec, len(r.names))
+	for i, name := range r.names {
+		specs[i] = r.routes[name].spec
+	}
+	return specs
+}
+
+// OnInvoke calls the handler of a function with the parameters of the invocation
+// and an empty context. An Error with the ErrCodeUnknownFunction or ErrCodeInvalidParams
+// code is returned if the function is not registered or the parameters do not match it.
+func (r *Router) OnInvoke(header Metadata, function string, params []string) ([]byte, error) {
+	return r.OnInvokeContext(context.Background(), header, function, params)
+}
+
+// OnInvokeContext calls the handler of a function like OnInvoke
+// with the context of the invocation.
+func (r *Router) OnInvokeContext(ctx context.Context, header Metadata, function string, params []string) ([]byte, error) {
+
+	result, err := r.call(ctx, header, function, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp, ok := result.(*Response); ok {
+		if resp == nil {
+			return nil, nil
+		}
+		return resp.Body, nil
+	}
+
+	return encodeResult(result)
+}
+
+// OnInvokeResponse calls the handler of a function like OnInvokeContext. The *Response
+// returned by the handler is returned as is; other results are returned in a
+// response that the connector writes in its default JSON envelope.
+func (r *Router) OnInvokeResponse(ctx context.Context, header Metadata, function string, params []string) (*Response, error) {
+
+	result, err := r.call(ctx, header, function, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp, ok := result.(*Response); ok && resp != nil {
+		return resp, nil
+	}
+
+	body, err := encodeResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Status: 200, Body: body, envelope: true}, nil
+}
+
+// call calls the handler of a function and returns its result
+func (r *Router) call(ctx context.Context, header Metadata, function string, params []string) (interface{}, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rt, ok := r.routes[function]
+	if !ok {
+		return nil, NewError(ErrCodeUnknownFunction, "function '%s' is unknown", function)
+	}
+
+	args, err := rt.parseParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if rt.withMeta {
+		args = append([]reflect.Value{reflect.ValueOf(header)}, args...)
+	}
+	if rt.withCtx {
+		args = append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+	}
+
+	out := rt.fn.Call(args)
+	if err, _ := out[len(out)-1].Interface().(error); err != nil {
+		return nil, err
+	} else if len(out) == 1 {
+		return nil, nil
+	}
+
+	return out[0].Interface(), nil
+}
+
+// encodeResult encodes the result of a handler
+func encodeResult(result interface{}) ([]byte, error) {
+	switch result := result.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return result, nil
+	case string:
+		return []byte(result), nil
+	default:
+		return json.Marshal(result)
+	}
+}
+
+// parseParams converts the parameters of an invocation to the arguments of the handler
+func (rt *route) parseParams(params []string) ([]reflect.Value, error) {
+
+	if rt.spec.Input == InputJSON {
+		if len(params) != 1 {
+			return nil, NewError(ErrCodeInvalidParams, "expects a JSON object as the only parameter")
+		}
+		v := reflect.New(indirect(rt.args[0]))
+		if err := json.Unmarshal([]byte(params[0]), v.Interface()); err != nil {
+			return nil, NewError(ErrCodeInvalidParams, "invalid JSON parameter: %s", err)
+		}
+		if val, ok := v.Interface().(validator); ok {
+			if err := val.Validate(); err != nil {
+				return nil, NewError(ErrCodeInvalidParams, "%s", err)
+			}
+		}
+		if rt.args[0].Kind() != reflect.Ptr {
+			v = v.Elem()
+		}
+		return []reflect.Value{v}, nil
+	}
+
+	if len(params) != len(rt.args) {
+		return nil, NewError(ErrCodeInvalidParams, "expects %d parameter(s), got %d", len(rt.args), len(params))
+	}
+
+	var args = make([]reflect.Value, len(params))
+	for i, t := range rt.args {
+		v := reflect.New(t).Elem()
+		var err error
+		switch t.Kind() {
+		case reflect.String:
+			v.SetString(params[i])
+		case reflect.Bool:
+			var b bool
+			b, err = strconv.ParseBool(params[i])
+			v.SetBool(b)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			n, err = strconv.ParseInt(params[i], 10, t.Bits())
+			v.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var n uint64
+			n, err = strconv.ParseUint(params[i], 10, t.Bits())
+			v.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			f, err = strconv.ParseFloat(params[i], t.Bits())
+			v.SetFloat(f)
+		}
+		if err != nil {
+			return nil, NewError(ErrCodeInvalidParams, "parameter %s must be of type %s", rt.spec.Params[i].Name, rt.spec.Params[i].Type)
+		}
+		args[i] = v
+	}
+
+	return args, nil
+}
+
+// indirect returns the type pointed to by a pointer type
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// paramType returns the catalog type of a parameter type.
+// It returns an empty string for unsupported positional types.
+func paramType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+	return ""
+}
+
+// structParams describes the JSON fields of a struct
+func structParams(t reflect.Type) []*ParamSpec {
+	var params = []*ParamSpec{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if len(tag) > 0 {
+			name = tag
+		}
+		typ := paramType(field.Type)
+		if len(typ) == 0 {
+			switch indirect(field.Type).Kind() {
+			case reflect.Slice, reflect.Array:
+				typ = "array"
+			default:
+				typ = "object"
+			}
+		}
+		params = append(params, &ParamSpec{Name: name, Type: typ})
+	}
+	return params
+}
@@ -0,0 +1,264 @@
+package stub
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ellcrys/cocoon/core/stub/proto_runtime"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+type transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+	Tags   []string
+	secret string
+}
+
+func (t *transfer) Validate() error {
+	if t.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	return nil
+}
+
+// routedCocoonCode is a cocoon code that embeds a router
+type routedCocoonCode struct {
+	*Router
+}
+
+func (c *routedCocoonCode) OnInit() error { return nil }
+func (c *routedCocoonCode) OnStop()       {}
+
+func TestRouter(t *testing.T) {
+	Convey("Router", t, func() {
+
+		r := NewRouter()
+		r.HandleArgs("add", "adds two numbers", func(a int, b float64) (float64, error) {
+			return float64(a) + b, nil
+		}, "a", "b")
+		r.HandleArgs("greet", "", func(meta Metadata, name string, loud bool) (string, error) {
+			greeting := "hello " + name + " from " + meta.Get("caller")
+			if loud {
+				greeting += "!"
+			}
+			return greeting, nil
+		})
+		r.HandleArgs("fail", "", func() error {
+			return NewError("insufficient_funds", "balance is too low")
+		})
+		r.HandleJSON("transfer", "moves funds", func(t transfer) (*transfer, error) {
+			return &t, nil
+		})
+
+		Convey(".HandleArgs", func() {
+
+			Convey("Should panic if the handler is not valid", func() {
+				So(func() { r.HandleArgs("x", "", "not a function") }, ShouldPanic)
+				So(func() { r.HandleArgs("x", "", func() {}) }, ShouldPanic)
+				So(func() { r.HandleArgs("x", "", func(a []int) error { return nil }) }, ShouldPanic)
+				So(func() { r.HandleArgs("x", "", func(a ...int) error { return nil }) }, ShouldPanic)
+			})
+
+			Convey("Should panic if the function is already registered or reserved", func() {
+				So(func() { r.HandleArgs("add", "", func() error { return nil }) }, ShouldPanic)
+				So(func() { r.HandleArgs("@@functions", "", func() error { return nil }) }, ShouldPanic)
+			})
+		})
+
+		Convey(".HandleJSON", func() {
+			Convey("Should panic if the handler does not take a struct", func() {
+				So(func() { r.HandleJSON("x", "", func(a int) error { return nil }) }, ShouldPanic)
+			})
+		})
+
+		Convey(".OnInvoke", func() {
+
+			Convey("Should convert positional parameters and encode the result to JSON", func() {
+				result, err := r.OnInvoke(nil, "add", []string{"1", "2.5"})
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, "3.5")
+			})
+
+			Convey("Should pass the metadata and return string results as is", func() {
+				result, err := r.OnInvoke(Metadata{"caller": "bob"}, "greet", []string{"alice", "true"})
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, "hello alice from bob!")
+			})
+
+			Convey("Should decode and validate JSON parameters", func() {
+				result, err := r.OnInvoke(nil, "transfer", []string{`{"from":"a","to":"b","amount":5}`})
+				So(err, ShouldBeNil)
+				var t transfer
+				So(util.FromJSON(result, &t), ShouldBeNil)
+				So(t.Amount, ShouldEqual, 5)
+
+				_, err = r.OnInvoke(nil, "transfer", []string{`{"amount":0}`})
+				So(err, ShouldResemble, NewError(ErrCodeInvalidParams, "amount must be positive"))
+
+				_, err = r.OnInvoke(nil, "transfer", []string{`not json`})
+				So(err.(*Error).Code, ShouldEqual, ErrCodeInvalidParams)
+			})
+
+			Convey("Should return an error if the parameters do not match", func() {
+				_, err := r.OnInvoke(nil, "add", []string{"1"})
+				So(err, ShouldResemble, NewError(ErrCodeInvalidParams, "expects 2 parameter(s), got 1"))
+
+				_, err = r.OnInvoke(nil, "add", []string{"one", "2"})
+				So(err, ShouldResemble, NewError(ErrCodeInvalidParams, "parameter a must be of type int"))
+			})
+
+			Convey("Should return an error if the function is unknown", func() {
+				_, err := r.OnInvoke(nil, "unknown", nil)
+				So(err.(*Error).Code, ShouldEqual, ErrCodeUnknownFunction)
+			})
+
+			Convey("Should return the error of the handler", func() {
+				_, err := r.OnInvoke(nil, "fail", nil)
+				So(err, ShouldResemble, NewError("insufficient_funds", "balance is too low"))
+			})
+		})
+
+		Convey(".OnInvokeContext", func() {
+
+			Convey("Should pass the context and the metadata to the handler", func() {
+				r.HandleArgs("whoami", "", func(ctx context.Context, meta Metadata, name string) (string, error) {
+					return fmt.Sprintf("%s %v %s", name, ctx.Value("user"), meta.Get("caller")), nil
+				}, "name")
+				ctx := context.WithValue(context.Background(), "user", "alice")
+				result, err := r.OnInvokeContext(ctx, Metadata{"caller": "bob"}, "whoami", []string{"carol"})
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, "carol alice bob")
+				So(r.Functions()[4].Params, ShouldResemble, []*ParamSpec{{Name: "name", Type: "string"}})
+			})
+
+			Convey("Should pass an empty context from OnInvoke", func() {
+				r.HandleArgs("deadline", "", func(ctx context.Context) (bool, error) {
+					_, ok := ctx.Deadline()
+					return ok, ctx.Err()
+				})
+				result, err := r.OnInvoke(nil, "deadline", nil)
+				So(err, ShouldBeNil)
+				So(string(result), ShouldEqual, "false")
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err = r.OnInvokeContext(ctx, nil, "deadline", nil)
+				So(err, ShouldEqual, context.Canceled)
+			})
+		})
+
+		Convey(".OnInvokeResponse", func() {
+
+			Convey("Should return the response of the handler as is", func() {
+				r.HandleArgs("download", "", func() (*Response, error) {
+					return NewResponse(200, []byte("a,b\n1,2\n")).SetContentType("text/csv"), nil
+				})
+				resp, err := r.OnInvokeResponse(context.Background(), nil, "download", nil)
+				So(err, ShouldBeNil)
+				So(resp.Raw(), ShouldBeTrue)
+				So(resp.Header["Content-Type"], ShouldEqual, "text/csv")
+
+				body, err := r.OnInvoke(nil, "download", nil)
+				So(err, ShouldBeNil)
+				So(string(body), ShouldEqual, "a,b\n1,2\n")
+			})
+
+			Convey("Should return other results in an enveloped response", func() {
+				resp, err := r.OnInvokeResponse(context.Background(), nil, "add", []string{"1", "2.5"})
+				So(err, ShouldBeNil)
+				So(resp.Raw(), ShouldBeFalse)
+				So(resp.Status, ShouldEqual, 200)
+				So(string(resp.Body), ShouldEqual, "3.5")
+			})
+
+			Convey("Should return the error of the handler", func() {
+				_, err := r.OnInvokeResponse(context.Background(), nil, "fail", nil)
+				So(err, ShouldResemble, NewError("insufficient_funds", "balance is too low"))
+			})
+		})
+
+		Convey(".Functions", func() {
+			Convey("Should describe the functions in the order they were registered", func() {
+				functions := r.Functions()
+				So(len(functions), ShouldEqual, 4)
+				So(functions[0], ShouldResemble, &FunctionSpec{
+					Name:   "add",
+					Doc:    "adds two numbers",
+					Input:  InputArgs,
+					Params: []*ParamSpec{{Name: "a", Type: "int"}, {Name: "b", Type: "float"}},
+				})
+				So(functions[1].Params, ShouldResemble, []*ParamSpec{{Name: "arg0", Type: "string"}, {Name: "arg1", Type: "bool"}})
+				So(functions[2].Params, ShouldBeEmpty)
+				So(functions[3], ShouldResemble, &FunctionSpec{
+					Name:  "transfer",
+					Doc:   "moves funds",
+					Input: InputJSON,
+					Params: []*ParamSpec{
+						{Name: "from", Type: "string"},
+						{Name: "to", Type: "string"},
+						{Name: "amount", Type: "int"},
+						{Name: "Tags", Type: "array"},
+					},
+				})
+			})
+		})
+
+		Convey("stubServer", func() {
+
+			ccode = &routedCocoonCode{Router: r}
+			server := new(stubServer)
+
+			Convey("Should return coded errors in the response", func() {
+				resp, err := server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: "fail"})
+				So(err, ShouldBeNil)
+				So(resp.Status, ShouldEqual, 400)
+				So(string(resp.Body), ShouldEqual, `{"code":"insufficient_funds","msg":"balance is too low"}`)
+
+				resp, err = server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: "unknown"})
+				So(err, ShouldBeNil)
+				So(resp.Status, ShouldEqual, 404)
+			})
+
+			Convey("Should return an error if the cocoon code sets an invalid status", func() {
+				r.HandleArgs("teapot", "", func() (*Response, error) {
+					return NewResponse(42, []byte("short and stout")), nil
+				})
+				resp, err := server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: "teapot"})
+				So(err, ShouldBeNil)
+				So(resp.Raw, ShouldBeFalse)
+				So(resp.Status, ShouldEqual, 500)
+				So(string(resp.Body), ShouldEqual, `{"code":"invalid_status","msg":"cocoon code returned an invalid status: 42"}`)
+			})
+
+			Convey("Should return the status, headers and body set by the cocoon code", func() {
+				r.HandleArgs("redirect", "", func() (*Response, error) {
+					return Redirect("/home", 302), nil
+				})
+				resp, err := server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: "redirect"})
+				So(err, ShouldBeNil)
+				So(resp.Raw, ShouldBeTrue)
+				So(resp.Status, ShouldEqual, 302)
+				So(resp.Header, ShouldResemble, map[string]string{"Location": "/home"})
+
+				resp, err = server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: "add", Params: []string{"1", "2"}})
+				So(err, ShouldBeNil)
+				So(resp.Raw, ShouldBeFalse)
+				So(resp.Status, ShouldEqual, 200)
+				So(string(resp.Body), ShouldEqual, "3")
+			})
+
+			Convey("Should return the function catalog", func() {
+				resp, err := server.Invoke(context.Background(), &proto_runtime.InvokeParam{Function: types.SysFuncListFunctions})
+				So(err, ShouldBeNil)
+				var functions []*FunctionSpec
+				So(util.FromJSON(resp.Body, &functions), ShouldBeNil)
+				So(functions, ShouldResemble, r.Functions())
+			})
+		})
+	})
+}
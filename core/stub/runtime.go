@@ -71,6 +71,10 @@ var (
 
 	// ViewDir is the directory where view files are stored
 	ViewDir = path.Join(SourceDir, "/static/views")
+
+	// InvokeTimeout is the maximum time to wait for a function
+	// of another cocoon invoked through Link.Invoke
+	InvokeTimeout = 30 * time.Second
 )
 
 // GetLogger returns the stubs logger.
@@ -149,6 +153,19 @@ func Run(cc CocoonCode) {
 	os.Exit(0)
 }
 
+// Connect connects the stub to the connector RPC server at addr without
+// starting the stub server, so that a cocoon code can be called within the
+// current process. Puts to chained ledgers are collected in blocks of at most
+// txPerBlock transactions every blockInterval. It returns a function that
+// stops the block maker. Only one connection should be active at a time.
+func Connect(addr string, txPerBlock int, blockInterval time.Duration) func() {
+	connectorRPCAddr = addr
+	bm := newblockMaker(txPerBlock, blockInterval)
+	defaultBlockMaker = bm
+	go bm.Begin(blockCommitter)
+	return bm.Stop
+}
+
 // startServer starts the server
 func startServer(server *grpc.Server, lis net.Listener) {
 	err := server.Serve(lis)
@@ -174,9 +191,16 @@ func blockCommitter(entries []*entry) interface{} {
 	}
 	ledgerName := entries[0].Tx.Ledger
 	txsJSON, _ := util.ToJSON(txs)
-	result, err := sendLedgerOp(&proto_connector.LedgerOperation{
+
+	// events are recorded in the event ledger through emit operations
+	opName := types.TxPut
+	if ledgerName == types.EventLedgerName {
+		opName = types.TxEmit
+	}
+
+	result, err := sendLedgerOp(context.Background(), &proto_connector.LedgerOperation{
 		ID:     util.UUID4(),
-		Name:   types.TxPut,
+		Name:   opName,
 		LinkTo: entries[0].LinkTo,
 		Params: []string{ledgerName},
 		Body:   txsJSON,
@@ -193,8 +217,14 @@ func blockCommitter(entries []*entry) interface{} {
 	return &putResult
 }
 
-// sendOp sends out a request to the connector.
-func sendOp(req *proto_connector.Request) ([]byte, error) {
+// transact sends a request to the connector and returns its response. The request
+// fails if no response is received before the timeout or the context is done.
+// A nil context is treated as an empty context.
+func transact(ctx context.Context, req *proto_connector.Request, timeout time.Duration) (*proto_connector.Response, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
 	client, err := grpc.Dial(connectorRPCAddr, grpc.WithInsecure())
 	if err != nil {
@@ -203,12 +233,24 @@ func sendOp(req *proto_connector.Request) ([]byte, error) {
 	defer client.Close()
 
 	ccClient := proto_connector.NewConnectorClient(client)
-	ctx, _ := context.WithTimeout(context.Background(), 1*time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 	resp, err := ccClient.Transact(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("%s", common.GetRPCErrDesc(err))
 	}
 
+	return resp, nil
+}
+
+// sendOp sends out a request to the connector.
+func sendOp(ctx context.Context, req *proto_connector.Request) ([]byte, error) {
+
+	resp, err := transact(ctx, req, 1*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
 	if resp.GetStatus() == 500 {
 		return nil, fmt.Errorf("server error")
 	}
@@ -217,16 +259,16 @@ func sendOp(req *proto_connector.Request) ([]byte, error) {
 }
 
 // sendLedgerOp sends a ledger transaction to the connector
-func sendLedgerOp(op *proto_connector.LedgerOperation) ([]byte, error) {
-	return sendOp(&proto_connector.Request{
+func sendLedgerOp(ctx context.Context, op *proto_connector.LedgerOperation) ([]byte, error) {
+	return sendOp(ctx, &proto_connector.Request{
 		OpType:   proto_connector.OpType_LedgerOp,
 		LedgerOp: op,
 	})
 }
 
 // sendLockOp sends a lock operation to the connector
-func sendLockOp(op *proto_connector.LockOperation) ([]byte, error) {
-	return sendOp(&proto_connector.Request{
+func sendLockOp(ctx context.Context, op *proto_connector.LockOperation) ([]byte, error) {
+	return sendOp(ctx, &proto_connector.Request{
 		OpType: proto_connector.OpType_LockOp,
 		LockOp: op,
 	})
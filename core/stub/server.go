@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/ellcrys/cocoon/core/stub/proto_runtime"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
@@ -65,7 +67,21 @@ func (server *stubServer) Invoke(ctx context.Context, params *proto_runtime.Invo
 	}
 
 	err = recoverPanic(func() error {
-		result, err = ccode.OnInvoke(params.GetHeader(), params.GetFunction(), params.GetParams())
+		switch code := ccode.(type) {
+		case Responder:
+			codeResp, err := code.OnInvokeResponse(ctx, params.GetHeader(), params.GetFunction(), params.GetParams())
+			if err != nil {
+				return err
+			} else if codeResp == nil {
+				codeResp = &Response{envelope: true}
+			}
+			resp = codeResp.toInvokeResponse(params.GetID())
+			return nil
+		case ContextCocoonCode:
+			result, err = code.OnInvokeContext(ctx, params.GetHeader(), params.GetFunction(), params.GetParams())
+		default:
+			result, err = ccode.OnInvoke(params.GetHeader(), params.GetFunction(), params.GetParams())
+		}
 		if err != nil {
 			return err
 		}
@@ -74,7 +90,15 @@ func (server *stubServer) Invoke(ctx context.Context, params *proto_runtime.Invo
 		return nil
 	})
 
-	if err != nil {
+	// coded errors are returned to the invoker
+	if codedErr, ok := err.(*Error); ok {
+		resp.Status = 400
+		if codedErr.Code == ErrCodeUnknownFunction {
+			resp.Status = 404
+		}
+		resp.Body, _ = util.ToJSON(codedErr)
+		return resp, nil
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -84,6 +108,12 @@ func (server *stubServer) Invoke(ctx context.Context, params *proto_runtime.Invo
 // SystemInvoke handles invocation request for system functions
 func (server *stubServer) SystemInvoke(ctx context.Context, params *proto_runtime.InvokeParam) ([]byte, error) {
 	switch params.GetFunction() {
+	case types.SysFuncListFunctions:
+		var functions = []*FunctionSpec{}
+		if catalog, ok := ccode.(FunctionCatalog); ok {
+			functions = catalog.Functions()
+		}
+		return util.ToJSON(functions)
 	default:
 		return nil, fmt.Errorf("function '%s' is unknown", params.Function)
 	}
@@ -0,0 +1,228 @@
+package stubtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/connector/server/acl"
+	"github.com/ellcrys/cocoon/core/connector/server/handlers"
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/stub"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	logging "github.com/op/go-logging"
+	context "golang.org/x/net/context"
+)
+
+var log = logging.MustGetLogger("stubtest")
+
+// connector serves the operations of the stub like the connector RPC
+// server. Ledger and lock operations are handled by the handlers of the
+// connector, using the embedded orderer and the memory locks of the harness.
+type connector struct {
+	h         *Harness
+	ledgerOps *handlers.LedgerOperations
+	lockOps   *handlers.LockOperations
+}
+
+// newConnector creates the connector of a harness
+func newConnector(h *Harness, odc proto_orderer.OrdererClient) *connector {
+	spec := &types.Spec{ID: h.cocoonID}
+	p := &platform{h: h}
+	return &connector{
+		h:         h,
+		ledgerOps: handlers.NewLedgerOperationHandler(log, spec, p, &ordererClients{client: odc}),
+		lockOps: handlers.NewLockOperationHandler(log, spec, p, func(cocoonID, key string, ttl time.Duration) (types.Lock, error) {
+			return h.newLock(cocoonID, key, ttl, ""), nil
+		}),
+	}
+}
+
+// Transact handles ledger, lock and invoke operations
+func (c *connector) Transact(ctx context.Context, req *proto_connector.Request) (*proto_connector.Response, error) {
+	switch req.OpType {
+	case proto_connector.OpType_LedgerOp:
+		op := c.normalizeLedgerOp(req.LedgerOp)
+		if err := c.h.failure(op.GetName()); err != nil {
+			return nil, err
+		}
+		return c.ledgerOps.Handle(ctx, op)
+	case proto_connector.OpType_LockOp:
+		op := c.normalizeLockOp(req.LockOp)
+		if err := c.h.failure(op.GetName()); err != nil {
+			return nil, err
+		}
+		return c.lockOps.Handle(ctx, op)
+	case proto_connector.OpType_CocoonCodeOp:
+		if err := c.h.failure(types.TxInvoke); err != nil {
+			return nil, err
+		}
+		return c.invoke(ctx, req.CocoonCodeOp)
+	default:
+		return nil, fmt.Errorf("unsupported operation type")
+	}
+}
+
+// Subscribe streams the changes of a ledger to the cocoon code
+func (c *connector) Subscribe(op *proto_connector.LedgerOperation, stream proto_connector.Connector_SubscribeServer) error {
+	op = c.normalizeLedgerOp(op)
+	if err := c.h.failure(op.GetName()); err != nil {
+		return err
+	}
+	return c.ledgerOps.Subscribe(stream.Context(), op, stream.Send)
+}
+
+// normalizeLedgerOp sets the link of an operation to the cocoon under test if it
+// is not set and pads the optional parameters so that they can be indexed.
+func (c *connector) normalizeLedgerOp(op *proto_connector.LedgerOperation) *proto_connector.LedgerOperation {
+	if len(op.GetLinkTo()) == 0 {
+		op.LinkTo = c.h.cocoonID
+	}
+	if len(op.Params) == 0 {
+		op.Params = []string{""}
+	}
+	return op
+}
+
+// normalizeLockOp sets the link and the cocoon of a lock
+// operation to the cocoon under test if they are not set
+func (c *connector) normalizeLockOp(op *proto_connector.LockOperation) *proto_connector.LockOperation {
+	if len(op.GetLinkTo()) == 0 {
+		op.LinkTo = c.h.cocoonID
+	}
+	if len(op.Params) > 0 && len(op.Params[0]) == 0 {
+		op.Params[0] = c.h.cocoonID
+	}
+	return op
+}
+
+// invoke calls a function of the cocoon code of the linked cocoon with
+// the cocoon under test as the caller. Like the stub, errors with a code
+// are returned in the response, a cocoon code that implements
+// stub.Responder sets the response and the context of the
+// operation is passed to context-aware cocoon codes.
+func (c *connector) invoke(ctx context.Context, op *proto_connector.CocoonCodeOperation) (*proto_connector.Response, error) {
+
+	linkTo := op.GetLinkTo()
+	if len(linkTo) == 0 {
+		linkTo = c.h.cocoonID
+	}
+
+	if linkTo == types.SystemCocoonID {
+		return nil, fmt.Errorf("system cocoon functions cannot be invoked")
+	}
+
+	c.h.Lock()
+	code, nativeLink, linkedACL := c.h.codes[linkTo], c.h.nativeLink, c.h.acls[linkTo]
+	c.h.Unlock()
+
+	if linkTo == c.h.cocoonID {
+		code = c.h.code
+	} else if code == nil {
+		return nil, fmt.Errorf("linked cocoon is not running")
+	} else if nativeLink != linkTo {
+		i := acl.NewInterpreterFromACLMap(linkedACL, false)
+		if errs := i.Validate(); len(errs) != 0 {
+			return nil, fmt.Errorf("cocoon ACL is not valid")
+		}
+		if !i.IsAllowed(acl.FunctionKey(op.GetFunction()), c.h.cocoonID, types.TxInvoke) {
+			return nil, fmt.Errorf("permission denied: %s operation not allowed", types.TxInvoke)
+		}
+	}
+
+	var result []byte
+	resp, err := func() (resp *stub.Response, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("Panicked: %v", r)
+			}
+		}()
+		meta := stub.Metadata{
+			"Transaction-Id":           op.GetID(),
+			types.HeaderCallerCocoonID: c.h.cocoonID,
+		}
+		switch code := code.(type) {
+		case stub.Responder:
+			return code.OnInvokeResponse(ctx, meta, op.GetFunction(), op.GetParams())
+		case stub.ContextCocoonCode:
+			result, err = code.OnInvokeContext(ctx, meta, op.GetFunction(), op.GetParams())
+		default:
+			result, err = code.OnInvoke(meta, op.GetFunction(), op.GetParams())
+		}
+		return nil, err
+	}()
+
+	if codedErr, ok := err.(*stub.Error); ok {
+		status := int32(400)
+		if codedErr.Code == stub.ErrCodeUnknownFunction {
+			status = 404
+		}
+		body, _ := util.ToJSON(codedErr)
+		return &proto_connector.Response{ID: op.GetID(), Status: status, Body: body}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if resp == nil {
+		return &proto_connector.Response{ID: op.GetID(), Status: 200, Body: result}, nil
+	}
+
+	status := int32(resp.Status)
+	if status == 0 {
+		status = 200
+	} else if !stub.IsValidStatus(resp.Status) {
+		body, _ := util.ToJSON(stub.NewError(stub.ErrCodeInvalidStatus, "cocoon code returned an invalid status: %d", resp.Status))
+		return &proto_connector.Response{ID: op.GetID(), Status: 500, Body: body}, nil
+	}
+
+	return &proto_connector.Response{
+		ID:     op.GetID(),
+		Status: status,
+		Body:   resp.Body,
+		Header: resp.Header,
+		Raw:    resp.Raw(),
+	}, nil
+}
+
+// platform provides the cocoons known to the harness. The release of the
+// cocoon under test is natively linked to the cocoon set with SetNativeLink
+// and the releases of other cocoons have the ACL set with SetACL.
+type platform struct {
+	h *Harness
+}
+
+// GetCocoonAndRelease gets a cocoon and its release
+func (p *platform) GetCocoonAndRelease(ctx context.Context, cocoonID, releaseID string, includePrivateFields bool) (*types.Cocoon, *types.Release, error) {
+	return p.GetCocoonAndLastActiveRelease(ctx, cocoonID, includePrivateFields)
+}
+
+// GetCocoonAndLastActiveRelease gets a cocoon and its release
+func (p *platform) GetCocoonAndLastActiveRelease(ctx context.Context, cocoonID string, includePrivateFields bool) (*types.Cocoon, *types.Release, error) {
+	p.h.Lock()
+	defer p.h.Unlock()
+	release := &types.Release{CocoonID: cocoonID, ACL: p.h.acls[cocoonID]}
+	if cocoonID == p.h.cocoonID {
+		release.Link = p.h.nativeLink
+	}
+	return &types.Cocoon{ID: cocoonID}, release, nil
+}
+
+// ordererClients provides clients of the embedded orderer of the harness
+type ordererClients struct {
+	client proto_orderer.OrdererClient
+}
+
+// GetClient returns a client of the embedded orderer
+func (o *ordererClients) GetClient() (proto_orderer.OrdererClient, func(), error) {
+	return o.client, func() {}, nil
+}
+
+// GetLeaderClient returns a client of the embedded
+// orderer, which leads every chain
+func (o *ordererClients) GetLeaderClient(cocoonID string, ledgers ...string) (proto_orderer.OrdererClient, func(), error) {
+	return o.GetClient()
+}
+
+// InvalidateLeader does nothing; the embedded orderer leads every chain
+func (o *ordererClients) InvalidateLeader(cocoonID, ledger string) {}
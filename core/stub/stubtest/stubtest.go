@@ -0,0 +1,314 @@
+// Package stubtest runs a cocoon code against an in-memory connector so that it
+// can be unit tested without the connector, orderer and database services.
+// Ledgers, blocks and locks behave as they do on the platform: ledger operations
+// are handled by the handlers of the connector and ordered by an embedded orderer
+// backed by a temporary bolt database, locks are memory locks and operations on
+// other cocoons are checked against ACLs configured on the harness.
+//
+// The stub keeps its connection in package state, so only one harness
+// may be open at a time. Tests using a harness must not run in parallel.
+package stubtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/connector/server/proto_connector"
+	"github.com/ellcrys/cocoon/core/lock/memory"
+	"github.com/ellcrys/cocoon/core/orderer/orderer"
+	"github.com/ellcrys/cocoon/core/orderer/proto_orderer"
+	"github.com/ellcrys/cocoon/core/stub"
+	"github.com/ellcrys/cocoon/core/types"
+	"github.com/ellcrys/util"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// DefaultCocoonID is the id of the cocoon under test
+// when the COCOON_ID environment variable is not set
+var DefaultCocoonID = "cocoon"
+
+// TxPerBlock is the maximum number of transactions per block
+var TxPerBlock = 100
+
+// BlockInterval is the time between the creation of blocks
+var BlockInterval = 10 * time.Millisecond
+
+// Harness runs a cocoon code connected to an in-memory connector
+type Harness struct {
+	sync.Mutex
+	id         string
+	cocoonID   string
+	code       stub.CocoonCode
+	dir        string
+	orderer    *orderer.Orderer
+	server     *grpc.Server
+	conn       *grpc.ClientConn
+	disconnect func()
+	acls       map[string]types.ACLMap
+	nativeLink string
+	failures   map[string]error
+	codes      map[string]stub.CocoonCode
+}
+
+// New creates a harness for a cocoon code and connects the stub to it.
+// Close must be called to disconnect the stub and remove the ledgers.
+func New(code stub.CocoonCode) (*Harness, error) {
+
+	dir, err := ioutil.TempDir("", "stubtest")
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Harness{
+		id:       util.UUID4(),
+		cocoonID: util.Env("COCOON_ID", DefaultCocoonID),
+		code:     code,
+		dir:      dir,
+		orderer:  orderer.NewOrderer(),
+		acls:     make(map[string]types.ACLMap),
+		failures: make(map[string]error),
+		codes:    make(map[string]stub.CocoonCode),
+	}
+
+	if err := h.orderer.Open("bolt://" + path.Join(dir, "ledger.db")); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to open orderer: %s", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		h.orderer.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	// the connector reaches the embedded orderer through the server of the
+	// harness, so that ledger operations are handled as on the platform
+	h.conn, err = grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		lis.Close()
+		h.orderer.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	h.server = grpc.NewServer()
+	proto_orderer.RegisterOrdererServer(h.server, h.orderer)
+	proto_connector.RegisterConnectorServer(h.server, newConnector(h, proto_orderer.NewOrdererClient(h.conn)))
+	go h.server.Serve(lis)
+
+	h.disconnect = stub.Connect(lis.Addr().String(), TxPerBlock, BlockInterval)
+	return h, nil
+}
+
+// Close disconnects the stub, stops the connector and removes the ledgers
+func (h *Harness) Close() {
+	h.disconnect()
+	h.conn.Close()
+	h.server.Stop()
+	h.orderer.Close()
+	os.RemoveAll(h.dir)
+}
+
+// CocoonID returns the id of the cocoon under test
+func (h *Harness) CocoonID() string {
+	return h.cocoonID
+}
+
+// SetACL sets the ACL of another cocoon. Operations of the cocoon under
+// test on the ledgers of the cocoon are checked against it.
+func (h *Harness) SetACL(cocoonID string, acl types.ACLMap) {
+	h.Lock()
+	defer h.Unlock()
+	h.acls[cocoonID] = acl
+}
+
+// SetNativeLink natively links the cocoon under test to another cocoon,
+// giving it the privileges of the linked cocoon
+func (h *Harness) SetNativeLink(cocoonID string) {
+	h.Lock()
+	defer h.Unlock()
+	h.nativeLink = cocoonID
+}
+
+// SetCocoonCode sets the cocoon code of another cocoon. Functions invoked
+// by the cocoon under test through Link.Invoke are called on it after they
+// are checked against the ACL of the cocoon. The code runs in the same
+// process as the cocoon under test, so its stub links point to the
+// resources of the cocoon under test.
+func (h *Harness) SetCocoonCode(cocoonID string, code stub.CocoonCode) {
+	h.Lock()
+	defer h.Unlock()
+	h.codes[cocoonID] = code
+}
+
+// FailOn makes the connector fail operations with a name, such as
+// types.TxPut, types.TxInvoke or types.OpLockAcquire, with err. All operations
+// fail if the name is empty.
+func (h *Harness) FailOn(opName string, err error) {
+	h.Lock()
+	defer h.Unlock()
+	h.failures[opName] = err
+}
+
+// ClearFailures stops failing operations
+func (h *Harness) ClearFailures() {
+	h.Lock()
+	defer h.Unlock()
+	h.failures = make(map[string]error)
+}
+
+// failure returns the error an operation must fail with, if any
+func (h *Harness) failure(opName string) error {
+	h.Lock()
+	defer h.Unlock()
+	if err := h.failures[opName]; err != nil {
+		return err
+	}
+	return h.failures[""]
+}
+
+// Init calls the OnInit method of the cocoon code
+func (h *Harness) Init() error {
+	return h.code.OnInit()
+}
+
+// Invoke calls the OnInvoke method of the cocoon code with the metadata of
+// an invoke request. A panic in the cocoon code is returned as an error.
+func (h *Harness) Invoke(meta stub.Metadata, function string, params ...string) (result []byte, err error) {
+	if meta == nil {
+		meta = make(stub.Metadata)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Panicked: %v", r)
+		}
+	}()
+	return h.code.OnInvoke(meta, function, params)
+}
+
+// InvokeContext is like Invoke but calls the OnInvokeContext method of the
+// cocoon code with ctx if the cocoon code implements stub.ContextCocoonCode.
+// Use it to test how the cocoon code handles deadlines and cancellation.
+func (h *Harness) InvokeContext(ctx context.Context, meta stub.Metadata, function string, params ...string) (result []byte, err error) {
+	code, ok := h.code.(stub.ContextCocoonCode)
+	if !ok {
+		return h.Invoke(meta, function, params...)
+	}
+	if meta == nil {
+		meta = make(stub.Metadata)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Panicked: %v", r)
+		}
+	}()
+	return code.OnInvokeContext(ctx, meta, function, params)
+}
+
+// resolveCocoonID returns the cocoon under test if cocoonID is empty
+func (h *Harness) resolveCocoonID(cocoonID string) string {
+	if len(cocoonID) == 0 {
+		return h.cocoonID
+	}
+	return cocoonID
+}
+
+// NewLedger creates a ledger of a cocoon. The cocoon under
+// test owns the ledger if cocoonID is empty.
+func (h *Harness) NewLedger(cocoonID, name string, chained, public bool) (*types.Ledger, error) {
+	result, err := h.orderer.CreateLedger(context.Background(), &proto_orderer.CreateLedgerParams{
+		CocoonID: h.resolveCocoonID(cocoonID),
+		Name:     name,
+		Chained:  chained,
+		Public:   public,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ledger types.Ledger
+	return &ledger, convert(result, &ledger)
+}
+
+// Put adds a transaction to a ledger of a cocoon as another writer would,
+// bypassing the stub. Use it to change the state read by the cocoon code,
+// for example to cause a revision conflict.
+func (h *Harness) Put(cocoonID, ledgerName, key string, value []byte) (*types.Transaction, error) {
+	result, err := h.orderer.Put(context.Background(), &proto_orderer.PutTransactionParams{
+		CocoonID:   h.resolveCocoonID(cocoonID),
+		LedgerName: ledgerName,
+		Transactions: []*proto_orderer.Transaction{{
+			Id:        util.UUID4(),
+			Ledger:    ledgerName,
+			Key:       key,
+			Value:     string(value),
+			CreatedAt: time.Now().Unix(),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	} else if len(result.TxReceipts) > 0 && len(result.TxReceipts[0].Err) > 0 {
+		return nil, fmt.Errorf("%s", result.TxReceipts[0].Err)
+	}
+	return h.Get(cocoonID, ledgerName, key)
+}
+
+// Get returns the current transaction of a key in a ledger of a cocoon
+func (h *Harness) Get(cocoonID, ledgerName, key string) (*types.Transaction, error) {
+	result, err := h.orderer.Get(context.Background(), &proto_orderer.GetParams{
+		CocoonID: h.resolveCocoonID(cocoonID),
+		Ledger:   ledgerName,
+		Key:      key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tx types.Transaction
+	return &tx, convert(result, &tx)
+}
+
+// ChainHeight returns the number of the last block of a chained ledger of a cocoon
+func (h *Harness) ChainHeight(cocoonID, ledgerName string) (int64, error) {
+	height, err := h.orderer.GetChainHeight(context.Background(), &proto_orderer.GetChainHeightParams{
+		CocoonID: h.resolveCocoonID(cocoonID),
+		Ledger:   ledgerName,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return height.GetHeight(), nil
+}
+
+// HoldLock acquires a lock on a key of a cocoon as another process would,
+// so that the cocoon code fails to acquire it. It returns a function
+// that releases the lock.
+func (h *Harness) HoldLock(cocoonID, key string, ttl time.Duration) (func(), error) {
+	lock := h.newLock(h.resolveCocoonID(cocoonID), key, ttl, "")
+	if err := lock.Acquire(); err != nil {
+		return nil, err
+	}
+	return func() { lock.Release() }, nil
+}
+
+// newLock creates a memory lock on a key of a cocoon.
+// Locks are scoped to the harness.
+func (h *Harness) newLock(cocoonID, key string, ttl time.Duration, session string) types.Lock {
+	lock := memory.NewLockWithTTL(fmt.Sprintf("%s/%s/%s", h.id, cocoonID, key), ttl)
+	lock.SetState(map[string]interface{}{"lock_session": session})
+	return lock
+}
+
+// convert copies a proto object to its equivalent type
+func convert(src, dst interface{}) error {
+	bs, err := util.ToJSON(src)
+	if err != nil {
+		return err
+	}
+	return util.FromJSON(bs, dst)
+}
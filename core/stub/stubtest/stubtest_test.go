@@ -0,0 +1,323 @@
+package stubtest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ellcrys/cocoon/core/orderer/orderer"
+	"github.com/ellcrys/cocoon/core/stub"
+	"github.com/ellcrys/cocoon/core/types"
+	logging "github.com/op/go-logging"
+	. "github.com/smartystreets/goconvey/convey"
+	context "golang.org/x/net/context"
+)
+
+// accounts is a cocoon code that stores balances
+type accounts struct{}
+
+func (c *accounts) OnInit() error {
+	if _, err := stub.Me.NewLedger("balances", true, false); err != nil {
+		return err
+	}
+	_, err := stub.Me.NewLedger("notes", false, false)
+	return err
+}
+
+func (c *accounts) OnInvoke(meta stub.Metadata, function string, params []string) ([]byte, error) {
+	switch function {
+	case "set":
+		tx, err := stub.Me.Put("balances", params[0], []byte(params[1]))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(tx.ID), nil
+	case "setIfUnchanged":
+		readSet := []*types.KeyRevision{{Key: params[0], Revision: params[2]}}
+		_, err := stub.Me.PutIfUnchanged(readSet, "notes", params[0], []byte(params[1]))
+		return nil, err
+	case "get":
+		tx, err := stub.Me.Get("balances", params[0])
+		if err != nil {
+			return nil, err
+		}
+		return []byte(tx.Value), nil
+	case "lock":
+		lock, err := stub.Me.Lock(params[0], 10*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		return nil, lock.Release()
+	case "getOther":
+		tx, err := stub.NewLink(params[0]).Get(params[1], params[2])
+		if err != nil {
+			return nil, err
+		}
+		return []byte(tx.Value), nil
+	case "caller":
+		return []byte(meta.Get("caller")), nil
+	case "pay":
+		if _, err := stub.Emit("paid", map[string]string{"to": params[0]}); err != nil {
+			return nil, err
+		}
+		_, err := stub.Emit("fee", params[0])
+		return nil, err
+	case "refund":
+		_, err := stub.Me.Batch().Put("balances", params[0], []byte("0")).Emit("refunded", params[0]).Commit()
+		return nil, err
+	case "refundIfUnchanged":
+		_, err := stub.Me.Batch().Emit("refunded", params[0]).PutSafe(params[1], "balances", params[0], []byte("0")).Commit()
+		return nil, err
+	case "forgeEvent":
+		_, err := stub.Me.Put(types.EventLedgerName, "event1", []byte(`{"name":"paid"}`))
+		return nil, err
+	case "invokeOther":
+		return stub.NewLink(params[0]).Invoke(params[1], params[2:])
+	default:
+		panic("unknown function")
+	}
+}
+
+func (c *accounts) OnStop() {}
+
+// newRates creates a routed cocoon code that returns exchange rates
+func newRates() stub.CocoonCode {
+	r := stub.NewRouter()
+	r.HandleArgs("rate", "", func(meta stub.Metadata, currency string) (string, error) {
+		if currency != "EUR" {
+			return "", stub.NewError("unknown_currency", "no rate for %s", currency)
+		}
+		return "1.1 for " + meta.CallerCocoonID(), nil
+	}, "currency")
+	r.HandleArgs("deadline", "", func(ctx context.Context) (bool, error) {
+		_, ok := ctx.Deadline()
+		return ok, nil
+	})
+	r.HandleArgs("export", "", func(format string) (*stub.Response, error) {
+		if format != "csv" {
+			return stub.NewResponse(406, []byte("unsupported format")), nil
+		}
+		return stub.NewResponse(200, []byte("EUR,1.1\n")).SetContentType("text/csv"), nil
+	}, "format")
+	return &rates{Router: r}
+}
+
+type rates struct {
+	*stub.Router
+}
+
+func (c *rates) OnInit() error { return nil }
+func (c *rates) OnStop()       {}
+
+func TestHarness(t *testing.T) {
+
+	orderer.SetLogLevel(logging.CRITICAL)
+	stub.SetDebugLevel(logging.CRITICAL)
+
+	Convey("Harness", t, func() {
+
+		h, err := New(new(accounts))
+		So(err, ShouldBeNil)
+		defer h.Close()
+		So(h.Init(), ShouldBeNil)
+
+		Convey("Should put and get keys of a chained ledger", func() {
+			_, err := h.Invoke(nil, "set", "alice", "10")
+			So(err, ShouldBeNil)
+			_, err = h.Invoke(nil, "set", "bob", "20")
+			So(err, ShouldBeNil)
+
+			value, err := h.Invoke(nil, "get", "alice")
+			So(err, ShouldBeNil)
+			So(string(value), ShouldEqual, "10")
+
+			tx, err := h.Get("", "balances", "bob")
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "20")
+
+			height, err := h.ChainHeight("", "balances")
+			So(err, ShouldBeNil)
+			So(height, ShouldEqual, 2)
+		})
+
+		Convey("Should pass the metadata to the cocoon code", func() {
+			value, err := h.Invoke(stub.Metadata{"caller": "carol"}, "caller")
+			So(err, ShouldBeNil)
+			So(string(value), ShouldEqual, "carol")
+		})
+
+		Convey("Should return a panic of the cocoon code as an error", func() {
+			_, err := h.Invoke(nil, "unknown")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "unknown function")
+		})
+
+		Convey("Should fail to put a key changed by another writer since it was read", func() {
+			tx, err := h.Put("", "notes", "note1", []byte("a"))
+			So(err, ShouldBeNil)
+			_, err = h.Put("", "notes", "note1", []byte("b"))
+			So(err, ShouldBeNil)
+			_, err = h.Invoke(nil, "setIfUnchanged", "note1", "c", tx.ID)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "stale object")
+		})
+
+		Convey("Should fail to acquire a lock held by another process", func() {
+			release, err := h.HoldLock("", "alice", 10*time.Second)
+			So(err, ShouldBeNil)
+			_, err = h.Invoke(nil, "lock", "alice")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, types.ErrLockAlreadyAcquired.Error())
+
+			release()
+			_, err = h.Invoke(nil, "lock", "alice")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Should check operations on other cocoons against their ACL", func() {
+			_, err := h.NewLedger("other", "shared", false, false)
+			So(err, ShouldBeNil)
+			_, err = h.Put("other", "shared", "key1", []byte("value1"))
+			So(err, ShouldBeNil)
+
+			_, err = h.Invoke(nil, "getOther", "other", "shared", "key1")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "permission denied: GET operation not allowed")
+
+			h.SetACL("other", types.ACLMap{"shared": "allow-get"})
+			value, err := h.Invoke(nil, "getOther", "other", "shared", "key1")
+			So(err, ShouldBeNil)
+			So(string(value), ShouldEqual, "value1")
+
+			Convey("Natively linked cocoons should have the privileges of the cocoon", func() {
+				h.SetACL("other", nil)
+				h.SetNativeLink("other")
+				_, err := h.Invoke(nil, "getOther", "other", "shared", "key1")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("Should invoke functions of other cocoons allowed by their ACL", func() {
+			_, err := h.Invoke(nil, "invokeOther", "rates", "rate", "EUR")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "linked cocoon is not running")
+
+			h.SetCocoonCode("rates", newRates())
+			_, err = h.Invoke(nil, "invokeOther", "rates", "rate", "EUR")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "permission denied: INVOKE operation not allowed")
+
+			h.SetACL("rates", types.ACLMap{"@rate": "allow-invoke"})
+			value, err := h.Invoke(nil, "invokeOther", "rates", "rate", "EUR")
+			So(err, ShouldBeNil)
+			So(string(value), ShouldEqual, "1.1 for "+h.CocoonID())
+
+			Convey("Should return the coded errors of the invoked function", func() {
+				_, err := h.Invoke(nil, "invokeOther", "rates", "rate", "XYZ")
+				So(err, ShouldResemble, stub.NewError("unknown_currency", "no rate for XYZ"))
+			})
+
+			Convey("Should pass the deadline of the invocation to the invoked function", func() {
+				h.SetACL("rates", types.ACLMap{"@rate": "allow-invoke", "@deadline": "allow-invoke"})
+				value, err := h.Invoke(nil, "invokeOther", "rates", "deadline")
+				So(err, ShouldBeNil)
+				So(string(value), ShouldEqual, "true")
+			})
+
+			Convey("Should return the body of raw responses of the invoked function", func() {
+				h.SetACL("rates", types.ACLMap{"@rate": "allow-invoke", "@export": "allow-invoke"})
+				value, err := h.Invoke(nil, "invokeOther", "rates", "export", "csv")
+				So(err, ShouldBeNil)
+				So(string(value), ShouldEqual, "EUR,1.1\n")
+
+				_, err = h.Invoke(nil, "invokeOther", "rates", "export", "xml")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "function responded with status 406: unsupported format")
+			})
+		})
+
+		Convey("Should fail the operations of a link whose context is done", func() {
+			_, err := h.Invoke(nil, "set", "alice", "10")
+			So(err, ShouldBeNil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = stub.Me.WithContext(ctx).Get("balances", "alice")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "context canceled")
+			_, err = stub.Me.WithContext(ctx).Put("balances", "alice", []byte("20"))
+			So(err, ShouldNotBeNil)
+
+			tx, err := stub.Me.Get("balances", "alice")
+			So(err, ShouldBeNil)
+			So(tx.Value, ShouldEqual, "10")
+		})
+
+		Convey("Should record emitted events and stream them to subscribers", func() {
+			_, err := h.Invoke(nil, "pay", "bob")
+			So(err, ShouldBeNil)
+			_, err = h.Invoke(nil, "refund", "bob")
+			So(err, ShouldBeNil)
+
+			events, stop, err := stub.Me.WatchEvents(1, "paid", "refunded")
+			So(err, ShouldBeNil)
+			defer stop()
+
+			paid := <-events
+			So(paid.Name, ShouldEqual, "paid")
+			So(string(paid.Payload), ShouldEqual, `{"to":"bob"}`)
+			So(paid.CocoonID, ShouldEqual, stub.GetCocoonID())
+			So(paid.BlockNumber, ShouldBeGreaterThan, 0)
+
+			refunded := <-events
+			So(refunded.Name, ShouldEqual, "refunded")
+			So(refunded.BlockNumber, ShouldBeGreaterThan, paid.BlockNumber)
+
+			Convey("Should stream new events", func() {
+				_, err := h.Invoke(nil, "pay", "carol")
+				So(err, ShouldBeNil)
+				event := <-events
+				So(string(event.Payload), ShouldEqual, `{"to":"carol"}`)
+			})
+
+			Convey("Should not record the events of a batch that fails", func() {
+				height, err := h.ChainHeight("", types.EventLedgerName)
+				So(err, ShouldBeNil)
+				tx, err := h.Put("", "balances", "bob", []byte("5"))
+				So(err, ShouldBeNil)
+				_, err = h.Invoke(nil, "refundIfUnchanged", "bob", tx.ID)
+				So(err, ShouldBeNil)
+				newHeight, err := h.ChainHeight("", types.EventLedgerName)
+				So(err, ShouldBeNil)
+				So(newHeight, ShouldEqual, height+1)
+
+				_, err = h.Invoke(nil, "refundIfUnchanged", "bob", tx.ID)
+				So(err, ShouldNotBeNil)
+				newHeight, err = h.ChainHeight("", types.EventLedgerName)
+				So(err, ShouldBeNil)
+				So(newHeight, ShouldEqual, height+1)
+			})
+
+			Convey("Should not record events written to the event ledger", func() {
+				_, err := h.Invoke(nil, "forgeEvent")
+				So(err, ShouldEqual, stub.ErrEventLedgerWrite)
+			})
+		})
+
+		Convey("Should fail operations until failures are cleared", func() {
+			h.FailOn(types.TxGet, fmt.Errorf("connector unavailable"))
+			_, err := h.Invoke(nil, "get", "alice")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "connector unavailable")
+
+			h.FailOn("", fmt.Errorf("orderer unavailable"))
+			_, err = h.Invoke(nil, "set", "alice", "10")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "orderer unavailable")
+
+			h.ClearFailures()
+			_, err = h.Invoke(nil, "set", "alice", "10")
+			So(err, ShouldBeNil)
+		})
+	})
+}
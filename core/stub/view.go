@@ -50,6 +50,22 @@ func Render(name string, dataSource interface{}) ([]byte, error) {
 	}).ToJSON(), nil
 }
 
+// RenderHTML creates a response with the markup rendered from an eml
+// view file and a data source. The markup is written as HTML by the connector.
+func RenderHTML(name string, dataSource interface{}) (*Response, error) {
+	if !HasView(name) {
+		return nil, ErrViewNotFound
+	}
+
+	content, err := ioutil.ReadFile(path.Join(ViewDir, name+".html"))
+	if err != nil {
+		return nil, err
+	}
+
+	markup := mustache.Render(string(content), dataSource)
+	return NewResponse(200, []byte(markup)).SetContentType("text/html; charset=utf-8"), nil
+}
+
 // RenderString creates a view from an eml component and a data source.
 // Data source can be a map or a struct type.The created View is json encoded.
 func RenderString(eml string, dataSource interface{}) (content []byte, err error) {
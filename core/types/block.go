@@ -11,8 +11,11 @@ type Block struct {
 	ChainName     string `json:"chainName,omitempty" structs:"chainName,omitempty" mapstructure:"chainName,omitempty" gorm:"index:idx_name_chain_name"`
 	PrevBlockHash string `json:"prevBlockHash,omitempty" structs:"prevBlockHash,omitempty" mapstructure:"prevBlockHash,omitempty" gorm:"type:varchar(64);unique_index:idx_name_prev_block_hash"`
 	Hash          string `json:"hash,omitempty" structs:"hash,omitempty" mapstructure:"hash,omitempty" gorm:"type:varchar(64);unique_index:idx_name_hash"`
+	MerkleRoot    string `json:"merkleRoot,omitempty" structs:"merkleRoot,omitempty" mapstructure:"merkleRoot,omitempty" gorm:"type:varchar(64)"`
 	Transactions  []byte `json:"transactions,omitempty" structs:"transactions,omitempty" mapstructure:"transactions,omitempty"`
 	CreatedAt     int64  `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty" gorm:"index:idx_name_created_at"`
+	Signature     string `json:"signature,omitempty" structs:"signature,omitempty" mapstructure:"signature,omitempty" gorm:"type:varchar(128)"`
+	KeyID         string `json:"keyId,omitempty" structs:"keyId,omitempty" mapstructure:"keyId,omitempty" gorm:"type:varchar(64)"`
 }
 
 // GetTransactions returns a slice of transactions in the block.
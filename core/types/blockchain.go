@@ -9,6 +9,23 @@ type Blockchain interface {
 	CreateChain(name string, public bool) (*Chain, error)
 	GetChain(name string) (*Chain, error)
 	CreateBlock(id, chainName string, transactions []*Transaction) (*Block, error)
+	ImportBlock(chainName string, block *Block) (*Block, error)
 	GetBlock(chainName, id string) (*Block, error)
+	GetBlockByNumber(chainName string, number uint) (*Block, error)
+	GetChainHeight(chainName string) (uint, error)
+	ListBlocks(chainName string, fromNumber uint, limit int) ([]*Block, error)
+	Compact(chainName string, toNumber uint) (*Checkpoint, error)
+	GetCheckpoint(chainName string) (*Checkpoint, error)
+	ImportCheckpoint(chainName string, checkpoint *Checkpoint) (*Checkpoint, error)
+	SetChainLeader(chainName, addr string) error
+	CheckChainLeader(chainName, addr string) error
+	SetSigner(signer BlockSigner)
+	WithDBTx(dbTx interface{}) (Blockchain, error)
 	Close() error
 }
+
+// BlockSigner defines an interface for signing the hash of new blocks
+type BlockSigner interface {
+	KeyID() string
+	Sign(hash string) (string, error)
+}
@@ -8,6 +8,7 @@ type Chain struct {
 	Name      string `json:"name,omitempty" structs:"name,omitempty" mapstructure:"name,omitempty" gorm:"type:varchar(128);unique_index:idx_name_chain_name"`
 	Public    bool   `json:"public,omitempty" structs:"public,omitempty" mapstructure:"public,omitempty"`
 	CreatedAt int64  `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty"`
+	Leader    string `json:"leader,omitempty" structs:"leader,omitempty" mapstructure:"leader,omitempty" gorm:"type:varchar(255)"`
 }
 
 // ToJSON returns the json equivalent of this object
@@ -0,0 +1,22 @@
+package types
+
+import "github.com/ellcrys/util"
+
+// Checkpoint represents the last block removed from a compacted chain.
+// It holds the number, hash and signature of the block so that the
+// remaining blocks can still be verified against it.
+type Checkpoint struct {
+	PK        uint   `json:"-" gorm:"primary_key"`
+	ChainName string `json:"chainName,omitempty" structs:"chainName,omitempty" mapstructure:"chainName,omitempty" gorm:"type:varchar(128);unique_index:idx_name_checkpoint_chain_name"`
+	Number    uint   `json:"number,omitempty" structs:"number,omitempty" mapstructure:"number,omitempty"`
+	Hash      string `json:"hash,omitempty" structs:"hash,omitempty" mapstructure:"hash,omitempty" gorm:"type:varchar(64)"`
+	Signature string `json:"signature,omitempty" structs:"signature,omitempty" mapstructure:"signature,omitempty" gorm:"type:varchar(128)"`
+	KeyID     string `json:"keyId,omitempty" structs:"keyId,omitempty" mapstructure:"keyId,omitempty" gorm:"type:varchar(64)"`
+	CreatedAt int64  `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty"`
+}
+
+// ToJSON returns the json equivalent of this object
+func (c *Checkpoint) ToJSON() []byte {
+	json, _ := util.ToJSON(c)
+	return json
+}
@@ -28,6 +28,21 @@ var (
 	// ErrBlockNotFound indicates a missing block
 	ErrBlockNotFound = fmt.Errorf("block not found")
 
+	// ErrLedgerNotChained indicates an operation that requires a chained ledger
+	ErrLedgerNotChained = fmt.Errorf("ledger is not chained")
+
+	// ErrInvalidRangeCursor indicates a range cursor that was not created by a range operation
+	ErrInvalidRangeCursor = fmt.Errorf("invalid range cursor")
+
+	// ErrNoSigningKey indicates an orderer that was started without a block signing key
+	ErrNoSigningKey = fmt.Errorf("orderer has no signing key")
+
+	// ErrNoKeyProvider indicates an encrypted value read by an orderer that was started without encryption keys
+	ErrNoKeyProvider = fmt.Errorf("orderer has no encryption key provider")
+
+	// ErrIndexNotFound indicates a query on an index that was not declared on the ledger
+	ErrIndexNotFound = fmt.Errorf("index not found")
+
 	// ErrOperationTimeout represents a timeout error that occurs when response
 	// is not received from orderer in time.
 	ErrOperationTimeout = fmt.Errorf("operation timed out")
@@ -56,6 +71,18 @@ var (
 	// ErrLockNotAcquired represents a lock on a key that is not acquired
 	ErrLockNotAcquired = fmt.Errorf("lock not acquired")
 
+	// ErrBatchAborted is the receipt error of a valid transaction that was not
+	// stored because another transaction of its atomic batch failed
+	ErrBatchAborted = fmt.Errorf("batch aborted: another transaction in the batch failed")
+
+	// ErrNotChainLeader is returned by an orderer that is asked to append to a
+	// chain it does not lead by a request that has already been forwarded, or
+	// that lost the leadership of the chain before the append was committed
+	ErrNotChainLeader = fmt.Errorf("orderer is not the leader of the chain")
+
+	// ErrChainLeaderUnknown is returned when the leader of a chain cannot be determined
+	ErrChainLeaderUnknown = fmt.Errorf("chain leader is unknown")
+
 	// ErrPermissionNotGrant is the error to send when the user does not have permission to perform an operation
 	ErrPermissionNotGrant = fmt.Errorf("Permission denied: You do not have permission to perform this operation")
 )
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// EventLedgerName is the name of the chained, public ledger of a cocoon
+// where the events it emits are recorded. The name is not a valid resource
+// name, so the ledger cannot be created or written by the cocoon code.
+const EventLedgerName = "@events"
+
+// Event describes a domain event emitted by a cocoon code.
+// BlockNumber is the number of the block of the event ledger
+// that includes the event and Index is the position of the
+// transaction of the event in the block.
+type Event struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	CocoonID    string          `json:"cocoonId"`
+	BlockNumber uint            `json:"blockNumber,omitempty"`
+	Index       uint            `json:"index"`
+	CreatedAt   int64           `json:"createdAt"`
+}
+
+// eventRecord is the value of the transaction that records an event
+type eventRecord struct {
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// MakeEventValue creates the value of the transaction that records an event
+func MakeEventValue(name string, payload json.RawMessage) string {
+	value, _ := json.Marshal(eventRecord{Name: name, Payload: payload})
+	return string(value)
+}
+
+// GetEvents returns the events recorded by the transactions of a ledger event
+// of the event ledger of a cocoon. If names are given, only events with one of
+// the names are returned. Transactions that do not record an event are ignored.
+func GetEvents(cocoonID string, ledgerEvent *LedgerEvent, names ...string) []*Event {
+
+	var blockNumber uint
+	if ledgerEvent.Block != nil {
+		blockNumber = ledgerEvent.Block.Number
+	}
+
+	var events []*Event
+	for i, tx := range ledgerEvent.Transactions {
+		var record eventRecord
+		if err := json.Unmarshal([]byte(tx.Value), &record); err != nil || len(record.Name) == 0 {
+			continue
+		}
+		if len(names) > 0 && !hasName(names, record.Name) {
+			continue
+		}
+		events = append(events, &Event{
+			ID:          tx.ID,
+			Name:        record.Name,
+			Payload:     record.Payload,
+			CocoonID:    cocoonID,
+			BlockNumber: blockNumber,
+			Index:       uint(i),
+			CreatedAt:   tx.CreatedAt,
+		})
+	}
+
+	return events
+}
+
+// hasName checks whether a name is in a list of names
+func hasName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
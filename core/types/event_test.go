@@ -0,0 +1,48 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvent(t *testing.T) {
+	Convey("Event", t, func() {
+		Convey(".GetEvents", func() {
+
+			ledgerEvent := &LedgerEvent{
+				Ledger: EventLedgerName,
+				Transactions: []*Transaction{
+					{ID: "tx1", Value: MakeEventValue("paid", json.RawMessage(`{"amount":5}`)), CreatedAt: 10},
+					{ID: "tx2", Value: MakeEventValue("refunded", nil), CreatedAt: 11},
+					{ID: "tx3", Value: "not an event"},
+				},
+				Block: &Block{Number: 3},
+			}
+
+			Convey("Should return the events recorded by the transactions", func() {
+				events := GetEvents("cocoon1", ledgerEvent)
+				So(len(events), ShouldEqual, 2)
+				So(events[0], ShouldResemble, &Event{
+					ID:          "tx1",
+					Name:        "paid",
+					Payload:     json.RawMessage(`{"amount":5}`),
+					CocoonID:    "cocoon1",
+					BlockNumber: 3,
+					Index:       0,
+					CreatedAt:   10,
+				})
+				So(events[1].Name, ShouldEqual, "refunded")
+				So(events[1].Index, ShouldEqual, 1)
+			})
+
+			Convey("Should only return events with one of the names", func() {
+				events := GetEvents("cocoon1", ledgerEvent, "refunded", "cancelled")
+				So(len(events), ShouldEqual, 1)
+				So(events[0].ID, ShouldEqual, "tx2")
+				So(events[0].Index, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,258 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Query operators supported by secondary indexes
+const (
+	// QueryOpEq matches indexed values equal to the query value
+	QueryOpEq = "="
+
+	// QueryOpLt matches indexed values less than the query value
+	QueryOpLt = "<"
+
+	// QueryOpLte matches indexed values less than or equal to the query value
+	QueryOpLte = "<="
+
+	// QueryOpGt matches indexed values greater than the query value
+	QueryOpGt = ">"
+
+	// QueryOpGte matches indexed values greater than or equal to the query value
+	QueryOpGte = ">="
+)
+
+// queryOps are the supported query operators
+var queryOps = []string{QueryOpEq, QueryOpLt, QueryOpLte, QueryOpGt, QueryOpGte}
+
+// indexPathPartExpr matches a field name of an index path
+var indexPathPartExpr = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// Index declares a secondary index on a JSON path of the values of a ledger.
+// The path is made of field names separated by dots (e.g `owner.name`).
+// Values that are not JSON objects or that do not include the path
+// are not indexed.
+type Index struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// GetPath returns the field names of the index path
+func (i *Index) GetPath() []string {
+	return strings.Split(i.Path, ".")
+}
+
+// Indexes is a list of secondary indexes. It is
+// stored as a JSON encoded text column by sql stores.
+type Indexes []*Index
+
+// Get returns the index with the given name or nil if none exists
+func (idx Indexes) Get(name string) *Index {
+	for _, index := range idx {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// Validate checks that the indexes have valid and unique names and paths
+func (idx Indexes) Validate() error {
+	seen := make(map[string]bool)
+	for _, index := range idx {
+		if index == nil || !indexPathPartExpr.MatchString(index.Name) {
+			return fmt.Errorf("invalid index name")
+		} else if seen[index.Name] {
+			return fmt.Errorf("index %s: duplicate index name", index.Name)
+		}
+		seen[index.Name] = true
+		for _, part := range index.GetPath() {
+			if !indexPathPartExpr.MatchString(part) {
+				return fmt.Errorf("index %s: invalid path", index.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer
+func (idx Indexes) Value() (driver.Value, error) {
+	if len(idx) == 0 {
+		return "", nil
+	}
+	bs, err := json.Marshal(idx)
+	return string(bs), err
+}
+
+// Scan implements sql.Scanner
+func (idx *Indexes) Scan(src interface{}) error {
+	var bs []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bs = v
+	case string:
+		bs = []byte(v)
+	default:
+		return fmt.Errorf("unexpected index list type %T", src)
+	}
+	if len(bs) == 0 {
+		*idx = nil
+		return nil
+	}
+	return json.Unmarshal(bs, idx)
+}
+
+// GetIndexValue returns the value at the path of an index in a JSON encoded
+// transaction value. It returns false if the value is not a JSON object
+// or does not include the path.
+func GetIndexValue(value string, path []string) (interface{}, bool) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, false
+	}
+	for _, field := range path {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if doc, ok = obj[field]; !ok {
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+// MakeIndexDoc creates a JSON object that only includes the values at the paths of
+// the indexes of a JSON encoded transaction value. Stores index it instead of the
+// value of a transaction whose value is encrypted. It returns an empty string if
+// the value includes none of the paths.
+func MakeIndexDoc(value string, indexes Indexes) string {
+
+	var doc map[string]interface{}
+	for _, index := range indexes {
+		indexed, ok := GetIndexValue(value, index.GetPath())
+		if !ok {
+			continue
+		}
+		if doc == nil {
+			doc = make(map[string]interface{})
+		}
+		path := index.GetPath()
+		obj := doc
+		for _, field := range path[:len(path)-1] {
+			next, ok := obj[field].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				obj[field] = next
+			}
+			obj = next
+		}
+		obj[path[len(path)-1]] = indexed
+	}
+
+	if doc == nil {
+		return ""
+	}
+
+	bs, _ := json.Marshal(doc)
+	return string(bs)
+}
+
+// ValidateQuery checks a query operator and the JSON encoded value it
+// compares indexed values with. Values compared by an ordering operator
+// must be a string, number or boolean. It returns the decoded value.
+func ValidateQuery(op, value string) (interface{}, error) {
+
+	found := false
+	for _, queryOp := range queryOps {
+		found = found || queryOp == op
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown query operator: %s", op)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return nil, fmt.Errorf("query value must be valid JSON")
+	}
+
+	if op != QueryOpEq {
+		switch decoded.(type) {
+		case string, float64, bool:
+		default:
+			return nil, fmt.Errorf("operator %s requires a string, number or boolean value", op)
+		}
+	}
+
+	return decoded, nil
+}
+
+// MatchQuery checks whether an indexed value matches a query. Values of different
+// JSON types never match. Numbers are compared numerically, strings by their
+// bytes and false is less than true. Objects, arrays and null only support QueryOpEq.
+func MatchQuery(indexed interface{}, op string, value interface{}) bool {
+
+	var cmp int
+	switch v := value.(type) {
+	case float64:
+		n, ok := indexed.(float64)
+		if !ok {
+			return false
+		}
+		cmp = compareFloats(n, v)
+	case string:
+		s, ok := indexed.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(s, v)
+	case bool:
+		b, ok := indexed.(bool)
+		if !ok {
+			return false
+		}
+		cmp = compareBools(b, v)
+	default:
+		return op == QueryOpEq && reflect.DeepEqual(indexed, value)
+	}
+
+	switch op {
+	case QueryOpEq:
+		return cmp == 0
+	case QueryOpLt:
+		return cmp < 0
+	case QueryOpLte:
+		return cmp <= 0
+	case QueryOpGt:
+		return cmp > 0
+	case QueryOpGte:
+		return cmp >= 0
+	}
+
+	return false
+}
+
+func compareFloats(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	if a == b {
+		return 0
+	} else if !a {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIndex(t *testing.T) {
+	Convey("Index", t, func() {
+
+		Convey(".GetIndexValue", func() {
+			Convey("Should return the value at the path", func() {
+				value, ok := GetIndexValue(`{"owner": {"name": "ann", "age": 30}}`, []string{"owner", "age"})
+				So(ok, ShouldBeTrue)
+				So(value, ShouldEqual, float64(30))
+			})
+
+			Convey("Should return false if the path or a valid JSON value does not exist", func() {
+				_, ok := GetIndexValue(`{"owner": "ann"}`, []string{"owner", "name"})
+				So(ok, ShouldBeFalse)
+				_, ok = GetIndexValue(`not json`, []string{"owner"})
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey(".MakeIndexDoc", func() {
+			Convey("Should only include the values at the index paths", func() {
+				indexes := Indexes{{Name: "by_age", Path: "owner.age"}, {Name: "by_status", Path: "status"}, {Name: "by_total", Path: "total"}}
+				doc := MakeIndexDoc(`{"owner": {"name": "ann", "age": 30}, "status": "paid", "secret": "x"}`, indexes)
+				So(doc, ShouldEqual, `{"owner":{"age":30},"status":"paid"}`)
+			})
+
+			Convey("Should return an empty string if no index path is included", func() {
+				So(MakeIndexDoc(`{"secret": "x"}`, Indexes{{Name: "by_status", Path: "status"}}), ShouldBeEmpty)
+				So(MakeIndexDoc(`not json`, Indexes{{Name: "by_status", Path: "status"}}), ShouldBeEmpty)
+			})
+		})
+
+		Convey(".ValidateQuery", func() {
+			Convey("Should return the decoded value", func() {
+				value, err := ValidateQuery(QueryOpGte, `"b"`)
+				So(err, ShouldBeNil)
+				So(value, ShouldEqual, "b")
+			})
+
+			Convey("Should return error if the operator or value is invalid", func() {
+				_, err := ValidateQuery("!=", `1`)
+				So(err, ShouldNotBeNil)
+				_, err = ValidateQuery(QueryOpEq, `{`)
+				So(err, ShouldNotBeNil)
+				_, err = ValidateQuery(QueryOpLt, `[1]`)
+				So(err, ShouldNotBeNil)
+				_, err = ValidateQuery(QueryOpEq, `[1]`)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey(".MatchQuery", func() {
+			Convey("Should compare values of the same type", func() {
+				So(MatchQuery(float64(10), QueryOpLt, float64(9)), ShouldBeFalse)
+				So(MatchQuery(float64(10), QueryOpGt, float64(9)), ShouldBeTrue)
+				So(MatchQuery("abc", QueryOpLte, "abd"), ShouldBeTrue)
+				So(MatchQuery(true, QueryOpGt, false), ShouldBeTrue)
+				So(MatchQuery([]interface{}{"a"}, QueryOpEq, []interface{}{"a"}), ShouldBeTrue)
+			})
+
+			Convey("Should not match values of different types", func() {
+				So(MatchQuery("10", QueryOpEq, float64(10)), ShouldBeFalse)
+				So(MatchQuery(float64(10), QueryOpGte, "1"), ShouldBeFalse)
+			})
+		})
+	})
+}
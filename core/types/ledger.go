@@ -26,13 +26,25 @@ func GetSystemPrivateLedgerName() string {
 
 // Ledger represents a group of transactions
 type Ledger struct {
-	Number       uint   `json:"number,omitempty" structs:"number,omitempty" mapstructure:"number,omitempty" gorm:"primary_key"`
-	Name         string `json:"name,omitempty" structs:"name,omitempty" mapstructure:"name,omitempty" gorm:"type:varchar(128);unique_index:idx_name_name"`
-	NameInternal string `json:"-" structs:"nameInternal,omitempty" mapstructure:"nameInternal,omitempty" gorm:"-" sql:"-"`
-	CocoonID     string `json:"cocoonId,omitempty" structs:"cocoonId,omitempty" mapstructure:"cocoonId,omitempty" gorm:"index:idx_name_cocoon_id"`
-	Public       bool   `json:"public,omitempty" structs:"public,omitempty" mapstructure:"public,omitempty" json:"public"`
-	Chained      bool   `json:"chained,omitempty" structs:"chained,omitempty" mapstructure:"chained,omitempty"`
-	CreatedAt    int64  `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty" gorm:"index:idx_name_created_at"`
+	Number        uint    `json:"number,omitempty" structs:"number,omitempty" mapstructure:"number,omitempty" gorm:"primary_key"`
+	Name          string  `json:"name,omitempty" structs:"name,omitempty" mapstructure:"name,omitempty" gorm:"type:varchar(128);unique_index:idx_name_name"`
+	NameInternal  string  `json:"-" structs:"nameInternal,omitempty" mapstructure:"nameInternal,omitempty" gorm:"-" sql:"-"`
+	CocoonID      string  `json:"cocoonId,omitempty" structs:"cocoonId,omitempty" mapstructure:"cocoonId,omitempty" gorm:"index:idx_name_cocoon_id"`
+	Public        bool    `json:"public,omitempty" structs:"public,omitempty" mapstructure:"public,omitempty" json:"public"`
+	Chained       bool    `json:"chained,omitempty" structs:"chained,omitempty" mapstructure:"chained,omitempty"`
+	CreatedAt     int64   `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty" gorm:"index:idx_name_created_at"`
+	KeepRevisions uint    `json:"keepRevisions,omitempty" structs:"keepRevisions,omitempty" mapstructure:"keepRevisions,omitempty" sql:"DEFAULT:0"`
+	KeepFor       int64   `json:"keepFor,omitempty" structs:"keepFor,omitempty" mapstructure:"keepFor,omitempty" sql:"DEFAULT:0"`
+	DataKey       string  `json:"dataKey,omitempty" structs:"-" mapstructure:"-" gorm:"type:text"`
+	Indexes       Indexes `json:"indexes,omitempty" structs:"-" mapstructure:"-" gorm:"type:text"`
+}
+
+// HasRetention checks whether a retention policy is set on the ledger.
+// KeepRevisions is the number of revisions kept per key and KeepFor is
+// the number of seconds a superseded revision is kept for. The most
+// recent revision of a key is never pruned.
+func (l *Ledger) HasRetention() bool {
+	return l.KeepRevisions > 0 || l.KeepFor > 0
 }
 
 // ToJSON returns the json equivalent of this object
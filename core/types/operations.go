@@ -7,6 +7,12 @@ const (
 	// TxPut represents a message to create a transaction
 	TxPut = "PUT"
 
+	// TxDelete represents a message to delete a key by adding a tombstone transaction
+	TxDelete = "DELETE"
+
+	// TxPutMulti represents a message to atomically create transactions in several ledgers
+	TxPutMulti = "PUT_MULTI"
+
 	// TxGetLedger represents a message to get a ledger
 	TxGetLedger = "GET_LEDGER"
 
@@ -16,9 +22,36 @@ const (
 	// TxGetBlockByID represents a message to get ledger's block by id
 	TxGetBlockByID = "GET_BLOCK_BY_ID"
 
+	// TxGetBlockByNumber represents a message to get ledger's block by number
+	TxGetBlockByNumber = "GET_BLOCK_BY_NUMBER"
+
+	// TxGetChainHeight represents a message to get the number of the last block of a ledger
+	TxGetChainHeight = "GET_CHAIN_HEIGHT"
+
+	// TxListBlocks represents a message to get a range of blocks of a ledger by number
+	TxListBlocks = "LIST_BLOCKS"
+
 	// TxRangeGet represents a message to get a range of transactions
 	TxRangeGet = "RANGE_GET"
 
+	// TxQuery represents a message to find the keys matching a query on a secondary index
+	TxQuery = "QUERY"
+
+	// TxGetHistory represents a message to get the previous values of a key
+	TxGetHistory = "GET_HISTORY"
+
+	// TxGetTxProof represents a message to get the block inclusion proof of a transaction
+	TxGetTxProof = "GET_TX_PROOF"
+
+	// TxSubscribe represents a message to subscribe to the changes of a ledger
+	TxSubscribe = "SUBSCRIBE"
+
+	// TxEmit represents a message to record events in the event ledger of a cocoon
+	TxEmit = "EMIT"
+
+	// TxInvoke represents a message to invoke a function of a cocoon code
+	TxInvoke = "INVOKE"
+
 	// OpLockAcquire represents a message to acquire a lock
 	OpLockAcquire = "LOCK_ACQUIRE"
 
@@ -27,4 +60,16 @@ const (
 
 	// OpLockCheckAcquire represents a message to check whether a session is still the acquirer of a lock
 	OpLockCheckAcquire = "LOCK_CHECK_ACQUIRE"
+
+	// SysFuncListFunctions is the system function of the stub that returns
+	// the catalog of the functions registered on the router of a cocoon code
+	SysFuncListFunctions = "@@functions"
 )
+
+// HeaderCallerCocoonID is the invoke metadata header that holds the id
+// of the cocoon that invoked a function of another cocoon
+const HeaderCallerCocoonID = "Caller-Cocoon-Id"
+
+// HeaderScheduledJob is the invoke metadata header that holds the name
+// of the scheduled job for which the connector invoked a function
+const HeaderScheduledJob = "Scheduled-Job"
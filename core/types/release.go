@@ -13,20 +13,22 @@ import (
 // Release represents a new update to a cocoon's
 // configuration
 type Release struct {
-	ID          string   `json:"id,omitempty" structs:"id,omitempty" mapstructure:"id,omitempty"`
-	CocoonID    string   `json:"cocoonID,omitempty" structs:"cocoonID,omitempty" mapstructure:"cocoonID,omitempty"`
-	URL         string   `json:"URL,omitempty" structs:"URL,omitempty" mapstructure:"URL,omitempty"`
-	Version     string   `json:"version,omitempty" structs:"version,omitempty" mapstructure:"version,omitempty"`
-	Language    string   `json:"language,omitempty" structs:"language,omitempty" mapstructure:"language,omitempty"`
-	BuildParam  string   `json:"buildParam,omitempty" structs:"buildParam,omitempty" mapstructure:"buildParam,omitempty"`
-	Link        string   `json:"link,omitempty" structs:"link,omitempty" mapstructure:"link,omitempty"`
-	SigApproved int      `json:"sigApproved,omitempty" structs:"sigApproved,omitempty" mapstructure:"sigApproved,omitempty"`
-	SigDenied   int      `json:"sigDenied,omitempty" structs:"sigDenied,omitempty" mapstructure:"sigDenied,omitempty"`
-	VotersID    []string `json:"votersID,omitempty" structs:"votersID,omitempty" mapstructure:"votersID,omitempty"`
-	Firewall    Firewall `json:"firewall,omitempty" structs:"firewall,omitempty" mapstructure:"firewall,omitempty"`
-	ACL         ACLMap   `json:"acl,omitempty" structs:"acl,omitempty" mapstructure:"acl,omitempty"`
-	Env         Env      `json:"env,omitempty" structs:"env,omitempty" mapstructure:"env,omitempty"`
-	CreatedAt   string   `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty"`
+	ID          string         `json:"id,omitempty" structs:"id,omitempty" mapstructure:"id,omitempty"`
+	CocoonID    string         `json:"cocoonID,omitempty" structs:"cocoonID,omitempty" mapstructure:"cocoonID,omitempty"`
+	URL         string         `json:"URL,omitempty" structs:"URL,omitempty" mapstructure:"URL,omitempty"`
+	Version     string         `json:"version,omitempty" structs:"version,omitempty" mapstructure:"version,omitempty"`
+	Language    string         `json:"language,omitempty" structs:"language,omitempty" mapstructure:"language,omitempty"`
+	BuildParam  string         `json:"buildParam,omitempty" structs:"buildParam,omitempty" mapstructure:"buildParam,omitempty"`
+	Link        string         `json:"link,omitempty" structs:"link,omitempty" mapstructure:"link,omitempty"`
+	SigApproved int            `json:"sigApproved,omitempty" structs:"sigApproved,omitempty" mapstructure:"sigApproved,omitempty"`
+	SigDenied   int            `json:"sigDenied,omitempty" structs:"sigDenied,omitempty" mapstructure:"sigDenied,omitempty"`
+	VotersID    []string       `json:"votersID,omitempty" structs:"votersID,omitempty" mapstructure:"votersID,omitempty"`
+	Firewall    Firewall       `json:"firewall,omitempty" structs:"firewall,omitempty" mapstructure:"firewall,omitempty"`
+	ACL         ACLMap         `json:"acl,omitempty" structs:"acl,omitempty" mapstructure:"acl,omitempty"`
+	Env         Env            `json:"env,omitempty" structs:"env,omitempty" mapstructure:"env,omitempty"`
+	Schedule    Schedule       `json:"schedule,omitempty" structs:"schedule,omitempty" mapstructure:"schedule,omitempty"`
+	Timeouts    InvokeTimeouts `json:"timeouts,omitempty" structs:"timeouts,omitempty" mapstructure:"timeouts,omitempty"`
+	CreatedAt   string         `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty"`
 }
 
 // Difference returns the difference between the current release and another release
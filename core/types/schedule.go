@@ -0,0 +1,98 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ellcrys/cocoon/core/common/cron"
+	"github.com/ellcrys/util"
+)
+
+// Missed-run policies of a scheduled job
+const (
+	// ScheduleMissedSkip drops the runs that could not be made on time
+	ScheduleMissedSkip = "skip"
+
+	// ScheduleMissedRunOnce makes a single run as soon as possible
+	// for any number of runs that could not be made on time
+	ScheduleMissedRunOnce = "run_once"
+)
+
+// Overlap policies of a scheduled job
+const (
+	// ScheduleOverlapSkip skips a run while the previous run of the job is in progress
+	ScheduleOverlapSkip = "skip"
+
+	// ScheduleOverlapAllow allows runs of the job to be in progress at the same time
+	ScheduleOverlapAllow = "allow"
+)
+
+// ScheduledJob describes a function of the cocoon code invoked on a cron expression.
+// A run is missed if the cocoon code is not healthy or, with the skip overlap policy,
+// if the previous run is still in progress.
+type ScheduledJob struct {
+	Name     string   `json:"name" structs:"name" mapstructure:"name,omitempty"`
+	Cron     string   `json:"cron" structs:"cron" mapstructure:"cron,omitempty"`
+	Function string   `json:"function" structs:"function" mapstructure:"function,omitempty"`
+	Params   []string `json:"params,omitempty" structs:"params" mapstructure:"params,omitempty"`
+	Missed   string   `json:"missed,omitempty" structs:"missed" mapstructure:"missed,omitempty"`
+	Overlap  string   `json:"overlap,omitempty" structs:"overlap" mapstructure:"overlap,omitempty"`
+}
+
+// GetMissed returns the missed-run policy of the job
+func (j ScheduledJob) GetMissed() string {
+	if len(j.Missed) == 0 {
+		return ScheduleMissedSkip
+	}
+	return j.Missed
+}
+
+// GetOverlap returns the overlap policy of the job
+func (j ScheduledJob) GetOverlap() string {
+	if len(j.Overlap) == 0 {
+		return ScheduleOverlapSkip
+	}
+	return j.Overlap
+}
+
+// Schedule defines the scheduled jobs of a cocoon
+type Schedule []ScheduledJob
+
+// Validate checks the jobs of the schedule
+func (s Schedule) Validate() []error {
+	var errs []error
+	var names = map[string]bool{}
+	for i, job := range s {
+		if len(job.Name) == 0 {
+			errs = append(errs, fmt.Errorf("job %d: name is required", i))
+		} else if names[job.Name] {
+			errs = append(errs, fmt.Errorf("job %d: name '%s' is already used", i, job.Name))
+		}
+		names[job.Name] = true
+		if len(job.Function) == 0 {
+			errs = append(errs, fmt.Errorf("job %d: function is required", i))
+		}
+		if _, err := cron.Parse(job.Cron); err != nil {
+			errs = append(errs, fmt.Errorf("job %d: cron: %s", i, err))
+		}
+		if m := job.GetMissed(); m != ScheduleMissedSkip && m != ScheduleMissedRunOnce {
+			errs = append(errs, fmt.Errorf("job %d: missed: unknown policy '%s'", i, m))
+		}
+		if o := job.GetOverlap(); o != ScheduleOverlapSkip && o != ScheduleOverlapAllow {
+			errs = append(errs, fmt.Errorf("job %d: overlap: unknown policy '%s'", i, o))
+		}
+	}
+	return errs
+}
+
+// NewScheduleFromByte creates a schedule from a JSON encoded list of jobs
+func NewScheduleFromByte(b []byte) Schedule {
+	var s Schedule
+	util.FromJSON(b, &s)
+	return s
+}
+
+// ToJSON returns the json equivalent of this object
+func (s Schedule) ToJSON() []byte {
+	b, _ := util.ToJSON(s)
+	return b
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSchedule(t *testing.T) {
+	Convey("Schedule", t, func() {
+		Convey(".Validate", func() {
+			Convey("Should return no error if the jobs are valid", func() {
+				s := Schedule([]ScheduledJob{
+					{Name: "cleanup", Cron: "*/5 * * * *", Function: "cleanup"},
+					{Name: "report", Cron: "@daily", Function: "report", Params: []string{"daily"}, Missed: ScheduleMissedRunOnce, Overlap: ScheduleOverlapAllow},
+				})
+				So(s.Validate(), ShouldBeEmpty)
+			})
+
+			Convey("Should return errors for invalid jobs", func() {
+				s := Schedule([]ScheduledJob{
+					{Name: "cleanup", Cron: "*/5 * * * *", Function: "cleanup"},
+					{Name: "cleanup", Cron: "* * *", Function: "", Missed: "always", Overlap: "queue"},
+				})
+				errs := s.Validate()
+				So(errs, ShouldHaveLength, 5)
+				So(errs[0].Error(), ShouldEqual, "job 1: name 'cleanup' is already used")
+				So(errs[1].Error(), ShouldEqual, "job 1: function is required")
+				So(errs[2].Error(), ShouldEqual, "job 1: cron: expected 5 fields, got 3")
+				So(errs[3].Error(), ShouldEqual, "job 1: missed: unknown policy 'always'")
+				So(errs[4].Error(), ShouldEqual, "job 1: overlap: unknown policy 'queue'")
+			})
+		})
+
+		Convey(".NewScheduleFromByte", func() {
+			Convey("Should decode a schedule encoded with ToJSON", func() {
+				s := Schedule([]ScheduledJob{
+					{Name: "cleanup", Cron: "*/5 * * * *", Function: "cleanup", Params: []string{"a"}},
+				})
+				So(NewScheduleFromByte(s.ToJSON()), ShouldResemble, s)
+			})
+
+			Convey("Should apply the default policies", func() {
+				s := NewScheduleFromByte([]byte(`[{"name":"cleanup","cron":"@hourly","function":"cleanup"}]`))
+				So(s[0].GetMissed(), ShouldEqual, ScheduleMissedSkip)
+				So(s[0].GetOverlap(), ShouldEqual, ScheduleOverlapSkip)
+			})
+		})
+	})
+}
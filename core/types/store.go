@@ -10,9 +10,26 @@ type Store interface {
 	CreateLedger(cocoonID, name string, chained, public bool) (*Ledger, error)
 	CreateLedgerThen(cocoonID, name string, chained, public bool, then func() error) (*Ledger, error)
 	GetLedger(name string) (*Ledger, error)
+	ListLedgers(cocoonID string) ([]*Ledger, error)
 	Put(ledger string, txs []*Transaction) ([]*TxReceipt, error)
 	PutThen(ledger string, txs []*Transaction, then func(validTxs []*Transaction) error) ([]*TxReceipt, error)
-	Get(ledger, key string) (*Transaction, error)
-	GetRange(ledger, startKey, endKey string, inclusive bool, limit, lastNum int) ([]*Transaction, error)
+	PutMulti(batch []*LedgerTransactions) ([][]*TxReceipt, error)
+	PutMultiThen(batch []*LedgerTransactions, then func(batch []*LedgerTransactions) error) ([][]*TxReceipt, error)
+	Get(ledger, key string, includeDeleted bool) (*Transaction, error)
+	GetByID(ledger, id string) (*Transaction, error)
+	GetRange(ledger, startKey, endKey string, inclusive, includeDeleted, reverse bool, afterKey string, limit, offset int) ([]*Transaction, error)
+	GetHistory(ledger, key string, limit int, cursor uint) ([]*Transaction, error)
+	ListTransactions(ledger string, afterNumber uint, limit int) ([]*Transaction, error)
+	GetLastTxNumber() (uint, error)
+	SetLedgerRetention(name string, keepRevisions uint, keepFor int64) (*Ledger, error)
+	ListRetainedLedgers() ([]*Ledger, error)
+	ListPrunableTransactions(ledger string, keepRevisions uint, before int64, limit int) ([]string, error)
+	DeleteTransactions(ledger string, ids []string) (int, error)
+	SetTransactionValues(ledger string, txs []*Transaction) (int, error)
+	SetLedgerDataKey(name, oldDataKey, newDataKey string) (bool, error)
+	ListEncryptedLedgers() ([]*Ledger, error)
+	SetLedgerIndexes(name string, indexes Indexes) (*Ledger, error)
+	Query(ledger, index, op, value, afterKey string, limit int) ([]*Transaction, error)
+	Transact(fn func(store Store, blockchain Blockchain) error) error
 	Close() error
 }
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultInvokeTimeout is the time an invocation of a function of a
+	// cocoon code may run if the cocoon does not set its timeout
+	DefaultInvokeTimeout = 2 * time.Minute
+
+	// MaxInvokeTimeout is the maximum invoke timeout a cocoon can set
+	MaxInvokeTimeout = 10 * time.Minute
+
+	// AnyFunction is the key of the timeout of functions that have no timeout set
+	AnyFunction = "*"
+)
+
+// InvokeTimeouts maps the functions of a cocoon code to the time their
+// invocation may run, as a duration string (e.g 30s, 1m30s). The timeout
+// set for AnyFunction applies to functions that have no timeout set.
+type InvokeTimeouts map[string]string
+
+// NewInvokeTimeouts creates a new InvokeTimeouts type from values in
+// map[string]interface{} or map[string]string. Non-string values are omitted.
+func NewInvokeTimeouts(val interface{}) InvokeTimeouts {
+	timeouts := InvokeTimeouts{}
+	switch t := val.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if valStr, ok := v.(string); ok {
+				timeouts[k] = valStr
+			}
+		}
+	case map[string]string:
+		for k, v := range t {
+			timeouts[k] = v
+		}
+	}
+	return timeouts
+}
+
+// Validate checks the timeouts
+func (t InvokeTimeouts) Validate() []error {
+
+	var functions []string
+	for function := range t {
+		functions = append(functions, function)
+	}
+	sort.Strings(functions)
+
+	var errs []error
+	for _, function := range functions {
+		d, err := time.ParseDuration(t[function])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid duration '%s'", function, t[function]))
+		} else if d < time.Second || d > MaxInvokeTimeout {
+			errs = append(errs, fmt.Errorf("%s: timeout must be between 1s and %s", function, MaxInvokeTimeout))
+		}
+	}
+	return errs
+}
+
+// Get returns the timeout of a function. It returns the timeout
+// set for AnyFunction or DefaultInvokeTimeout if the function
+// has no valid timeout set.
+func (t InvokeTimeouts) Get(function string) time.Duration {
+	for _, key := range []string{function, AnyFunction} {
+		if d, err := time.ParseDuration(t[key]); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultInvokeTimeout
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInvokeTimeouts(t *testing.T) {
+	Convey("InvokeTimeouts", t, func() {
+		Convey(".NewInvokeTimeouts", func() {
+			Convey("Should omit non-string values", func() {
+				timeouts := NewInvokeTimeouts(map[string]interface{}{"transfer": "10s", "report": 30})
+				So(timeouts, ShouldResemble, InvokeTimeouts{"transfer": "10s"})
+			})
+		})
+
+		Convey(".Validate", func() {
+			Convey("Should return no error if the timeouts are valid", func() {
+				So(InvokeTimeouts{"*": "1m", "transfer": "10s", "report": "10m"}.Validate(), ShouldBeEmpty)
+			})
+
+			Convey("Should return errors for invalid timeouts", func() {
+				errs := InvokeTimeouts{"a": "soon", "b": "500ms", "c": "1h"}.Validate()
+				So(errs, ShouldHaveLength, 3)
+				So(errs[0].Error(), ShouldEqual, "a: invalid duration 'soon'")
+				So(errs[1].Error(), ShouldEqual, "b: timeout must be between 1s and 10m0s")
+				So(errs[2].Error(), ShouldEqual, "c: timeout must be between 1s and 10m0s")
+			})
+		})
+
+		Convey(".Get", func() {
+			Convey("Should return the timeout of a function", func() {
+				timeouts := InvokeTimeouts{"*": "1m", "transfer": "10s"}
+				So(timeouts.Get("transfer"), ShouldEqual, 10*time.Second)
+				So(timeouts.Get("report"), ShouldEqual, time.Minute)
+			})
+
+			Convey("Should return the default timeout if no timeout is set", func() {
+				So(InvokeTimeouts(nil).Get("transfer"), ShouldEqual, DefaultInvokeTimeout)
+			})
+		})
+	})
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/base64"
 	"fmt"
 	"strings"
 
@@ -12,30 +13,48 @@ import (
 // All transaction entries must reference the hash of the immediate transaction
 // sharing the same ledger name.
 type Transaction struct {
-	Number         uint   `json:"number,omitempty" structs:"number,omitempty" mapstructure:"number,omitempty" gorm:"primary_key"`
-	Ledger         string `json:"ledger,omitempty" structs:"ledger,omitempty" mapstructure:"ledger,omitempty" gorm:"type:varchar(128);index:idx_name_ledger_name"`
-	ID             string `json:"id,omitempty" structs:"id,omitempty" mapstructure:"id,omitempty" gorm:"type:varchar(64);unique_index:idx_name_id"`
-	Key            string `json:"key,omitempty" structs:"key,omitempty" mapstructure:"key,omitempty" gorm:"type:varchar(128);index:idx_name_key"`
-	Value          string `json:"value,omitempty" structs:"value,omitempty" mapstructure:"value,omitempty" gorm:"type:text"`
-	Hash           string `json:"hash,omitempty" structs:"hash,omitempty" mapstructure:"hash,omitempty" gorm:"type:varchar(64);unique_index:idx_name_hash"`
-	BlockID        string `json:"blockId,omitempty" structs:"blockId,omitempty" mapstructure:"blockId,omitempty"`
-	RevisionTo     string `json:"revisionTo,omitempty" structs:"revisionTo,omitempty" mapstructure:"revisionTo,omitempty" gorm:"type:varchar(64);unique_index:idx_name_revision_to" sql:"DEFAULT:NULL"`
-	CreatedAt      int64  `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty" gorm:"index:idx_name_created_at"`
-	LedgerInternal string `json:"-" structs:"-" mapstructure:"-" gorm:"-" sql:"-"`
-	KeyInternal    string `json:"-" structs:"-" mapstructure:"-" gorm:"-" sql:"-"`
-	Block          *Block `json:"block" structs:"block" mapstructure:"block,omitempty" gorm:"-" sql:"-"`
+	Number         uint           `json:"number,omitempty" structs:"number,omitempty" mapstructure:"number,omitempty" gorm:"primary_key"`
+	Ledger         string         `json:"ledger,omitempty" structs:"ledger,omitempty" mapstructure:"ledger,omitempty" gorm:"type:varchar(128);index:idx_name_ledger_name"`
+	ID             string         `json:"id,omitempty" structs:"id,omitempty" mapstructure:"id,omitempty" gorm:"type:varchar(64);unique_index:idx_name_id"`
+	Key            string         `json:"key,omitempty" structs:"key,omitempty" mapstructure:"key,omitempty" gorm:"type:varchar(128);index:idx_name_key"`
+	Value          string         `json:"value,omitempty" structs:"value,omitempty" mapstructure:"value,omitempty" gorm:"type:text"`
+	Hash           string         `json:"hash,omitempty" structs:"hash,omitempty" mapstructure:"hash,omitempty" gorm:"type:varchar(64);unique_index:idx_name_hash"`
+	BlockID        string         `json:"blockId,omitempty" structs:"blockId,omitempty" mapstructure:"blockId,omitempty"`
+	RevisionTo     string         `json:"revisionTo,omitempty" structs:"revisionTo,omitempty" mapstructure:"revisionTo,omitempty" gorm:"type:varchar(64);unique_index:idx_name_revision_to" sql:"DEFAULT:NULL"`
+	Deleted        bool           `json:"deleted,omitempty" structs:"deleted,omitempty" mapstructure:"deleted,omitempty" sql:"DEFAULT:false"`
+	CreatedAt      int64          `json:"createdAt,omitempty" structs:"createdAt,omitempty" mapstructure:"createdAt,omitempty" gorm:"index:idx_name_created_at"`
+	LedgerInternal string         `json:"-" structs:"-" mapstructure:"-" gorm:"-" sql:"-"`
+	KeyInternal    string         `json:"-" structs:"-" mapstructure:"-" gorm:"-" sql:"-"`
+	Block          *Block         `json:"block" structs:"block" mapstructure:"block,omitempty" gorm:"-" sql:"-"`
+	ReadSet        []*KeyRevision `json:"readSet,omitempty" structs:"-" mapstructure:"-" gorm:"-" sql:"-"`
+	IndexDoc       string         `json:"-" structs:"-" mapstructure:"-" gorm:"type:text"`
 }
 
-// MakeHash creates a hash of a transaction
+// KeyRevision describes the revision of a key that was read by the creator of a
+// transaction. The revision is the id of the most recent transaction of the key,
+// including tombstones, or empty if the key has never been written.
+// A transaction is only stored if every key in its read-set is still at
+// the revision that was read. Read-sets are not stored.
+type KeyRevision struct {
+	Key      string `json:"key"`
+	Revision string `json:"revision"`
+}
+
+// MakeHash creates a hash of a transaction. The deletion flag is only
+// included for tombstones so that hashes of other transactions are unchanged.
 func (t *Transaction) MakeHash() string {
-	return util.Sha256(fmt.Sprintf(
+	data := fmt.Sprintf(
 		"%s;%s;%s;%s;%s;%d",
 		t.ID,
 		crypto.ToBase64([]byte(t.LedgerInternal)),
 		crypto.ToBase64([]byte(t.KeyInternal)),
 		crypto.ToBase64([]byte(t.Value)),
 		crypto.ToBase64([]byte(t.BlockID)),
-		t.CreatedAt))
+		t.CreatedAt)
+	if t.Deleted {
+		data += ";deleted"
+	}
+	return util.Sha256(data)
 }
 
 // ToJSON returns the json equivalent of this object
@@ -55,6 +74,13 @@ func GetActualKeyFromTxKey(key string) string {
 	return strings.Split(key, ";")[1]
 }
 
+// LedgerTransactions defines a group of transactions to be stored in a ledger
+// as part of an atomic multi-ledger batch
+type LedgerTransactions struct {
+	Ledger       string         `json:"ledger,omitempty"`
+	Transactions []*Transaction `json:"transactions,omitempty"`
+}
+
 // TxReceipt defines a structure for representing transaction status
 // from endpoint that manipulate transactions
 type TxReceipt struct {
@@ -67,3 +93,43 @@ type PutResult struct {
 	TxReceipts []*TxReceipt `json:"txReceipts,omitempty"`
 	Block      *Block       `json:"block,omitempty"`
 }
+
+// PutMultiResult defines a structure for the result of a multi-ledger
+// put operation. Results are in the order of the ledgers in the batch.
+type PutMultiResult struct {
+	Results []*PutResult `json:"results,omitempty"`
+}
+
+// RangeResult defines a structure for range operation result.
+// NextCursor is empty when there are no more transactions to fetch.
+type RangeResult struct {
+	Transactions []*Transaction `json:"transactions,omitempty"`
+	NextCursor   string         `json:"nextCursor,omitempty"`
+}
+
+// LedgerEvent describes transactions committed to a ledger. Block is
+// the block that includes the transactions if the ledger is chained.
+// The transactions of the block are not included in Block.
+type LedgerEvent struct {
+	Ledger       string         `json:"ledger,omitempty"`
+	Transactions []*Transaction `json:"transactions,omitempty"`
+	Block        *Block         `json:"block,omitempty"`
+}
+
+// rangeCursorVersion is prepended to the key encoded in a range cursor
+const rangeCursorVersion = "1:"
+
+// MakeRangeCursor creates an opaque token for continuing a range
+// operation after the transaction with the given key.
+func MakeRangeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(rangeCursorVersion + key))
+}
+
+// ParseRangeCursor returns the key encoded in a range cursor
+func ParseRangeCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), rangeCursorVersion) {
+		return "", ErrInvalidRangeCursor
+	}
+	return strings.TrimPrefix(string(data), rangeCursorVersion), nil
+}
@@ -21,6 +21,29 @@ func TestTransaction(t *testing.T) {
 				}
 				So(tx.MakeHash(), ShouldEqual, "25cea8016b574f5f68ca721976a53918cdd2d33724ea957c592413bf5ec4481a")
 			})
+
+			Convey("Should create a different hash for a tombstone", func() {
+				tx := &Transaction{ID: "some_id", Key: "key", CreatedAt: 123456789}
+				tombstone := &Transaction{ID: "some_id", Key: "key", CreatedAt: 123456789, Deleted: true}
+				So(tombstone.MakeHash(), ShouldNotEqual, tx.MakeHash())
+			})
+		})
+
+		Convey(".MakeRangeCursor", func() {
+			Convey("Should create a cursor that can be parsed back to the key", func() {
+				cursor := MakeRangeCursor("order:12")
+				So(cursor, ShouldNotContainSubstring, "order")
+				key, err := ParseRangeCursor(cursor)
+				So(err, ShouldBeNil)
+				So(key, ShouldEqual, "order:12")
+			})
+
+			Convey("Should return error if cursor is invalid", func() {
+				_, err := ParseRangeCursor("not a cursor")
+				So(err, ShouldEqual, ErrInvalidRangeCursor)
+				_, err = ParseRangeCursor("b3JkZXI6MTI")
+				So(err, ShouldEqual, ErrInvalidRangeCursor)
+			})
 		})
 	})
 }